@@ -54,6 +54,11 @@ var statusCmd = &cobra.Command{
 	Use:     "status",
 	GroupID: groupAuth,
 	Short:   "Show current authentication status",
+	Long: `Show the active profile and token health.
+
+The set and list subcommands report and read commit build statuses
+(external CI results); see 'bbkt status set --help'.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		runStatus()
 		return nil
@@ -0,0 +1,415 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+var branchRestrictionsCmd = &cobra.Command{
+	Use:     "branch-restrictions",
+	Aliases: []string{"branch-permissions"},
+	GroupID: groupData,
+	Short:   "Manage branch permissions and merge checks",
+	Long: `List, create, update, and delete branch restrictions, and apply a policy
+file across repositories.
+
+A restriction targets branches either by --pattern (a name or glob such as
+main or release/*) or by --branch-type (a branching model type: development,
+production, feature, bugfix, release, hotfix). See 'bbkt branch-restrictions
+kinds' for every kind and whether it takes a --value or user/group
+exemptions.`,
+	Example: `  bbkt branch-restrictions list
+  bbkt branch-restrictions create --kind force --pattern main
+  bbkt branch-restrictions create --kind push --pattern main --groups release-managers
+  bbkt branch-restrictions create --kind require_approvals_to_merge --branch-type production --value 2
+  bbkt branch-restrictions apply --file policy.yaml --dry-run`,
+}
+
+var branchRestrictionsListCmd = &cobra.Command{
+	Use:   "list [workspace] [repo-slug]",
+	Short: "List branch restrictions",
+	Args:  cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		kind, _ := cmd.Flags().GetString("kind")
+		pattern, _ := cmd.Flags().GetString("pattern")
+
+		client := getClient()
+		result, err := client.ListBranchRestrictions(bitbucket.ListBranchRestrictionsArgs{
+			Workspace: workspace,
+			RepoSlug:  repoSlug,
+			Kind:      kind,
+			Pattern:   pattern,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result) == 0 {
+				fmt.Println("No branch restrictions found.")
+				return
+			}
+			t := NewTable()
+			t.Header("ID", "Kind", "Branches", "Value", "Exempt")
+			for _, r := range result {
+				value := "-"
+				if r.Value != nil {
+					value = strconv.Itoa(*r.Value)
+				}
+				t.Row(strconv.Itoa(r.ID), r.Kind, r.Target(), value, orDash(restrictionExemptions(r)))
+			}
+			t.Flush()
+		})
+		return nil
+	},
+}
+
+var branchRestrictionsKindsCmd = &cobra.Command{
+	Use:   "kinds",
+	Short: "List restriction kinds",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		PrintOrJSON(cmd, bitbucket.BranchRestrictionKinds, func() {
+			t := NewTable()
+			t.Header("Kind", "Takes", "Description")
+			for _, k := range bitbucket.BranchRestrictionKinds {
+				takes := "-"
+				switch {
+				case k.HasValue:
+					takes = "--value"
+				case k.Exemptions:
+					takes = "--users/--groups"
+				}
+				t.Row(k.Kind, takes, k.Description)
+			}
+			t.Flush()
+		})
+		return nil
+	},
+}
+
+var branchRestrictionsCreateCmd = &cobra.Command{
+	Use:   "create [workspace] [repo-slug] --kind <kind> --pattern <glob>|--branch-type <type>",
+	Short: "Create a branch restriction",
+	Args:  cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		spec := restrictionSpecFromFlags(cmd, bitbucket.BranchRestrictionSpec{})
+
+		client := getClient()
+		r, err := client.CreateBranchRestriction(bitbucket.CreateBranchRestrictionArgs{
+			Workspace:             workspace,
+			RepoSlug:              repoSlug,
+			BranchRestrictionSpec: spec,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, r, func() {
+			fmt.Printf("Created %s restriction on %s (ID %d).\n", r.Kind, r.Target(), r.ID)
+		})
+		return nil
+	},
+}
+
+var branchRestrictionsUpdateCmd = &cobra.Command{
+	Use:   "update [workspace] [repo-slug] <id>",
+	Short: "Change a branch restriction",
+	Long: `Change the given fields of a branch restriction; anything not passed is
+kept. --users and --groups replace the current exemptions.`,
+	Example: `  bbkt branch-restrictions update 12 --value 3
+  bbkt branch-restrictions update 14 --groups release-managers,sre`,
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		id, err := strconv.Atoi(trailing[0])
+		if err != nil {
+			return fmt.Errorf("invalid restriction ID %q", trailing[0])
+		}
+		ra := bitbucket.BranchRestrictionArgs{Workspace: workspace, RepoSlug: repoSlug, ID: id}
+
+		client := getClient()
+		current, err := client.GetBranchRestriction(ra)
+		if err != nil {
+			return err
+		}
+		r, err := client.UpdateBranchRestriction(bitbucket.UpdateBranchRestrictionArgs{
+			BranchRestrictionArgs: ra,
+			BranchRestrictionSpec: restrictionSpecFromFlags(cmd, specFromRestriction(current)),
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, r, func() {
+			fmt.Printf("Updated %s restriction on %s (ID %d).\n", r.Kind, r.Target(), r.ID)
+		})
+		return nil
+	},
+}
+
+var branchRestrictionsDeleteCmd = &cobra.Command{
+	Use:   "delete [workspace] [repo-slug] <id>",
+	Short: "Delete a branch restriction",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		id, err := strconv.Atoi(trailing[0])
+		if err != nil {
+			return fmt.Errorf("invalid restriction ID %q", trailing[0])
+		}
+
+		client := getClient()
+		if err := client.DeleteBranchRestriction(bitbucket.BranchRestrictionArgs{
+			Workspace: workspace,
+			RepoSlug:  repoSlug,
+			ID:        id,
+		}); err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, map[string]any{
+			"id":      id,
+			"deleted": true,
+		}, func() {
+			fmt.Printf("Deleted branch restriction %d.\n", id)
+		})
+		return nil
+	},
+}
+
+var branchRestrictionsApplyCmd = &cobra.Command{
+	Use:   "apply [workspace] [repo-slug] --file <policy.yaml>",
+	Short: "Make repositories' branch restrictions match a policy file",
+	Long: `Compare each repository's branch restrictions with a policy file, print
+the difference, and apply it. Applying twice changes nothing the second time.
+
+Restrictions are matched on kind and branch target. Missing ones are
+created and ones whose value or exemptions differ are updated. Restrictions
+the policy does not list are left alone unless the policy sets prune: true
+or --prune is passed, in which case they are deleted.
+
+  repositories: [api, "svc-*"]   # optional; slugs or globs, default the current repo
+  prune: false
+  restrictions:
+    - kind: push
+      pattern: main
+      groups: [release-managers]
+    - kind: force
+      pattern: main
+    - kind: require_approvals_to_merge
+      pattern: main
+      value: 2
+    - kind: delete
+      branch_type: production`,
+	Example: `  bbkt branch-restrictions apply --file policy.yaml --dry-run
+  bbkt branch-restrictions apply my-workspace --file policy.yaml`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		prune, _ := cmd.Flags().GetBool("prune")
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		policy, err := bitbucket.ParseBranchPolicy(data)
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		prune = prune || policy.Prune
+
+		client := getClient()
+		var workspace string
+		var repos []string
+		if len(policy.Repositories) > 0 {
+			workspace, _, _, err = ParseArgs(cmd, args, -1)
+			if err != nil {
+				return err
+			}
+			repos, err = client.SelectRepositories(workspace, "", policy.Repositories)
+			if err != nil {
+				return err
+			}
+			if len(repos) == 0 {
+				return fmt.Errorf("no repositories in %s match %s", workspace, strings.Join(policy.Repositories, ", "))
+			}
+		} else {
+			var repoSlug string
+			workspace, repoSlug, _, err = ParseArgs(cmd, args, 0)
+			if err != nil {
+				return err
+			}
+			repos = []string{repoSlug}
+		}
+
+		type repoPlan struct {
+			Repository string                              `json:"repository"`
+			Changes    []bitbucket.BranchRestrictionChange `json:"changes"`
+			Applied    int                                 `json:"applied"`
+			Error      string                              `json:"error,omitempty"`
+		}
+		var results []repoPlan
+		failed := 0
+		for _, repo := range repos {
+			rp := repoPlan{Repository: workspace + "/" + repo}
+			current, err := client.ListBranchRestrictions(bitbucket.ListBranchRestrictionsArgs{Workspace: workspace, RepoSlug: repo})
+			if err == nil {
+				rp.Changes, err = bitbucket.PlanBranchRestrictions(current, policy.Restrictions, prune)
+			}
+			if err == nil && !dryRun {
+				rp.Applied, err = client.ApplyBranchRestrictions(workspace, repo, rp.Changes)
+			}
+			if err != nil {
+				rp.Error = err.Error()
+				failed++
+			}
+			results = append(results, rp)
+		}
+
+		PrintOrJSON(cmd, results, func() {
+			for _, rp := range results {
+				fmt.Println(rp.Repository)
+				printRestrictionPlan(rp.Changes)
+				if rp.Error != "" {
+					fmt.Printf("  error: %s\n", rp.Error)
+				}
+			}
+			if dryRun {
+				fmt.Println("\nDry run: nothing was changed.")
+			}
+		})
+		if failed > 0 {
+			return fmt.Errorf("%d of %d repositories failed", failed, len(repos))
+		}
+		return nil
+	},
+}
+
+// printRestrictionPlan prints a plan as +/~/- lines with a summary.
+func printRestrictionPlan(plan []bitbucket.BranchRestrictionChange) {
+	var create, update, del, keep int
+	for _, ch := range plan {
+		switch ch.Action {
+		case bitbucket.ChangeCreate:
+			create++
+			fmt.Printf("  + %s\n", ch.Key())
+		case bitbucket.ChangeUpdate:
+			update++
+			fmt.Printf("  ~ %s (%s)\n", ch.Key(), strings.Join(ch.Diff, "; "))
+		case bitbucket.ChangeDelete:
+			del++
+			fmt.Printf("  - %s\n", ch.Key())
+		default:
+			keep++
+			if ch.Desired == nil {
+				fmt.Printf("    %s (not in policy, kept)\n", ch.Key())
+			}
+		}
+	}
+	fmt.Printf("  %d to create, %d to update, %d to delete, %d unchanged\n", create, update, del, keep)
+}
+
+// restrictionSpecFromFlags overlays the flags the user passed onto base.
+func restrictionSpecFromFlags(cmd *cobra.Command, base bitbucket.BranchRestrictionSpec) bitbucket.BranchRestrictionSpec {
+	flags := cmd.Flags()
+	if flags.Changed("kind") {
+		base.Kind, _ = flags.GetString("kind")
+	}
+	if flags.Changed("pattern") {
+		base.Pattern, _ = flags.GetString("pattern")
+		base.BranchType = ""
+	}
+	if flags.Changed("branch-type") {
+		base.BranchType, _ = flags.GetString("branch-type")
+		base.Pattern = ""
+	}
+	if flags.Changed("value") {
+		v, _ := flags.GetInt("value")
+		base.Value = &v
+	}
+	if flags.Changed("users") {
+		base.Users, _ = flags.GetStringSlice("users")
+	}
+	if flags.Changed("groups") {
+		base.Groups, _ = flags.GetStringSlice("groups")
+	}
+	return base
+}
+
+// specFromRestriction turns an existing restriction back into a spec.
+func specFromRestriction(r *bitbucket.BranchRestriction) bitbucket.BranchRestrictionSpec {
+	spec := bitbucket.BranchRestrictionSpec{Kind: r.Kind, Value: r.Value}
+	if r.BranchMatchKind == "branching_model" {
+		spec.BranchType = r.BranchType
+	} else {
+		spec.Pattern = r.Pattern
+	}
+	for _, u := range r.Users {
+		spec.Users = append(spec.Users, u.UUID)
+	}
+	for _, g := range r.Groups {
+		spec.Groups = append(spec.Groups, g.Slug)
+	}
+	return spec
+}
+
+func restrictionExemptions(r bitbucket.BranchRestriction) string {
+	var names []string
+	for _, u := range r.Users {
+		names = append(names, u.DisplayName)
+	}
+	for _, g := range r.Groups {
+		names = append(names, "@"+g.Slug)
+	}
+	return strings.Join(names, ", ")
+}
+
+func init() {
+	RootCmd.AddCommand(branchRestrictionsCmd)
+	branchRestrictionsCmd.AddCommand(branchRestrictionsListCmd)
+	branchRestrictionsCmd.AddCommand(branchRestrictionsKindsCmd)
+	branchRestrictionsCmd.AddCommand(branchRestrictionsCreateCmd)
+	branchRestrictionsCmd.AddCommand(branchRestrictionsUpdateCmd)
+	branchRestrictionsCmd.AddCommand(branchRestrictionsDeleteCmd)
+	branchRestrictionsCmd.AddCommand(branchRestrictionsApplyCmd)
+
+	branchRestrictionsListCmd.Flags().String("kind", "", "Only this kind")
+	branchRestrictionsListCmd.Flags().String("pattern", "", "Only restrictions with this exact pattern")
+
+	for _, c := range []*cobra.Command{branchRestrictionsCreateCmd, branchRestrictionsUpdateCmd} {
+		c.Flags().String("kind", "", "Restriction kind (see 'bbkt branch-restrictions kinds')")
+		c.Flags().String("pattern", "", "Branch name or glob, e.g. main or release/*")
+		c.Flags().String("branch-type", "", "Branching model type: development | production | feature | bugfix | release | hotfix")
+		c.Flags().Int("value", 0, "Value for kinds that take one, e.g. required approvals")
+		c.Flags().StringSlice("users", nil, "Exempt users by UUID or account ID (push, restrict_merges)")
+		c.Flags().StringSlice("groups", nil, "Exempt group slugs (push, restrict_merges)")
+		c.MarkFlagsMutuallyExclusive("pattern", "branch-type")
+	}
+	_ = branchRestrictionsCreateCmd.MarkFlagRequired("kind")
+
+	branchRestrictionsApplyCmd.Flags().StringP("file", "f", "", "Policy file (YAML)")
+	_ = branchRestrictionsApplyCmd.MarkFlagRequired("file")
+	branchRestrictionsApplyCmd.Flags().Bool("dry-run", false, "Print the changes without applying them")
+	branchRestrictionsApplyCmd.Flags().Bool("prune", false, "Delete restrictions the policy does not list")
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+var statusSetCmd = &cobra.Command{
+	Use:   "set [workspace] [repo-slug] <commit>",
+	Short: "Report a build result on a commit",
+	Long: `Create or update a build status on a commit, e.g. from an external CI
+system. Reusing a --key updates that build's status instead of adding another.
+<commit> may be a hash, HEAD, or a branch or tag; names resolve in the local
+clone when it is a clone of the target repository, and through the API
+otherwise.`,
+	Example: `  bbkt status set HEAD --key jenkins-unit --state INPROGRESS --url "$BUILD_URL"
+  bbkt status set 3f2aa9a --key jenkins-unit --state FAILED --url "$BUILD_URL" \
+      --description "12 tests failed"`,
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		key, _ := cmd.Flags().GetString("key")
+		state, _ := cmd.Flags().GetString("state")
+		url, _ := cmd.Flags().GetString("url")
+		name, _ := cmd.Flags().GetString("name")
+		desc, _ := cmd.Flags().GetString("description")
+		refName, _ := cmd.Flags().GetString("ref-name")
+
+		client := getClient()
+		commit := resolveCommit(client, workspace, repoSlug, trailing[0])
+		st, err := client.SetCommitStatus(bitbucket.SetCommitStatusArgs{
+			Workspace:   workspace,
+			RepoSlug:    repoSlug,
+			Commit:      commit,
+			Key:         key,
+			State:       state,
+			URL:         url,
+			Name:        name,
+			Description: desc,
+			RefName:     refName,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, st, func() {
+			fmt.Printf("Build '%s' is %s on %s.\n", st.Key, st.State, shortHash(commit))
+		})
+		return nil
+	},
+}
+
+var statusListCmd = &cobra.Command{
+	Use:   "list [workspace] [repo-slug] [commit]",
+	Short: "List build statuses for a commit or pull request",
+	Example: `  bbkt status list HEAD
+  bbkt status list --pr 42`,
+	Args: cobra.MaximumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prID, _ := cmd.Flags().GetInt("pr")
+		trailingCount := 1
+		if prID > 0 {
+			trailingCount = 0
+		}
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, trailingCount)
+		if err != nil {
+			return err
+		}
+		page, pagelen := paginationArgs(cmd)
+
+		client := getClient()
+		var result *bitbucket.Paginated[bitbucket.CommitStatus]
+		if prID > 0 {
+			result, err = client.ListPullRequestStatuses(bitbucket.ListPullRequestStatusesArgs{
+				Workspace: workspace,
+				RepoSlug:  repoSlug,
+				PRID:      prID,
+				Page:      page,
+				Pagelen:   pagelen,
+			})
+		} else {
+			result, err = client.ListCommitStatuses(bitbucket.ListCommitStatusesArgs{
+				Workspace: workspace,
+				RepoSlug:  repoSlug,
+				Commit:    resolveCommit(client, workspace, repoSlug, trailing[0]),
+				Page:      page,
+				Pagelen:   pagelen,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result.Values) == 0 {
+				fmt.Println("No build statuses found.")
+				return
+			}
+			t := NewTable()
+			t.Header("State", "Key", "Name", "Description", "Updated", "URL")
+			for _, s := range result.Values {
+				t.Row(s.State, s.Key, orDash(s.Name), orDash(Truncate(s.Description, 40)), FormatTime(s.UpdatedOn), s.URL)
+			}
+			t.Flush()
+			fmt.Printf("\nOverall: %s\n", bitbucket.SummarizeStatuses(result.Values))
+			PrintPaginationFooter(result.Size, result.Page, len(result.Values), result.Next != "")
+		})
+		return nil
+	},
+}
+
+var commitHashRe = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+
+// resolveCommit turns a ref (HEAD, a branch, a tag) into a full hash in
+// workspace/repoSlug. The local clone resolves it when it is a clone of
+// that repository, or when ref is HEAD (e.g. a CI checkout of a mirror);
+// otherwise the API does, so a branch name never picks up the local
+// clone's commit for another repository. Anything that already looks like
+// a hash, or that can't be resolved, is passed through unchanged for the
+// API to judge.
+func resolveCommit(client *bitbucket.Client, workspace, repoSlug, ref string) string {
+	if commitHashRe.MatchString(ref) {
+		return ref
+	}
+	if ref == "HEAD" || isLocalClone(workspace, repoSlug) {
+		if hash, ok := localCommit(ref); ok {
+			return hash
+		}
+	}
+	if c, err := client.GetCommit(bitbucket.GetCommitArgs{Workspace: workspace, RepoSlug: repoSlug, Commit: ref}); err == nil && c.Hash != "" {
+		return c.Hash
+	}
+	return ref
+}
+
+// localCommit resolves ref with git in the working directory.
+func localCommit(ref string) (string, bool) {
+	out, err := exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}").Output()
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(out)), true
+}
+
+// isLocalClone reports whether the working directory is a clone of
+// workspace/repoSlug.
+func isLocalClone(workspace, repoSlug string) bool {
+	ws, rs, err := bitbucket.GetLocalRepoInfo()
+	return err == nil && strings.EqualFold(ws, workspace) && strings.EqualFold(rs, repoSlug)
+}
+
+// warnFailingBuilds prints a warning to stderr when a pull request has
+// failing build statuses. Lookup errors are ignored: the warning is advisory.
+func warnFailingBuilds(client *bitbucket.Client, workspace, repoSlug string, prID int) {
+	sum, err := client.PullRequestBuildSummary(workspace, repoSlug, prID)
+	if err != nil || sum.Failed == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "warning: PR #%d has failing builds: %s (%s)\n", prID, strings.Join(sum.FailedKeys, ", "), sum)
+}
+
+func init() {
+	statusCmd.AddCommand(statusSetCmd)
+	statusCmd.AddCommand(statusListCmd)
+
+	statusSetCmd.Flags().String("key", "", "Unique build key, e.g. the CI job name (required)")
+	statusSetCmd.Flags().String("state", "", "SUCCESSFUL | FAILED | INPROGRESS | STOPPED (required)")
+	statusSetCmd.Flags().String("url", "", "Link to the build results (required)")
+	statusSetCmd.Flags().String("name", "", "Display name (default the key)")
+	statusSetCmd.Flags().String("description", "", "Short result description")
+	statusSetCmd.Flags().String("ref-name", "", "Branch or tag the build ran for")
+
+	statusListCmd.Flags().Int("pr", 0, "List statuses for this pull request instead of a commit")
+	addPaginationFlags(statusListCmd)
+}
@@ -0,0 +1,384 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+var deploymentsCmd = &cobra.Command{
+	Use:     "deployments",
+	Aliases: []string{"deploys"},
+	GroupID: groupData,
+	Short:   "List Bitbucket Deployments (promotion history)",
+	Long: `List deployments of pipeline releases to environments, newest first.
+Workspace/repo are inferred from your git clone when omitted.
+
+See also 'bbkt environments status' for what is live in each environment.`,
+	Example: `  bbkt deployments list
+  bbkt deployments list --env production      # production's promotion history`,
+}
+
+var deploymentsListCmd = &cobra.Command{
+	Use:   "list [workspace] [repo-slug]",
+	Short: "List deployments, optionally for one environment",
+	Args:  cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		env, _ := cmd.Flags().GetString("env")
+		page, pagelen := paginationArgs(cmd)
+
+		client := getClient()
+		envNames := map[string]string{}
+		if envs, err := client.ListEnvironments(bitbucket.ListEnvironmentsArgs{Workspace: workspace, RepoSlug: repoSlug}); err == nil {
+			for _, e := range envs {
+				envNames[e.UUID] = e.Name
+			}
+		}
+		result, err := client.ListDeployments(bitbucket.ListDeploymentsArgs{
+			Workspace:   workspace,
+			RepoSlug:    repoSlug,
+			Environment: env,
+			Page:        page,
+			Pagelen:     pagelen,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result.Values) == 0 {
+				fmt.Println("No deployments found.")
+				return
+			}
+			t := NewTable()
+			t.Header("Environment", "Release", "Commit", "Result", "Deployer", "Started")
+			for _, d := range result.Values {
+				envName := "-"
+				if d.Environment != nil {
+					envName = orDash(envNames[d.Environment.UUID])
+				}
+				deployer, started := "-", "-"
+				if d.State != nil {
+					if d.State.Deployer != nil {
+						deployer = d.State.Deployer.DisplayName
+					}
+					started = FormatTimePtr(d.State.StartedOn)
+				}
+				t.Row(envName, orDash(d.ReleaseName()), shortHash(d.CommitHash()), orDash(d.Result()), deployer, started)
+			}
+			t.Flush()
+			PrintPaginationFooter(result.Size, result.Page, len(result.Values), result.Next != "")
+		})
+		return nil
+	},
+}
+
+var environmentsCmd = &cobra.Command{
+	Use:     "environments",
+	Aliases: []string{"envs"},
+	GroupID: groupData,
+	Short:   "Manage deployment environments and see what is deployed where",
+	Long: `List, create, and delete deployment environments, show which commit is
+live in each one, and diff two environments.
+Workspace/repo are inferred from your git clone when omitted.
+
+Environments can be referred to by name, slug, or UUID.`,
+	Example: `  bbkt environments status                    # what's in each environment right now
+  bbkt environments get production            # details + promotion history
+  bbkt environments diff staging production   # commits staging has that production doesn't
+  bbkt environments create "QA 2" --type Test`,
+}
+
+var environmentsListCmd = &cobra.Command{
+	Use:   "list [workspace] [repo-slug]",
+	Short: "List deployment environments in promotion order",
+	Args:  cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		result, err := client.ListEnvironments(bitbucket.ListEnvironmentsArgs{Workspace: workspace, RepoSlug: repoSlug})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result) == 0 {
+				fmt.Println("No environments found.")
+				return
+			}
+			t := NewTable()
+			t.Header("Name", "Slug", "Type", "UUID")
+			for _, e := range result {
+				t.Row(e.Name, e.Slug, envTypeName(e), e.UUID)
+			}
+			t.Flush()
+		})
+		return nil
+	},
+}
+
+var environmentsGetCmd = &cobra.Command{
+	Use:   "get [workspace] [repo-slug] <environment>",
+	Short: "Show an environment and its recent deployments",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		client := getClient()
+		env, err := client.GetEnvironment(bitbucket.GetEnvironmentArgs{
+			Workspace:   workspace,
+			RepoSlug:    repoSlug,
+			Environment: trailing[0],
+		})
+		if err != nil {
+			return err
+		}
+		history, err := client.ListDeployments(bitbucket.ListDeploymentsArgs{
+			Workspace:   workspace,
+			RepoSlug:    repoSlug,
+			Environment: env.UUID,
+			Pagelen:     limit,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, map[string]any{
+			"environment": env,
+			"deployments": history.Values,
+		}, func() {
+			KV("Name", env.Name)
+			KV("Slug", env.Slug)
+			KV("Type", envTypeName(*env))
+			KV("UUID", env.UUID)
+			if env.Lock != nil {
+				KV("Lock", env.Lock.Name)
+			}
+			fmt.Println()
+			if len(history.Values) == 0 {
+				fmt.Println("No deployments yet.")
+				return
+			}
+			t := NewTable()
+			t.Header("Release", "Commit", "Result", "Deployer", "Started", "Completed")
+			for _, d := range history.Values {
+				deployer, started, completed := "-", "-", "-"
+				if d.State != nil {
+					if d.State.Deployer != nil {
+						deployer = d.State.Deployer.DisplayName
+					}
+					started = FormatTimePtr(d.State.StartedOn)
+					completed = FormatTimePtr(d.State.CompletedOn)
+				}
+				t.Row(orDash(d.ReleaseName()), shortHash(d.CommitHash()), orDash(d.Result()), deployer, started, completed)
+			}
+			t.Flush()
+		})
+		return nil
+	},
+}
+
+var environmentsStatusCmd = &cobra.Command{
+	Use:   "status [workspace] [repo-slug]",
+	Short: "Show the commit currently deployed to each environment",
+	Args:  cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		result, err := client.GetEnvironmentStatuses(bitbucket.ListEnvironmentsArgs{Workspace: workspace, RepoSlug: repoSlug})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result) == 0 {
+				fmt.Println("No environments found.")
+				return
+			}
+			t := NewTable()
+			t.Header("Environment", "Type", "Release", "Commit", "Deployed", "By")
+			for _, s := range result {
+				release, commit, deployed, by := "-", "-", "-", "-"
+				if d := s.Current; d != nil {
+					release = orDash(d.ReleaseName())
+					commit = shortHash(d.CommitHash())
+					if d.State != nil {
+						deployed = FormatTimePtr(d.State.CompletedOn)
+						if d.State.Deployer != nil {
+							by = d.State.Deployer.DisplayName
+						}
+					}
+				}
+				t.Row(s.Environment.Name, envTypeName(s.Environment), release, commit, deployed, by)
+			}
+			t.Flush()
+		})
+		return nil
+	},
+}
+
+var environmentsDiffCmd = &cobra.Command{
+	Use:   "diff [workspace] [repo-slug] <from-env> <to-env>",
+	Short: "List commits live in one environment but not yet in another",
+	Long: `List the commits deployed to <from-env> that have not reached <to-env> —
+what promoting <from-env> to <to-env> would ship.`,
+	Example: `  bbkt environments diff staging production`,
+	Args:    cobra.RangeArgs(2, 4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 2)
+		if err != nil {
+			return err
+		}
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		client := getClient()
+		diff, err := client.DiffEnvironments(bitbucket.DiffEnvironmentsArgs{
+			Workspace: workspace,
+			RepoSlug:  repoSlug,
+			From:      trailing[0],
+			To:        trailing[1],
+			Pagelen:   limit,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, diff, func() {
+			fmt.Printf("%s (%s) → %s (%s)\n\n", diff.From, shortHash(diff.FromCommit), diff.To, shortHash(diff.ToCommit))
+			if len(diff.Commits) == 0 {
+				fmt.Printf("%s is up to date with %s.\n", diff.To, diff.From)
+				return
+			}
+			t := NewTable()
+			t.Header("Hash", "Author", "Date", "Message")
+			for _, c := range diff.Commits {
+				author := "-"
+				if c.Author != nil {
+					author = c.Author.Raw
+					if c.Author.User != nil {
+						author = c.Author.User.DisplayName
+					}
+				}
+				t.Row(shortHash(c.Hash), Truncate(author, 25), FormatTime(c.Date), Truncate(c.Message, 60))
+			}
+			t.Flush()
+			if diff.More {
+				fmt.Println("\n… more commits not shown (raise --limit)")
+			}
+		})
+		return nil
+	},
+}
+
+var environmentsCreateCmd = &cobra.Command{
+	Use:   "create [workspace] [repo-slug] <name>",
+	Short: "Create a deployment environment",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		envType, _ := cmd.Flags().GetString("type")
+
+		client := getClient()
+		env, err := client.CreateEnvironment(bitbucket.CreateEnvironmentArgs{
+			Workspace: workspace,
+			RepoSlug:  repoSlug,
+			Name:      trailing[0],
+			Type:      envType,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, env, func() {
+			fmt.Printf("Environment '%s' created (%s).\n", env.Name, env.UUID)
+		})
+		return nil
+	},
+}
+
+var environmentsDeleteCmd = &cobra.Command{
+	Use:   "delete [workspace] [repo-slug] <environment>",
+	Short: "Delete a deployment environment (destructive — no confirmation)",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		if err := client.DeleteEnvironment(bitbucket.DeleteEnvironmentArgs{
+			Workspace:   workspace,
+			RepoSlug:    repoSlug,
+			Environment: trailing[0],
+		}); err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, map[string]any{
+			"environment": trailing[0],
+			"deleted":     true,
+		}, func() {
+			fmt.Printf("Environment '%s' deleted.\n", trailing[0])
+		})
+		return nil
+	},
+}
+
+func envTypeName(e bitbucket.Environment) string {
+	if e.EnvironmentType == nil {
+		return "-"
+	}
+	return e.EnvironmentType.Name
+}
+
+// shortHash abbreviates a commit hash for table output.
+func shortHash(h string) string {
+	if h == "" {
+		return "-"
+	}
+	if len(h) > 12 {
+		return h[:12]
+	}
+	return h
+}
+
+func init() {
+	RootCmd.AddCommand(deploymentsCmd)
+	deploymentsCmd.AddCommand(deploymentsListCmd)
+
+	deploymentsListCmd.Flags().String("env", "", "Only deployments to this environment (name, slug, or UUID)")
+	addPaginationFlags(deploymentsListCmd)
+
+	RootCmd.AddCommand(environmentsCmd)
+	environmentsCmd.AddCommand(environmentsListCmd)
+	environmentsCmd.AddCommand(environmentsGetCmd)
+	environmentsCmd.AddCommand(environmentsStatusCmd)
+	environmentsCmd.AddCommand(environmentsDiffCmd)
+	environmentsCmd.AddCommand(environmentsCreateCmd)
+	environmentsCmd.AddCommand(environmentsDeleteCmd)
+
+	environmentsGetCmd.Flags().Int("limit", 10, "Number of recent deployments to show")
+	environmentsDiffCmd.Flags().Int("limit", 50, "Maximum commits to list")
+	environmentsCreateCmd.Flags().String("type", "Test", "Environment type: Test | Staging | Production")
+}
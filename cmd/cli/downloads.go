@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+var downloadsCmd = &cobra.Command{
+	Use:     "downloads",
+	Aliases: []string{"dl"},
+	GroupID: groupData,
+	Short:   "Manage files in a repository's Downloads section",
+	Long: `List, upload, download, and delete files in a repository's Downloads
+section (often used for release binaries). Workspace/repo are inferred
+from your git clone when omitted.
+
+Uploads and downloads are streamed, so large files are never held in
+memory. Both print the file's SHA-256; an interrupted download leaves a
+.part file that the next run resumes from.`,
+	Example: `  bbkt downloads list
+  bbkt downloads upload dist/app-1.4.0.tar.gz
+  bbkt downloads download app-1.4.0.tar.gz -o /tmp/
+  bbkt downloads delete app-1.3.0.tar.gz`,
+}
+
+var downloadsListCmd = &cobra.Command{
+	Use:   "list [workspace] [repo-slug]",
+	Short: "List files in Downloads",
+	Args:  cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		page, pagelen := paginationArgs(cmd)
+		client := getClient()
+		result, err := client.ListDownloads(bitbucket.ListDownloadsArgs{
+			Workspace: workspace,
+			RepoSlug:  repoSlug,
+			Page:      page,
+			Pagelen:   pagelen,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result.Values) == 0 {
+				fmt.Println("No downloads found.")
+				return
+			}
+			t := NewTable()
+			t.Header("Name", "Size", "Downloads", "Uploaded", "By")
+			for _, d := range result.Values {
+				by := "-"
+				if d.User != nil {
+					by = d.User.DisplayName
+				}
+				t.Row(d.Name, FormatBytes(d.Size), fmt.Sprintf("%d", d.Downloads), FormatTime(d.CreatedOn), by)
+			}
+			t.Flush()
+			PrintPaginationFooter(result.Size, result.Page, len(result.Values), result.Next != "")
+		})
+		return nil
+	},
+}
+
+var downloadsUploadCmd = &cobra.Command{
+	Use:   "upload [workspace] [repo-slug] <file>",
+	Short: "Upload a file to Downloads (replaces a file with the same name)",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		name, _ := cmd.Flags().GetString("name")
+
+		f, err := os.Open(trailing[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		st, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			name = filepath.Base(trailing[0])
+		}
+
+		h := sha256.New()
+		var r io.Reader = io.TeeReader(f, h)
+		progress, done := newProgress("Uploading " + name)
+		if progress != nil {
+			r = &progressReader{r: r, total: st.Size(), fn: progress}
+		}
+
+		client := getClient()
+		err = client.UploadDownload(bitbucket.UploadDownloadArgs{
+			Workspace: workspace,
+			RepoSlug:  repoSlug,
+			Filename:  name,
+			Reader:    r,
+			Size:      st.Size(),
+		})
+		done()
+		if err != nil {
+			return err
+		}
+
+		sum := hex.EncodeToString(h.Sum(nil))
+		PrintOrJSON(cmd, map[string]any{
+			"name":   name,
+			"bytes":  st.Size(),
+			"sha256": sum,
+		}, func() {
+			fmt.Printf("Uploaded %s (%s)\n", name, FormatBytes(st.Size()))
+			KV("SHA-256", sum)
+		})
+		return nil
+	},
+}
+
+var downloadsDownloadCmd = &cobra.Command{
+	Use:   "download [workspace] [repo-slug] <name>",
+	Short: "Download a file from Downloads (resumes a partial download)",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		out, _ := cmd.Flags().GetString("output")
+		dest := downloadDest(out, trailing[0])
+
+		progress, done := newProgress("Downloading " + trailing[0])
+		client := getClient()
+		result, err := client.DownloadFile(bitbucket.DownloadFileArgs{
+			Workspace: workspace,
+			RepoSlug:  repoSlug,
+			Name:      trailing[0],
+			Dest:      dest,
+		}, progress)
+		done()
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			printDownloadResult(result)
+		})
+		return nil
+	},
+}
+
+var downloadsDeleteCmd = &cobra.Command{
+	Use:   "delete [workspace] [repo-slug] <name>",
+	Short: "Delete a file from Downloads",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		if err := client.DeleteDownload(bitbucket.DeleteDownloadArgs{
+			Workspace: workspace,
+			RepoSlug:  repoSlug,
+			Name:      trailing[0],
+		}); err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, map[string]any{
+			"name":    trailing[0],
+			"deleted": true,
+		}, func() {
+			fmt.Printf("Deleted %s from %s/%s downloads.\n", trailing[0], workspace, repoSlug)
+		})
+		return nil
+	},
+}
+
+// downloadDest resolves -o: empty means the current directory, and an
+// existing directory (or a path ending in a separator) gets name appended.
+func downloadDest(out, name string) string {
+	name = filepath.Base(name)
+	if out == "" {
+		return name
+	}
+	if st, err := os.Stat(out); (err == nil && st.IsDir()) || os.IsPathSeparator(out[len(out)-1]) {
+		return filepath.Join(out, name)
+	}
+	return out
+}
+
+func printDownloadResult(r *bitbucket.DownloadResult) {
+	verb := "Downloaded"
+	if r.Resumed {
+		verb = "Resumed and completed"
+	}
+	fmt.Printf("%s %s (%s)\n", verb, r.Path, FormatBytes(r.Bytes))
+	KV("SHA-256", r.SHA256)
+}
+
+// progressReader reports bytes read to a progress callback.
+type progressReader struct {
+	r     io.Reader
+	done  int64
+	total int64
+	fn    func(done, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.done += int64(n)
+	p.fn(p.done, p.total)
+	return n, err
+}
+
+func init() {
+	RootCmd.AddCommand(downloadsCmd)
+	downloadsCmd.AddCommand(downloadsListCmd)
+	downloadsCmd.AddCommand(downloadsUploadCmd)
+	downloadsCmd.AddCommand(downloadsDownloadCmd)
+	downloadsCmd.AddCommand(downloadsDeleteCmd)
+
+	addPaginationFlags(downloadsListCmd)
+
+	downloadsUploadCmd.Flags().String("name", "", "Name to store the file under (default: the file's base name)")
+
+	downloadsDownloadCmd.Flags().StringP("output", "o", "", "Destination file or directory (default: current directory)")
+}
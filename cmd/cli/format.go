@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 	"time"
@@ -144,6 +145,46 @@ func FormatDuration(secs int) string {
 	return fmt.Sprintf("%dm%ds", m, s)
 }
 
+// FormatBytes formats a byte count with a binary unit (KiB, MiB, ...).
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// parseSince parses a --since value: a relative age like 30d, 2w or 12h, or
+// an absolute date (2006-01-02 or RFC 3339).
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if n, err := strconv.Atoi(s[:len(s)-1]); err == nil && n >= 0 {
+		switch s[len(s)-1] {
+		case 'd':
+			return now.AddDate(0, 0, -n), nil
+		case 'w':
+			return now.AddDate(0, 0, -7*n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: use e.g. 30d, 2w, 12h, or 2006-01-02", s)
+}
+
 // Truncate truncates a string to maxLen and adds "..." if needed.
 func Truncate(s string, maxLen int) string {
 	// Replace newlines with spaces for single-line display
@@ -158,6 +199,38 @@ func Truncate(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
+// colorEnabled reports whether stdout is a terminal and NO_COLOR is unset,
+// so ANSI highlighting is safe to print.
+func colorEnabled() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	fi, err := os.Stdout.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// newProgress returns a transfer progress callback that redraws a single
+// status line on stderr, or nil when stderr is not a terminal (so logs and
+// pipes don't fill up with carriage returns). Call done() once finished.
+func newProgress(label string) (progress func(done, total int64), done func()) {
+	if fi, err := os.Stderr.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return nil, func() {}
+	}
+	var last time.Time
+	progress = func(n, total int64) {
+		if time.Since(last) < 100*time.Millisecond && n != total {
+			return
+		}
+		last = time.Now()
+		if total > 0 {
+			fmt.Fprintf(os.Stderr, "\r%s  %s / %s (%d%%)\033[K", label, FormatBytes(n), FormatBytes(total), n*100/total)
+		} else {
+			fmt.Fprintf(os.Stderr, "\r%s  %s\033[K", label, FormatBytes(n))
+		}
+	}
+	return progress, func() { fmt.Fprint(os.Stderr, "\r\033[K") }
+}
+
 // PrintPaginationFooter prints a summary line showing current page info.
 // When hasNext is true, includes a hint to fetch the next page with --page.
 func PrintPaginationFooter(size, page, pageLen int, hasNext bool) {
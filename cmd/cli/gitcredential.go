@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+var authGitCredentialCmd = &cobra.Command{
+	Use:    "git-credential <get|store|erase>",
+	Short:  "Git credential helper that answers with a bbkt profile's token",
+	Hidden: true,
+	Long: `Implements git's credential helper protocol for HTTPS remotes on the
+Bitbucket host, answering 'get' with the selected profile's token. 'bbkt
+repos clone' configures it on HTTPS clones; to use it elsewhere:
+
+  git config credential.https://bitbucket.org.helper '!bbkt --profile work auth git-credential'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		req, err := bitbucket.ReadGitCredentialRequest(os.Stdin)
+		if err != nil {
+			return err
+		}
+		// store and erase are no-ops: tokens live in the profile.
+		if args[0] != "get" || req["protocol"] != "https" || !bitbucket.IsBitbucketGitHost(req["host"]) {
+			return nil
+		}
+
+		creds, err := gitCredentials()
+		if err != nil {
+			return err
+		}
+		username, password, err := creds.GitCredentials()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("username=%s\npassword=%s\n", username, password)
+		return nil
+	},
+}
+
+// gitCredentials resolves the credentials git should use, the same way
+// getClient does: environment variables first, then the selected profile.
+// Expired OAuth tokens are refreshed.
+func gitCredentials() (*bitbucket.Credentials, error) {
+	if token := os.Getenv("BITBUCKET_ACCESS_TOKEN"); token != "" {
+		return &bitbucket.Credentials{AuthType: bitbucket.AuthTypeOAuth, AccessToken: token}, nil
+	}
+	if token := os.Getenv("BITBUCKET_API_TOKEN"); token != "" && os.Getenv("BITBUCKET_USERNAME") != "" {
+		return &bitbucket.Credentials{AuthType: bitbucket.AuthTypeAPIToken, APIToken: token}, nil
+	}
+
+	creds, err := bitbucket.LoadCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("not authenticated; run 'bbkt auth' first")
+	}
+	if creds.IsExpired() {
+		if err := bitbucket.RefreshOAuth(creds); err != nil {
+			return nil, fmt.Errorf("failed to refresh oauth token: %v", err)
+		}
+	}
+	return creds, nil
+}
+
+// gitProtocol picks the clone protocol: the --protocol flag, then
+// $BBKT_GIT_PROTOCOL, then the profile's preference, then HTTPS.
+func gitProtocol(cmd *cobra.Command) (string, error) {
+	protocol, _ := cmd.Flags().GetString("protocol")
+	if protocol == "" {
+		protocol = os.Getenv("BBKT_GIT_PROTOCOL")
+	}
+	if protocol == "" {
+		if creds, err := bitbucket.LoadCredentials(); err == nil {
+			protocol = creds.GitProtocol
+		}
+	}
+	if protocol == "" {
+		protocol = bitbucket.GitProtocolHTTPS
+	}
+	if protocol != bitbucket.GitProtocolSSH && protocol != bitbucket.GitProtocolHTTPS {
+		return "", fmt.Errorf("invalid git protocol %q: expected ssh or https", protocol)
+	}
+	return protocol, nil
+}
+
+// cloneGitOptions returns the -c options git needs over protocol: for HTTPS,
+// a credential helper bound to the profile in use. Credentials from
+// environment variables are read again from the environment when git asks.
+func cloneGitOptions(protocol string) ([]string, error) {
+	if protocol != bitbucket.GitProtocolHTTPS {
+		return nil, nil
+	}
+	profile := ""
+	if os.Getenv("BITBUCKET_ACCESS_TOKEN") == "" && os.Getenv("BITBUCKET_API_TOKEN") == "" {
+		creds, err := bitbucket.LoadCredentials()
+		if err != nil {
+			return nil, fmt.Errorf("not authenticated; run 'bbkt auth' first")
+		}
+		profile = creds.ProfileName
+	}
+	return credentialHelperArgs(profile)
+}
+
+// credentialHelperArgs returns git -c options that make git ask bbkt, as
+// the given profile, for HTTPS credentials instead of any other helper.
+func credentialHelperArgs(profile string) ([]string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	helper := "!" + shellQuote(exe)
+	if profile != "" {
+		helper += " --profile " + shellQuote(profile)
+	}
+	helper += " auth git-credential"
+	return []string{"-c", "credential.helper=", "-c", "credential.helper=" + helper}, nil
+}
+
+// shellQuote quotes s for the POSIX shell git runs helpers with.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func init() {
+	authCmd.AddCommand(authGitCredentialCmd)
+}
@@ -0,0 +1,289 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+var insightsCmd = &cobra.Command{
+	Use:     "insights",
+	GroupID: groupData,
+	Short:   "Manage Code Insights reports and annotations on commits",
+	Long: `List, inspect, delete, and upload Code Insights reports. Reports show up
+on pull requests whose source branch points at the commit, and their
+annotations are shown inline on the diff.
+
+upload converts SARIF, JUnit XML, or Cobertura output into a report.
+Annotations are sent in batches of 100; Bitbucket keeps at most 1000 per
+report, so extra findings are dropped with a warning. <commit> may be a
+hash, HEAD, or a branch or tag; names resolve in the local clone when it is
+a clone of the target repository, and through the API otherwise.`,
+	Example: `  bbkt insights upload --sarif results.sarif --commit HEAD
+  bbkt insights upload --junit report.xml
+  bbkt insights upload --cobertura coverage.xml --min-coverage 80
+  bbkt insights list HEAD
+  bbkt insights get HEAD sarif-gosec`,
+}
+
+var insightsListCmd = &cobra.Command{
+	Use:   "list [workspace] [repo-slug] <commit>",
+	Short: "List the reports on a commit",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		result, err := client.ListInsightReports(bitbucket.InsightReportArgs{
+			Workspace: workspace,
+			RepoSlug:  repoSlug,
+			Commit:    resolveCommit(client, workspace, repoSlug, trailing[0]),
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result.Values) == 0 {
+				fmt.Println("No reports found.")
+				return
+			}
+			t := NewTable()
+			t.Header("ID", "Title", "Type", "Result", "Reporter", "Updated")
+			for _, r := range result.Values {
+				t.Row(reportID(r), r.Title, orDash(r.ReportType), orDash(r.Result), orDash(r.Reporter), FormatTimePtr(r.UpdatedOn))
+			}
+			t.Flush()
+		})
+		return nil
+	},
+}
+
+var insightsGetCmd = &cobra.Command{
+	Use:   "get [workspace] [repo-slug] <commit> <report-id>",
+	Short: "Show a report and its annotations",
+	Args:  cobra.RangeArgs(2, 4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 2)
+		if err != nil {
+			return err
+		}
+		client := getClient()
+		ra := bitbucket.InsightReportArgs{
+			Workspace: workspace,
+			RepoSlug:  repoSlug,
+			Commit:    resolveCommit(client, workspace, repoSlug, trailing[0]),
+			ReportID:  trailing[1],
+		}
+
+		report, err := client.GetInsightReport(ra)
+		if err != nil {
+			return err
+		}
+		anns, err := client.ListInsightAnnotations(ra)
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, bitbucket.ReportWithFindings{InsightReport: *report, Annotations: anns}, func() {
+			KV("Title", report.Title)
+			KV("ID", reportID(*report))
+			KV("Type", orDash(report.ReportType))
+			KV("Result", orDash(report.Result))
+			KV("Reporter", orDash(report.Reporter))
+			if report.Link != "" {
+				KV("Link", report.Link)
+			}
+			for _, d := range report.Data {
+				KVf(d.Title, "%v", d.Value)
+			}
+			if report.Details != "" {
+				fmt.Printf("\n%s\n", report.Details)
+			}
+			if len(anns) == 0 {
+				fmt.Println("\nNo annotations.")
+				return
+			}
+			fmt.Println()
+			t := NewTable()
+			t.Header("Severity", "Type", "Location", "Summary")
+			for _, a := range anns {
+				t.Row(orDash(a.Severity), a.AnnotationType, annotationLocation(a), Truncate(a.Summary, 80))
+			}
+			t.Flush()
+		})
+		return nil
+	},
+}
+
+var insightsDeleteCmd = &cobra.Command{
+	Use:   "delete [workspace] [repo-slug] <commit> <report-id>",
+	Short: "Delete a report and its annotations",
+	Args:  cobra.RangeArgs(2, 4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 2)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		if err := client.DeleteInsightReport(bitbucket.InsightReportArgs{
+			Workspace: workspace,
+			RepoSlug:  repoSlug,
+			Commit:    resolveCommit(client, workspace, repoSlug, trailing[0]),
+			ReportID:  trailing[1],
+		}); err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, map[string]any{
+			"report_id": trailing[1],
+			"deleted":   true,
+		}, func() {
+			fmt.Printf("Deleted report %s.\n", trailing[1])
+		})
+		return nil
+	},
+}
+
+var insightsUploadCmd = &cobra.Command{
+	Use:   "upload [workspace] [repo-slug] --sarif|--junit|--cobertura <file>",
+	Short: "Upload SARIF, JUnit XML, or Cobertura results as a report",
+	Long: `Convert a tool's output into a Code Insights report and upload it with its
+annotations. Re-uploading to the same report ID replaces the report.
+
+  --sarif      one annotation per result; security rules become vulnerabilities
+  --junit      one annotation per failing test
+  --cobertura  line coverage, plus one annotation per uncovered line range
+
+Absolute paths in the input are made relative to the git checkout root.`,
+	Example: `  bbkt insights upload --sarif results.sarif --commit HEAD
+  bbkt insights upload --junit build/test-results.xml --title "Unit tests"
+  bbkt insights upload --cobertura coverage.xml --min-coverage 80 --report-id coverage-go`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		sarif, _ := cmd.Flags().GetString("sarif")
+		junit, _ := cmd.Flags().GetString("junit")
+		cobertura, _ := cmd.Flags().GetString("cobertura")
+		commit, _ := cmd.Flags().GetString("commit")
+		reportIDFlag, _ := cmd.Flags().GetString("report-id")
+		title, _ := cmd.Flags().GetString("title")
+		link, _ := cmd.Flags().GetString("link")
+		minCoverage, _ := cmd.Flags().GetFloat64("min-coverage")
+
+		opts := bitbucket.InsightsConvertOptions{RootDir: checkoutRoot(), MinCoverage: minCoverage}
+		var file string
+		var convert func([]byte, bitbucket.InsightsConvertOptions) (*bitbucket.InsightsUpload, error)
+		switch {
+		case sarif != "":
+			file, convert = sarif, bitbucket.InsightsFromSARIF
+		case junit != "":
+			file, convert = junit, bitbucket.InsightsFromJUnit
+		case cobertura != "":
+			file, convert = cobertura, bitbucket.InsightsFromCobertura
+		default:
+			return fmt.Errorf("one of --sarif, --junit, or --cobertura is required")
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		upload, err := convert(data, opts)
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		if reportIDFlag != "" {
+			upload.ReportID = reportIDFlag
+		}
+		if title != "" {
+			upload.Report.Title = title
+		}
+		if link != "" {
+			upload.Report.Link = link
+		}
+		if upload.Dropped > 0 {
+			fmt.Fprintf(os.Stderr, "warning: %d finding(s) dropped; a report holds at most %d annotations\n",
+				upload.Dropped, bitbucket.MaxAnnotationsPerReport)
+		}
+
+		client := getClient()
+		hash := resolveCommit(client, workspace, repoSlug, commit)
+		report, sent, err := client.PublishInsights(bitbucket.InsightReportArgs{
+			Workspace: workspace,
+			RepoSlug:  repoSlug,
+			Commit:    hash,
+		}, upload)
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, map[string]any{
+			"commit":      hash,
+			"report_id":   upload.ReportID,
+			"report":      report,
+			"annotations": sent,
+			"dropped":     upload.Dropped,
+		}, func() {
+			fmt.Printf("Uploaded report %s (%s) to %s with %d annotation(s).\n",
+				upload.ReportID, orDash(report.Result), shortHash(hash), sent)
+		})
+		return nil
+	},
+}
+
+func reportID(r bitbucket.InsightReport) string {
+	if r.ExternalID != "" {
+		return r.ExternalID
+	}
+	return r.UUID
+}
+
+func annotationLocation(a bitbucket.InsightAnnotation) string {
+	switch {
+	case a.Path == "":
+		return "-"
+	case a.Line > 0:
+		return fmt.Sprintf("%s:%d", a.Path, a.Line)
+	default:
+		return a.Path
+	}
+}
+
+// checkoutRoot is the top of the current git checkout, or "" outside one.
+func checkoutRoot() string {
+	out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func init() {
+	RootCmd.AddCommand(insightsCmd)
+	insightsCmd.AddCommand(insightsListCmd)
+	insightsCmd.AddCommand(insightsGetCmd)
+	insightsCmd.AddCommand(insightsDeleteCmd)
+	insightsCmd.AddCommand(insightsUploadCmd)
+
+	insightsUploadCmd.Flags().String("sarif", "", "SARIF 2.1 results file")
+	insightsUploadCmd.Flags().String("junit", "", "JUnit XML test results file")
+	insightsUploadCmd.Flags().String("cobertura", "", "Cobertura XML coverage file")
+	insightsUploadCmd.MarkFlagsMutuallyExclusive("sarif", "junit", "cobertura")
+	insightsUploadCmd.Flags().String("commit", "HEAD", "Commit hash, HEAD, or branch to attach the report to")
+	insightsUploadCmd.Flags().String("report-id", "", "Report ID (default derived from the input type)")
+	insightsUploadCmd.Flags().String("title", "", "Report title (default derived from the input)")
+	insightsUploadCmd.Flags().String("link", "", "Link to the full results")
+	insightsUploadCmd.Flags().Float64("min-coverage", 0, "Fail a Cobertura report below this line coverage percentage")
+}
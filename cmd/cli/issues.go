@@ -12,16 +12,24 @@ var issuesCmd = &cobra.Command{
 	Use:     "issues",
 	Aliases: []string{"issue"},
 	GroupID: groupData,
-	Short:   "List, get, create, and update repository issues",
-	Long: `Manage issues in a Bitbucket repository's issue tracker.
-Workspace/repo are inferred from your git clone when omitted.
+	Short:   "List, create, and update issues; comment, attach files, vote, and watch",
+	Long: `Manage issues in a Bitbucket repository's issue tracker, including their
+comments, attachments, change history, votes, and watches, and export or
+import whole trackers. Workspace/repo are inferred from your git clone when omitted.
 
 Alias: issue`,
 	Example: `  bbkt issues list                         # open issues on current repo
   bbkt issues list --kind bug --priority major
+  bbkt issues list --milestone v2.0 --component api
   bbkt issues get 17
   bbkt issues create -t "Crash on login" --kind bug --priority critical
-  bbkt issues update 17 --state resolved`,
+  bbkt issues update 17 --state resolved
+  bbkt issues comment 17 -m "Reproduced on 1.4"
+  bbkt issues attach 17 crash.log
+  bbkt issues history 17
+  bbkt issues vote 17
+  bbkt issues export --format csv -o issues.csv
+  bbkt issues import issues.json --dry-run`,
 }
 
 var issuesListCmd = &cobra.Command{
@@ -37,6 +45,9 @@ var issuesListCmd = &cobra.Command{
 		state, _ := cmd.Flags().GetString("state")
 		kind, _ := cmd.Flags().GetString("kind")
 		priority, _ := cmd.Flags().GetString("priority")
+		milestone, _ := cmd.Flags().GetString("milestone")
+		component, _ := cmd.Flags().GetString("component")
+		version, _ := cmd.Flags().GetString("version")
 		search, _ := cmd.Flags().GetString("search")
 		sort, _ := cmd.Flags().GetString("sort")
 		page, pagelen := paginationArgs(cmd)
@@ -48,6 +59,9 @@ var issuesListCmd = &cobra.Command{
 			State:     state,
 			Kind:      kind,
 			Priority:  priority,
+			Milestone: milestone,
+			Component: component,
+			Version:   version,
 			Search:    search,
 			Sort:      sort,
 			Page:      page,
@@ -123,6 +137,7 @@ var issuesGetCmd = &cobra.Command{
 			if result.Reporter != nil {
 				KV("Reporter", result.Reporter.DisplayName)
 			}
+			printIssueOptions(result)
 			if result.Content.Raw != "" {
 				KV("Description", Truncate(result.Content.Raw, 80))
 			}
@@ -150,6 +165,9 @@ var issuesCreateCmd = &cobra.Command{
 		kind, _ := cmd.Flags().GetString("kind")
 		priority, _ := cmd.Flags().GetString("priority")
 		assignee, _ := cmd.Flags().GetString("assignee")
+		milestone, _ := cmd.Flags().GetString("milestone")
+		component, _ := cmd.Flags().GetString("component")
+		version, _ := cmd.Flags().GetString("version")
 
 		client := getClient()
 		result, err := client.CreateIssue(bitbucket.CreateIssueArgs{
@@ -160,6 +178,9 @@ var issuesCreateCmd = &cobra.Command{
 			Kind:      kind,
 			Priority:  priority,
 			Assignee:  assignee,
+			Milestone: milestone,
+			Component: component,
+			Version:   version,
 		})
 		if err != nil {
 			return err
@@ -170,6 +191,7 @@ var issuesCreateCmd = &cobra.Command{
 			KV("Kind", result.Kind)
 			KV("Priority", result.Priority)
 			KV("State", result.State)
+			printIssueOptions(result)
 			KV("Created", FormatTime(result.CreatedOn))
 		})
 		return nil
@@ -222,6 +244,18 @@ var issuesUpdateCmd = &cobra.Command{
 			v, _ := cmd.Flags().GetString("assignee")
 			updateArgs.Assignee = &v
 		}
+		if cmd.Flags().Changed("milestone") {
+			v, _ := cmd.Flags().GetString("milestone")
+			updateArgs.Milestone = &v
+		}
+		if cmd.Flags().Changed("component") {
+			v, _ := cmd.Flags().GetString("component")
+			updateArgs.Component = &v
+		}
+		if cmd.Flags().Changed("version") {
+			v, _ := cmd.Flags().GetString("version")
+			updateArgs.Version = &v
+		}
 
 		client := getClient()
 		result, err := client.UpdateIssue(updateArgs)
@@ -237,12 +271,26 @@ var issuesUpdateCmd = &cobra.Command{
 			if result.Assignee != nil {
 				KV("Assignee", result.Assignee.DisplayName)
 			}
+			printIssueOptions(result)
 			KV("Updated", FormatTime(result.UpdatedOn))
 		})
 		return nil
 	},
 }
 
+// printIssueOptions prints the issue's milestone, component, and version,
+// skipping those not set.
+func printIssueOptions(issue *bitbucket.Issue) {
+	for _, o := range []struct {
+		label  string
+		option *bitbucket.IssueOption
+	}{{"Milestone", issue.Milestone}, {"Component", issue.Component}, {"Version", issue.Version}} {
+		if o.option != nil {
+			KV(o.label, o.option.Name)
+		}
+	}
+}
+
 func init() {
 	RootCmd.AddCommand(issuesCmd)
 	issuesCmd.AddCommand(issuesListCmd)
@@ -253,6 +301,9 @@ func init() {
 	issuesListCmd.Flags().String("state", "", "Filter by state: new | open | resolved | on hold | invalid | duplicate | wontfix | closed")
 	issuesListCmd.Flags().String("kind", "", "Filter by kind: bug | enhancement | proposal | task")
 	issuesListCmd.Flags().String("priority", "", "Filter by priority: trivial | minor | major | critical | blocker")
+	issuesListCmd.Flags().String("milestone", "", "Filter by milestone name")
+	issuesListCmd.Flags().String("component", "", "Filter by component name")
+	issuesListCmd.Flags().String("version", "", "Filter by version name")
 	issuesListCmd.Flags().StringP("search", "q", "", "Search query string")
 	issuesListCmd.Flags().String("sort", "", "Sort field (prefix with - for desc, e.g. -updated_on)")
 	addPaginationFlags(issuesListCmd)
@@ -262,6 +313,9 @@ func init() {
 	issuesCreateCmd.Flags().String("kind", "bug", "Kind: bug | enhancement | proposal | task")
 	issuesCreateCmd.Flags().String("priority", "major", "Priority: trivial | minor | major | critical | blocker")
 	issuesCreateCmd.Flags().String("assignee", "", "Assignee account ID")
+	issuesCreateCmd.Flags().String("milestone", "", "Milestone name (see 'bbkt issues milestones')")
+	issuesCreateCmd.Flags().String("component", "", "Component name (see 'bbkt issues components')")
+	issuesCreateCmd.Flags().String("version", "", "Version name (see 'bbkt issues versions')")
 	_ = issuesCreateCmd.MarkFlagRequired("title")
 
 	issuesUpdateCmd.Flags().StringP("title", "t", "", "New title for the issue")
@@ -270,4 +324,7 @@ func init() {
 	issuesUpdateCmd.Flags().String("kind", "", "New kind")
 	issuesUpdateCmd.Flags().String("priority", "", "New priority")
 	issuesUpdateCmd.Flags().String("assignee", "", "New assignee account ID (or 'unassign')")
+	issuesUpdateCmd.Flags().String("milestone", "", "New milestone name (empty to clear)")
+	issuesUpdateCmd.Flags().String("component", "", "New component name (empty to clear)")
+	issuesUpdateCmd.Flags().String("version", "", "New version name (empty to clear)")
 }
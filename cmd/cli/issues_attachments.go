@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var issuesAttachCmd = &cobra.Command{
+	Use:   "attach [workspace] [repo-slug] <issue-id> <file>",
+	Short: "Attach a file to an issue (streamed; replaces an attachment with the same name)",
+	Args:  cobra.RangeArgs(2, 4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		issue, rest, err := issueScope(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		name, _ := cmd.Flags().GetString("name")
+
+		f, err := os.Open(rest[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		st, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			name = filepath.Base(rest[0])
+		}
+
+		h := sha256.New()
+		var r io.Reader = io.TeeReader(f, h)
+		progress, done := newProgress("Uploading " + name)
+		if progress != nil {
+			r = &progressReader{r: r, total: st.Size(), fn: progress}
+		}
+
+		client := getClient()
+		err = client.UploadIssueAttachment(issue, name, r, st.Size())
+		done()
+		if err != nil {
+			return err
+		}
+
+		sum := hex.EncodeToString(h.Sum(nil))
+		PrintOrJSON(cmd, map[string]any{
+			"issue_id": issue.IssueID,
+			"name":     name,
+			"bytes":    st.Size(),
+			"sha256":   sum,
+		}, func() {
+			fmt.Printf("Attached %s (%s) to issue #%d\n", name, FormatBytes(st.Size()), issue.IssueID)
+			KV("SHA-256", sum)
+		})
+		return nil
+	},
+}
+
+var issuesAttachmentsCmd = &cobra.Command{
+	Use:   "attachments",
+	Short: "List, download, and delete an issue's attachments",
+	Long: `List, download, and delete files attached to an issue. Use
+'bbkt issues attach' to add one. Downloads are streamed and resume from a
+.part file left by an interrupted run.`,
+}
+
+var issuesAttachmentsListCmd = &cobra.Command{
+	Use:   "list [workspace] [repo-slug] <issue-id>",
+	Short: "List an issue's attachments",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		issue, _, err := issueScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		page, pagelen := paginationArgs(cmd)
+
+		client := getClient()
+		result, err := client.ListIssueAttachments(issue, page, pagelen)
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result.Values) == 0 {
+				fmt.Printf("Issue #%d has no attachments.\n", issue.IssueID)
+				return
+			}
+			for _, a := range result.Values {
+				fmt.Println(a.Name)
+			}
+			PrintPaginationFooter(result.Size, result.Page, len(result.Values), result.Next != "")
+		})
+		return nil
+	},
+}
+
+var issuesAttachmentsDownloadCmd = &cobra.Command{
+	Use:   "download [workspace] [repo-slug] <issue-id> <name>",
+	Short: "Download an attachment (resumes a partial download)",
+	Args:  cobra.RangeArgs(2, 4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		issue, rest, err := issueScope(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		out, _ := cmd.Flags().GetString("output")
+		dest := downloadDest(out, rest[0])
+
+		progress, done := newProgress("Downloading " + rest[0])
+		client := getClient()
+		result, err := client.DownloadIssueAttachment(issue, rest[0], dest, progress)
+		done()
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			printDownloadResult(result)
+		})
+		return nil
+	},
+}
+
+var issuesAttachmentsDeleteCmd = &cobra.Command{
+	Use:   "delete [workspace] [repo-slug] <issue-id> <name>",
+	Short: "Delete an attachment from an issue",
+	Args:  cobra.RangeArgs(2, 4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		issue, rest, err := issueScope(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		if err := client.DeleteIssueAttachment(issue, rest[0]); err != nil {
+			return err
+		}
+		PrintOrJSON(cmd, map[string]any{"issue_id": issue.IssueID, "name": rest[0], "deleted": true}, func() {
+			fmt.Printf("Deleted %s from issue #%d\n", rest[0], issue.IssueID)
+		})
+		return nil
+	},
+}
+
+func init() {
+	issuesCmd.AddCommand(issuesAttachCmd)
+	issuesCmd.AddCommand(issuesAttachmentsCmd)
+	issuesAttachmentsCmd.AddCommand(issuesAttachmentsListCmd)
+	issuesAttachmentsCmd.AddCommand(issuesAttachmentsDownloadCmd)
+	issuesAttachmentsCmd.AddCommand(issuesAttachmentsDeleteCmd)
+
+	issuesAttachCmd.Flags().String("name", "", "Name to store the attachment under (default: the file's base name)")
+	addPaginationFlags(issuesAttachmentsListCmd)
+	issuesAttachmentsDownloadCmd.Flags().StringP("output", "o", "", "Destination file or directory (default: current directory)")
+}
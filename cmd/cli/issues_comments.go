@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+// issueScope parses "[workspace] [repo-slug] <issue-id>" followed by n more
+// arguments, which are returned as rest.
+func issueScope(cmd *cobra.Command, args []string, n int) (issue bitbucket.IssueArgs, rest []string, err error) {
+	workspace, repoSlug, trailing, err := ParseArgs(cmd, args, n+1)
+	if err != nil {
+		return issue, nil, err
+	}
+	id, err := strconv.Atoi(trailing[0])
+	if err != nil {
+		return issue, nil, fmt.Errorf("invalid issue ID %q (must be a number)", trailing[0])
+	}
+	return bitbucket.IssueArgs{Workspace: workspace, RepoSlug: repoSlug, IssueID: id}, trailing[1:], nil
+}
+
+var issuesCommentCmd = &cobra.Command{
+	Use:   "comment [workspace] [repo-slug] <issue-id> -m <text>",
+	Short: "Comment on an issue",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		issue, _, err := issueScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		content, _ := cmd.Flags().GetString("content")
+
+		client := getClient()
+		c, err := client.AddIssueComment(issue, content)
+		if err != nil {
+			return err
+		}
+		PrintOrJSON(cmd, c, func() {
+			fmt.Printf("Comment #%d added to issue #%d\n", c.ID, issue.IssueID)
+		})
+		return nil
+	},
+}
+
+var issuesCommentsCmd = &cobra.Command{
+	Use:   "comments [workspace] [repo-slug] <issue-id>",
+	Short: "List comments on an issue",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		issue, _, err := issueScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		page, pagelen := paginationArgs(cmd)
+
+		client := getClient()
+		result, err := client.ListIssueComments(issue, page, pagelen)
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			shown := 0
+			for _, c := range result.Values {
+				// Comments that only record a change have no text; 'history' shows those.
+				if c.Content.Raw == "" {
+					continue
+				}
+				author := "unknown"
+				if c.User != nil {
+					author = c.User.DisplayName
+				}
+				fmt.Printf("#%d %s (%s)\n%s\n\n", c.ID, author, FormatTime(c.CreatedOn), c.Content.Raw)
+				shown++
+			}
+			if shown == 0 {
+				fmt.Println("No comments found.")
+			}
+			PrintPaginationFooter(result.Size, result.Page, len(result.Values), result.Next != "")
+		})
+		return nil
+	},
+}
+
+var issuesHistoryCmd = &cobra.Command{
+	Use:   "history [workspace] [repo-slug] <issue-id>",
+	Short: "Show an issue's change history",
+	Long: `List the changes made to an issue, oldest first: each change's author,
+time, the fields it changed from and to, and any comment made with it.`,
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		issue, _, err := issueScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		page, pagelen := paginationArgs(cmd)
+
+		client := getClient()
+		result, err := client.ListIssueChanges(issue, page, pagelen)
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result.Values) == 0 {
+				fmt.Printf("Issue #%d has not been changed.\n", issue.IssueID)
+				return
+			}
+			for _, ch := range result.Values {
+				author := "unknown"
+				if ch.User != nil {
+					author = ch.User.DisplayName
+				}
+				fmt.Printf("%s  %s\n", FormatTime(ch.CreatedOn), author)
+				for _, field := range ch.Fields() {
+					fc := ch.Changes[field]
+					fmt.Printf("  %s: %s → %s\n", field, orDash(Truncate(firstLine(fc.Old), 40)), orDash(Truncate(firstLine(fc.New), 40)))
+				}
+				if msg := strings.TrimSpace(ch.Message.Raw); msg != "" {
+					fmt.Printf("  %s\n", Truncate(firstLine(msg), 80))
+				}
+			}
+			PrintPaginationFooter(result.Size, result.Page, len(result.Values), result.Next != "")
+		})
+		return nil
+	},
+}
+
+// issueFlagCmd builds the vote/unvote/watch/unwatch commands.
+func issueFlagCmd(use, short, done string, set func(*bitbucket.Client, bitbucket.IssueArgs) error) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " [workspace] [repo-slug] <issue-id>",
+		Short: short,
+		Args:  cobra.RangeArgs(1, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			issue, _, err := issueScope(cmd, args, 0)
+			if err != nil {
+				return err
+			}
+			if err := set(getClient(), issue); err != nil {
+				return err
+			}
+			PrintOrJSON(cmd, map[string]any{"issue_id": issue.IssueID, "action": use, "ok": true}, func() {
+				fmt.Printf(done+"\n", issue.IssueID)
+			})
+			return nil
+		},
+	}
+}
+
+var (
+	issuesVoteCmd = issueFlagCmd("vote", "Vote for an issue", "Voted for issue #%d",
+		func(c *bitbucket.Client, a bitbucket.IssueArgs) error { return c.VoteIssue(a, true) })
+	issuesUnvoteCmd = issueFlagCmd("unvote", "Retract your vote for an issue", "Retracted your vote for issue #%d",
+		func(c *bitbucket.Client, a bitbucket.IssueArgs) error { return c.VoteIssue(a, false) })
+	issuesWatchCmd = issueFlagCmd("watch", "Watch an issue for changes", "Watching issue #%d",
+		func(c *bitbucket.Client, a bitbucket.IssueArgs) error { return c.WatchIssue(a, true) })
+	issuesUnwatchCmd = issueFlagCmd("unwatch", "Stop watching an issue", "No longer watching issue #%d",
+		func(c *bitbucket.Client, a bitbucket.IssueArgs) error { return c.WatchIssue(a, false) })
+)
+
+func init() {
+	issuesCmd.AddCommand(issuesCommentCmd)
+	issuesCmd.AddCommand(issuesCommentsCmd)
+	issuesCmd.AddCommand(issuesHistoryCmd)
+	issuesCmd.AddCommand(issuesVoteCmd)
+	issuesCmd.AddCommand(issuesUnvoteCmd)
+	issuesCmd.AddCommand(issuesWatchCmd)
+	issuesCmd.AddCommand(issuesUnwatchCmd)
+
+	issuesCommentCmd.Flags().StringP("content", "m", "", "Comment body (markdown supported)")
+	_ = issuesCommentCmd.MarkFlagRequired("content")
+
+	addPaginationFlags(issuesCommentsCmd)
+	addPaginationFlags(issuesHistoryCmd)
+}
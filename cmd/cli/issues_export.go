@@ -0,0 +1,391 @@
+package cli
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+var issuesExportCmd = &cobra.Command{
+	Use:   "export [workspace] [repo-slug]",
+	Short: "Export every issue with its comments and attachments",
+	Long: `Export the issue tracker, walking every page of issues, comments, and
+attachments.
+
+  json  an array of issues, each with its comments and attachment links
+  csv   one row per issue; comments are joined into one column and
+        attachments listed by name
+  zip   Bitbucket's own export archive, attachments included, which
+        'bbkt issues import' (or the repository's settings) can restore
+
+json and csv are written to stdout unless -o is given; zip defaults to
+<repo>-issues.zip.`,
+	Example: `  bbkt issues export > issues.json
+  bbkt issues export --format csv -o issues.csv --state open
+  bbkt issues export acme api --format zip`,
+	Args: cobra.RangeArgs(0, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		flags := cmd.Flags()
+		format, _ := flags.GetString("format")
+		out, _ := flags.GetString("output")
+		state, _ := flags.GetString("state")
+		parallel, _ := flags.GetInt("parallel")
+		if parallel < 1 {
+			return fmt.Errorf("--parallel must be at least 1")
+		}
+
+		client := getClient()
+		if format == "zip" {
+			if flags.Changed("state") {
+				return fmt.Errorf("--state is not supported with --format zip")
+			}
+			if out == "" || out == "-" {
+				out = repoSlug + "-issues.zip"
+			}
+			status, statusDone := newJobStatus("Exporting issues")
+			progress, done := newProgress("Downloading " + filepath.Base(out))
+			result, err := client.ExportIssuesArchive(bitbucket.IssueArchiveArgs{Workspace: workspace, RepoSlug: repoSlug}, out, status, progress)
+			statusDone()
+			done()
+			if err != nil {
+				return err
+			}
+			PrintOrJSON(cmd, result, func() {
+				printDownloadResult(result)
+			})
+			return nil
+		}
+		if format != "json" && format != "csv" {
+			return fmt.Errorf("unknown format %q: expected json, csv, or zip", format)
+		}
+
+		issues, err := client.ExportIssues(bitbucket.ExportIssuesArgs{Workspace: workspace, RepoSlug: repoSlug, State: state, Parallel: parallel})
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = os.Stdout
+		if out != "" && out != "-" {
+			f, err := os.Create(out) //nolint:gosec // path chosen by the user
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		if format == "csv" {
+			err = bitbucket.WriteIssuesCSV(w, issues)
+		} else {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(issues)
+		}
+		if err != nil {
+			return err
+		}
+
+		var comments, attachments int
+		for _, is := range issues {
+			comments += len(is.Comments)
+			attachments += len(is.Attachments)
+		}
+		dest := "stdout"
+		if w != os.Stdout {
+			dest = out
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d issues (%d comments, %d attachments) from %s/%s to %s\n",
+			len(issues), comments, attachments, workspace, repoSlug, dest)
+		return nil
+	},
+}
+
+var issuesImportCmd = &cobra.Command{
+	Use:   "import [workspace] [repo-slug] <file>",
+	Short: "Create issues from JSON or CSV, or replace them from a Bitbucket zip export (--replace is destructive — no confirmation)",
+	Long: `Import issues from a file written by 'bbkt issues export' or from any CSV
+with a header row. json and csv are taken from the file's extension unless
+--format is given; "-" reads json or csv from stdin.
+
+json and csv issues are created one by one, up to --parallel at a time, with
+their comments (headed with the original author and time) and state.
+Issues already in the repository are skipped, matched by --dedupe:
+
+  title        same title, ignoring case and spacing (default)
+  external-id  same ID in the source tracker; bbkt records it at the end of
+               each issue it imports, so re-running an import is safe
+  none         create everything
+
+A CSV's columns are matched to fields by name; --map field=column picks
+another column. Fields: title, content, kind, priority, state, assignee
+(account ID), milestone, component, version, comments, and external_id
+(default the id column). Attachments are not copied; use zip for that.
+
+--format zip uploads a Bitbucket export archive, which REPLACES every issue
+in the repository, attachments included. It is never chosen from the file
+extension, and a repository that already has issues is refused unless
+--replace is given. --dedupe and --map do not apply.
+
+--dry-run reports what would happen and checks milestones, components, and
+versions exist, without changing anything.`,
+	Example: `  bbkt issues import issues.json --dry-run
+  bbkt issues import templates.csv --dedupe none
+  bbkt issues import jira.csv --map title=Summary --map content=Description --map external_id="Issue key" --dedupe external-id
+  bbkt issues import acme api api-issues.zip --format zip --dry-run
+  bbkt issues import acme api api-issues.zip --format zip --replace`,
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, rest, err := ParseArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		file := rest[0]
+		flags := cmd.Flags()
+		format, _ := flags.GetString("format")
+		mappings, _ := flags.GetStringArray("map")
+		dedupe, _ := flags.GetString("dedupe")
+		parallel, _ := flags.GetInt("parallel")
+		dryRun, _ := flags.GetBool("dry-run")
+		replace, _ := flags.GetBool("replace")
+		if parallel < 1 {
+			return fmt.Errorf("--parallel must be at least 1")
+		}
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+			if format == "zip" {
+				return fmt.Errorf("importing a zip archive replaces every issue in %s/%s: pass --format zip to confirm", workspace, repoSlug)
+			}
+			if format != "json" && format != "csv" {
+				return fmt.Errorf("cannot tell the format of %s: use --format json|csv|zip", file)
+			}
+		}
+		if replace && format != "zip" {
+			return fmt.Errorf("--replace only applies to --format zip")
+		}
+
+		if format == "zip" {
+			if len(mappings) > 0 || flags.Changed("dedupe") {
+				return fmt.Errorf("--map and --dedupe do not apply to --format zip")
+			}
+			return runImportArchive(cmd, workspace, repoSlug, file, dryRun, replace)
+		}
+		if format != "json" && format != "csv" {
+			return fmt.Errorf("unknown format %q: expected json, csv, or zip", format)
+		}
+		if len(mappings) > 0 && format != "csv" {
+			return fmt.Errorf("--map only applies to csv")
+		}
+		mapping := map[string]string{}
+		for _, m := range mappings {
+			field, column, ok := strings.Cut(m, "=")
+			if !ok || field == "" || column == "" {
+				return fmt.Errorf("invalid --map %q: expected field=column", m)
+			}
+			mapping[field] = column
+		}
+
+		var r io.Reader = os.Stdin
+		if file != "-" {
+			f, err := os.Open(file) //nolint:gosec // path chosen by the user
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		var issues []bitbucket.ExportedIssue
+		if format == "csv" {
+			issues, err = bitbucket.ReadIssuesCSV(r, mapping)
+		} else {
+			issues, err = bitbucket.ReadIssuesJSON(r)
+		}
+		if err != nil {
+			return err
+		}
+		if len(issues) == 0 {
+			return fmt.Errorf("no issues in %s", file)
+		}
+
+		client := getClient()
+		results, err := client.ImportIssues(bitbucket.ImportIssuesArgs{
+			Workspace: workspace,
+			RepoSlug:  repoSlug,
+			Issues:    issues,
+			Dedupe:    dedupe,
+			Parallel:  parallel,
+			DryRun:    dryRun,
+		})
+		if err != nil {
+			return err
+		}
+
+		counts := map[string]int{}
+		for _, res := range results {
+			counts[res.Status]++
+		}
+		PrintOrJSON(cmd, results, func() {
+			t := NewTable()
+			t.Header("Source", "Title", "Status", "Issue", "Reason")
+			for _, res := range results {
+				id := "-"
+				if res.IssueID != 0 {
+					id = fmt.Sprintf("#%d", res.IssueID)
+				}
+				t.Row(orDash(res.ExternalID), Truncate(res.Title, 50), res.Status, id, res.Message)
+			}
+			t.Flush()
+			fmt.Println()
+			if dryRun {
+				fmt.Printf("Dry run: %d issues would be created in %s/%s, %d skipped, %d invalid.\n",
+					counts[bitbucket.ImportWouldCreate], workspace, repoSlug, counts[bitbucket.ImportSkipped], counts[bitbucket.ImportFailed])
+				return
+			}
+			fmt.Printf("%d issues in %s/%s: %d created, %d skipped, %d failed.\n",
+				len(results), workspace, repoSlug, counts[bitbucket.ImportCreated], counts[bitbucket.ImportSkipped], counts[bitbucket.ImportFailed])
+		})
+		if counts[bitbucket.ImportFailed] > 0 {
+			return fmt.Errorf("%d of %d issues failed", counts[bitbucket.ImportFailed], len(results))
+		}
+		return nil
+	},
+}
+
+// archiveSummary counts what a Bitbucket issue export archive holds.
+type archiveSummary struct {
+	Archive     string `json:"archive"`
+	Issues      int    `json:"issues"`
+	Comments    int    `json:"comments"`
+	Attachments int    `json:"attachments"`
+	Replaces    int    `json:"replaces"`
+}
+
+// runImportArchive uploads a Bitbucket zip export, or with dryRun checks
+// it and reports what the import would replace. A repository that has
+// issues is only replaced with replace.
+func runImportArchive(cmd *cobra.Command, workspace, repoSlug, file string, dryRun, replace bool) error {
+	summary, err := readIssueArchive(file)
+	if err != nil {
+		return err
+	}
+	client := getClient()
+
+	existing, err := client.ListIssues(bitbucket.ListIssuesArgs{Workspace: workspace, RepoSlug: repoSlug, Pagelen: 1})
+	if err != nil {
+		return err
+	}
+	summary.Replaces = existing.Size
+	if dryRun {
+		PrintOrJSON(cmd, summary, func() {
+			fmt.Printf("Dry run: %s holds %d issues, %d comments, and %d attachments.\n",
+				file, summary.Issues, summary.Comments, summary.Attachments)
+			if summary.Replaces > 0 {
+				fmt.Printf("Importing it would replace the %d existing issues in %s/%s (requires --replace).\n", summary.Replaces, workspace, repoSlug)
+			}
+		})
+		return nil
+	}
+	if summary.Replaces > 0 && !replace {
+		return fmt.Errorf("%s/%s has %d issues, which importing %s would delete: pass --replace to go ahead, or import json or csv to add to them",
+			workspace, repoSlug, summary.Replaces, file)
+	}
+
+	f, err := os.Open(file) //nolint:gosec // path chosen by the user
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	var r io.Reader = f
+	progress, done := newProgress("Uploading " + filepath.Base(file))
+	if progress != nil {
+		r = &progressReader{r: r, total: st.Size(), fn: progress}
+	}
+	status, statusDone := newJobStatus("Importing issues")
+	result, err := client.ImportIssuesArchive(bitbucket.IssueArchiveArgs{Workspace: workspace, RepoSlug: repoSlug}, r, st.Size(), status)
+	done()
+	statusDone()
+	if err != nil {
+		return err
+	}
+	PrintOrJSON(cmd, result, func() {
+		fmt.Printf("Imported %s into %s/%s: %d issues, %d comments, %d attachments.\n",
+			file, workspace, repoSlug, summary.Issues, summary.Comments, summary.Attachments)
+	})
+	return nil
+}
+
+// readIssueArchive checks file is a Bitbucket issue export and counts its
+// contents from db-2.0.json.
+func readIssueArchive(file string) (*archiveSummary, error) {
+	zr, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a zip archive: %v", file, err)
+	}
+	defer zr.Close()
+	for _, entry := range zr.File {
+		if filepath.Base(entry.Name) != "db-2.0.json" {
+			continue
+		}
+		rc, err := entry.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		var db struct {
+			Issues      []json.RawMessage `json:"issues"`
+			Comments    []json.RawMessage `json:"comments"`
+			Attachments []json.RawMessage `json:"attachments"`
+		}
+		if err := json.NewDecoder(rc).Decode(&db); err != nil {
+			return nil, fmt.Errorf("failed to parse %s in %s: %v", entry.Name, file, err)
+		}
+		return &archiveSummary{Archive: file, Issues: len(db.Issues), Comments: len(db.Comments), Attachments: len(db.Attachments)}, nil
+	}
+	return nil, fmt.Errorf("%s is not a Bitbucket issue export: no db-2.0.json", file)
+}
+
+// newJobStatus is newProgress for Bitbucket's issue export and import
+// jobs, which report a phase and percentage rather than bytes.
+func newJobStatus(label string) (status func(bitbucket.IssueJobStatus), done func()) {
+	if fi, err := os.Stderr.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return nil, func() {}
+	}
+	status = func(s bitbucket.IssueJobStatus) {
+		phase := s.Phase
+		if phase == "" {
+			phase = strings.ToLower(s.Status)
+		}
+		fmt.Fprintf(os.Stderr, "\r%s  %s (%.0f%%)\033[K", label, phase, s.Pct)
+	}
+	return status, func() { fmt.Fprint(os.Stderr, "\r\033[K") }
+}
+
+func init() {
+	issuesCmd.AddCommand(issuesExportCmd)
+	issuesCmd.AddCommand(issuesImportCmd)
+
+	issuesExportCmd.Flags().String("format", "json", "Export format: json | csv | zip")
+	issuesExportCmd.Flags().StringP("output", "o", "", "Destination file (default: stdout for json and csv, <repo>-issues.zip for zip)")
+	issuesExportCmd.Flags().String("state", "", "Only export issues in this state (json and csv)")
+	issuesExportCmd.Flags().Int("parallel", 4, "Issues whose comments and attachments are fetched at once")
+
+	issuesImportCmd.Flags().String("format", "", "Import format: json | csv | zip (default: from the file extension)")
+	issuesImportCmd.Flags().StringArray("map", nil, "Map a field to a CSV column as field=column (repeatable)")
+	issuesImportCmd.Flags().String("dedupe", bitbucket.DedupeByTitle, "Skip issues already present by: title | external-id | none")
+	issuesImportCmd.Flags().Int("parallel", 4, "Issues created at once")
+	issuesImportCmd.Flags().Bool("dry-run", false, "Show what would be imported without changing anything")
+	issuesImportCmd.Flags().Bool("replace", false, "With --format zip, replace the repository's existing issues")
+}
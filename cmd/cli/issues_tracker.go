@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+// issueOptionsCmd builds the milestones/components/versions list commands.
+func issueOptionsCmd(list string, fetch func(*bitbucket.Client, bitbucket.ListIssueOptionsArgs) (*bitbucket.Paginated[bitbucket.IssueOption], error)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   list + " [workspace] [repo-slug]",
+		Short: fmt.Sprintf("List the %s issues can be assigned to", list),
+		Args:  cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+			if err != nil {
+				return err
+			}
+			page, pagelen := paginationArgs(cmd)
+
+			result, err := fetch(getClient(), bitbucket.ListIssueOptionsArgs{
+				Workspace: workspace,
+				RepoSlug:  repoSlug,
+				Page:      page,
+				Pagelen:   pagelen,
+			})
+			if err != nil {
+				return err
+			}
+
+			PrintOrJSON(cmd, result, func() {
+				if len(result.Values) == 0 {
+					fmt.Printf("%s/%s has no %s.\n", workspace, repoSlug, list)
+					return
+				}
+				t := NewTable()
+				t.Header("ID", "Name")
+				for _, o := range result.Values {
+					t.Row(fmt.Sprintf("%d", o.ID), o.Name)
+				}
+				t.Flush()
+				PrintPaginationFooter(result.Size, result.Page, len(result.Values), result.Next != "")
+			})
+			return nil
+		},
+	}
+	addPaginationFlags(cmd)
+	return cmd
+}
+
+func init() {
+	issuesCmd.AddCommand(issueOptionsCmd(bitbucket.IssueMilestones, (*bitbucket.Client).ListMilestones))
+	issuesCmd.AddCommand(issueOptionsCmd(bitbucket.IssueComponents, (*bitbucket.Client).ListComponents))
+	issuesCmd.AddCommand(issueOptionsCmd(bitbucket.IssueVersions, (*bitbucket.Client).ListVersions))
+}
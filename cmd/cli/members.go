@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+var membersCmd = &cobra.Command{
+	Use:     "members",
+	GroupID: groupData,
+	Short:   "List workspace members",
+	Long: `List the members of a workspace with their account IDs and UUIDs, which
+reviewer, assignee, and permission commands take. Use 'bbkt users find' to
+look up one person.`,
+}
+
+var membersListCmd = &cobra.Command{
+	Use:   "list [workspace]",
+	Short: "List the members of a workspace",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, _, _, err := ParseArgs(cmd, args, -1)
+		if err != nil {
+			return err
+		}
+		page, pagelen := paginationArgs(cmd)
+
+		client := getClient()
+		result, err := client.ListMembers(bitbucket.ListMembersArgs{Workspace: workspace, Page: page, Pagelen: pagelen})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result.Values) == 0 {
+				fmt.Println("No members found.")
+				return
+			}
+			users := make([]bitbucket.User, 0, len(result.Values))
+			for _, m := range result.Values {
+				users = append(users, m.User)
+			}
+			printUsers(users)
+			PrintPaginationFooter(result.Size, result.Page, len(result.Values), result.Next != "")
+		})
+		return nil
+	},
+}
+
+var usersCmd = &cobra.Command{
+	Use:     "users",
+	GroupID: groupData,
+	Short:   "Find users by name to get their account ID or UUID",
+}
+
+var usersFindCmd = &cobra.Command{
+	Use:   "find [workspace] <name | @nickname | email>",
+	Short: "Find workspace members by name, nickname, account ID, or email",
+	Long: `Search the workspace's members for a name, nickname, account ID, or UUID
+fragment (case-insensitive; a leading @ is ignored). Exact matches are listed
+first. Email lookups only work for workspace admins.`,
+	Example: `  bbkt users find alice
+  bbkt users find acme @asmith
+  bbkt users find alice@example.com --json`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, _, _, err := ParseArgs(cmd, args[:len(args)-1], -1)
+		if err != nil {
+			return err
+		}
+		query := args[len(args)-1]
+
+		client := getClient()
+		users, err := client.FindMembers(workspace, query)
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, users, func() {
+			if len(users) == 0 {
+				fmt.Printf("No members of %s match %q.\n", workspace, query)
+				return
+			}
+			printUsers(users)
+		})
+		return nil
+	},
+}
+
+func printUsers(users []bitbucket.User) {
+	t := NewTable()
+	t.Header("Name", "Nickname", "Account ID", "UUID")
+	for _, u := range users {
+		t.Row(u.DisplayName, orDash(u.Nickname), orDash(u.AccountID), orDash(u.UUID))
+	}
+	t.Flush()
+}
+
+// resolveUser turns "@name" into the account ID (or UUID) of the single
+// workspace member it matches. Other values are returned unchanged.
+func resolveUser(client *bitbucket.Client, workspace, user string) (string, error) {
+	if !strings.HasPrefix(user, "@") {
+		return user, nil
+	}
+	users, err := client.FindMembers(workspace, user)
+	if err != nil {
+		return "", err
+	}
+	// A single exact name or nickname match wins over partial matches.
+	var exact []bitbucket.User
+	for _, u := range users {
+		if strings.EqualFold(u.Nickname, user[1:]) || strings.EqualFold(u.DisplayName, user[1:]) {
+			exact = append(exact, u)
+		}
+	}
+	if len(exact) == 1 {
+		users = exact
+	}
+	switch len(users) {
+	case 0:
+		return "", fmt.Errorf("no member of %s matches %s", workspace, user)
+	case 1:
+		if users[0].AccountID != "" {
+			return users[0].AccountID, nil
+		}
+		return users[0].UUID, nil
+	}
+	var names []string
+	for _, u := range users {
+		names = append(names, u.DisplayName)
+	}
+	return "", fmt.Errorf("%s matches %d members (%s); use 'bbkt users find' and pass an account ID", user, len(users), strings.Join(names, ", "))
+}
+
+func init() {
+	RootCmd.AddCommand(membersCmd)
+	membersCmd.AddCommand(membersListCmd)
+	addPaginationFlags(membersListCmd)
+
+	RootCmd.AddCommand(usersCmd)
+	usersCmd.AddCommand(usersFindCmd)
+}
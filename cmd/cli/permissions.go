@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+var permissionsCmd = &cobra.Command{
+	Use:     "permissions",
+	Aliases: []string{"perms"},
+	GroupID: groupData,
+	Short:   "Show workspace, project, and repository permissions; grant and revoke repository access",
+	Long: `Show who can access a workspace, project, or repository, and grant or
+revoke explicit repository permissions for users and groups. Users are given
+by account ID, UUID, or @name (resolved among workspace members); groups by
+slug. Listing and changing repository permissions requires admin access.`,
+	Example: `  bbkt permissions workspace
+  bbkt permissions project PLAT
+  bbkt permissions repo acme api
+  bbkt permissions grant acme api --user @alice --permission write
+  bbkt permissions revoke --group contractors`,
+}
+
+var permissionsWorkspaceCmd = &cobra.Command{
+	Use:   "workspace [workspace]",
+	Short: "List each member's workspace permission (owner, collaborator, member)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, _, _, err := ParseArgs(cmd, args, -1)
+		if err != nil {
+			return err
+		}
+		query, _ := cmd.Flags().GetString("query")
+		page, pagelen := paginationArgs(cmd)
+
+		client := getClient()
+		result, err := client.ListWorkspacePermissions(bitbucket.ListWorkspacePermissionsArgs{
+			Workspace: workspace,
+			Query:     query,
+			Page:      page,
+			Pagelen:   pagelen,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result.Values) == 0 {
+				fmt.Println("No permissions found.")
+				return
+			}
+			t := NewTable()
+			t.Header("Name", "Account ID", "Permission")
+			for _, p := range result.Values {
+				t.Row(p.User.DisplayName, orDash(p.User.AccountID), p.Permission)
+			}
+			t.Flush()
+			PrintPaginationFooter(result.Size, result.Page, len(result.Values), result.Next != "")
+		})
+		return nil
+	},
+}
+
+var permissionsProjectCmd = &cobra.Command{
+	Use:   "project [workspace] <project-key>",
+	Short: "List explicit user and group permissions on a project",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  projectsPermissionsListCmd.RunE,
+}
+
+var permissionsRepoCmd = &cobra.Command{
+	Use:   "repo [workspace] [repo-slug]",
+	Short: "List explicit user and group permissions on a repository",
+	Args:  cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		perms, err := client.ListRepositoryPermissions(bitbucket.RepositoryPermissionArgs{Workspace: workspace, RepoSlug: repoSlug})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, perms, func() { printPermissions(perms, workspace+"/"+repoSlug) })
+		return nil
+	},
+}
+
+// printPermissions prints explicit user and group permissions on target.
+func printPermissions(perms []bitbucket.Permission, target string) {
+	if len(perms) == 0 {
+		fmt.Printf("%s has no explicit permissions.\n", target)
+		return
+	}
+	t := NewTable()
+	t.Header("Type", "Name", "Permission")
+	for _, p := range perms {
+		kind, name := p.Subject()
+		t.Row(kind, name, p.Permission)
+	}
+	t.Flush()
+}
+
+var permissionsGrantCmd = &cobra.Command{
+	Use:   "grant [workspace] [repo-slug] (--user <id> | --group <slug>) --permission <level>",
+	Short: "Grant or change a user's or group's repository permission",
+	Args:  cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		client := getClient()
+		kind, subject, err := resolvedPermissionSubject(cmd, client, workspace)
+		if err != nil {
+			return err
+		}
+		permission, _ := cmd.Flags().GetString("permission")
+
+		p, err := client.SetRepositoryPermission(bitbucket.RepositoryPermissionArgs{Workspace: workspace, RepoSlug: repoSlug}, kind, subject, permission)
+		if err != nil {
+			return err
+		}
+		PrintOrJSON(cmd, p, func() {
+			fmt.Printf("Granted %s %s %s on %s/%s\n", kind, subject, permission, workspace, repoSlug)
+		})
+		return nil
+	},
+}
+
+var permissionsRevokeCmd = &cobra.Command{
+	Use:   "revoke [workspace] [repo-slug] (--user <id> | --group <slug>)",
+	Short: "Remove a user's or group's explicit repository permission",
+	Args:  cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		client := getClient()
+		kind, subject, err := resolvedPermissionSubject(cmd, client, workspace)
+		if err != nil {
+			return err
+		}
+
+		if err := client.RemoveRepositoryPermission(bitbucket.RepositoryPermissionArgs{Workspace: workspace, RepoSlug: repoSlug}, kind, subject); err != nil {
+			return err
+		}
+		PrintOrJSON(cmd, map[string]any{"repository": workspace + "/" + repoSlug, kind: subject, "revoked": true}, func() {
+			fmt.Printf("Revoked %s %s's permission on %s/%s\n", kind, subject, workspace, repoSlug)
+		})
+		return nil
+	},
+}
+
+// resolvedPermissionSubject reads --user or --group, resolving an @name
+// user among the workspace's members.
+func resolvedPermissionSubject(cmd *cobra.Command, client *bitbucket.Client, workspace string) (kind, subject string, err error) {
+	kind, subject, err = permissionSubject(cmd)
+	if err != nil || kind != bitbucket.PermissionUser {
+		return kind, subject, err
+	}
+	subject, err = resolveUser(client, workspace, subject)
+	return kind, subject, err
+}
+
+func init() {
+	RootCmd.AddCommand(permissionsCmd)
+	permissionsCmd.AddCommand(permissionsWorkspaceCmd)
+	permissionsCmd.AddCommand(permissionsProjectCmd)
+	permissionsCmd.AddCommand(permissionsRepoCmd)
+	permissionsCmd.AddCommand(permissionsGrantCmd)
+	permissionsCmd.AddCommand(permissionsRevokeCmd)
+
+	permissionsWorkspaceCmd.Flags().StringP("query", "q", "", `Filter using Bitbucket query syntax (e.g. permission="owner")`)
+	addPaginationFlags(permissionsWorkspaceCmd)
+
+	for _, c := range []*cobra.Command{permissionsGrantCmd, permissionsRevokeCmd} {
+		c.Flags().String("user", "", "User account ID, UUID, or @name")
+		c.Flags().String("group", "", "Group slug")
+	}
+	permissionsGrantCmd.Flags().String("permission", "", "Permission: "+strings.Join(bitbucket.RepositoryPermissions, " | "))
+	_ = permissionsGrantCmd.MarkFlagRequired("permission")
+}
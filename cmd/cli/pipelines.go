@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/huh"
@@ -25,7 +26,14 @@ Alias: pipe`,
   bbkt pipelines trigger -r feature/x --pattern deploy   # run a custom: pipeline
   bbkt pipelines steps {pipeline-uuid}
   bbkt pipelines log {pipeline-uuid} {step-uuid}
-  bbkt pipelines stop {pipeline-uuid}`,
+  bbkt pipelines tests {pipeline-uuid}        # test summary and failing tests per step
+  bbkt pipelines artifacts download {pipeline-uuid} -o out/
+  bbkt pipelines caches delete node           # drop a stale dependency cache
+  bbkt pipelines stop {pipeline-uuid}
+  bbkt pipelines lint                         # validate ./bitbucket-pipelines.yml offline
+  bbkt pipelines lint --ref main              # validate the committed file on main
+  bbkt pipelines plan --ref feature/x         # which pipeline runs for a branch push
+  bbkt pipelines plan --ref feature/x --pr    # ...for a PR from feature/x`,
 }
 
 var pipelinesListCmd = &cobra.Command{
@@ -327,6 +335,546 @@ var pipelinesLogsCmd = &cobra.Command{
 	},
 }
 
+var pipelinesTestsCmd = &cobra.Command{
+	Use:   "tests [workspace] [repo-slug] <pipeline-uuid>",
+	Short: "Show test results and failures for a pipeline run",
+	Long: `Summarise each step's test reports (passed/failed/errored/skipped) and
+list the failing test cases with their failure reasons.
+
+Steps that failed without publishing test reports fall back to a
+heuristic over the step log: the exit code line, the command that failed,
+and the last block of error output — instead of the whole raw log.`,
+	Example: `  bbkt pipelines tests {pipeline-uuid}
+  bbkt pipelines tests {pipeline-uuid} --step Unit
+  bbkt pipelines tests {pipeline-uuid} --failures-only --json`,
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		step, _ := cmd.Flags().GetString("step")
+		maxFailures, _ := cmd.Flags().GetInt("max-failures")
+		failuresOnly, _ := cmd.Flags().GetBool("failures-only")
+
+		client := getClient()
+		results, err := client.GetPipelineTestResults(bitbucket.GetPipelineTestResultsArgs{
+			Workspace:    workspace,
+			RepoSlug:     repoSlug,
+			PipelineUUID: trailing[0],
+			Step:         step,
+			MaxFailures:  maxFailures,
+		})
+		if err != nil {
+			return err
+		}
+		if failuresOnly {
+			failing := results[:0]
+			for _, r := range results {
+				if r.Failing() {
+					failing = append(failing, r)
+				}
+			}
+			results = failing
+		}
+
+		PrintOrJSON(cmd, results, func() {
+			if len(results) == 0 {
+				fmt.Println("No failing steps.")
+				return
+			}
+			for i, r := range results {
+				if i > 0 {
+					fmt.Println()
+				}
+				printStepTestResult(r)
+			}
+		})
+		return nil
+	},
+}
+
+func printStepTestResult(r bitbucket.StepTestResult) {
+	fmt.Printf("%s  %s\n", r.StepName, orDash(r.State))
+
+	switch r.Source {
+	case "test_reports":
+		s := r.Summary
+		fmt.Printf("  %d tests: %d passed, %d failed, %d errors, %d skipped\n",
+			s.Total, s.Passed, s.Failed, s.Errored, s.Skipped)
+		for _, tc := range r.FailedTests {
+			name := tc.FullyQualifiedName
+			if name == "" {
+				name = tc.Name
+			}
+			fmt.Printf("  ✗ %s (%s)\n", name, strings.ToLower(tc.Status))
+			for _, reason := range tc.Reasons {
+				fmt.Printf("      %s\n", Truncate(firstLine(reason.Message), 160))
+			}
+		}
+		if r.Truncated {
+			fmt.Println("  … more failures not shown (raise --max-failures)")
+		}
+	case "log":
+		fmt.Println("  No test reports; failure extracted from the step log:")
+		if r.Log.FailedCommand != "" {
+			KV("Command", r.Log.FailedCommand)
+		}
+		if r.Log.ExitLine != "" {
+			KV("Exit", r.Log.ExitLine)
+		}
+		for _, line := range r.Log.ErrorBlock {
+			fmt.Printf("    %s\n", line)
+		}
+	default:
+		if r.Note != "" {
+			fmt.Printf("  %s\n", r.Note)
+		} else {
+			fmt.Println("  No test reports.")
+		}
+	}
+}
+
+// firstLine returns s up to its first newline.
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+var pipelinesArtifactsCmd = &cobra.Command{
+	Use:   "artifacts",
+	Short: "List and download step artifacts from a pipeline run",
+}
+
+var pipelinesArtifactsListCmd = &cobra.Command{
+	Use:   "list [workspace] [repo-slug] <pipeline-uuid>",
+	Short: "List artifacts produced by a pipeline run",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		step, _ := cmd.Flags().GetString("step")
+
+		client := getClient()
+		result, err := client.ListPipelineArtifacts(bitbucket.ListPipelineArtifactsArgs{
+			Workspace:    workspace,
+			RepoSlug:     repoSlug,
+			PipelineUUID: trailing[0],
+			Step:         step,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result) == 0 {
+				fmt.Println("No artifacts found.")
+				return
+			}
+			t := NewTable()
+			t.Header("Step", "Artifact", "Size", "UUID")
+			for _, a := range result {
+				t.Row(a.StepName, a.DisplayName(), FormatBytes(a.FileSizeBytes), a.UUID)
+			}
+			t.Flush()
+		})
+		return nil
+	},
+}
+
+var pipelinesArtifactsDownloadCmd = &cobra.Command{
+	Use:   "download [workspace] [repo-slug] <pipeline-uuid>",
+	Short: "Download a pipeline run's artifacts into a directory",
+	Long: `Download every artifact of a pipeline run (or those matching --name,
+a path glob such as 'dist/*.zip') into --output, keeping each artifact's
+path relative to the build directory. Interrupted downloads resume.`,
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		step, _ := cmd.Flags().GetString("step")
+		pattern, _ := cmd.Flags().GetString("name")
+		outDir, _ := cmd.Flags().GetString("output")
+
+		client := getClient()
+		artifacts, err := client.ListPipelineArtifacts(bitbucket.ListPipelineArtifactsArgs{
+			Workspace:    workspace,
+			RepoSlug:     repoSlug,
+			PipelineUUID: trailing[0],
+			Step:         step,
+		})
+		if err != nil {
+			return err
+		}
+
+		results := []*bitbucket.DownloadResult{}
+		for _, a := range artifacts {
+			name := a.DisplayName()
+			if pattern != "" {
+				if ok, _ := filepath.Match(pattern, name); !ok {
+					continue
+				}
+			}
+			if !filepath.IsLocal(filepath.FromSlash(name)) {
+				return fmt.Errorf("artifact path %q escapes the output directory", name)
+			}
+			dest := filepath.Join(outDir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+				return err
+			}
+
+			progress, done := newProgress("Downloading " + name)
+			r, err := client.DownloadPipelineArtifact(bitbucket.DownloadPipelineArtifactArgs{
+				Workspace:    workspace,
+				RepoSlug:     repoSlug,
+				PipelineUUID: trailing[0],
+				StepUUID:     a.StepUUID,
+				ArtifactUUID: a.UUID,
+				Dest:         dest,
+			}, progress)
+			done()
+			if err != nil {
+				return err
+			}
+			results = append(results, r)
+			if !outputJSON(cmd) {
+				printDownloadResult(r)
+			}
+		}
+
+		if outputJSON(cmd) {
+			PrintJSON(results)
+		} else if len(results) == 0 {
+			fmt.Println("No matching artifacts.")
+		}
+		return nil
+	},
+}
+
+var pipelinesCachesCmd = &cobra.Command{
+	Use:   "caches",
+	Short: "List and clear pipeline dependency caches",
+}
+
+var pipelinesCachesListCmd = &cobra.Command{
+	Use:   "list [workspace] [repo-slug]",
+	Short: "List pipeline caches with their size and creation time",
+	Args:  cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		result, err := client.ListPipelineCaches(bitbucket.ListPipelineCachesArgs{
+			Workspace: workspace,
+			RepoSlug:  repoSlug,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result) == 0 {
+				fmt.Println("No caches found.")
+				return
+			}
+			t := NewTable()
+			t.Header("Name", "Path", "Size", "Created")
+			var total int64
+			for _, c := range result {
+				total += c.FileSizeBytes
+				t.Row(c.Name, orDash(c.Path), FormatBytes(c.FileSizeBytes), FormatTimePtr(c.CreatedOn))
+			}
+			t.Flush()
+			fmt.Printf("\n%d cache(s), %s total\n", len(result), FormatBytes(total))
+		})
+		return nil
+	},
+}
+
+var pipelinesCachesDeleteCmd = &cobra.Command{
+	Use:   "delete [workspace] [repo-slug] <name>",
+	Short: "Delete every version of a named cache",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		if err := client.DeletePipelineCache(bitbucket.DeletePipelineCacheArgs{
+			Workspace: workspace,
+			RepoSlug:  repoSlug,
+			Name:      trailing[0],
+		}); err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, map[string]any{
+			"name":    trailing[0],
+			"deleted": true,
+		}, func() {
+			fmt.Printf("Cache '%s' deleted; the next run will rebuild it.\n", trailing[0])
+		})
+		return nil
+	},
+}
+
+var pipelinesCachesClearCmd = &cobra.Command{
+	Use:   "clear [workspace] [repo-slug]",
+	Short: "Delete all of the repository's pipeline caches",
+	Args:  cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		names, err := client.ClearPipelineCaches(bitbucket.ListPipelineCachesArgs{
+			Workspace: workspace,
+			RepoSlug:  repoSlug,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, map[string]any{
+			"cleared": names,
+		}, func() {
+			if len(names) == 0 {
+				fmt.Println("No caches to clear.")
+				return
+			}
+			fmt.Printf("Cleared %d cache(s): %s\n", len(names), strings.Join(names, ", "))
+		})
+		return nil
+	},
+}
+
+var pipelinesLintCmd = &cobra.Command{
+	Use:   "lint [file]",
+	Short: "Validate a bitbucket-pipelines.yml file (offline unless --ref is given)",
+	Long: `Check a bitbucket-pipelines.yml for YAML syntax errors, unknown keys,
+invalid step/parallel/stage nesting, undefined caches and services, and
+size/max-time limits. Problems are reported as file:line:col diagnostics.
+
+By default the local file (./bitbucket-pipelines.yml) is linted without
+any network access. Pass --ref to lint the file committed to the
+repository at that branch, tag, or commit instead; in that mode the
+positional args are [workspace] [repo-slug].
+
+Exits non-zero when any error-level diagnostic is found.`,
+	Example: `  bbkt pipelines lint
+  bbkt pipelines lint ci/bitbucket-pipelines.yml
+  bbkt pipelines lint --ref feature/x
+  bbkt pipelines lint myws myrepo --ref main`,
+	Args: cobra.RangeArgs(0, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ref, _ := cmd.Flags().GetString("ref")
+
+		var result *bitbucket.LintResult
+		name := bitbucket.PipelinesConfigPath
+		if cmd.Flags().Changed("ref") {
+			workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+			if err != nil {
+				return err
+			}
+			client := getClient()
+			result, err = client.LintPipelinesConfig(bitbucket.LintPipelinesConfigArgs{
+				Workspace: workspace,
+				RepoSlug:  repoSlug,
+				Ref:       ref,
+			})
+			if err != nil {
+				return err
+			}
+			name = fmt.Sprintf("%s/%s@%s:%s", workspace, repoSlug, ref, name)
+		} else {
+			if len(args) > 1 {
+				return fmt.Errorf("expected at most one file argument (use --ref to lint a committed file)")
+			}
+			if len(args) == 1 {
+				name = args[0]
+			}
+			data, err := os.ReadFile(name)
+			if err != nil {
+				return fmt.Errorf("reading %s: %w", name, err)
+			}
+			result = bitbucket.LintPipelinesYAML(data)
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			for _, d := range result.Diagnostics {
+				fmt.Printf("%s:%s\n", name, d)
+			}
+			fmt.Printf("%s: %s\n", name, result.Summary())
+		})
+		if !result.Valid {
+			return fmt.Errorf("%s is invalid: %s", name, result.Summary())
+		}
+		return nil
+	},
+}
+
+var pipelinesPlanCmd = &cobra.Command{
+	Use:   "plan [workspace] [repo-slug]",
+	Short: "Show which pipeline would run for a ref, with anchors and definitions expanded",
+	Long: `Resolve which section of bitbucket-pipelines.yml runs for a ref — the
+default pipeline, a glob-matched branches/tags entry, a pull-requests
+entry (--pr) or a custom pipeline (--custom) — and print its expanded
+steps with their images, services, caches and triggers.
+
+The config is read from the repository at --ref. Pass --file to plan
+from a local file instead; that mode makes no network calls.
+
+Within a section an exact name beats any glob, and among globs the most
+specific one (most literal characters) wins.`,
+	Example: `  bbkt pipelines plan --ref main
+  bbkt pipelines plan --ref feature/login --pr
+  bbkt pipelines plan --ref v1.4.0 --tag
+  bbkt pipelines plan --custom nightly --ref main
+  bbkt pipelines plan --ref feature/x -f bitbucket-pipelines.yml --scripts`,
+	Args: cobra.RangeArgs(0, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ref, _ := cmd.Flags().GetString("ref")
+		pr, _ := cmd.Flags().GetBool("pr")
+		tag, _ := cmd.Flags().GetBool("tag")
+		custom, _ := cmd.Flags().GetString("custom")
+		file, _ := cmd.Flags().GetString("file")
+		showScripts, _ := cmd.Flags().GetBool("scripts")
+
+		if ref == "" && custom == "" {
+			return fmt.Errorf("--ref is required (or --custom to plan a custom pipeline)")
+		}
+		if pr && tag {
+			return fmt.Errorf("--pr and --tag are mutually exclusive")
+		}
+
+		var result *bitbucket.PipelinePlan
+		var err error
+		if file != "" {
+			data, rerr := os.ReadFile(file)
+			if rerr != nil {
+				return fmt.Errorf("reading %s: %w", file, rerr)
+			}
+			result, err = bitbucket.PlanPipelinesYAML(data, bitbucket.PipelinePlanTarget{
+				Ref:         ref,
+				PullRequest: pr,
+				Tag:         tag,
+				Custom:      custom,
+			})
+		} else {
+			workspace, repoSlug, _, perr := ParseArgs(cmd, args, 0)
+			if perr != nil {
+				return perr
+			}
+			client := getClient()
+			result, err = client.PlanPipelinesConfig(bitbucket.PlanPipelinesConfigArgs{
+				Workspace:   workspace,
+				RepoSlug:    repoSlug,
+				Ref:         ref,
+				PullRequest: pr,
+				Tag:         tag,
+				Custom:      custom,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			printPipelinePlan(result, showScripts)
+		})
+		return nil
+	},
+}
+
+func printPipelinePlan(plan *bitbucket.PipelinePlan, showScripts bool) {
+	if !plan.Matched {
+		fmt.Printf("No pipeline runs: %s\n", plan.Note)
+		return
+	}
+
+	section := "pipelines." + plan.Section
+	if plan.Pattern != "" {
+		section += fmt.Sprintf("[%q]", plan.Pattern)
+	}
+	fmt.Printf("Pipeline: %s\n", section)
+	for _, v := range plan.Variables {
+		line := v.Name
+		if v.Default != "" {
+			line += "=" + v.Default
+		}
+		if len(v.AllowedValues) > 0 {
+			line += fmt.Sprintf(" (one of %s)", strings.Join(v.AllowedValues, ", "))
+		}
+		KV("Variable", line)
+	}
+	fmt.Println()
+
+	t := NewTable()
+	t.Header("#", "Step", "Group", "Trigger", "Image", "Size", "Services", "Caches", "Deployment")
+	for _, s := range plan.Steps {
+		services := make([]string, 0, len(s.Services))
+		for _, svc := range s.Services {
+			services = append(services, svc.Name)
+		}
+		t.Row(
+			fmt.Sprintf("%d", s.Index),
+			Truncate(s.Name, 40),
+			orDash(s.Group),
+			s.Trigger,
+			orDash(s.Image),
+			s.Size,
+			orDash(strings.Join(services, ",")),
+			orDash(strings.Join(s.Caches, ",")),
+			orDash(s.Deployment),
+		)
+	}
+	t.Flush()
+
+	if !showScripts {
+		return
+	}
+	for _, s := range plan.Steps {
+		fmt.Printf("\n%d. %s\n", s.Index, s.Name)
+		for _, svc := range s.Services {
+			KV("Service", strings.TrimSpace(svc.Name+" "+svc.Image))
+		}
+		if len(s.Condition) > 0 {
+			KV("Runs if changed", strings.Join(s.Condition, ", "))
+		}
+		if len(s.Artifacts) > 0 {
+			KV("Artifacts", strings.Join(s.Artifacts, ", "))
+		}
+		KV("Max time", fmt.Sprintf("%dm", s.MaxTime))
+		for _, line := range s.Script {
+			fmt.Printf("    $ %s\n", line)
+		}
+		for _, line := range s.AfterScript {
+			fmt.Printf("    (after) $ %s\n", line)
+		}
+	}
+}
+
+// orDash renders an empty table cell as "-".
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 func init() {
 	RootCmd.AddCommand(pipelinesCmd)
 	pipelinesCmd.AddCommand(pipelinesListCmd)
@@ -335,6 +883,16 @@ func init() {
 	pipelinesCmd.AddCommand(pipelinesStopCmd)
 	pipelinesCmd.AddCommand(pipelinesStepsCmd)
 	pipelinesCmd.AddCommand(pipelinesLogsCmd)
+	pipelinesCmd.AddCommand(pipelinesTestsCmd)
+	pipelinesCmd.AddCommand(pipelinesArtifactsCmd)
+	pipelinesArtifactsCmd.AddCommand(pipelinesArtifactsListCmd)
+	pipelinesArtifactsCmd.AddCommand(pipelinesArtifactsDownloadCmd)
+	pipelinesCmd.AddCommand(pipelinesCachesCmd)
+	pipelinesCachesCmd.AddCommand(pipelinesCachesListCmd)
+	pipelinesCachesCmd.AddCommand(pipelinesCachesDeleteCmd)
+	pipelinesCachesCmd.AddCommand(pipelinesCachesClearCmd)
+	pipelinesCmd.AddCommand(pipelinesLintCmd)
+	pipelinesCmd.AddCommand(pipelinesPlanCmd)
 
 	pipelinesListCmd.Flags().String("status", "", "Filter by status: SUCCESSFUL | FAILED | INPROGRESS | STOPPED")
 	pipelinesListCmd.Flags().String("sort", "-created_on", "Sort field (prefix with - for desc)")
@@ -345,4 +903,22 @@ func init() {
 	// No -p shorthand: it collides with the global persistent --profile (-p),
 	// which panics cobra when the flags merge. Long --pattern only.
 	pipelinesTriggerCmd.Flags().String("pattern", "", "Name of a 'custom:' pipeline from bitbucket-pipelines.yml (omit to run the branch's default pipeline)")
+
+	pipelinesTestsCmd.Flags().String("step", "", "Only this step (UUID or name)")
+	pipelinesTestsCmd.Flags().Int("max-failures", 50, "Maximum failing test cases to show per step")
+	pipelinesTestsCmd.Flags().Bool("failures-only", false, "Hide steps that passed")
+
+	pipelinesArtifactsListCmd.Flags().String("step", "", "Only this step (UUID or name)")
+	pipelinesArtifactsDownloadCmd.Flags().String("step", "", "Only this step (UUID or name)")
+	pipelinesArtifactsDownloadCmd.Flags().String("name", "", "Only artifacts whose path matches this glob")
+	pipelinesArtifactsDownloadCmd.Flags().StringP("output", "o", ".", "Directory to download into")
+
+	pipelinesLintCmd.Flags().String("ref", "", "Lint the file committed at this branch, tag, or commit instead of a local file")
+
+	pipelinesPlanCmd.Flags().String("ref", "", "Branch or tag to simulate (the config is also read from this ref unless --file is set)")
+	pipelinesPlanCmd.Flags().Bool("pr", false, "Simulate a pull request whose source branch is --ref")
+	pipelinesPlanCmd.Flags().Bool("tag", false, "Treat --ref as a tag")
+	pipelinesPlanCmd.Flags().String("custom", "", "Plan the named custom pipeline")
+	pipelinesPlanCmd.Flags().StringP("file", "f", "", "Plan from a local bitbucket-pipelines.yml (offline)")
+	pipelinesPlanCmd.Flags().Bool("scripts", false, "Also print each step's script, services and artifacts")
 }
@@ -0,0 +1,432 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+// ─── Repository settings ─────────────────────────────────────────────────────
+
+var pipelinesConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Enable or disable Pipelines and edit repository pipeline settings",
+	Example: `  bbkt pipelines config show
+  bbkt pipelines config enable
+  bbkt pipelines config build-number 1000`,
+}
+
+var pipelinesConfigShowCmd = &cobra.Command{
+	Use:   "show [workspace] [repo-slug]",
+	Short: "Show whether Pipelines is enabled",
+	Args:  cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		cfg, err := client.GetPipelinesConfig(bitbucket.PipelinesConfigArgs{Workspace: workspace, RepoSlug: repoSlug})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, cfg, func() {
+			KV("Repository", workspace+"/"+repoSlug)
+			KV("Pipelines", formatEnabled(cfg.Enabled))
+		})
+		return nil
+	},
+}
+
+func newPipelinesConfigToggleCmd(enable bool) *cobra.Command {
+	verb, short := "disable", "Turn Pipelines off (runs stop being triggered; history is kept)"
+	if enable {
+		verb, short = "enable", "Turn Pipelines on for the repository"
+	}
+	return &cobra.Command{
+		Use:   verb + " [workspace] [repo-slug]",
+		Short: short,
+		Args:  cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+			if err != nil {
+				return err
+			}
+
+			client := getClient()
+			cfg, err := client.SetPipelinesEnabled(bitbucket.PipelinesConfigArgs{Workspace: workspace, RepoSlug: repoSlug}, enable)
+			if err != nil {
+				return err
+			}
+
+			PrintOrJSON(cmd, cfg, func() {
+				fmt.Printf("Pipelines %s for %s/%s.\n", formatEnabled(cfg.Enabled), workspace, repoSlug)
+			})
+			return nil
+		},
+	}
+}
+
+var pipelinesConfigBuildNumberCmd = &cobra.Command{
+	Use:   "build-number [workspace] [repo-slug] <next>",
+	Short: "Set the build number the next pipeline run gets",
+	Long: `Set the repository's next build number, e.g. to continue numbering after
+migrating from another CI. Bitbucket only accepts a value above the current
+build number.`,
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		next, err := strconv.Atoi(trailing[0])
+		if err != nil {
+			return fmt.Errorf("invalid build number %q", trailing[0])
+		}
+
+		client := getClient()
+		got, err := client.SetPipelinesBuildNumber(bitbucket.PipelinesConfigArgs{Workspace: workspace, RepoSlug: repoSlug}, next)
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, map[string]any{"next": got}, func() {
+			fmt.Printf("Next pipeline run will be #%d.\n", got)
+		})
+		return nil
+	},
+}
+
+func formatEnabled(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+// ─── SSH key pair ────────────────────────────────────────────────────────────
+
+var pipelinesSSHKeyCmd = &cobra.Command{
+	Use:   "ssh-key",
+	Short: "Manage the repository's Pipelines SSH key pair",
+	Long: `Show, set, or delete the SSH key pair Pipelines steps use to reach other
+servers. Bitbucket never returns the private key, and bbkt never prints it.`,
+	Example: `  bbkt pipelines ssh-key show
+  bbkt pipelines ssh-key set --private-key-file ~/.ssh/deploy_ed25519
+  bbkt pipelines ssh-key delete`,
+}
+
+var pipelinesSSHKeyShowCmd = &cobra.Command{
+	Use:   "show [workspace] [repo-slug]",
+	Short: "Show the public key and its fingerprint",
+	Args:  cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		kp, err := client.GetPipelineSSHKeyPair(bitbucket.PipelineSSHArgs{Workspace: workspace, RepoSlug: repoSlug})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, map[string]any{
+			"public_key":  kp.PublicKey,
+			"fingerprint": kp.Fingerprint(),
+		}, func() {
+			printSSHKeyPair(kp)
+		})
+		return nil
+	},
+}
+
+var pipelinesSSHKeySetCmd = &cobra.Command{
+	Use:   "set [workspace] [repo-slug]",
+	Short: "Upload an SSH key pair, replacing any existing one",
+	Long: `Upload an SSH key pair for Pipelines. The private key is read from
+--private-key-file and the public key from --public-key-file, which defaults to
+the private key path plus ".pub". The key must not have a passphrase.`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		privFile, _ := cmd.Flags().GetString("private-key-file")
+		pubFile, _ := cmd.Flags().GetString("public-key-file")
+		if privFile == "" {
+			return fmt.Errorf("--private-key-file is required")
+		}
+		if pubFile == "" {
+			pubFile = privFile + ".pub"
+		}
+
+		priv, err := os.ReadFile(privFile)
+		if err != nil {
+			return fmt.Errorf("reading private key: %w", err)
+		}
+		pub, err := os.ReadFile(pubFile)
+		if err != nil {
+			return fmt.Errorf("reading public key: %w", err)
+		}
+
+		client := getClient()
+		kp, err := client.SetPipelineSSHKeyPair(bitbucket.SetPipelineSSHKeyPairArgs{
+			PipelineSSHArgs: bitbucket.PipelineSSHArgs{Workspace: workspace, RepoSlug: repoSlug},
+			PrivateKey:      string(priv),
+			PublicKey:       string(pub),
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, map[string]any{
+			"public_key":  kp.PublicKey,
+			"fingerprint": kp.Fingerprint(),
+		}, func() {
+			fmt.Println("SSH key pair set.")
+			printSSHKeyPair(kp)
+		})
+		return nil
+	},
+}
+
+var pipelinesSSHKeyDeleteCmd = &cobra.Command{
+	Use:   "delete [workspace] [repo-slug]",
+	Short: "Delete the SSH key pair (destructive — no confirmation)",
+	Args:  cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		if err := client.DeletePipelineSSHKeyPair(bitbucket.PipelineSSHArgs{Workspace: workspace, RepoSlug: repoSlug}); err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, map[string]any{"deleted": true}, func() {
+			fmt.Printf("SSH key pair deleted from %s/%s.\n", workspace, repoSlug)
+		})
+		return nil
+	},
+}
+
+func printSSHKeyPair(kp *bitbucket.PipelineSSHKeyPair) {
+	KV("Fingerprint", orDash(kp.Fingerprint()))
+	KV("Public key", orDash(strings.TrimSpace(kp.PublicKey)))
+}
+
+// ─── Known hosts ─────────────────────────────────────────────────────────────
+
+var pipelinesKnownHostsCmd = &cobra.Command{
+	Use:   "known-hosts",
+	Short: "Manage the host keys Pipelines trusts for SSH",
+	Example: `  bbkt pipelines known-hosts list
+  ssh-keyscan -t ed25519 deploy.example.com | bbkt pipelines known-hosts add deploy.example.com --key-file -
+  bbkt pipelines known-hosts delete deploy.example.com`,
+}
+
+var pipelinesKnownHostsListCmd = &cobra.Command{
+	Use:   "list [workspace] [repo-slug]",
+	Short: "List known hosts with their key fingerprints",
+	Args:  cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		result, err := client.ListKnownHosts(bitbucket.PipelineSSHArgs{Workspace: workspace, RepoSlug: repoSlug})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result.Values) == 0 {
+				fmt.Println("No known hosts configured.")
+				return
+			}
+			t := NewTable()
+			t.Header("Hostname", "Key Type", "Fingerprint", "UUID")
+			for _, h := range result.Values {
+				keyType := ""
+				if h.PublicKey != nil {
+					keyType = h.PublicKey.KeyType
+				}
+				t.Row(h.Hostname, orDash(keyType), orDash(h.Fingerprint()), h.UUID)
+			}
+			t.Flush()
+		})
+		return nil
+	},
+}
+
+var pipelinesKnownHostsAddCmd = &cobra.Command{
+	Use:   "add [workspace] [repo-slug] <hostname>",
+	Short: "Trust a host's public key",
+	Long: `Add a known host. The key is given with --key ("ssh-ed25519 AAAA...") or
+--key-file; known_hosts and ssh-keyscan lines are accepted, and "-" reads
+stdin. Check the printed fingerprint against the one the host publishes.`,
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		key, _ := cmd.Flags().GetString("key")
+		keyFile, _ := cmd.Flags().GetString("key-file")
+		switch {
+		case key != "" && keyFile != "":
+			return fmt.Errorf("--key and --key-file are mutually exclusive")
+		case keyFile == "-":
+			b, err := os.ReadFile("/dev/stdin")
+			if err != nil {
+				return fmt.Errorf("reading stdin: %w", err)
+			}
+			key = string(b)
+		case keyFile != "":
+			b, err := os.ReadFile(keyFile)
+			if err != nil {
+				return fmt.Errorf("reading --key-file: %w", err)
+			}
+			key = string(b)
+		case key == "":
+			return fmt.Errorf("--key or --key-file is required (try: ssh-keyscan %s | bbkt pipelines known-hosts add %s --key-file -)", trailing[0], trailing[0])
+		}
+
+		client := getClient()
+		h, err := client.AddKnownHost(bitbucket.AddKnownHostArgs{
+			PipelineSSHArgs: bitbucket.PipelineSSHArgs{Workspace: workspace, RepoSlug: repoSlug},
+			Hostname:        trailing[0],
+			Key:             key,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, h, func() {
+			fmt.Printf("Known host '%s' added.\n", h.Hostname)
+			if h.PublicKey != nil {
+				KV("Key type", h.PublicKey.KeyType)
+			}
+			KV("Fingerprint", orDash(h.Fingerprint()))
+			KV("UUID", h.UUID)
+		})
+		return nil
+	},
+}
+
+var pipelinesKnownHostsDeleteCmd = &cobra.Command{
+	Use:   "delete [workspace] [repo-slug] <hostname|uuid>",
+	Short: "Remove a known host",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		if err := client.DeleteKnownHost(bitbucket.DeleteKnownHostArgs{
+			PipelineSSHArgs: bitbucket.PipelineSSHArgs{Workspace: workspace, RepoSlug: repoSlug},
+			Host:            trailing[0],
+		}); err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, map[string]any{
+			"host":    trailing[0],
+			"deleted": true,
+		}, func() {
+			fmt.Printf("Known host '%s' deleted.\n", trailing[0])
+		})
+		return nil
+	},
+}
+
+// ─── OIDC ────────────────────────────────────────────────────────────────────
+
+var pipelinesOIDCCmd = &cobra.Command{
+	Use:   "oidc",
+	Short: "Inspect the workspace's Pipelines OpenID Connect provider",
+}
+
+var pipelinesOIDCShowCmd = &cobra.Command{
+	Use:   "show [workspace]",
+	Short: "Show the OIDC issuer, audience, and signing keys",
+	Long: `Show what a cloud provider (AWS, GCP, Azure, Vault) needs to trust
+Pipelines OIDC tokens: the identity provider URL, the audience, and the keys
+used to sign tokens.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, _, _, err := ParseArgs(cmd, args, -1)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		oidc, err := client.GetPipelinesOIDC(bitbucket.GetPipelinesOIDCArgs{Workspace: workspace})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, oidc, func() {
+			cfg := oidc.Configuration
+			KV("Identity provider", cfg.Issuer)
+			KV("Audience", orDash(oidc.Audience))
+			KV("JWKS URI", cfg.JwksURI)
+			if len(cfg.SigningAlgsSupported) > 0 {
+				KV("Signing algs", strings.Join(cfg.SigningAlgsSupported, ", "))
+			}
+			if len(cfg.ClaimsSupported) > 0 {
+				KV("Claims", strings.Join(cfg.ClaimsSupported, ", "))
+			}
+			if len(oidc.Keys) == 0 {
+				return
+			}
+			fmt.Println()
+			t := NewTable()
+			t.Header("Key ID", "Type", "Alg", "Use")
+			for _, k := range oidc.Keys {
+				t.Row(k.Kid, k.Kty, orDash(k.Alg), orDash(k.Use))
+			}
+			t.Flush()
+		})
+		return nil
+	},
+}
+
+func init() {
+	pipelinesCmd.AddCommand(pipelinesConfigCmd)
+	pipelinesConfigCmd.AddCommand(pipelinesConfigShowCmd)
+	pipelinesConfigCmd.AddCommand(newPipelinesConfigToggleCmd(true))
+	pipelinesConfigCmd.AddCommand(newPipelinesConfigToggleCmd(false))
+	pipelinesConfigCmd.AddCommand(pipelinesConfigBuildNumberCmd)
+	pipelinesCmd.AddCommand(pipelinesSSHKeyCmd)
+	pipelinesSSHKeyCmd.AddCommand(pipelinesSSHKeyShowCmd)
+	pipelinesSSHKeyCmd.AddCommand(pipelinesSSHKeySetCmd)
+	pipelinesSSHKeyCmd.AddCommand(pipelinesSSHKeyDeleteCmd)
+	pipelinesCmd.AddCommand(pipelinesKnownHostsCmd)
+	pipelinesKnownHostsCmd.AddCommand(pipelinesKnownHostsListCmd)
+	pipelinesKnownHostsCmd.AddCommand(pipelinesKnownHostsAddCmd)
+	pipelinesKnownHostsCmd.AddCommand(pipelinesKnownHostsDeleteCmd)
+	pipelinesCmd.AddCommand(pipelinesOIDCCmd)
+	pipelinesOIDCCmd.AddCommand(pipelinesOIDCShowCmd)
+
+	pipelinesSSHKeySetCmd.Flags().String("private-key-file", "", "Path to the unencrypted private key (required; never printed)")
+	pipelinesSSHKeySetCmd.Flags().String("public-key-file", "", "Path to the public key (default <private-key-file>.pub)")
+
+	pipelinesKnownHostsAddCmd.Flags().String("key", "", `Host public key, e.g. "ssh-ed25519 AAAA..."`)
+	pipelinesKnownHostsAddCmd.Flags().String("key-file", "", `File holding the host key in authorized_keys or known_hosts format ("-" for stdin)`)
+}
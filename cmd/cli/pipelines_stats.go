@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+var pipelinesStatsCmd = &cobra.Command{
+	Use:   "stats [workspace] [repo-slug]",
+	Short: "Success rate, durations, build minutes, and failing/flaky steps",
+	Long: `Analyse recent pipeline runs: success rate, p50/p95 duration per pipeline
+and per step, build-minute consumption, the most frequently failing steps, and
+flaky steps that both failed and passed on the same commit.
+
+Success rates ignore stopped and in-progress runs. Per-step stats cost one
+request per run; pass --no-steps for a quick run-level summary.
+Use --json or --csv to feed dashboards.`,
+	Example: `  bbkt pipelines stats
+  bbkt pipelines stats --branch main --since 30d
+  bbkt pipelines stats --since 2026-01-01 --limit 500 --csv > stats.csv`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		branch, _ := cmd.Flags().GetString("branch")
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		limit, _ := cmd.Flags().GetInt("limit")
+		noSteps, _ := cmd.Flags().GetBool("no-steps")
+		asCSV, _ := cmd.Flags().GetBool("csv")
+		since, err := parseSince(sinceFlag, time.Now())
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		st, err := client.GetPipelineStats(bitbucket.PipelineStatsArgs{
+			Workspace: workspace,
+			RepoSlug:  repoSlug,
+			Branch:    branch,
+			Since:     since,
+			Limit:     limit,
+			SkipSteps: noSteps,
+		})
+		if err != nil {
+			return err
+		}
+
+		if asCSV && !outputJSON(cmd) {
+			return writePipelineStatsCSV(st)
+		}
+		PrintOrJSON(cmd, st, func() {
+			printPipelineStats(st)
+		})
+		return nil
+	},
+}
+
+func printPipelineStats(st *bitbucket.PipelineStats) {
+	if st.Runs == 0 {
+		fmt.Println("No pipeline runs in range.")
+		return
+	}
+	KVf("Runs", "%d (%s – %s)", st.Runs, FormatTimePtr(st.From), FormatTimePtr(st.To))
+	KVf("Success rate", "%s (%d passed, %d failed, %d stopped, %d running)",
+		formatRate(st.SuccessRate), st.Successful, st.Failed, st.Stopped, st.InProgress)
+	KVf("Duration", "p50 %s, p95 %s", FormatDuration(st.P50Secs), FormatDuration(st.P95Secs))
+	KVf("Build minutes", "%.1f", st.BuildMinutes)
+
+	fmt.Println()
+	t := NewTable()
+	t.Header("Pipeline", "Runs", "Success", "p50", "p95", "Build Min")
+	for _, p := range st.Pipelines {
+		t.Row(p.Name, strconv.Itoa(p.Runs), formatRate(p.SuccessRate),
+			FormatDuration(p.P50Secs), FormatDuration(p.P95Secs), fmt.Sprintf("%.1f", p.BuildMinutes))
+	}
+	t.Flush()
+
+	if len(st.Steps) > 0 {
+		fmt.Println()
+		t := NewTable()
+		t.Header("Step", "Runs", "Failures", "Fail Rate", "p50", "p95")
+		for _, s := range st.Steps {
+			t.Row(s.Name, strconv.Itoa(s.Runs), strconv.Itoa(s.Failures), formatRate(s.FailureRate),
+				FormatDuration(s.P50Secs), FormatDuration(s.P95Secs))
+		}
+		t.Flush()
+	}
+
+	if len(st.FailingSteps) > 0 {
+		fmt.Println("\nMost failing steps:")
+		for _, s := range st.FailingSteps {
+			fmt.Printf("  %-30s %d failure(s) in %d run(s)\n", s.Name, s.Failures, s.Runs)
+		}
+	}
+	if len(st.FlakySteps) > 0 {
+		fmt.Println("\nFlaky steps (failed and passed on the same commit):")
+		for _, f := range st.FlakySteps {
+			fmt.Printf("  %-30s %s  %d failed / %d passed\n", f.Name, shortHash(f.Commit), f.Failures, f.Passes)
+		}
+	}
+}
+
+// writePipelineStatsCSV writes one row per summary, pipeline, step, and flaky
+// step, distinguished by the kind column, so a single sheet can be pivoted.
+func writePipelineStatsCSV(st *bitbucket.PipelineStats) error {
+	w := csv.NewWriter(os.Stdout)
+	_ = w.Write([]string{"kind", "name", "commit", "runs", "failures", "success_rate", "p50_seconds", "p95_seconds", "build_minutes"})
+
+	itoa := strconv.Itoa
+	ftoa := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+	_ = w.Write([]string{"total", "", "", itoa(st.Runs), itoa(st.Failed), ftoa(st.SuccessRate),
+		itoa(st.P50Secs), itoa(st.P95Secs), ftoa(st.BuildMinutes)})
+	for _, p := range st.Pipelines {
+		_ = w.Write([]string{"pipeline", p.Name, "", itoa(p.Runs), itoa(p.Failed), ftoa(p.SuccessRate),
+			itoa(p.P50Secs), itoa(p.P95Secs), ftoa(p.BuildMinutes)})
+	}
+	for _, s := range st.Steps {
+		_ = w.Write([]string{"step", s.Name, "", itoa(s.Runs), itoa(s.Failures), ftoa(1 - s.FailureRate),
+			itoa(s.P50Secs), itoa(s.P95Secs), ""})
+	}
+	for _, f := range st.FlakySteps {
+		_ = w.Write([]string{"flaky", f.Name, f.Commit, itoa(f.Failures + f.Passes), itoa(f.Failures), "", "", "", ""})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func formatRate(r float64) string {
+	return fmt.Sprintf("%.1f%%", r*100)
+}
+
+func init() {
+	pipelinesCmd.AddCommand(pipelinesStatsCmd)
+
+	pipelinesStatsCmd.Flags().String("branch", "", "Only runs on this branch")
+	pipelinesStatsCmd.Flags().String("since", "30d", "Only runs created since this age (30d, 2w, 12h) or date (2006-01-02); empty for no limit")
+	pipelinesStatsCmd.Flags().Int("limit", 100, "Maximum runs to analyse, newest first")
+	pipelinesStatsCmd.Flags().Bool("no-steps", false, "Skip per-step, failing and flaky step stats (one request per run)")
+	pipelinesStatsCmd.Flags().Bool("csv", false, "Output CSV instead of tables")
+}
@@ -10,7 +10,7 @@ import (
 var profileCmd = &cobra.Command{
 	Use:     "profile",
 	GroupID: groupAuth,
-	Short:   "Manage credential profiles (list, switch, refresh)",
+	Short:   "Manage credential profiles (list, switch, refresh, protocol)",
 	Long: `Manage Bitbucket credential profiles for multiple accounts (e.g.
 personal vs work). Each profile is a separate set of credentials
 stored in ~/.config/bbkt/credentials.json.
@@ -21,6 +21,7 @@ Running 'bbkt profile' with no subcommand lists profiles. Use
 	Example: `  bbkt profile                     # list profiles
   bbkt profile use work            # set active profile
   bbkt profile refresh             # refresh cached workspace list
+  bbkt profile protocol ssh        # clone over SSH with this profile
   bbkt --profile work prs list     # one-shot override`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		listProfiles()
@@ -136,9 +137,49 @@ var profileRefreshCmd = &cobra.Command{
 	},
 }
 
+var profileProtocolCmd = &cobra.Command{
+	Use:   "protocol [ssh|https]",
+	Short: "Show or set the profile's preferred git clone protocol",
+	Long: `Show or set the protocol 'bbkt repos clone' and 'bbkt repos fork --clone'
+use for the profile. HTTPS clones authenticate with the profile's token through
+bbkt's git credential helper; SSH clones use your SSH keys. --protocol and
+BBKT_GIT_PROTOCOL override it per command.`,
+	Example: `  bbkt profile protocol             # show the current preference
+  bbkt --profile work profile protocol ssh`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{bitbucket.GitProtocolSSH, bitbucket.GitProtocolHTTPS},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		creds, err := bitbucket.LoadCredentials()
+		if err != nil {
+			return fmt.Errorf("no profile found; run 'bbkt auth' first")
+		}
+
+		if len(args) == 1 {
+			protocol := args[0]
+			if protocol != bitbucket.GitProtocolSSH && protocol != bitbucket.GitProtocolHTTPS {
+				return fmt.Errorf("invalid git protocol %q: expected ssh or https", protocol)
+			}
+			creds.GitProtocol = protocol
+			if err := bitbucket.SaveProfile(creds); err != nil {
+				return fmt.Errorf("saving profile: %w", err)
+			}
+		}
+
+		protocol := creds.GitProtocol
+		if protocol == "" {
+			protocol = bitbucket.GitProtocolHTTPS
+		}
+		PrintOrJSON(cmd, map[string]any{"profile": creds.ProfileName, "git_protocol": protocol}, func() {
+			fmt.Printf("Profile %q clones over %s\n", creds.ProfileName, protocol)
+		})
+		return nil
+	},
+}
+
 func init() {
 	RootCmd.AddCommand(profileCmd)
 	profileCmd.AddCommand(profileListCmd)
 	profileCmd.AddCommand(profileUseCmd)
 	profileCmd.AddCommand(profileRefreshCmd)
+	profileCmd.AddCommand(profileProtocolCmd)
 }
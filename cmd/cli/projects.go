@@ -0,0 +1,464 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+var projectsCmd = &cobra.Command{
+	Use:     "projects",
+	Aliases: []string{"project"},
+	GroupID: groupData,
+	Short:   "List, create, and manage projects, their reviewers and permissions",
+	Long: `Manage the projects that group repositories in a workspace. The workspace
+is inferred from your current git clone when omitted.`,
+	Example: `  bbkt projects list
+  bbkt projects get PLAT
+  bbkt projects create acme PLAT --name Platform --private
+  bbkt projects repos PLAT
+  bbkt projects reviewers add PLAT 557058:6c1f...
+  bbkt projects permissions grant PLAT --group developers --permission write`,
+}
+
+// projectScope splits args into the workspace (optional, inferred when
+// omitted), the project key, and the last n arguments.
+func projectScope(cmd *cobra.Command, args []string, n int) (workspace, key string, rest []string, err error) {
+	if len(args) < n+1 {
+		return "", "", nil, fmt.Errorf("a project key is required")
+	}
+	split := len(args) - n - 1
+	if workspace, _, _, err = ParseArgs(cmd, args[:split], -1); err != nil {
+		return "", "", nil, err
+	}
+	return workspace, args[split], args[split+1:], nil
+}
+
+var projectsListCmd = &cobra.Command{
+	Use:   "list [workspace]",
+	Short: "List projects in a workspace",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, _, _, err := ParseArgs(cmd, args, -1)
+		if err != nil {
+			return err
+		}
+		query, _ := cmd.Flags().GetString("query")
+		sort, _ := cmd.Flags().GetString("sort")
+		page, pagelen := paginationArgs(cmd)
+
+		client := getClient()
+		result, err := client.ListProjects(bitbucket.ListProjectsArgs{
+			Workspace: workspace,
+			Query:     query,
+			Sort:      sort,
+			Page:      page,
+			Pagelen:   pagelen,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result.Values) == 0 {
+				fmt.Println("No projects found.")
+				return
+			}
+			t := NewTable()
+			t.Header("Key", "Name", "Visibility", "Updated")
+			for _, p := range result.Values {
+				t.Row(p.Key, p.Name, FormatPrivate(p.IsPrivate), FormatTime(p.UpdatedOn))
+			}
+			t.Flush()
+			PrintPaginationFooter(result.Size, result.Page, len(result.Values), result.Next != "")
+		})
+		return nil
+	},
+}
+
+var projectsGetCmd = &cobra.Command{
+	Use:   "get [workspace] <project-key>",
+	Short: "Show a project",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, key, _, err := projectScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		p, err := client.GetProject(bitbucket.ProjectArgs{Workspace: workspace, ProjectKey: key})
+		if err != nil {
+			return err
+		}
+		PrintOrJSON(cmd, p, func() { printProject(p) })
+		return nil
+	},
+}
+
+func printProject(p *bitbucket.Project) {
+	KV("Key", p.Key)
+	KV("Name", p.Name)
+	KV("Description", orDash(p.Description))
+	KV("Visibility", FormatPrivate(p.IsPrivate))
+	KV("Public Repos", FormatBool(p.HasPubliclyVisibleRepos))
+	KV("Created", FormatTime(p.CreatedOn))
+	KV("Updated", FormatTime(p.UpdatedOn))
+}
+
+var projectsCreateCmd = &cobra.Command{
+	Use:   "create [workspace] <project-key> --name <name>",
+	Short: "Create a project",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, key, _, err := projectScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		name, _ := cmd.Flags().GetString("name")
+		desc, _ := cmd.Flags().GetString("description")
+		private, _ := cmd.Flags().GetBool("private")
+
+		client := getClient()
+		p, err := client.CreateProject(bitbucket.CreateProjectArgs{
+			Workspace:   workspace,
+			Key:         key,
+			Name:        name,
+			Description: desc,
+			IsPrivate:   &private,
+		})
+		if err != nil {
+			return err
+		}
+		PrintOrJSON(cmd, p, func() {
+			fmt.Printf("Project %s (%s) created in %s\n", p.Key, p.Name, workspace)
+		})
+		return nil
+	},
+}
+
+var projectsUpdateCmd = &cobra.Command{
+	Use:   "update [workspace] <project-key>",
+	Short: "Change a project's key, name, description, or visibility",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, key, _, err := projectScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		flags := cmd.Flags()
+		update := bitbucket.UpdateProjectArgs{Workspace: workspace, ProjectKey: key}
+		if flags.Changed("key") {
+			v, _ := flags.GetString("key")
+			update.Key = &v
+		}
+		if flags.Changed("name") {
+			v, _ := flags.GetString("name")
+			update.Name = &v
+		}
+		if flags.Changed("description") {
+			v, _ := flags.GetString("description")
+			update.Description = &v
+		}
+		if flags.Changed("private") {
+			v, _ := flags.GetBool("private")
+			update.IsPrivate = &v
+		}
+
+		client := getClient()
+		p, err := client.UpdateProject(update)
+		if err != nil {
+			return err
+		}
+		PrintOrJSON(cmd, p, func() {
+			fmt.Printf("Project %s updated\n", p.Key)
+			printProject(p)
+		})
+		return nil
+	},
+}
+
+var projectsDeleteCmd = &cobra.Command{
+	Use:   "delete [workspace] <project-key>",
+	Short: "Delete an empty project (destructive — no confirmation)",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, key, _, err := projectScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		if err := client.DeleteProject(bitbucket.ProjectArgs{Workspace: workspace, ProjectKey: key}); err != nil {
+			return err
+		}
+		PrintOrJSON(cmd, map[string]any{"workspace": workspace, "project_key": key, "deleted": true}, func() {
+			fmt.Printf("Project %s deleted from %s\n", key, workspace)
+		})
+		return nil
+	},
+}
+
+var projectsReposCmd = &cobra.Command{
+	Use:   "repos [workspace] <project-key>",
+	Short: "List the repositories in a project",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, key, _, err := projectScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		page, pagelen := paginationArgs(cmd)
+
+		client := getClient()
+		result, err := client.ListProjectRepositories(bitbucket.ProjectArgs{Workspace: workspace, ProjectKey: key}, page, pagelen)
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result.Values) == 0 {
+				fmt.Printf("No repositories in project %s.\n", key)
+				return
+			}
+			t := NewTable()
+			t.Header("Full Name", "Language", "Visibility", "Updated")
+			for _, r := range result.Values {
+				t.Row(r.FullName, orDash(r.Language), FormatPrivate(r.IsPrivate), FormatTime(r.UpdatedOn))
+			}
+			t.Flush()
+			PrintPaginationFooter(result.Size, result.Page, len(result.Values), result.Next != "")
+		})
+		return nil
+	},
+}
+
+var projectsReviewersCmd = &cobra.Command{
+	Use:   "reviewers",
+	Short: "List, add, and remove a project's default reviewers",
+	Long: `Project default reviewers are added to new pull requests in every
+repository of the project, alongside each repository's own default reviewers.
+Users are given by account ID, UUID, or @name.`,
+}
+
+var projectsReviewersListCmd = &cobra.Command{
+	Use:   "list [workspace] <project-key>",
+	Short: "List a project's default reviewers",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, key, _, err := projectScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		reviewers, err := client.ListProjectDefaultReviewers(bitbucket.ProjectArgs{Workspace: workspace, ProjectKey: key})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, reviewers, func() {
+			if len(reviewers) == 0 {
+				fmt.Printf("Project %s has no default reviewers.\n", key)
+				return
+			}
+			t := NewTable()
+			t.Header("Name", "Account ID", "Scope")
+			for _, r := range reviewers {
+				t.Row(r.User.DisplayName, orDash(r.User.AccountID), orDash(r.ReviewerType))
+			}
+			t.Flush()
+		})
+		return nil
+	},
+}
+
+var projectsReviewersAddCmd = &cobra.Command{
+	Use:   "add [workspace] <project-key> <user>",
+	Short: "Add a project default reviewer",
+	Args:  cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, key, rest, err := projectScope(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		user, err := resolveUser(client, workspace, rest[0])
+		if err != nil {
+			return err
+		}
+		if err := client.AddProjectDefaultReviewer(bitbucket.ProjectArgs{Workspace: workspace, ProjectKey: key}, user); err != nil {
+			return err
+		}
+		PrintOrJSON(cmd, map[string]any{"project_key": key, "user": user, "added": true}, func() {
+			fmt.Printf("Added %s as a default reviewer of %s\n", user, key)
+		})
+		return nil
+	},
+}
+
+var projectsReviewersRemoveCmd = &cobra.Command{
+	Use:   "remove [workspace] <project-key> <user>",
+	Short: "Remove a project default reviewer",
+	Args:  cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, key, rest, err := projectScope(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		user, err := resolveUser(client, workspace, rest[0])
+		if err != nil {
+			return err
+		}
+		if err := client.RemoveProjectDefaultReviewer(bitbucket.ProjectArgs{Workspace: workspace, ProjectKey: key}, user); err != nil {
+			return err
+		}
+		PrintOrJSON(cmd, map[string]any{"project_key": key, "user": user, "removed": true}, func() {
+			fmt.Printf("Removed %s from the default reviewers of %s\n", user, key)
+		})
+		return nil
+	},
+}
+
+var projectsPermissionsCmd = &cobra.Command{
+	Use:     "permissions",
+	Aliases: []string{"perms"},
+	Short:   "List, grant, and revoke project permissions for users and groups",
+	Long: `Explicit project permissions apply to every repository in the project.
+Permissions are ` + strings.Join(bitbucket.ProjectPermissions, ", ") + `. Users are
+given by account ID, UUID, or @name, groups by slug.`,
+}
+
+var projectsPermissionsListCmd = &cobra.Command{
+	Use:   "list [workspace] <project-key>",
+	Short: "List explicit user and group permissions on a project",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, key, _, err := projectScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		perms, err := client.ListProjectPermissions(bitbucket.ProjectArgs{Workspace: workspace, ProjectKey: key})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, perms, func() { printPermissions(perms, "Project "+key) })
+		return nil
+	},
+}
+
+// permissionSubject reads --user or --group.
+func permissionSubject(cmd *cobra.Command) (kind, subject string, err error) {
+	user, _ := cmd.Flags().GetString("user")
+	group, _ := cmd.Flags().GetString("group")
+	switch {
+	case user != "" && group != "":
+		return "", "", fmt.Errorf("pass either --user or --group, not both")
+	case user != "":
+		return bitbucket.PermissionUser, user, nil
+	case group != "":
+		return bitbucket.PermissionGroup, group, nil
+	}
+	return "", "", fmt.Errorf("--user or --group is required")
+}
+
+var projectsPermissionsGrantCmd = &cobra.Command{
+	Use:   "grant [workspace] <project-key> (--user <id> | --group <slug>) --permission <level>",
+	Short: "Grant or change a user's or group's project permission",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, key, _, err := projectScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		client := getClient()
+		kind, subject, err := resolvedPermissionSubject(cmd, client, workspace)
+		if err != nil {
+			return err
+		}
+		permission, _ := cmd.Flags().GetString("permission")
+
+		p, err := client.SetProjectPermission(bitbucket.ProjectArgs{Workspace: workspace, ProjectKey: key}, kind, subject, permission)
+		if err != nil {
+			return err
+		}
+		PrintOrJSON(cmd, p, func() {
+			fmt.Printf("Granted %s %s %s on %s\n", kind, subject, permission, key)
+		})
+		return nil
+	},
+}
+
+var projectsPermissionsRevokeCmd = &cobra.Command{
+	Use:   "revoke [workspace] <project-key> (--user <id> | --group <slug>)",
+	Short: "Remove a user's or group's explicit project permission",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, key, _, err := projectScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		client := getClient()
+		kind, subject, err := resolvedPermissionSubject(cmd, client, workspace)
+		if err != nil {
+			return err
+		}
+
+		if err := client.RemoveProjectPermission(bitbucket.ProjectArgs{Workspace: workspace, ProjectKey: key}, kind, subject); err != nil {
+			return err
+		}
+		PrintOrJSON(cmd, map[string]any{"project_key": key, kind: subject, "revoked": true}, func() {
+			fmt.Printf("Revoked %s %s's permission on %s\n", kind, subject, key)
+		})
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(projectsCmd)
+	projectsCmd.AddCommand(projectsListCmd)
+	projectsCmd.AddCommand(projectsGetCmd)
+	projectsCmd.AddCommand(projectsCreateCmd)
+	projectsCmd.AddCommand(projectsUpdateCmd)
+	projectsCmd.AddCommand(projectsDeleteCmd)
+	projectsCmd.AddCommand(projectsReposCmd)
+	projectsCmd.AddCommand(projectsReviewersCmd)
+	projectsCmd.AddCommand(projectsPermissionsCmd)
+	projectsReviewersCmd.AddCommand(projectsReviewersListCmd)
+	projectsReviewersCmd.AddCommand(projectsReviewersAddCmd)
+	projectsReviewersCmd.AddCommand(projectsReviewersRemoveCmd)
+	projectsPermissionsCmd.AddCommand(projectsPermissionsListCmd)
+	projectsPermissionsCmd.AddCommand(projectsPermissionsGrantCmd)
+	projectsPermissionsCmd.AddCommand(projectsPermissionsRevokeCmd)
+
+	projectsListCmd.Flags().StringP("query", "q", "", "Filter projects using Bitbucket query syntax")
+	projectsListCmd.Flags().String("sort", "", "Sort field (e.g. -updated_on for newest first)")
+	addPaginationFlags(projectsListCmd)
+	addPaginationFlags(projectsReposCmd)
+
+	projectsCreateCmd.Flags().String("name", "", "Project name")
+	projectsCreateCmd.Flags().String("description", "", "Project description")
+	projectsCreateCmd.Flags().Bool("private", true, "Create as a private project (--private=false for public)")
+	_ = projectsCreateCmd.MarkFlagRequired("name")
+
+	projectsUpdateCmd.Flags().String("key", "", "New project key")
+	projectsUpdateCmd.Flags().String("name", "", "New project name")
+	projectsUpdateCmd.Flags().String("description", "", "New description (empty clears it)")
+	projectsUpdateCmd.Flags().Bool("private", false, "Make the project private (--private=false for public)")
+
+	for _, c := range []*cobra.Command{projectsPermissionsGrantCmd, projectsPermissionsRevokeCmd} {
+		c.Flags().String("user", "", "User account ID, UUID, or @name")
+		c.Flags().String("group", "", "Group slug")
+	}
+	projectsPermissionsGrantCmd.Flags().String("permission", "", "Permission: "+strings.Join(bitbucket.ProjectPermissions, " | "))
+	_ = projectsPermissionsGrantCmd.MarkFlagRequired("permission")
+}
@@ -90,6 +90,12 @@ var prsListCmd = &cobra.Command{
 	},
 }
 
+// prWithBuilds is a pull request plus the summary of its builds.
+type prWithBuilds struct {
+	*bitbucket.PullRequest
+	Builds *bitbucket.BuildSummary `json:"builds,omitempty"`
+}
+
 var prsGetCmd = &cobra.Command{
 	Use:   "get [workspace] [repo-slug] <pr-id>",
 	Short: "Get details for a specific pull request",
@@ -115,7 +121,13 @@ var prsGetCmd = &cobra.Command{
 			return err
 		}
 
-		PrintOrJSON(cmd, result, func() {
+		// A failed status lookup only leaves out the build summary.
+		out := prWithBuilds{PullRequest: result}
+		if builds, err := client.PullRequestBuildSummary(workspace, repoSlug, prID); err == nil {
+			out.Builds = &builds
+		}
+
+		PrintOrJSON(cmd, out, func() {
 			fmt.Printf("Pull Request #%d: %s\n", result.ID, result.Title)
 			KV("State", result.State)
 			if result.Draft {
@@ -135,6 +147,9 @@ var prsGetCmd = &cobra.Command{
 			}
 			KVf("Comments", "%d", result.CommentCount)
 			KVf("Tasks", "%d", result.TaskCount)
+			if out.Builds != nil {
+				KV("Builds", out.Builds.String())
+			}
 			if len(result.Reviewers) > 0 {
 				names := make([]string, len(result.Reviewers))
 				for i, r := range result.Reviewers {
@@ -283,6 +298,8 @@ var prsMergeCmd = &cobra.Command{
 		closeSource, _ := cmd.Flags().GetBool("close-source-branch")
 
 		client := getClient()
+		warnFailingBuilds(client, workspace, repoSlug, prID)
+
 		result, err := client.MergePullRequest(bitbucket.MergePullRequestArgs{
 			Workspace:         workspace,
 			RepoSlug:          repoSlug,
@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/zach-snell/bbkt/internal/bitbucket"
@@ -112,6 +114,15 @@ var reposGetCmd = &cobra.Command{
 			if result.Project != nil {
 				KV("Project", result.Project.Key)
 			}
+			if result.ForkPolicy != "" {
+				KV("Fork Policy", result.ForkPolicy)
+			}
+			if result.Parent != nil {
+				KV("Fork Of", result.Parent.FullName)
+			}
+			if result.Website != "" {
+				KV("Website", result.Website)
+			}
 			KV("Created", FormatTime(result.CreatedOn))
 			KV("Updated", FormatTime(result.UpdatedOn))
 		})
@@ -132,6 +143,8 @@ var reposCreateCmd = &cobra.Command{
 		desc, _ := cmd.Flags().GetString("description")
 		lang, _ := cmd.Flags().GetString("language")
 		project, _ := cmd.Flags().GetString("project")
+		enablePipelines, _ := cmd.Flags().GetBool("enable-pipelines")
+		template, _ := cmd.Flags().GetString("pipelines-template")
 
 		isPrivatePtr := new(bool)
 		*isPrivatePtr, _ = cmd.Flags().GetBool("private")
@@ -144,10 +157,23 @@ var reposCreateCmd = &cobra.Command{
 			Language:    lang,
 			ProjectKey:  project,
 			IsPrivate:   isPrivatePtr,
+
+			EnablePipelines:   enablePipelines,
+			PipelinesTemplate: template,
 		})
-		if err != nil {
+		// A failed follow-up step still returns the created repository;
+		// report only the steps that succeeded, then fail.
+		var setupErr *bitbucket.RepositorySetupError
+		if err != nil && (result == nil || !errors.As(err, &setupErr)) {
 			return err
 		}
+		configured, enabled := template != "", enablePipelines
+		if setupErr != nil {
+			enabled = false
+			if setupErr.Step == bitbucket.SetupPipelinesConfig {
+				configured = false
+			}
+		}
 
 		PrintOrJSON(cmd, result, func() {
 			fmt.Printf("Created repository: %s\n", result.FullName)
@@ -157,6 +183,71 @@ var reposCreateCmd = &cobra.Command{
 				KV("Language", result.Language)
 			}
 			KV("Created", FormatTime(result.CreatedOn))
+			if configured {
+				KV("Pipelines config", template+" template")
+			}
+			if enabled {
+				KV("Pipelines", "enabled")
+			}
+		})
+		return err
+	},
+}
+
+var reposEditCmd = &cobra.Command{
+	Use:   "edit [workspace] [repo-slug]",
+	Short: "Change a repository's settings",
+	Long: `Change the settings passed as flags; everything else is left as is.
+--main-branch must name an existing branch.`,
+	Example: `  bbkt repos edit --description "Payments API" --fork-policy no_public_forks
+  bbkt repos edit my-workspace api --main-branch trunk --project PAY
+  bbkt repos edit --private=false --website https://pay.example.com`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		update := bitbucket.UpdateRepositoryArgs{Workspace: workspace, RepoSlug: repoSlug}
+		flags := cmd.Flags()
+		for name, dst := range map[string]**string{
+			"description": &update.Description,
+			"main-branch": &update.MainBranch,
+			"language":    &update.Language,
+			"project":     &update.ProjectKey,
+			"fork-policy": &update.ForkPolicy,
+			"website":     &update.Website,
+		} {
+			if flags.Changed(name) {
+				v, _ := flags.GetString(name)
+				*dst = &v
+			}
+		}
+		if flags.Changed("private") {
+			v, _ := flags.GetBool("private")
+			update.IsPrivate = &v
+		}
+
+		client := getClient()
+		result, err := client.UpdateRepository(update)
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			fmt.Printf("Updated repository: %s\n", result.FullName)
+			KV("Visibility", FormatPrivate(result.IsPrivate))
+			if result.MainBranch != nil {
+				KV("Main Branch", result.MainBranch.Name)
+			}
+			if result.ForkPolicy != "" {
+				KV("Fork Policy", result.ForkPolicy)
+			}
+			if result.Project != nil {
+				KV("Project", result.Project.Key)
+			}
+			KV("Updated", FormatTime(result.UpdatedOn))
 		})
 		return nil
 	},
@@ -196,6 +287,7 @@ func init() {
 	reposCmd.AddCommand(reposListCmd)
 	reposCmd.AddCommand(reposGetCmd)
 	reposCmd.AddCommand(reposCreateCmd)
+	reposCmd.AddCommand(reposEditCmd)
 	reposCmd.AddCommand(reposDeleteCmd)
 
 	reposListCmd.Flags().StringP("query", "q", "", "Filter repositories using Bitbucket query syntax")
@@ -207,4 +299,14 @@ func init() {
 	reposCreateCmd.Flags().String("language", "", "Primary programming language")
 	reposCreateCmd.Flags().String("project", "", "Project key to assign the repo to")
 	reposCreateCmd.Flags().Bool("private", true, "Create as a private repository")
+	reposCreateCmd.Flags().Bool("enable-pipelines", false, "Enable Pipelines on the new repository")
+	reposCreateCmd.Flags().String("pipelines-template", "", "Commit a starter bitbucket-pipelines.yml: "+strings.Join(bitbucket.PipelinesTemplateNames(), " | "))
+
+	reposEditCmd.Flags().String("description", "", "Repository description")
+	reposEditCmd.Flags().Bool("private", true, "Make the repository private (--private=false for public)")
+	reposEditCmd.Flags().String("main-branch", "", "Main branch (must exist)")
+	reposEditCmd.Flags().String("language", "", "Primary programming language")
+	reposEditCmd.Flags().String("project", "", "Move the repository to this project key")
+	reposEditCmd.Flags().String("fork-policy", "", "Fork policy: "+strings.Join(bitbucket.ForkPolicies, " | "))
+	reposEditCmd.Flags().String("website", "", "Website URL")
 }
@@ -0,0 +1,217 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+const repoSpecHelp = `The spec lists the settings to manage; anything left out is not touched.
+Lists (default_reviewers, branch_restrictions, webhooks, variables) are
+matched on reviewer, kind and branch, URL, and key. Entries the spec does not
+list are kept unless it sets prune: true or --prune is passed. ${NAME} in
+webhook secrets and variable values is read from the environment. A missing
+repository is created.
+
+  description: Payments API
+  private: true
+  main_branch: main
+  fork_policy: no_public_forks     # allow_forks | no_public_forks | no_forks
+  project: PAY
+  language: go
+  website: https://pay.example.com
+  pipelines: true
+  branching_model:
+    development: {branch: develop}  # {} uses the main branch
+    production: {branch: main}      # {enabled: false} turns it off
+    prefixes: {feature: feature/, bugfix: bugfix/, release: release/, hotfix: ""}
+  default_reviewers: ["557058:6c1f..."]   # account IDs or UUIDs
+  branch_restrictions:
+    - {kind: force, pattern: main}
+    - {kind: require_approvals_to_merge, pattern: main, value: 2}
+  webhooks:
+    - url: https://ci.example.com/hook
+      events: ["repo:push", "pullrequest:*"]
+      secret: ${CI_HOOK_SECRET}
+  variables:
+    - {key: REGION, value: eu-west-1}
+    - {key: DEPLOY_TOKEN, value: "${DEPLOY_TOKEN}", secured: true}
+
+Secured variable values and webhook secrets cannot be read back, so they are
+only written when the entry is created (or a webhook has no secret).
+
+--workspace-wide applies the spec to every repository in the workspace, or
+those matching --select slug globs and/or a --query filter.`
+
+var reposPlanCmd = &cobra.Command{
+	Use:   "plan [workspace] [repo-slug] --file <repo.yaml>",
+	Short: "Show what applying a repository spec would change",
+	Long: `Compare a repository with a settings spec and print the changes apply
+would make, without making them.
+
+` + repoSpecHelp,
+	Example: `  bbkt repos plan -f repo.yaml
+  bbkt repos plan my-workspace -f baseline.yaml --workspace-wide --select 'svc-*'`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRepoSpec(cmd, args, false)
+	},
+}
+
+var reposApplyCmd = &cobra.Command{
+	Use:   "apply [workspace] [repo-slug] --file <repo.yaml>",
+	Short: "Make repositories match a settings spec",
+	Long: `Compare a repository with a settings spec, print the changes, and make
+only those changes. Applying twice changes nothing the second time.
+
+` + repoSpecHelp,
+	Example: `  bbkt repos apply -f repo.yaml
+  bbkt repos apply my-workspace -f baseline.yaml --workspace-wide --query 'project.key="PLAT"'`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRepoSpec(cmd, args, true)
+	},
+}
+
+type repoSpecResult struct {
+	Repository string                 `json:"repository"`
+	Changes    []bitbucket.RepoChange `json:"changes"`
+	Applied    int                    `json:"applied"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+func runRepoSpec(cmd *cobra.Command, args []string, apply bool) error {
+	flags := cmd.Flags()
+	file, _ := flags.GetString("file")
+	prune, _ := flags.GetBool("prune")
+	wide, _ := flags.GetBool("workspace-wide")
+	globs, _ := flags.GetStringSlice("select")
+	query, _ := flags.GetString("query")
+	if !wide && (len(globs) > 0 || query != "") {
+		return fmt.Errorf("--select and --query require --workspace-wide")
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	spec, err := bitbucket.ParseRepoSpec(data)
+	if err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+	prune = prune || spec.Prune
+
+	client := getClient()
+	var workspace string
+	var repos []string
+	if wide {
+		if workspace, _, _, err = ParseArgs(cmd, args, -1); err != nil {
+			return err
+		}
+		if repos, err = client.SelectRepositories(workspace, query, globs); err != nil {
+			return err
+		}
+		if len(repos) == 0 {
+			return fmt.Errorf("no repositories in %s match", workspace)
+		}
+	} else {
+		var repoSlug string
+		if workspace, repoSlug, _, err = ParseArgs(cmd, args, 0); err != nil {
+			return err
+		}
+		repos = []string{repoSlug}
+	}
+
+	var results []repoSpecResult
+	failed := 0
+	for _, repo := range repos {
+		res := repoSpecResult{Repository: workspace + "/" + repo}
+		st, err := client.GetRepoState(workspace, repo, spec)
+		if err == nil {
+			res.Changes, err = bitbucket.PlanRepo(st, spec, prune)
+		}
+		if err == nil && apply {
+			res.Applied, err = client.ApplyRepoChanges(res.Changes)
+		}
+		if err != nil {
+			res.Error = err.Error()
+			failed++
+		}
+		results = append(results, res)
+	}
+
+	PrintOrJSON(cmd, results, func() {
+		var create, update, del int
+		for i, res := range results {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Println(res.Repository)
+			c, u, d := printRepoChanges(res.Changes)
+			create, update, del = create+c, update+u, del+d
+			if res.Error != "" {
+				fmt.Printf("  error: %s\n", res.Error)
+			}
+		}
+		if len(results) > 1 {
+			fmt.Printf("\nTotal across %d repositories: %d to create, %d to update, %d to delete.\n", len(results), create, update, del)
+		}
+		if !apply && create+update+del > 0 {
+			fmt.Println("\nNothing was changed. Run 'bbkt repos apply' to make these changes.")
+		}
+	})
+	if failed > 0 {
+		return fmt.Errorf("%d of %d repositories failed", failed, len(repos))
+	}
+	return nil
+}
+
+// printRepoChanges prints a plan as +/~/- lines with a summary and returns
+// the create, update, and delete counts.
+func printRepoChanges(plan []bitbucket.RepoChange) (create, update, del int) {
+	var keep int
+	for _, ch := range plan {
+		switch ch.Action {
+		case bitbucket.ChangeCreate:
+			create++
+			fmt.Printf("  + %s\n", ch)
+		case bitbucket.ChangeUpdate:
+			update++
+			fmt.Printf("  ~ %s\n", ch)
+		case bitbucket.ChangeDelete:
+			del++
+			fmt.Printf("  - %s\n", ch)
+		default:
+			keep++
+			if ch.Unmanaged {
+				fmt.Printf("    %s (not in spec, kept)\n", ch)
+			}
+			continue
+		}
+		for _, d := range ch.Diff {
+			fmt.Printf("      %s\n", d)
+		}
+	}
+	if create+update+del == 0 {
+		fmt.Println("  No changes.")
+		return
+	}
+	fmt.Printf("  Plan: %d to create, %d to update, %d to delete, %d unchanged.\n", create, update, del, keep)
+	return
+}
+
+func init() {
+	reposCmd.AddCommand(reposPlanCmd)
+	reposCmd.AddCommand(reposApplyCmd)
+
+	for _, c := range []*cobra.Command{reposPlanCmd, reposApplyCmd} {
+		c.Flags().StringP("file", "f", "", "Repository spec (YAML)")
+		_ = c.MarkFlagRequired("file")
+		c.Flags().Bool("prune", false, "Delete list entries the spec does not name")
+		c.Flags().Bool("workspace-wide", false, "Apply to every matching repository in the workspace")
+		c.Flags().StringSlice("select", nil, "With --workspace-wide, only repository slugs matching these globs")
+		c.Flags().StringP("query", "q", "", "With --workspace-wide, only repositories matching this Bitbucket query")
+	}
+}
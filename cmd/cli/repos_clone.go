@@ -0,0 +1,263 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+var reposCloneCmd = &cobra.Command{
+	Use:   "clone [workspace/repo | repo] [dir]",
+	Short: "Clone a repository, or every repository in a workspace",
+	Long: `Clone a repository using the clone URL Bitbucket reports for it. The
+protocol is --protocol, else $BBKT_GIT_PROTOCOL, else the profile's preference
+('bbkt profile protocol'), else https. HTTPS clones authenticate with the
+profile's token through bbkt's git credential helper, which is recorded in the
+clone's config so later fetches and pushes use the same profile.
+
+With --all, every repository in the workspace (or those matching --filter, a
+Bitbucket query) is cloned into --dir/<slug>, up to --parallel at a time.
+Repositories already cloned there are updated with 'git pull --ff-only';
+directories that are not git repositories are skipped.`,
+	Example: `  bbkt repos clone acme/api
+  bbkt repos clone api ~/src/api -W acme
+  bbkt repos clone acme/api --protocol ssh
+  bbkt repos clone --workspace acme --all --filter 'language="go"' --dir ~/src/acme`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		if all {
+			return runCloneAll(cmd, args)
+		}
+		if cmd.Flags().Changed("filter") || cmd.Flags().Changed("parallel") {
+			return fmt.Errorf("--filter and --parallel require --all")
+		}
+
+		workspace, repoSlug, dir, err := cloneTarget(cmd, args)
+		if err != nil {
+			return err
+		}
+		protocol, err := gitProtocol(cmd)
+		if err != nil {
+			return err
+		}
+		gitOpts, err := cloneGitOptions(protocol)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		repo, err := client.GetRepository(bitbucket.GetRepositoryArgs{Workspace: workspace, RepoSlug: repoSlug})
+		if err != nil {
+			return err
+		}
+		href := repo.CloneURL(protocol)
+		if href == "" {
+			return fmt.Errorf("%s has no %s clone URL", repo.FullName, protocol)
+		}
+		if dir == "" {
+			dir, _ = cmd.Flags().GetString("dir")
+		}
+		if dir == "" {
+			dir = repo.Slug
+		}
+
+		git := exec.Command("git", cloneArgs(gitOpts, href, dir)...)
+		git.Stdin, git.Stdout, git.Stderr = os.Stdin, os.Stderr, os.Stderr
+		if err := git.Run(); err != nil {
+			return fmt.Errorf("git clone failed: %v", err)
+		}
+
+		PrintOrJSON(cmd, cloneResult{Repository: repo.FullName, Dir: dir, URL: href, Status: cloneCloned}, func() {
+			fmt.Printf("Cloned %s into %s\n", repo.FullName, dir)
+		})
+		return nil
+	},
+}
+
+// cloneTarget resolves the repository and directory for a single clone:
+// "workspace/repo [dir]", "repo [dir]" with -W, or -R with no arguments.
+func cloneTarget(cmd *cobra.Command, args []string) (workspace, repoSlug, dir string, err error) {
+	if len(args) == 0 {
+		workspace, repoSlug, _, err = ParseArgs(cmd, nil, 0)
+		return workspace, repoSlug, "", err
+	}
+	if len(args) == 2 {
+		dir = args[1]
+	}
+	if w, s, ok := splitRepoSpec(args[0]); ok {
+		return w, s, dir, nil
+	}
+	workspace, _ = scopeFromFlags(cmd)
+	if workspace == "" {
+		return "", "", "", missingScopeError("workspace")
+	}
+	return workspace, args[0], dir, nil
+}
+
+// cloneArgs builds 'git clone' arguments. Options go after 'clone' so they
+// are saved in the new repository's config.
+func cloneArgs(gitOpts []string, href, dir string) []string {
+	args := append([]string{"clone"}, gitOpts...)
+	return append(args, href, dir)
+}
+
+// Outcomes of cloning one repository with --all.
+const (
+	cloneCloned  = "cloned"
+	cloneUpdated = "updated"
+	cloneSkipped = "skipped"
+	cloneFailed  = "failed"
+)
+
+type cloneResult struct {
+	Repository string `json:"repository"`
+	Dir        string `json:"dir"`
+	URL        string `json:"url,omitempty"`
+	Status     string `json:"status"`
+	Message    string `json:"message,omitempty"`
+}
+
+func runCloneAll(cmd *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("--all takes at most a workspace argument")
+	}
+	workspace, _, _, err := ParseArgs(cmd, args, -1)
+	if err != nil {
+		return err
+	}
+	flags := cmd.Flags()
+	filter, _ := flags.GetString("filter")
+	base, _ := flags.GetString("dir")
+	parallel, _ := flags.GetInt("parallel")
+	if parallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1")
+	}
+	protocol, err := gitProtocol(cmd)
+	if err != nil {
+		return err
+	}
+	gitOpts, err := cloneGitOptions(protocol)
+	if err != nil {
+		return err
+	}
+	if base == "" {
+		base = workspace
+	}
+
+	client := getClient()
+	repos, err := client.ListAllRepositories(workspace, filter)
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		return fmt.Errorf("no repositories in %s match", workspace)
+	}
+	if err := os.MkdirAll(base, 0o755); err != nil {
+		return err
+	}
+
+	asJSON := outputJSON(cmd)
+	results := make([]cloneResult, len(repos))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for i := range repos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			res := syncClone(&repos[i], protocol, filepath.Join(base, repos[i].Slug), gitOpts)
+			results[i] = res
+			if !asJSON {
+				mu.Lock()
+				fmt.Fprintf(os.Stderr, "%-8s %s\n", res.Status, res.Repository)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Repository < results[j].Repository })
+	counts := map[string]int{}
+	for _, res := range results {
+		counts[res.Status]++
+	}
+
+	PrintOrJSON(cmd, results, func() {
+		if counts[cloneFailed] > 0 || counts[cloneSkipped] > 0 {
+			t := NewTable()
+			t.Header("Repository", "Status", "Reason")
+			for _, res := range results {
+				if res.Status == cloneFailed || res.Status == cloneSkipped {
+					t.Row(res.Repository, res.Status, res.Message)
+				}
+			}
+			t.Flush()
+			fmt.Println()
+		}
+		fmt.Printf("%d repositories in %s: %d cloned, %d updated, %d skipped, %d failed.\n",
+			len(results), base, counts[cloneCloned], counts[cloneUpdated], counts[cloneSkipped], counts[cloneFailed])
+	})
+	if counts[cloneFailed] > 0 {
+		return fmt.Errorf("%d of %d repositories failed", counts[cloneFailed], len(results))
+	}
+	return nil
+}
+
+// syncClone clones repo into dir, or fast-forwards it if dir is already a
+// git repository. git never prompts, so a worker cannot block on a terminal.
+func syncClone(repo *bitbucket.Repository, protocol, dir string, gitOpts []string) cloneResult {
+	res := cloneResult{Repository: repo.FullName, Dir: dir, URL: repo.CloneURL(protocol)}
+	if res.URL == "" {
+		res.Status, res.Message = cloneFailed, fmt.Sprintf("no %s clone URL", protocol)
+		return res
+	}
+
+	var args []string
+	if fi, err := os.Stat(dir); err == nil {
+		if !fi.IsDir() {
+			res.Status, res.Message = cloneSkipped, "exists and is not a directory"
+			return res
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+			res.Status, res.Message = cloneSkipped, "exists and is not a git repository"
+			return res
+		}
+		res.Status = cloneUpdated
+		args = append(append([]string{}, gitOpts...), "-C", dir, "pull", "--ff-only", "--quiet")
+	} else {
+		res.Status = cloneCloned
+		args = cloneArgs(append([]string{"--quiet"}, gitOpts...), res.URL, dir)
+	}
+
+	git := exec.Command("git", args...)
+	git.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if out, err := git.CombinedOutput(); err != nil {
+		res.Status = cloneFailed
+		res.Message = strings.TrimSpace(string(out))
+		if res.Message == "" {
+			res.Message = err.Error()
+		}
+	}
+	return res
+}
+
+func init() {
+	reposCmd.AddCommand(reposCloneCmd)
+
+	reposCloneCmd.Flags().String("protocol", "", "Clone protocol: ssh | https (default the profile's preference, else https)")
+	reposCloneCmd.Flags().Bool("all", false, "Clone or update every repository in the workspace")
+	reposCloneCmd.Flags().String("filter", "", "With --all, only repositories matching this Bitbucket query")
+	reposCloneCmd.Flags().String("dir", "", "Directory to clone into; with --all, the parent directory (default the workspace slug)")
+	reposCloneCmd.Flags().Int("parallel", 4, "With --all, how many repositories to clone at once")
+}
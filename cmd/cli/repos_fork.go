@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+var reposForkCmd = &cobra.Command{
+	Use:   "fork [workspace] [repo-slug]",
+	Short: "Fork a repository, optionally cloning it with an upstream remote",
+	Long: `Fork a repository into --to-workspace (default your personal workspace).
+With --clone the fork is cloned into --dir (default its slug) and the source
+repository is added as the 'upstream' remote.`,
+	Example: `  bbkt repos fork acme api --to-workspace me
+  bbkt repos fork acme api --name api-experiments --clone
+  bbkt repos fork --clone --protocol https`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		flags := cmd.Flags()
+		target, _ := flags.GetString("to-workspace")
+		name, _ := flags.GetString("name")
+		desc, _ := flags.GetString("description")
+		project, _ := flags.GetString("project")
+		clone, _ := flags.GetBool("clone")
+		dir, _ := flags.GetString("dir")
+		protocol, err := gitProtocol(cmd)
+		if err != nil {
+			return err
+		}
+		var isPrivate *bool
+		if flags.Changed("private") {
+			v, _ := flags.GetBool("private")
+			isPrivate = &v
+		}
+
+		client := getClient()
+		source, err := client.GetRepository(bitbucket.GetRepositoryArgs{Workspace: workspace, RepoSlug: repoSlug})
+		if err != nil {
+			return err
+		}
+		fork, err := client.ForkRepository(bitbucket.ForkRepositoryArgs{
+			Workspace:       workspace,
+			RepoSlug:        repoSlug,
+			TargetWorkspace: target,
+			Name:            name,
+			Description:     desc,
+			IsPrivate:       isPrivate,
+			ProjectKey:      project,
+		})
+		if err != nil {
+			return err
+		}
+
+		if clone {
+			if dir == "" {
+				dir = fork.Slug
+			}
+			gitOpts, err := cloneGitOptions(protocol)
+			if err != nil {
+				return err
+			}
+			if err := cloneFork(fork.CloneURL(protocol), source.CloneURL(protocol), dir, gitOpts); err != nil {
+				return fmt.Errorf("forked %s, but %v", fork.FullName, err)
+			}
+		}
+
+		PrintOrJSON(cmd, fork, func() {
+			fmt.Printf("Forked %s to %s\n", source.FullName, fork.FullName)
+			KV("Visibility", FormatPrivate(fork.IsPrivate))
+			if href := fork.CloneURL(protocol); href != "" {
+				KV("Clone", href)
+			}
+			if clone {
+				KV("Cloned to", dir)
+				KV("Upstream", source.CloneURL(protocol))
+			}
+		})
+		return nil
+	},
+}
+
+// forkCloneAttempts covers the few seconds Bitbucket can take to copy a
+// fork's git data after the fork is created.
+const forkCloneAttempts = 5
+
+// cloneFork clones forkURL into dir with cloneArgs and adds
+// upstreamURL as 'upstream'.
+func cloneFork(forkURL, upstreamURL, dir string, gitOpts []string) error {
+	if forkURL == "" || upstreamURL == "" {
+		return fmt.Errorf("no clone URL was returned")
+	}
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("%s already exists", dir)
+	}
+	var err error
+	for attempt := 1; attempt <= forkCloneAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+		git := exec.Command("git", cloneArgs(gitOpts, forkURL, dir)...)
+		git.Stdout, git.Stderr = os.Stderr, os.Stderr
+		if err = git.Run(); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("git clone failed: %v", err)
+	}
+	if out, err := exec.Command("git", "-C", dir, "remote", "add", "upstream", upstreamURL).CombinedOutput(); err != nil {
+		return fmt.Errorf("adding upstream remote failed: %v: %s", err, out)
+	}
+	return nil
+}
+
+var reposForksCmd = &cobra.Command{
+	Use:   "forks [workspace] [repo-slug]",
+	Short: "List forks of a repository",
+	Args:  cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := ParseArgs(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		page, pagelen := paginationArgs(cmd)
+
+		client := getClient()
+		result, err := client.ListForks(bitbucket.ListForksArgs{
+			Workspace: workspace,
+			RepoSlug:  repoSlug,
+			Page:      page,
+			Pagelen:   pagelen,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result.Values) == 0 {
+				fmt.Println("No forks found.")
+				return
+			}
+			t := NewTable()
+			t.Header("Full Name", "Owner", "Visibility", "Updated")
+			for _, r := range result.Values {
+				owner := "-"
+				if r.Owner != nil {
+					owner = r.Owner.DisplayName
+				}
+				t.Row(r.FullName, owner, FormatPrivate(r.IsPrivate), FormatTime(r.UpdatedOn))
+			}
+			t.Flush()
+			PrintPaginationFooter(result.Size, result.Page, len(result.Values), result.Next != "")
+		})
+		return nil
+	},
+}
+
+func init() {
+	reposCmd.AddCommand(reposForkCmd)
+	reposCmd.AddCommand(reposForksCmd)
+
+	reposForkCmd.Flags().String("to-workspace", "", "Workspace to create the fork in (default your personal workspace)")
+	reposForkCmd.Flags().String("name", "", "Name of the fork (default the source repository's name)")
+	reposForkCmd.Flags().String("description", "", "Description of the fork")
+	reposForkCmd.Flags().Bool("private", false, "Make the fork private, or public with --private=false (default the source's visibility)")
+	reposForkCmd.Flags().String("project", "", "Project key in the target workspace")
+	reposForkCmd.Flags().Bool("clone", false, "Clone the fork and add the source as the 'upstream' remote")
+	reposForkCmd.Flags().String("dir", "", "Directory to clone into (default the fork's slug)")
+	reposForkCmd.Flags().String("protocol", "", "Clone protocol: ssh | https (default the profile's preference, else https)")
+
+	addPaginationFlags(reposForksCmd)
+}
@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+var runnersCmd = &cobra.Command{
+	Use:     "runners",
+	GroupID: groupData,
+	Short:   "Manage self-hosted Pipelines runners",
+	Long: `List, register, enable/disable, and delete self-hosted Pipelines runners.
+
+Runners are workspace runners by default; positional args are then
+[workspace] <runner-uuid>. Pass --level repository for a repository's own
+runners, with the usual [workspace] [repo-slug] layout (inferred from your
+git clone when omitted).`,
+	Example: `  bbkt runners list
+  bbkt runners list --level repository
+  bbkt runners create build-01 --labels linux,docker.large
+  bbkt runners disable {runner-uuid}`,
+}
+
+// runnerScope resolves positionals for the runner commands according to
+// --level; see levelScope.
+func runnerScope(cmd *cobra.Command, args []string, trailing int) (bitbucket.RunnerScopeArgs, []string, error) {
+	workspace, repoSlug, rest, err := levelScope(cmd, args, trailing)
+	return bitbucket.RunnerScopeArgs{Workspace: workspace, RepoSlug: repoSlug}, rest, err
+}
+
+// levelScope resolves positionals for commands with a --level flag:
+// workspace-level resources take [workspace] <trailing...>, repository-level
+// ones the standard [workspace] [repo-slug] <trailing...>. repoSlug is empty
+// at workspace level.
+func levelScope(cmd *cobra.Command, args []string, trailing int) (workspace, repoSlug string, rest []string, err error) {
+	level, _ := cmd.Flags().GetString("level")
+	switch level {
+	case "repository", "repo":
+		return ParseArgs(cmd, args, trailing)
+	case "workspace", "":
+	default:
+		return "", "", nil, fmt.Errorf("invalid --level %q: must be workspace or repository", level)
+	}
+
+	var wsArgs []string
+	switch len(args) {
+	case trailing:
+	case trailing + 1:
+		wsArgs = args[:1]
+	default:
+		return "", "", nil, fmt.Errorf("expected [workspace] and %d positional arg(s); got %d", trailing, len(args))
+	}
+	workspace, _, _, err = ParseArgs(cmd, wsArgs, -1)
+	return workspace, "", args[len(wsArgs):], err
+}
+
+var runnersListCmd = &cobra.Command{
+	Use:   "list [workspace] [repo-slug]",
+	Short: "List runners with their labels, status, and last-seen time",
+	Args:  cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scope, _, err := runnerScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		page, pagelen := paginationArgs(cmd)
+
+		client := getClient()
+		result, err := client.ListRunners(bitbucket.ListRunnersArgs{
+			RunnerScopeArgs: scope,
+			Page:            page,
+			Pagelen:         pagelen,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result.Values) == 0 {
+				fmt.Println("No runners found.")
+				return
+			}
+			t := NewTable()
+			t.Header("Name", "Status", "Labels", "Last Seen", "UUID")
+			for _, r := range result.Values {
+				t.Row(r.Name, orDash(r.Status()), strings.Join(r.Labels, ","), FormatTimePtr(r.LastSeen()), r.UUID)
+			}
+			t.Flush()
+			PrintPaginationFooter(result.Size, result.Page, len(result.Values), result.Next != "")
+		})
+		return nil
+	},
+}
+
+var runnersGetCmd = &cobra.Command{
+	Use:   "get [workspace] [repo-slug] <runner-uuid>",
+	Short: "Show a runner",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scope, rest, err := runnerScope(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		r, err := client.GetRunner(bitbucket.RunnerArgs{RunnerScopeArgs: scope, RunnerUUID: rest[0]})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, r, func() {
+			printRunner(r)
+		})
+		return nil
+	},
+}
+
+var runnersCreateCmd = &cobra.Command{
+	Use:   "create [workspace] [repo-slug] <name>",
+	Short: "Register a runner and print the credentials to start it",
+	Long: `Register a new runner and print the OAuth client credentials and the
+docker command that starts it.
+
+The client secret is shown only once — Bitbucket cannot return it again.`,
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scope, rest, err := runnerScope(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		labels, _ := cmd.Flags().GetStringSlice("labels")
+
+		client := getClient()
+		r, err := client.CreateRunner(bitbucket.CreateRunnerArgs{
+			RunnerScopeArgs: scope,
+			Name:            rest[0],
+			Labels:          labels,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, r, func() {
+			printRunner(r)
+			if r.OAuthClient == nil {
+				return
+			}
+			fmt.Println()
+			KV("OAuth client ID", r.OAuthClient.ID)
+			KV("OAuth secret", r.OAuthClient.Secret)
+			fmt.Println("\nThe secret is shown only once. Start the runner with:")
+			fmt.Println()
+
+			wsUUID, repoUUID := "<workspace-uuid>", ""
+			if ws, err := client.GetWorkspace(bitbucket.GetWorkspaceArgs{Workspace: scope.Workspace}); err == nil {
+				wsUUID = ws.UUID
+			}
+			if scope.RepoSlug != "" {
+				repoUUID = "<repository-uuid>"
+				if repo, err := client.GetRepository(bitbucket.GetRepositoryArgs{Workspace: scope.Workspace, RepoSlug: scope.RepoSlug}); err == nil {
+					repoUUID = repo.UUID
+				}
+			}
+			fmt.Println(bitbucket.RunnerDockerCommand(r, wsUUID, repoUUID))
+		})
+		return nil
+	},
+}
+
+var runnersDeleteCmd = &cobra.Command{
+	Use:   "delete [workspace] [repo-slug] <runner-uuid>",
+	Short: "Unregister a runner (destructive — no confirmation)",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scope, rest, err := runnerScope(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		if err := client.DeleteRunner(bitbucket.RunnerArgs{RunnerScopeArgs: scope, RunnerUUID: rest[0]}); err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, map[string]any{
+			"runner_uuid": rest[0],
+			"deleted":     true,
+		}, func() {
+			fmt.Printf("Runner %s deleted.\n", rest[0])
+		})
+		return nil
+	},
+}
+
+func newRunnerToggleCmd(enable bool) *cobra.Command {
+	verb, short := "disable", "Stop scheduling steps on a runner"
+	if enable {
+		verb, short = "enable", "Resume scheduling steps on a disabled runner"
+	}
+	return &cobra.Command{
+		Use:   verb + " [workspace] [repo-slug] <runner-uuid>",
+		Short: short,
+		Args:  cobra.RangeArgs(1, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scope, rest, err := runnerScope(cmd, args, 1)
+			if err != nil {
+				return err
+			}
+
+			client := getClient()
+			r, err := client.SetRunnerEnabled(bitbucket.RunnerArgs{RunnerScopeArgs: scope, RunnerUUID: rest[0]}, enable)
+			if err != nil {
+				return err
+			}
+
+			PrintOrJSON(cmd, r, func() {
+				fmt.Printf("Runner '%s' %sd (status %s).\n", r.Name, verb, orDash(r.Status()))
+			})
+			return nil
+		},
+	}
+}
+
+func printRunner(r *bitbucket.Runner) {
+	KV("Name", r.Name)
+	KV("UUID", r.UUID)
+	KV("Status", orDash(r.Status()))
+	KV("Labels", strings.Join(r.Labels, ", "))
+	KV("Last seen", FormatTimePtr(r.LastSeen()))
+	if r.State != nil && r.State.Version != nil {
+		KV("Version", r.State.Version.Current)
+	}
+	KV("Created", FormatTimePtr(r.CreatedOn))
+}
+
+func init() {
+	RootCmd.AddCommand(runnersCmd)
+	runnersCmd.AddCommand(runnersListCmd)
+	runnersCmd.AddCommand(runnersGetCmd)
+	runnersCmd.AddCommand(runnersCreateCmd)
+	runnersCmd.AddCommand(runnersDeleteCmd)
+	runnersCmd.AddCommand(newRunnerToggleCmd(true))
+	runnersCmd.AddCommand(newRunnerToggleCmd(false))
+
+	runnersCmd.PersistentFlags().String("level", "workspace", "Runner level: workspace | repository")
+	addPaginationFlags(runnersListCmd)
+	runnersCreateCmd.Flags().StringSlice("labels", nil, "Runner labels (default self.hosted,linux; self.hosted is always added)")
+}
@@ -0,0 +1,525 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+var snippetsCmd = &cobra.Command{
+	Use:     "snippets",
+	Aliases: []string{"snippet"},
+	GroupID: groupData,
+	Short:   "List, create, edit, and clone snippets",
+	Long: `Manage a workspace's snippets: small, shareable sets of files with their
+own comments and revision history. Snippets are identified by the ID in their
+URL (e.g. kypj9). The workspace is inferred from your current git clone when
+omitted.`,
+	Example: `  bbkt snippets list
+  bbkt snippets create -f a.sql -f b.sql --title "Slow queries"
+  kubectl logs api-0 | bbkt snippets create --filename api.log
+  bbkt snippets get kypj9
+  bbkt snippets edit kypj9 -f a.sql
+  bbkt snippets clone kypj9`,
+}
+
+// snippetScope splits args into the workspace (optional, inferred when
+// omitted), the snippet ID, and the last n arguments.
+func snippetScope(cmd *cobra.Command, args []string, n int) (bitbucket.SnippetArgs, []string, error) {
+	if len(args) < n+1 {
+		return bitbucket.SnippetArgs{}, nil, fmt.Errorf("a snippet ID is required")
+	}
+	split := len(args) - n - 1
+	workspace, _, _, err := ParseArgs(cmd, args[:split], -1)
+	if err != nil {
+		return bitbucket.SnippetArgs{}, nil, err
+	}
+	return bitbucket.SnippetArgs{Workspace: workspace, SnippetID: args[split]}, args[split+1:], nil
+}
+
+var snippetsListCmd = &cobra.Command{
+	Use:   "list [workspace]",
+	Short: "List snippets in a workspace",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, _, _, err := ParseArgs(cmd, args, -1)
+		if err != nil {
+			return err
+		}
+		role, _ := cmd.Flags().GetString("role")
+		page, pagelen := paginationArgs(cmd)
+
+		client := getClient()
+		result, err := client.ListSnippets(bitbucket.ListSnippetsArgs{
+			Workspace: workspace,
+			Role:      role,
+			Page:      page,
+			Pagelen:   pagelen,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result.Values) == 0 {
+				fmt.Println("No snippets found.")
+				return
+			}
+			t := NewTable()
+			t.Header("ID", "Title", "Visibility", "Creator", "Updated")
+			for _, s := range result.Values {
+				creator := "-"
+				if s.Creator != nil {
+					creator = s.Creator.DisplayName
+				}
+				t.Row(s.EncodedID(), orDash(Truncate(s.Title, 50)), FormatPrivate(s.IsPrivate), creator, FormatTime(s.UpdatedOn))
+			}
+			t.Flush()
+			PrintPaginationFooter(result.Size, result.Page, len(result.Values), result.Next != "")
+		})
+		return nil
+	},
+}
+
+// snippetWithContents is a snippet plus the text of its files.
+type snippetWithContents struct {
+	*bitbucket.Snippet
+	Contents map[string]string `json:"contents"`
+}
+
+var snippetsGetCmd = &cobra.Command{
+	Use:   "get [workspace] <snippet-id>",
+	Short: "Show a snippet and the contents of its files",
+	Long: `Show a snippet's details followed by each of its files. With --file, only
+that file's content is printed, unformatted, so it can be piped or redirected.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snippet, _, err := snippetScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		file, _ := cmd.Flags().GetString("file")
+
+		client := getClient()
+		if file != "" {
+			data, err := client.GetSnippetFile(snippet, file)
+			if err != nil {
+				return err
+			}
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+
+		s, err := client.GetSnippet(snippet)
+		if err != nil {
+			return err
+		}
+		out := snippetWithContents{Snippet: s, Contents: map[string]string{}}
+		for _, name := range s.FileNames() {
+			data, err := client.GetSnippetFile(snippet, name)
+			if err != nil {
+				return err
+			}
+			out.Contents[name] = string(data)
+		}
+
+		PrintOrJSON(cmd, out, func() {
+			printSnippet(s)
+			for _, name := range s.FileNames() {
+				fmt.Printf("\n==> %s <==\n", name)
+				content := out.Contents[name]
+				fmt.Print(content)
+				if content != "" && !strings.HasSuffix(content, "\n") {
+					fmt.Println()
+				}
+			}
+		})
+		return nil
+	},
+}
+
+func printSnippet(s *bitbucket.Snippet) {
+	KV("ID", s.EncodedID())
+	KV("Title", orDash(s.Title))
+	KV("Visibility", FormatPrivate(s.IsPrivate))
+	if s.Creator != nil {
+		KV("Creator", s.Creator.DisplayName)
+	}
+	KV("Files", orDash(strings.Join(s.FileNames(), ", ")))
+	KV("Created", FormatTime(s.CreatedOn))
+	KV("Updated", FormatTime(s.UpdatedOn))
+	KV("URL", orDash(s.URL()))
+}
+
+// snippetFiles reads the files named by --file, keyed by base name. With no
+// --file, and allowStdin, the content comes from stdin under --filename.
+func snippetFiles(cmd *cobra.Command, allowStdin bool) (map[string]string, error) {
+	paths, _ := cmd.Flags().GetStringArray("file")
+	files := map[string]string{}
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.Base(p)
+		if _, dup := files[name]; dup {
+			return nil, fmt.Errorf("more than one file is named %s", name)
+		}
+		files[name] = string(data)
+	}
+	if len(files) > 0 || !allowStdin {
+		return files, nil
+	}
+
+	if fi, err := os.Stdin.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+		return nil, fmt.Errorf("no files given: pass --file or pipe content on stdin")
+	}
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("reading stdin: %v", err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("stdin is empty")
+	}
+	name, _ := cmd.Flags().GetString("filename")
+	files[name] = string(data)
+	return files, nil
+}
+
+var snippetsCreateCmd = &cobra.Command{
+	Use:   "create [workspace] [-f <file>]...",
+	Short: "Create a snippet from files or stdin",
+	Long: `Create a snippet from one or more files (repeat -f), each named after its
+base name, or from stdin when no files are given. The snippet is private to
+the workspace unless --public is given. Its URL is printed so it can be
+shared.`,
+	Example: `  bbkt snippets create -f a.sql -f b.sql --title "Slow queries"
+  go test ./... 2>&1 | bbkt snippets create --filename test.log
+  bbkt snippets create -f demo.go --public`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, _, _, err := ParseArgs(cmd, args, -1)
+		if err != nil {
+			return err
+		}
+		files, err := snippetFiles(cmd, true)
+		if err != nil {
+			return err
+		}
+		title, _ := cmd.Flags().GetString("title")
+		public, _ := cmd.Flags().GetBool("public")
+
+		client := getClient()
+		s, err := client.CreateSnippet(bitbucket.CreateSnippetArgs{
+			Workspace: workspace,
+			Title:     title,
+			IsPrivate: !public,
+			Files:     files,
+		})
+		if err != nil {
+			return err
+		}
+		PrintOrJSON(cmd, s, func() {
+			fmt.Printf("Snippet %s created: %s\n", s.EncodedID(), s.URL())
+		})
+		return nil
+	},
+}
+
+var snippetsEditCmd = &cobra.Command{
+	Use:   "edit [workspace] <snippet-id>",
+	Short: "Change a snippet's title or visibility, or add and replace files",
+	Long: `Change a snippet's title or visibility, and add or replace files with -f
+(matched by base name). Files not given are kept. Each edit that changes files
+adds a revision to the snippet's history.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snippet, _, err := snippetScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		flags := cmd.Flags()
+		if flags.Changed("private") && flags.Changed("public") {
+			return fmt.Errorf("--private and --public cannot be used together")
+		}
+		update := bitbucket.UpdateSnippetArgs{Workspace: snippet.Workspace, SnippetID: snippet.SnippetID}
+		if flags.Changed("title") {
+			v, _ := flags.GetString("title")
+			update.Title = &v
+		}
+		if flags.Changed("private") {
+			v, _ := flags.GetBool("private")
+			update.IsPrivate = &v
+		}
+		if flags.Changed("public") {
+			v, _ := flags.GetBool("public")
+			v = !v
+			update.IsPrivate = &v
+		}
+		if update.Files, err = snippetFiles(cmd, false); err != nil {
+			return err
+		}
+
+		client := getClient()
+		s, err := client.UpdateSnippet(update)
+		if err != nil {
+			return err
+		}
+		PrintOrJSON(cmd, s, func() {
+			fmt.Printf("Snippet %s updated\n", s.EncodedID())
+			printSnippet(s)
+		})
+		return nil
+	},
+}
+
+var snippetsDeleteCmd = &cobra.Command{
+	Use:   "delete [workspace] <snippet-id>",
+	Short: "Delete a snippet and its history (destructive — no confirmation)",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snippet, _, err := snippetScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		if err := client.DeleteSnippet(snippet); err != nil {
+			return err
+		}
+		PrintOrJSON(cmd, map[string]any{"workspace": snippet.Workspace, "snippet_id": snippet.SnippetID, "deleted": true}, func() {
+			fmt.Printf("Snippet %s deleted\n", snippet.SnippetID)
+		})
+		return nil
+	},
+}
+
+var snippetsCloneCmd = &cobra.Command{
+	Use:   "clone [workspace/]<snippet-id> [dir]",
+	Short: "Clone a snippet's git repository",
+	Long: `Clone a snippet, which is a git repository, into dir (default the snippet
+ID). The protocol and HTTPS credentials are chosen as for 'bbkt repos clone'.`,
+	Example: `  bbkt snippets clone kypj9
+  bbkt snippets clone acme/kypj9 ~/scratch/queries --protocol ssh`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var snippet bitbucket.SnippetArgs
+		if w, id, ok := splitRepoSpec(args[0]); ok {
+			snippet = bitbucket.SnippetArgs{Workspace: w, SnippetID: id}
+		} else {
+			var err error
+			if snippet, _, err = snippetScope(cmd, args[:1], 0); err != nil {
+				return err
+			}
+		}
+		var dir string
+		if len(args) == 2 {
+			dir = args[1]
+		}
+		protocol, err := gitProtocol(cmd)
+		if err != nil {
+			return err
+		}
+		gitOpts, err := cloneGitOptions(protocol)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		s, err := client.GetSnippet(snippet)
+		if err != nil {
+			return err
+		}
+		href := s.Links.Clone(protocol)
+		if href == "" {
+			return fmt.Errorf("snippet %s has no %s clone URL", snippet.SnippetID, protocol)
+		}
+		if dir == "" {
+			dir = s.EncodedID()
+		}
+
+		git := exec.Command("git", cloneArgs(gitOpts, href, dir)...)
+		git.Stdin, git.Stdout, git.Stderr = os.Stdin, os.Stderr, os.Stderr
+		if err := git.Run(); err != nil {
+			return fmt.Errorf("git clone failed: %v", err)
+		}
+
+		PrintOrJSON(cmd, map[string]any{"snippet_id": s.EncodedID(), "dir": dir, "url": href}, func() {
+			fmt.Printf("Cloned snippet %s into %s\n", s.EncodedID(), dir)
+		})
+		return nil
+	},
+}
+
+var snippetsHistoryCmd = &cobra.Command{
+	Use:   "history [workspace] <snippet-id>",
+	Short: "List a snippet's revisions",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snippet, _, err := snippetScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		page, pagelen := paginationArgs(cmd)
+
+		client := getClient()
+		result, err := client.ListSnippetCommits(snippet, page, pagelen)
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result.Values) == 0 {
+				fmt.Println("No history found.")
+				return
+			}
+			t := NewTable()
+			t.Header("Hash", "Author", "Date", "Message")
+			for _, c := range result.Values {
+				author := "-"
+				if c.Author != nil {
+					author = c.Author.Raw
+					if c.Author.User != nil {
+						author = c.Author.User.DisplayName
+					}
+				}
+				t.Row(shortHash(c.Hash), author, FormatTime(c.Date), Truncate(firstLine(c.Message), 50))
+			}
+			t.Flush()
+			PrintPaginationFooter(result.Size, result.Page, len(result.Values), result.Next != "")
+		})
+		return nil
+	},
+}
+
+var snippetsCommentsCmd = &cobra.Command{
+	Use:   "comments",
+	Short: "List, add, and delete comments on a snippet",
+}
+
+var snippetsCommentsListCmd = &cobra.Command{
+	Use:   "list [workspace] <snippet-id>",
+	Short: "List comments on a snippet",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snippet, _, err := snippetScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		page, pagelen := paginationArgs(cmd)
+
+		client := getClient()
+		result, err := client.ListSnippetComments(snippet, page, pagelen)
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result.Values) == 0 {
+				fmt.Println("No comments found.")
+				return
+			}
+			for _, c := range result.Values {
+				author := "unknown"
+				if c.User != nil {
+					author = c.User.DisplayName
+				}
+				fmt.Printf("#%d %s (%s)\n%s\n\n", c.ID, author, FormatTime(c.CreatedOn), c.Content.Raw)
+			}
+			PrintPaginationFooter(result.Size, result.Page, len(result.Values), result.Next != "")
+		})
+		return nil
+	},
+}
+
+var snippetsCommentsAddCmd = &cobra.Command{
+	Use:   "add [workspace] <snippet-id> -m <text>",
+	Short: "Comment on a snippet",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snippet, _, err := snippetScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		content, _ := cmd.Flags().GetString("content")
+
+		client := getClient()
+		c, err := client.AddSnippetComment(snippet, content)
+		if err != nil {
+			return err
+		}
+		PrintOrJSON(cmd, c, func() {
+			fmt.Printf("Comment #%d added to snippet %s\n", c.ID, snippet.SnippetID)
+		})
+		return nil
+	},
+}
+
+var snippetsCommentsDeleteCmd = &cobra.Command{
+	Use:   "delete [workspace] <snippet-id> <comment-id>",
+	Short: "Delete a comment on a snippet",
+	Args:  cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snippet, rest, err := snippetScope(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		id, err := strconv.Atoi(rest[0])
+		if err != nil {
+			return fmt.Errorf("invalid comment ID %q", rest[0])
+		}
+
+		client := getClient()
+		if err := client.DeleteSnippetComment(snippet, id); err != nil {
+			return err
+		}
+		PrintOrJSON(cmd, map[string]any{"snippet_id": snippet.SnippetID, "comment_id": id, "deleted": true}, func() {
+			fmt.Printf("Comment #%d deleted from snippet %s\n", id, snippet.SnippetID)
+		})
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(snippetsCmd)
+	snippetsCmd.AddCommand(snippetsListCmd)
+	snippetsCmd.AddCommand(snippetsGetCmd)
+	snippetsCmd.AddCommand(snippetsCreateCmd)
+	snippetsCmd.AddCommand(snippetsEditCmd)
+	snippetsCmd.AddCommand(snippetsDeleteCmd)
+	snippetsCmd.AddCommand(snippetsCloneCmd)
+	snippetsCmd.AddCommand(snippetsHistoryCmd)
+	snippetsCmd.AddCommand(snippetsCommentsCmd)
+	snippetsCommentsCmd.AddCommand(snippetsCommentsListCmd)
+	snippetsCommentsCmd.AddCommand(snippetsCommentsAddCmd)
+	snippetsCommentsCmd.AddCommand(snippetsCommentsDeleteCmd)
+
+	snippetsListCmd.Flags().String("role", "", "Only snippets you are the: "+strings.Join(bitbucket.SnippetRoles, " | "))
+	addPaginationFlags(snippetsListCmd)
+
+	snippetsGetCmd.Flags().String("file", "", "Print only this file's raw content")
+
+	for _, c := range []*cobra.Command{snippetsCreateCmd, snippetsEditCmd} {
+		c.Flags().StringArrayP("file", "f", nil, "File to include (repeatable)")
+		c.Flags().String("title", "", "Snippet title")
+		c.Flags().Bool("public", false, "Let anyone with the link see the snippet")
+	}
+	snippetsEditCmd.Flags().Bool("private", false, "Only workspace members can see the snippet")
+	snippetsCreateCmd.Flags().String("filename", "snippet.txt", "File name for content read from stdin")
+
+	snippetsCloneCmd.Flags().String("protocol", "", "Clone protocol: ssh | https (default the profile's preference, else https)")
+
+	addPaginationFlags(snippetsHistoryCmd)
+	addPaginationFlags(snippetsCommentsListCmd)
+
+	snippetsCommentsAddCmd.Flags().StringP("content", "m", "", "Comment body (markdown supported)")
+	_ = snippetsCommentsAddCmd.MarkFlagRequired("content")
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/zach-snell/bbkt/internal/bitbucket"
@@ -23,6 +24,7 @@ Alias: src`,
   bbkt source read README.md --ref develop
   bbkt source tree src --max-depth 2
   bbkt source search "TODO"               # requires code-search-enabled workspace
+  bbkt source search "retry lang:go" --workspace-wide
   bbkt source history src/main.go
   bbkt source write notes.md -c "draft" -m "add notes" -b scratch
   bbkt source delete old.txt -m "cleanup"`,
@@ -185,10 +187,37 @@ var sourceHistoryCmd = &cobra.Command{
 
 var sourceSearchCmd = &cobra.Command{
 	Use:   "search [workspace] [repo-slug] <query>",
-	Short: "Search for code in a repository (requires code-search-enabled workspace)",
-	Args:  cobra.RangeArgs(1, 3),
+	Short: "Search code in a repository or a whole workspace (requires code-search-enabled workspace)",
+	Long: `Search code with Bitbucket's code search. Results print grep-style as
+repo:path:line: text, with matches highlighted on a terminal; files that only
+matched by name print as repo:path.
+
+The query accepts Bitbucket's qualifiers (repo:, lang:, ext:, path:, and
+quoted phrases). --workspace-wide searches every repository in the workspace
+instead of the current one; --lang and --ext add lang: and ext: qualifiers.`,
+	Example: `  bbkt source search "NewClient"
+  bbkt source search acme "retry lang:go" --workspace-wide
+  bbkt source search "TODO" --workspace-wide --ext ts --context`,
+	Args: cobra.RangeArgs(1, 3),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		workspace, repoSlug, trailing, err := ParseArgs(cmd, args, 1)
+		flags := cmd.Flags()
+		wide, _ := flags.GetBool("workspace-wide")
+		lang, _ := flags.GetString("lang")
+		ext, _ := flags.GetString("ext")
+		context, _ := flags.GetBool("context")
+		page, pagelen := paginationArgs(cmd)
+
+		var workspace, repoSlug string
+		var err error
+		query := args[len(args)-1]
+		if wide {
+			if len(args) > 2 {
+				return fmt.Errorf("--workspace-wide takes [workspace] <query>")
+			}
+			workspace, _, _, err = ParseArgs(cmd, args[:len(args)-1], -1)
+		} else {
+			workspace, repoSlug, _, err = ParseArgs(cmd, args, 1)
+		}
 		if err != nil {
 			return err
 		}
@@ -197,18 +226,71 @@ var sourceSearchCmd = &cobra.Command{
 		result, err := client.SearchCode(bitbucket.SearchCodeArgs{
 			Workspace:   workspace,
 			RepoSlug:    repoSlug,
-			SearchQuery: trailing[0],
+			SearchQuery: query,
+			Language:    lang,
+			Extension:   ext,
+			Page:        page,
+			Pagelen:     pagelen,
 		})
 		if err != nil {
 			return err
 		}
 
-		// Bitbucket returns custom JSON structure for code search
-		fmt.Println(string(result))
+		PrintOrJSON(cmd, result, func() {
+			if len(result.Values) == 0 {
+				fmt.Println("No matches found.")
+				return
+			}
+			printCodeSearch(result, workspace, context)
+			PrintPaginationFooter(result.Size, result.Page, len(result.Values), result.Next != "")
+		})
 		return nil
 	},
 }
 
+// printCodeSearch prints results grep-style: repo:path:line: text for
+// matching lines and, with context, repo:path-line- text for the lines
+// around them. Repositories in workspace print by slug.
+func printCodeSearch(result *bitbucket.CodeSearchResults, workspace string, context bool) {
+	color := colorEnabled()
+	for _, res := range result.Values {
+		prefix := strings.TrimPrefix(res.Repository(), workspace+"/") + ":" + res.File.Path
+		printed := false
+		for _, cm := range res.ContentMatches {
+			for _, l := range cm.Lines {
+				if l.IsMatch() {
+					fmt.Printf("%s:%d: %s\n", prefix, l.Line, highlightSegments(l.Segments, color))
+				} else if context {
+					fmt.Printf("%s-%d- %s\n", prefix, l.Line, l.Text())
+				} else {
+					continue
+				}
+				printed = true
+			}
+			if context && len(cm.Lines) > 0 {
+				fmt.Println("--")
+			}
+		}
+		if !printed {
+			fmt.Println(prefix)
+		}
+	}
+}
+
+// highlightSegments joins a line's segments, marking matches in bold red
+// when color is on.
+func highlightSegments(segments []bitbucket.CodeSearchSegment, color bool) string {
+	var b strings.Builder
+	for _, s := range segments {
+		if s.Match && color {
+			b.WriteString("\033[1;31m" + s.Text + "\033[0m")
+		} else {
+			b.WriteString(s.Text)
+		}
+	}
+	return b.String()
+}
+
 var sourceWriteCmd = &cobra.Command{
 	Use:   "write [workspace] [repo-slug] <path>",
 	Short: "Write or update a file in the repository",
@@ -293,6 +375,12 @@ func init() {
 
 	sourceHistoryCmd.Flags().String("ref", "", "Commit hash, branch, or tag (default: HEAD)")
 
+	sourceSearchCmd.Flags().Bool("workspace-wide", false, "Search every repository in the workspace")
+	sourceSearchCmd.Flags().String("lang", "", "Only files in this language (e.g. go, python)")
+	sourceSearchCmd.Flags().String("ext", "", "Only files with this extension (e.g. ts)")
+	sourceSearchCmd.Flags().Bool("context", false, "Also print the lines around each match")
+	addPaginationFlags(sourceSearchCmd)
+
 	sourceWriteCmd.Flags().StringP("content", "c", "", "File content to write")
 	sourceWriteCmd.Flags().StringP("message", "m", "", "Commit message")
 	sourceWriteCmd.Flags().StringP("branch", "b", "", "Branch to commit to (defaults to repo default branch)")
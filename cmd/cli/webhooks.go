@@ -0,0 +1,447 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+var webhooksCmd = &cobra.Command{
+	Use:     "webhooks",
+	Aliases: []string{"hooks"},
+	GroupID: groupData,
+	Short:   "Manage repository and workspace webhooks",
+	Long: `List, create, update, delete, test, and audit webhooks.
+
+Webhooks are repository webhooks by default, with the usual
+[workspace] [repo-slug] layout (inferred from your git clone when omitted).
+Pass --level workspace for workspace webhooks; positional args are then
+[workspace] <uid>.
+
+Event names are checked against the built-in catalog (bbkt webhooks events);
+"category:*" subscribes to every event in a category, e.g. pullrequest:*.`,
+	Example: `  bbkt webhooks list
+  bbkt webhooks create --url https://ci.example.com/hook --events repo:push,pullrequest:* --generate-secret
+  bbkt webhooks update {hook-uuid} --active=false
+  bbkt webhooks test {hook-uuid} --secret "$HOOK_SECRET"
+  bbkt webhooks audit --level workspace --resolve`,
+}
+
+var webhooksListCmd = &cobra.Command{
+	Use:   "list [workspace] [repo-slug]",
+	Short: "List webhooks with their URL, events, and state",
+	Args:  cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := levelScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		page, pagelen := paginationArgs(cmd)
+
+		client := getClient()
+		result, err := client.ListWebhooks(bitbucket.ListWebhooksArgs{
+			WebhookScopeArgs: bitbucket.WebhookScopeArgs{Workspace: workspace, RepoSlug: repoSlug},
+			Page:             page,
+			Pagelen:          pagelen,
+		})
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, result, func() {
+			if len(result.Values) == 0 {
+				fmt.Println("No webhooks found.")
+				return
+			}
+			t := NewTable()
+			t.Header("Description", "URL", "Active", "Secret", "Events", "UUID")
+			for _, h := range result.Values {
+				t.Row(Truncate(h.Description, 30), h.URL, FormatBool(h.Active), FormatBool(h.SecretSet),
+					fmt.Sprintf("%d", len(h.Events)), h.UUID)
+			}
+			t.Flush()
+			PrintPaginationFooter(result.Size, result.Page, len(result.Values), result.Next != "")
+		})
+		return nil
+	},
+}
+
+var webhooksGetCmd = &cobra.Command{
+	Use:   "get [workspace] [repo-slug] <uid>",
+	Short: "Show a webhook",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hookArgs, err := webhookArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		h, err := client.GetWebhook(hookArgs)
+		if err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, h, func() {
+			printWebhook(h)
+		})
+		return nil
+	},
+}
+
+var webhooksCreateCmd = &cobra.Command{
+	Use:   "create [workspace] [repo-slug] --url <url> --events <event,...>",
+	Short: "Create a webhook",
+	Long: `Create a webhook. --generate-secret creates a random secret and prints it
+once; Bitbucket signs each delivery with it in the X-Hub-Signature header
+but never returns it again.`,
+	Example: `  bbkt webhooks create --url https://ci.example.com/hook --events repo:push
+  bbkt webhooks create --level workspace --url https://bot.example.com --events pullrequest:* --generate-secret`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := levelScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		url, _ := cmd.Flags().GetString("url")
+		events, _ := cmd.Flags().GetStringSlice("events")
+		description, _ := cmd.Flags().GetString("description")
+		inactive, _ := cmd.Flags().GetBool("inactive")
+		skipCert, _ := cmd.Flags().GetBool("skip-cert-verification")
+		secret, generated, err := webhookSecret(cmd)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		h, err := client.CreateWebhook(bitbucket.CreateWebhookArgs{
+			WebhookScopeArgs:     bitbucket.WebhookScopeArgs{Workspace: workspace, RepoSlug: repoSlug},
+			URL:                  url,
+			Description:          description,
+			Events:               events,
+			Secret:               secret,
+			Inactive:             inactive,
+			SkipCertVerification: skipCert,
+		})
+		if err != nil {
+			return err
+		}
+
+		out := map[string]any{"webhook": h}
+		if generated {
+			out["secret"] = secret
+		}
+		PrintOrJSON(cmd, out, func() {
+			fmt.Printf("Created webhook %s.\n\n", h.UUID)
+			printWebhook(h)
+			if generated {
+				fmt.Println()
+				KV("Secret", secret)
+				fmt.Println("\nStore this secret now — Bitbucket will not show it again.")
+			}
+		})
+		return nil
+	},
+}
+
+var webhooksUpdateCmd = &cobra.Command{
+	Use:   "update [workspace] [repo-slug] <uid>",
+	Short: "Change a webhook's URL, events, secret, or active state",
+	Long: `Change the given fields of a webhook; anything not passed is kept.
+--events replaces the event list, --add-events extends it.`,
+	Example: `  bbkt webhooks update {hook-uuid} --active=false
+  bbkt webhooks update {hook-uuid} --add-events pullrequest:comment_created
+  bbkt webhooks update {hook-uuid} --generate-secret
+  bbkt webhooks update {hook-uuid} --remove-secret`,
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hookArgs, err := webhookArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+		update := bitbucket.UpdateWebhookArgs{WebhookArgs: hookArgs}
+		flags := cmd.Flags()
+		if flags.Changed("url") {
+			v, _ := flags.GetString("url")
+			update.URL = &v
+		}
+		if flags.Changed("description") {
+			v, _ := flags.GetString("description")
+			update.Description = &v
+		}
+		if flags.Changed("active") {
+			v, _ := flags.GetBool("active")
+			update.Active = &v
+		}
+		if flags.Changed("skip-cert-verification") {
+			v, _ := flags.GetBool("skip-cert-verification")
+			update.SkipCertVerification = &v
+		}
+		update.Events, _ = flags.GetStringSlice("events")
+		secret, generated, err := webhookSecret(cmd)
+		if err != nil {
+			return err
+		}
+		remove, _ := flags.GetBool("remove-secret")
+		if remove && secret != "" {
+			return fmt.Errorf("--remove-secret cannot be combined with --secret or --generate-secret")
+		}
+		if secret != "" || remove {
+			update.Secret = &secret
+		}
+
+		client := getClient()
+		if add, _ := flags.GetStringSlice("add-events"); len(add) > 0 {
+			if len(update.Events) == 0 {
+				current, err := client.GetWebhook(hookArgs)
+				if err != nil {
+					return err
+				}
+				update.Events = current.Events
+			}
+			update.Events = append(update.Events, add...)
+		}
+
+		h, err := client.UpdateWebhook(update)
+		if err != nil {
+			return err
+		}
+
+		out := map[string]any{"webhook": h}
+		if generated {
+			out["secret"] = secret
+		}
+		PrintOrJSON(cmd, out, func() {
+			fmt.Printf("Updated webhook %s.\n\n", h.UUID)
+			printWebhook(h)
+			if generated {
+				fmt.Println()
+				KV("Secret", secret)
+				fmt.Println("\nStore this secret now — Bitbucket will not show it again.")
+			}
+		})
+		return nil
+	},
+}
+
+var webhooksDeleteCmd = &cobra.Command{
+	Use:   "delete [workspace] [repo-slug] <uid>",
+	Short: "Delete a webhook",
+	Args:  cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hookArgs, err := webhookArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		client := getClient()
+		if err := client.DeleteWebhook(hookArgs); err != nil {
+			return err
+		}
+
+		PrintOrJSON(cmd, map[string]any{
+			"uid":     hookArgs.UID,
+			"deleted": true,
+		}, func() {
+			fmt.Printf("Deleted webhook %s.\n", hookArgs.UID)
+		})
+		return nil
+	},
+}
+
+var webhooksTestCmd = &cobra.Command{
+	Use:   "test [workspace] [repo-slug] <uid>",
+	Short: "Send a test delivery to a webhook's URL",
+	Long: `Send a diagnostics:ping delivery from this machine straight to the
+webhook's URL and report the response status and latency. Bitbucket never
+returns a webhook's secret, so pass --secret to sign the request the way real
+deliveries are signed.
+
+The request comes from your network, not Bitbucket's: a success here does
+not prove Bitbucket can reach the URL.`,
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hookArgs, err := webhookArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+		secret, _ := cmd.Flags().GetString("secret")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		client := getClient()
+		h, err := client.GetWebhook(hookArgs)
+		if err != nil {
+			return err
+		}
+		res := bitbucket.TestWebhook(context.Background(), &http.Client{Timeout: timeout}, h, secret)
+
+		PrintOrJSON(cmd, res, func() {
+			KV("URL", res.URL)
+			if res.Error != "" {
+				KV("Result", "error: "+res.Error)
+			} else {
+				KVf("Result", "%d %s", res.StatusCode, http.StatusText(res.StatusCode))
+			}
+			KVf("Latency", "%dms", res.DurationMs)
+			KV("Signed", FormatBool(res.Signed))
+		})
+		if !res.OK() {
+			return fmt.Errorf("test delivery to %s failed", res.URL)
+		}
+		return nil
+	},
+}
+
+var webhooksEventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "List the events a webhook can subscribe to",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		PrintOrJSON(cmd, bitbucket.WebhookEvents, func() {
+			t := NewTable()
+			t.Header("Event", "Description")
+			for _, e := range bitbucket.WebhookEvents {
+				t.Row(e.Event, e.Description)
+			}
+			t.Flush()
+		})
+		return nil
+	},
+}
+
+var webhooksAuditCmd = &cobra.Command{
+	Use:   "audit [workspace] [repo-slug]",
+	Short: "Flag webhooks with insecure or unreachable-looking URLs",
+	Long: `Check every webhook for URLs Bitbucket probably cannot deliver to
+(private or loopback addresses, internal host names) or delivers to
+insecurely (plain HTTP, disabled certificate checks, no secret), and for
+inactive hooks. --resolve also looks up each host name in DNS.`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workspace, repoSlug, _, err := levelScope(cmd, args, 0)
+		if err != nil {
+			return err
+		}
+		resolve, _ := cmd.Flags().GetBool("resolve")
+
+		client := getClient()
+		hooks, err := client.ListAllWebhooks(bitbucket.WebhookScopeArgs{Workspace: workspace, RepoSlug: repoSlug})
+		if err != nil {
+			return err
+		}
+		var lookup func(string) error
+		if resolve {
+			lookup = func(host string) error {
+				_, err := net.LookupHost(host)
+				return err
+			}
+		}
+		audits := bitbucket.AuditWebhooks(hooks, lookup)
+
+		PrintOrJSON(cmd, audits, func() {
+			if len(audits) == 0 {
+				fmt.Println("No webhooks found.")
+				return
+			}
+			flagged := 0
+			t := NewTable()
+			t.Header("URL", "Issues", "UUID")
+			for _, a := range audits {
+				issues := "ok"
+				if len(a.Issues) > 0 {
+					flagged++
+					issues = strings.Join(a.Issues, "; ")
+				}
+				t.Row(a.Webhook.URL, issues, a.Webhook.UUID)
+			}
+			t.Flush()
+			fmt.Printf("\n%d of %d webhook(s) flagged.\n", flagged, len(audits))
+		})
+		return nil
+	},
+}
+
+// webhookArgs resolves [workspace] [repo-slug] <uid> according to --level.
+func webhookArgs(cmd *cobra.Command, args []string) (bitbucket.WebhookArgs, error) {
+	workspace, repoSlug, rest, err := levelScope(cmd, args, 1)
+	if err != nil {
+		return bitbucket.WebhookArgs{}, err
+	}
+	return bitbucket.WebhookArgs{
+		WebhookScopeArgs: bitbucket.WebhookScopeArgs{Workspace: workspace, RepoSlug: repoSlug},
+		UID:              rest[0],
+	}, nil
+}
+
+// webhookSecret reads --secret / --generate-secret. generated reports whether
+// the secret was made up here and so must be shown to the user.
+func webhookSecret(cmd *cobra.Command) (secret string, generated bool, err error) {
+	secret, _ = cmd.Flags().GetString("secret")
+	gen, _ := cmd.Flags().GetBool("generate-secret")
+	if !gen {
+		return secret, false, nil
+	}
+	if secret != "" {
+		return "", false, fmt.Errorf("--secret and --generate-secret are mutually exclusive")
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", false, err
+	}
+	return hex.EncodeToString(b), true, nil
+}
+
+func printWebhook(h *bitbucket.Webhook) {
+	KV("UUID", h.UUID)
+	KV("URL", h.URL)
+	KV("Description", orDash(h.Description))
+	KV("Active", FormatBool(h.Active))
+	KV("Secret", FormatBool(h.SecretSet))
+	if h.SkipCertVerification {
+		KV("TLS verification", "disabled")
+	}
+	KV("Events", strings.Join(h.Events, ", "))
+	KV("Created", FormatTimePtr(h.CreatedAt))
+}
+
+func init() {
+	RootCmd.AddCommand(webhooksCmd)
+	webhooksCmd.AddCommand(webhooksListCmd)
+	webhooksCmd.AddCommand(webhooksGetCmd)
+	webhooksCmd.AddCommand(webhooksCreateCmd)
+	webhooksCmd.AddCommand(webhooksUpdateCmd)
+	webhooksCmd.AddCommand(webhooksDeleteCmd)
+	webhooksCmd.AddCommand(webhooksTestCmd)
+	webhooksCmd.AddCommand(webhooksEventsCmd)
+	webhooksCmd.AddCommand(webhooksAuditCmd)
+
+	webhooksCmd.PersistentFlags().String("level", "repository", "Webhook level: repository | workspace")
+	addPaginationFlags(webhooksListCmd)
+
+	for _, c := range []*cobra.Command{webhooksCreateCmd, webhooksUpdateCmd} {
+		c.Flags().String("url", "", "Delivery URL")
+		c.Flags().String("description", "", "Description")
+		c.Flags().StringSlice("events", nil, "Events, e.g. repo:push,pullrequest:* (see 'bbkt webhooks events')")
+		c.Flags().String("secret", "", "Secret used to sign deliveries")
+		c.Flags().Bool("generate-secret", false, "Generate a random secret and print it once")
+		c.Flags().Bool("skip-cert-verification", false, "Skip TLS certificate verification")
+	}
+	_ = webhooksCreateCmd.MarkFlagRequired("url")
+	_ = webhooksCreateCmd.MarkFlagRequired("events")
+	webhooksCreateCmd.Flags().Bool("inactive", false, "Create the webhook disabled")
+	webhooksUpdateCmd.Flags().StringSlice("add-events", nil, "Events to add to the current list")
+	webhooksUpdateCmd.Flags().Bool("active", true, "Enable (true) or disable (false) the webhook")
+	webhooksUpdateCmd.Flags().Bool("remove-secret", false, "Remove the webhook's secret")
+
+	webhooksTestCmd.Flags().String("secret", "", "Secret to sign the test delivery with")
+	webhooksTestCmd.Flags().Duration("timeout", 10*time.Second, "Request timeout")
+	webhooksAuditCmd.Flags().Bool("resolve", false, "Also check that each host name resolves in DNS")
+}
@@ -0,0 +1,321 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+var webhooksListenCmd = &cobra.Command{
+	Use:   "listen",
+	Short: "Run a local webhook receiver that dispatches events",
+	Long: `Run an HTTP server that receives Bitbucket webhook deliveries, verifies
+their X-Hub-Signature against --secret, and dispatches each event:
+
+  --exec <cmd>                    run a command per event, with the event as JSON on stdin
+  --route '<event>[@<repo>]=<cmd>'  run <cmd> only for matching events (repeatable;
+                                  the first matching route wins, --exec is the fallback)
+  (neither)                       print events as NDJSON on stdout; --ndjson forces this
+
+Events and repos are glob patterns ("pullrequest:*", "acme/*"); --event and
+--from-repo drop everything else before dispatch. Commands are split on spaces
+and run without a shell, with BBKT_EVENT, BBKT_REPO, and BBKT_REQUEST_UUID
+set. Events are handled one at a time after Bitbucket's request is answered,
+so slow handlers do not cause retries; handler failures are logged.
+
+--record <dir> saves each delivery; --replay dispatches saved deliveries (or
+bare payloads with --event-key) through the same filters and handlers
+without starting a server, for testing handlers offline.`,
+	Example: `  bbkt webhooks listen --port 8080 --secret "$HOOK_SECRET" --exec ./handler.sh
+  bbkt webhooks listen --secret "$HOOK_SECRET" --route 'pullrequest:created=./on-pr.sh' \
+      --route 'repo:commit_status_updated@acme/*=./on-build.sh'
+  bbkt webhooks listen --event 'pullrequest:*' | jq .pullrequest.title
+  bbkt webhooks listen --replay deliveries/pr-opened.json --exec ./handler.sh`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+		port, _ := flags.GetInt("port")
+		host, _ := flags.GetString("host")
+		path, _ := flags.GetString("path")
+		secret, _ := flags.GetString("secret")
+		if secret == "" {
+			secret = os.Getenv("BBKT_WEBHOOK_SECRET")
+		}
+		execCmd, _ := flags.GetString("exec")
+		routeSpecs, _ := flags.GetStringArray("route")
+		events, _ := flags.GetStringSlice("event")
+		repos, _ := flags.GetStringSlice("from-repo")
+		ndjson, _ := flags.GetBool("ndjson")
+		record, _ := flags.GetString("record")
+		replay, _ := flags.GetStringArray("replay")
+		eventKey, _ := flags.GetString("event-key")
+		timeout, _ := flags.GetDuration("timeout")
+
+		d := &webhookDispatcher{
+			filter:  bitbucket.WebhookFilter{Events: events, Repos: repos},
+			exec:    strings.Fields(execCmd),
+			ndjson:  ndjson || (execCmd == "" && len(routeSpecs) == 0),
+			record:  record,
+			timeout: timeout,
+			out:     os.Stdout,
+		}
+		for _, spec := range routeSpecs {
+			r, err := parseWebhookRoute(spec)
+			if err != nil {
+				return err
+			}
+			d.routes = append(d.routes, r)
+		}
+		if record != "" {
+			if err := os.MkdirAll(record, 0o755); err != nil {
+				return err
+			}
+		}
+
+		if len(replay) > 0 {
+			return replayWebhooks(cmd.Context(), d, replay, eventKey)
+		}
+		if secret == "" {
+			fmt.Fprintln(os.Stderr, "warning: no --secret; deliveries are not verified")
+		}
+		return serveWebhooks(d, net.JoinHostPort(host, strconv.Itoa(port)), path, secret)
+	},
+}
+
+// webhookRoute sends events matching filter to command.
+type webhookRoute struct {
+	filter  bitbucket.WebhookFilter
+	command []string
+}
+
+// parseWebhookRoute parses '<event>[@<repo>]=<command>'.
+func parseWebhookRoute(spec string) (webhookRoute, error) {
+	match, command, ok := strings.Cut(spec, "=")
+	argv := strings.Fields(command)
+	if !ok || strings.TrimSpace(match) == "" || len(argv) == 0 {
+		return webhookRoute{}, fmt.Errorf("invalid --route %q: expected <event>[@<repo>]=<command>", spec)
+	}
+	event, repo, _ := strings.Cut(strings.TrimSpace(match), "@")
+	r := webhookRoute{command: argv, filter: bitbucket.WebhookFilter{Events: []string{event}}}
+	if repo != "" {
+		r.filter.Repos = []string{repo}
+	}
+	return r, nil
+}
+
+// webhookDispatcher filters deliveries and hands them to a route's command,
+// the --exec command, or stdout as NDJSON.
+type webhookDispatcher struct {
+	filter  bitbucket.WebhookFilter
+	routes  []webhookRoute
+	exec    []string
+	ndjson  bool
+	record  string
+	timeout time.Duration
+
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func (d *webhookDispatcher) dispatch(ctx context.Context, del *bitbucket.WebhookDelivery) error {
+	if d.record != "" {
+		if err := d.save(del); err != nil {
+			return err
+		}
+	}
+	if !d.filter.Match(del) {
+		return nil
+	}
+
+	data, err := json.Marshal(del)
+	if err != nil {
+		return err
+	}
+	if d.ndjson {
+		d.mu.Lock()
+		_, err := fmt.Fprintf(d.out, "%s\n", data)
+		d.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	argv := d.exec
+	for _, r := range d.routes {
+		if r.filter.Match(del) {
+			argv = r.command
+			break
+		}
+	}
+	if len(argv) == 0 {
+		return nil
+	}
+	return d.run(ctx, argv, del, data)
+}
+
+func (d *webhookDispatcher) run(ctx context.Context, argv []string, del *bitbucket.WebhookDelivery, data []byte) error {
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+	c := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	c.Stdin = bytes.NewReader(data)
+	c.Stdout = os.Stdout
+	if d.ndjson {
+		// Keep stdout machine-readable.
+		c.Stdout = os.Stderr
+	}
+	c.Stderr = os.Stderr
+	c.Env = append(os.Environ(),
+		"BBKT_EVENT="+del.Event,
+		"BBKT_REPO="+del.RepoFullName(),
+		"BBKT_REQUEST_UUID="+del.RequestUUID,
+	)
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("%s for %s: %v", argv[0], del.Event, err)
+	}
+	return nil
+}
+
+// save writes the delivery to the --record directory.
+func (d *webhookDispatcher) save(del *bitbucket.WebhookDelivery) error {
+	data, err := json.MarshalIndent(del.Recording(), "", "  ")
+	if err != nil {
+		return err
+	}
+	// Neither header is covered by the signature, so both are reduced to
+	// characters that cannot leave the --record directory.
+	id := recordingNamePart(del.RequestUUID)
+	if id == "" {
+		id = strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	name := fmt.Sprintf("%s-%s-%s.json", time.Now().UTC().Format("20060102T150405"), recordingNamePart(del.Event), id)
+	return os.WriteFile(filepath.Join(d.record, name), data, 0o644)
+}
+
+// recordingNamePart replaces everything but letters, digits, '-' and '_'
+// in s with '_'.
+func recordingNamePart(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// webhookQueueSize bounds deliveries waiting for a handler; when it is full
+// new deliveries are refused so Bitbucket retries them later.
+const webhookQueueSize = 64
+
+func serveWebhooks(d *webhookDispatcher, addr, path, secret string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	queue := make(chan *bitbucket.WebhookDelivery, webhookQueueSize)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for del := range queue {
+			if err := d.dispatch(ctx, del); err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			}
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.Handle(path, &bitbucket.WebhookReceiver{
+		Secret: secret,
+		Handle: func(_ context.Context, del *bitbucket.WebhookDelivery) error {
+			select {
+			case queue <- del:
+				return nil
+			default:
+				return errors.New("handler queue is full")
+			}
+		},
+	})
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	fmt.Fprintf(os.Stderr, "Listening for webhooks on %s%s (Ctrl-C to stop)\n", addr, path)
+
+	var err error
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err = srv.Shutdown(shutdownCtx)
+		cancel()
+	}
+	close(queue)
+	wg.Wait()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func replayWebhooks(ctx context.Context, d *webhookDispatcher, files []string, eventKey string) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	failed := 0
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		del, err := bitbucket.LoadWebhookRecording(data, eventKey)
+		if err != nil {
+			return fmt.Errorf("%s: %v", f, err)
+		}
+		if err := d.dispatch(ctx, del); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s: %v\n", f, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d replayed deliveries failed", failed, len(files))
+	}
+	return nil
+}
+
+func init() {
+	webhooksCmd.AddCommand(webhooksListenCmd)
+
+	f := webhooksListenCmd.Flags()
+	f.Int("port", 8080, "Port to listen on")
+	f.String("host", "", "Interface to listen on (default all)")
+	f.String("path", "/", "URL path to accept deliveries on")
+	f.String("secret", "", "Webhook secret to verify X-Hub-Signature (default $BBKT_WEBHOOK_SECRET)")
+	f.String("exec", "", "Command to run for each event, with the event JSON on stdin")
+	f.StringArray("route", nil, "Route '<event>[@<repo>]=<command>' (repeatable)")
+	f.StringSlice("event", nil, "Only handle these events (globs, e.g. pullrequest:*)")
+	f.StringSlice("from-repo", nil, "Only handle events from these repositories (globs, e.g. acme/*)")
+	f.Bool("ndjson", false, "Print events as NDJSON on stdout (default when no --exec or --route)")
+	f.String("record", "", "Save each delivery to this directory for --replay")
+	f.StringArray("replay", nil, "Dispatch a saved delivery file instead of listening (repeatable)")
+	f.String("event-key", "", "Event key for --replay files that hold a bare payload")
+	f.Duration("timeout", 5*time.Minute, "Time limit for each handler command")
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+func TestWebhookRecording_StaysInRecordDir(t *testing.T) {
+	root := t.TempDir()
+	record := filepath.Join(root, "a", "b", "record")
+	if err := os.MkdirAll(record, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	d := &webhookDispatcher{record: record}
+	srv := httptest.NewServer(&bitbucket.WebhookReceiver{
+		Handle: func(ctx context.Context, del *bitbucket.WebhookDelivery) error { return d.dispatch(ctx, del) },
+	})
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(`{}`))
+	req.Header.Set("X-Event-Key", "repo:push/../../..")
+	req.Header.Set("X-Request-UUID", "x/../../../../evil")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+
+	var written []string
+	_ = filepath.WalkDir(root, func(p string, e os.DirEntry, err error) error {
+		if err == nil && !e.IsDir() {
+			written = append(written, p)
+		}
+		return nil
+	})
+	if len(written) != 1 || filepath.Dir(written[0]) != record {
+		t.Fatalf("recordings = %v, want one file in %s", written, record)
+	}
+	if name := filepath.Base(written[0]); !strings.HasSuffix(name, "-repo_push_________-x_____________evil.json") {
+		t.Errorf("recording name = %q", name)
+	}
+}
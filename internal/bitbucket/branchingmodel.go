@@ -0,0 +1,80 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// BranchingModelSettings is a repository's branching model configuration.
+type BranchingModelSettings struct {
+	Development *BranchingModelBranch `json:"development,omitempty"`
+	Production  *BranchingModelBranch `json:"production,omitempty"`
+	BranchTypes []BranchType          `json:"branch_types,omitempty"`
+}
+
+// BranchingModelBranch is the development or production branch of a
+// branching model. Enabled only applies to production, which is optional.
+type BranchingModelBranch struct {
+	Name          string `json:"name,omitempty"`
+	UseMainBranch bool   `json:"use_mainbranch"`
+	Enabled       *bool  `json:"enabled,omitempty"`
+	IsValid       bool   `json:"is_valid,omitempty"`
+}
+
+// BranchType is a branch prefix of the branching model, e.g. feature/.
+type BranchType struct {
+	Kind    string `json:"kind"`
+	Enabled bool   `json:"enabled"`
+	Prefix  string `json:"prefix,omitempty"`
+}
+
+// BranchTypeKinds are the prefixed branch types of a branching model.
+var BranchTypeKinds = []string{"feature", "bugfix", "release", "hotfix"}
+
+type BranchingModelArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+}
+
+func (a BranchingModelArgs) path() (string, error) {
+	if a.Workspace == "" || a.RepoSlug == "" {
+		return "", fmt.Errorf("workspace and repo_slug are required")
+	}
+	return fmt.Sprintf("/repositories/%s/%s/branching-model/settings",
+		QueryEscape(a.Workspace), QueryEscape(a.RepoSlug)), nil
+}
+
+// GetBranchingModel gets a repository's branching model settings.
+func (c *Client) GetBranchingModel(args BranchingModelArgs) (*BranchingModelSettings, error) {
+	path, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	return GetJSON[BranchingModelSettings](c, path)
+}
+
+// UpdateBranchingModel changes a repository's branching model. Parts left
+// nil are not changed; branch types not listed keep their settings.
+func (c *Client) UpdateBranchingModel(args BranchingModelArgs, settings BranchingModelSettings) (*BranchingModelSettings, error) {
+	path, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	for _, bt := range settings.BranchTypes {
+		if !containsString(BranchTypeKinds, bt.Kind) {
+			return nil, fmt.Errorf("invalid branch type %q: expected one of %s", bt.Kind, strings.Join(BranchTypeKinds, ", "))
+		}
+	}
+
+	respData, err := c.Put(path, settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update branching model: %v", err)
+	}
+
+	var out BranchingModelSettings
+	if err := json.Unmarshal(respData, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &out, nil
+}
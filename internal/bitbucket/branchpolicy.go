@@ -0,0 +1,40 @@
+package bitbucket
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	yaml "go.yaml.in/yaml/v4"
+)
+
+// BranchPolicy is a branch restrictions policy file: the restrictions every
+// listed repository should have.
+type BranchPolicy struct {
+	// Repositories are slugs or globs within the workspace; empty means the
+	// repository the policy is applied to.
+	Repositories []string `yaml:"repositories,omitempty"`
+	// Prune deletes restrictions the policy does not list.
+	Prune        bool                    `yaml:"prune,omitempty"`
+	Restrictions []BranchRestrictionSpec `yaml:"restrictions"`
+}
+
+// ParseBranchPolicy parses and validates a policy file. Unknown fields are
+// rejected so typos do not silently drop a restriction.
+func ParseBranchPolicy(data []byte) (*BranchPolicy, error) {
+	var p BranchPolicy
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid policy: %v", err)
+	}
+	if len(p.Restrictions) == 0 {
+		return nil, fmt.Errorf("invalid policy: no restrictions")
+	}
+	// Planning an empty state validates every entry and rejects duplicates.
+	if _, err := PlanBranchRestrictions(nil, p.Restrictions, false); err != nil {
+		return nil, fmt.Errorf("invalid policy: %v", err)
+	}
+	return &p, nil
+}
@@ -0,0 +1,464 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// BranchRestriction is a branch permission or merge check on a repository.
+type BranchRestriction struct {
+	ID              int     `json:"id,omitempty"`
+	Kind            string  `json:"kind"`
+	BranchMatchKind string  `json:"branch_match_kind"`
+	Pattern         string  `json:"pattern,omitempty"`
+	BranchType      string  `json:"branch_type,omitempty"`
+	Value           *int    `json:"value,omitempty"`
+	Users           []User  `json:"users,omitempty"`
+	Groups          []Group `json:"groups,omitempty"`
+}
+
+// Group is a workspace user group.
+type Group struct {
+	Slug string `json:"slug"`
+	Name string `json:"name,omitempty"`
+}
+
+// Target describes which branches the restriction covers, e.g. "main" or
+// "type:release".
+func (r BranchRestriction) Target() string {
+	if r.BranchMatchKind == "branching_model" {
+		return "type:" + r.BranchType
+	}
+	return r.Pattern
+}
+
+// BranchRestrictionKind describes one kind of branch restriction.
+type BranchRestrictionKind struct {
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+	// HasValue kinds take a numeric value, e.g. the number of approvals.
+	HasValue bool `json:"has_value,omitempty"`
+	// Exemptions kinds accept users and groups who are exempt from (or, for
+	// push and restrict_merges, the only ones allowed past) the restriction.
+	Exemptions bool `json:"exemptions,omitempty"`
+}
+
+// BranchRestrictionKinds is the catalog of restriction kinds.
+var BranchRestrictionKinds = []BranchRestrictionKind{
+	{Kind: "push", Description: "Only the listed users and groups may push", Exemptions: true},
+	{Kind: "force", Description: "Prevent force pushes (rewriting history)"},
+	{Kind: "delete", Description: "Prevent deleting the branch"},
+	{Kind: "restrict_merges", Description: "Only the listed users and groups may merge pull requests", Exemptions: true},
+	{Kind: "require_approvals_to_merge", Description: "Minimum approvals before merging", HasValue: true},
+	{Kind: "require_default_reviewer_approvals_to_merge", Description: "Minimum default reviewer approvals before merging", HasValue: true},
+	{Kind: "require_passing_builds_to_merge", Description: "Minimum successful builds before merging", HasValue: true},
+	{Kind: "require_commits_behind", Description: "Maximum commits the source may be behind the destination", HasValue: true},
+	{Kind: "require_tasks_to_be_completed", Description: "All pull request tasks must be resolved"},
+	{Kind: "require_no_changes_requested", Description: "No reviewer may have requested changes"},
+	{Kind: "require_all_dependencies_merged", Description: "Pull requests this one depends on must be merged"},
+	{Kind: "reset_pullrequest_approvals_on_change", Description: "Reset approvals when the source branch changes"},
+	{Kind: "smart_reset_pullrequest_approvals", Description: "Reset approvals only when the diff changes"},
+	{Kind: "reset_pullrequest_changes_requested_on_change", Description: "Reset change requests when the source branch changes"},
+	{Kind: "enforce_merge_checks", Description: "Block merging until merge checks pass (Premium)"},
+	{Kind: "allow_auto_merge_when_builds_pass", Description: "Allow auto-merge once builds pass"},
+}
+
+// BranchingModelTypes are the branch types a restriction can target instead
+// of a pattern.
+var BranchingModelTypes = []string{"development", "production", "feature", "bugfix", "release", "hotfix"}
+
+func branchRestrictionKind(kind string) (BranchRestrictionKind, bool) {
+	for _, k := range BranchRestrictionKinds {
+		if k.Kind == kind {
+			return k, true
+		}
+	}
+	return BranchRestrictionKind{}, false
+}
+
+// BranchRestrictionSpec is a restriction as written by a user: in flags, a
+// policy file, or a tool call. Exactly one of Pattern and BranchType is set.
+type BranchRestrictionSpec struct {
+	Kind       string   `json:"kind" yaml:"kind" jsonschema:"Restriction kind, e.g. push, force, delete, require_approvals_to_merge"`
+	Pattern    string   `json:"pattern,omitempty" yaml:"pattern,omitempty" jsonschema:"Branch name or glob, e.g. main or release/*"`
+	BranchType string   `json:"branch_type,omitempty" yaml:"branch_type,omitempty" jsonschema:"Branching model type instead of a pattern: development, production, feature, bugfix, release, hotfix"`
+	Value      *int     `json:"value,omitempty" yaml:"value,omitempty" jsonschema:"Numeric value for kinds that take one, e.g. required approvals"`
+	Users      []string `json:"users,omitempty" yaml:"users,omitempty" jsonschema:"Exempt users by UUID or account ID (push and restrict_merges only)"`
+	Groups     []string `json:"groups,omitempty" yaml:"groups,omitempty" jsonschema:"Exempt group slugs (push and restrict_merges only)"`
+}
+
+// Validate checks the spec against the kind catalog.
+func (s BranchRestrictionSpec) Validate() error {
+	k, ok := branchRestrictionKind(s.Kind)
+	if !ok {
+		return fmt.Errorf("unknown restriction kind %q", s.Kind)
+	}
+	switch {
+	case s.Pattern == "" && s.BranchType == "":
+		return fmt.Errorf("%s: pattern or branch_type is required", s.Kind)
+	case s.Pattern != "" && s.BranchType != "":
+		return fmt.Errorf("%s: pattern and branch_type are mutually exclusive", s.Kind)
+	case s.BranchType != "" && !containsString(BranchingModelTypes, s.BranchType):
+		return fmt.Errorf("%s: unknown branch_type %q; must be one of %s", s.Kind, s.BranchType, strings.Join(BranchingModelTypes, ", "))
+	case k.HasValue && s.Value == nil:
+		return fmt.Errorf("%s: value is required", s.Kind)
+	case k.HasValue && *s.Value < 0:
+		return fmt.Errorf("%s: value must not be negative", s.Kind)
+	case !k.HasValue && s.Value != nil:
+		return fmt.Errorf("%s does not take a value", s.Kind)
+	case !k.Exemptions && (len(s.Users) > 0 || len(s.Groups) > 0):
+		return fmt.Errorf("%s does not take users or groups", s.Kind)
+	}
+	return nil
+}
+
+// Target is the spec's branch target in BranchRestriction.Target form.
+func (s BranchRestrictionSpec) Target() string {
+	if s.BranchType != "" {
+		return "type:" + s.BranchType
+	}
+	return s.Pattern
+}
+
+// uuidPattern matches a user UUID, with or without its braces. Account
+// IDs (e.g. 557058:6f3c... or a bare 24-digit hex string) never do.
+var uuidPattern = regexp.MustCompile(`^\{?[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\}?$`)
+
+// body renders the spec as an API request body.
+func (s BranchRestrictionSpec) body() map[string]any {
+	b := map[string]any{"kind": s.Kind}
+	if s.BranchType != "" {
+		b["branch_match_kind"] = "branching_model"
+		b["branch_type"] = s.BranchType
+	} else {
+		b["branch_match_kind"] = "glob"
+		b["pattern"] = s.Pattern
+	}
+	if s.Value != nil {
+		b["value"] = *s.Value
+	}
+	if k, _ := branchRestrictionKind(s.Kind); k.Exemptions {
+		users := []map[string]string{}
+		for _, u := range s.Users {
+			if strings.HasPrefix(u, "{") || uuidPattern.MatchString(u) {
+				users = append(users, map[string]string{"uuid": normalizeUUID(u)})
+			} else {
+				users = append(users, map[string]string{"account_id": u})
+			}
+		}
+		groups := []map[string]string{}
+		for _, g := range s.Groups {
+			groups = append(groups, map[string]string{"slug": g})
+		}
+		b["users"] = users
+		b["groups"] = groups
+	}
+	return b
+}
+
+type ListBranchRestrictionsArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+	Kind      string `json:"kind,omitempty" jsonschema:"Only this kind"`
+	Pattern   string `json:"pattern,omitempty" jsonschema:"Only restrictions with this exact pattern"`
+}
+
+// maxBranchRestrictionPages bounds ListBranchRestrictions.
+const maxBranchRestrictionPages = 10
+
+// ListBranchRestrictions lists every branch restriction on a repository.
+func (c *Client) ListBranchRestrictions(args ListBranchRestrictionsArgs) ([]BranchRestriction, error) {
+	if args.Workspace == "" || args.RepoSlug == "" {
+		return nil, fmt.Errorf("workspace and repo_slug are required")
+	}
+
+	query := ""
+	if args.Kind != "" {
+		query += "&kind=" + QueryEscape(args.Kind)
+	}
+	if args.Pattern != "" {
+		query += "&pattern=" + QueryEscape(args.Pattern)
+	}
+
+	out := []BranchRestriction{}
+	for page := 1; page <= maxBranchRestrictionPages; page++ {
+		result, err := GetPaginated[BranchRestriction](c, fmt.Sprintf("/repositories/%s/%s/branch-restrictions?pagelen=100&page=%d%s",
+			QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), page, query))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, result.Values...)
+		if result.Next == "" {
+			break
+		}
+	}
+	return out, nil
+}
+
+type BranchRestrictionArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+	ID        int    `json:"id" jsonschema:"Branch restriction ID"`
+}
+
+func (a BranchRestrictionArgs) path() (string, error) {
+	if a.Workspace == "" || a.RepoSlug == "" || a.ID == 0 {
+		return "", fmt.Errorf("workspace, repo_slug, and id are required")
+	}
+	return fmt.Sprintf("/repositories/%s/%s/branch-restrictions/%d",
+		QueryEscape(a.Workspace), QueryEscape(a.RepoSlug), a.ID), nil
+}
+
+// GetBranchRestriction gets a single branch restriction.
+func (c *Client) GetBranchRestriction(args BranchRestrictionArgs) (*BranchRestriction, error) {
+	path, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	return GetJSON[BranchRestriction](c, path)
+}
+
+// DeleteBranchRestriction deletes a branch restriction.
+func (c *Client) DeleteBranchRestriction(args BranchRestrictionArgs) error {
+	path, err := args.path()
+	if err != nil {
+		return err
+	}
+	return c.Delete(path)
+}
+
+type CreateBranchRestrictionArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+	BranchRestrictionSpec
+}
+
+// CreateBranchRestriction creates a branch restriction.
+func (c *Client) CreateBranchRestriction(args CreateBranchRestrictionArgs) (*BranchRestriction, error) {
+	if args.Workspace == "" || args.RepoSlug == "" {
+		return nil, fmt.Errorf("workspace and repo_slug are required")
+	}
+	if err := args.Validate(); err != nil {
+		return nil, err
+	}
+
+	respData, err := c.Post(fmt.Sprintf("/repositories/%s/%s/branch-restrictions",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug)), args.body())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create branch restriction: %v", err)
+	}
+
+	var out BranchRestriction
+	if err := json.Unmarshal(respData, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &out, nil
+}
+
+type UpdateBranchRestrictionArgs struct {
+	BranchRestrictionArgs
+	BranchRestrictionSpec
+}
+
+// UpdateBranchRestriction replaces a branch restriction's settings.
+func (c *Client) UpdateBranchRestriction(args UpdateBranchRestrictionArgs) (*BranchRestriction, error) {
+	path, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	if err := args.Validate(); err != nil {
+		return nil, err
+	}
+
+	respData, err := c.Put(path, args.body())
+	if err != nil {
+		return nil, fmt.Errorf("failed to update branch restriction: %v", err)
+	}
+
+	var out BranchRestriction
+	if err := json.Unmarshal(respData, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &out, nil
+}
+
+// ─── Plan / apply ────────────────────────────────────────────────────────────
+
+// Change actions in a BranchRestrictionChange.
+const (
+	ChangeCreate = "create"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+	ChangeKeep   = "keep"
+)
+
+// BranchRestrictionChange is one step of a branch restriction plan. Current is
+// nil for creates; Desired is nil for deletes and unmanaged restrictions.
+type BranchRestrictionChange struct {
+	Action  string                 `json:"action"`
+	Current *BranchRestriction     `json:"current,omitempty"`
+	Desired *BranchRestrictionSpec `json:"desired,omitempty"`
+	// Diff lists the fields an update changes, e.g. "value: 1 -> 2".
+	Diff []string `json:"diff,omitempty"`
+}
+
+// Key identifies the restriction the change is about, e.g. "push main".
+func (ch BranchRestrictionChange) Key() string {
+	if ch.Desired != nil {
+		return ch.Desired.Kind + " " + ch.Desired.Target()
+	}
+	return ch.Current.Kind + " " + ch.Current.Target()
+}
+
+// PlanBranchRestrictions compares the current restrictions with the desired
+// ones. Restrictions are matched on kind and target; a desired one with no
+// match is created, one whose value or exemptions differ is updated. Current
+// restrictions nothing matches are deleted when prune is set and kept
+// otherwise. The plan is sorted by key.
+func PlanBranchRestrictions(current []BranchRestriction, desired []BranchRestrictionSpec, prune bool) ([]BranchRestrictionChange, error) {
+	seen := map[string]bool{}
+	for _, d := range desired {
+		if err := d.Validate(); err != nil {
+			return nil, err
+		}
+		key := d.Kind + " " + d.Target()
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate restriction %q", key)
+		}
+		seen[key] = true
+	}
+
+	matched := map[int]bool{}
+	var plan []BranchRestrictionChange
+	for i := range desired {
+		d := &desired[i]
+		var cur *BranchRestriction
+		for j := range current {
+			if !matched[j] && current[j].Kind == d.Kind && current[j].Target() == d.Target() {
+				cur = &current[j]
+				matched[j] = true
+				break
+			}
+		}
+		if cur == nil {
+			plan = append(plan, BranchRestrictionChange{Action: ChangeCreate, Desired: d})
+			continue
+		}
+		ch := BranchRestrictionChange{Action: ChangeKeep, Current: cur, Desired: d, Diff: restrictionDiff(cur, d)}
+		if len(ch.Diff) > 0 {
+			ch.Action = ChangeUpdate
+		}
+		plan = append(plan, ch)
+	}
+	for j := range current {
+		if matched[j] {
+			continue
+		}
+		action := ChangeKeep
+		if prune {
+			action = ChangeDelete
+		}
+		plan = append(plan, BranchRestrictionChange{Action: action, Current: &current[j]})
+	}
+
+	sort.SliceStable(plan, func(a, b int) bool { return plan[a].Key() < plan[b].Key() })
+	return plan, nil
+}
+
+func restrictionDiff(cur *BranchRestriction, d *BranchRestrictionSpec) []string {
+	var diff []string
+	if d.Value != nil && (cur.Value == nil || *cur.Value != *d.Value) {
+		from := "none"
+		if cur.Value != nil {
+			from = fmt.Sprint(*cur.Value)
+		}
+		diff = append(diff, fmt.Sprintf("value: %s -> %d", from, *d.Value))
+	}
+	if k, _ := branchRestrictionKind(d.Kind); !k.Exemptions {
+		return diff
+	}
+
+	var curUsers []string
+	for _, u := range cur.Users {
+		id := matchesUser(d.Users, u)
+		if id == "" {
+			id = u.AccountID
+		}
+		if id == "" {
+			id = u.UUID
+		}
+		curUsers = append(curUsers, id)
+	}
+	if !sameSet(curUsers, d.Users) {
+		diff = append(diff, fmt.Sprintf("users: [%s] -> [%s]", strings.Join(sorted(curUsers), ", "), strings.Join(sorted(d.Users), ", ")))
+	}
+	var curGroups []string
+	for _, g := range cur.Groups {
+		curGroups = append(curGroups, g.Slug)
+	}
+	if !sameSet(curGroups, d.Groups) {
+		diff = append(diff, fmt.Sprintf("groups: [%s] -> [%s]", strings.Join(sorted(curGroups), ", "), strings.Join(sorted(d.Groups), ", ")))
+	}
+	return diff
+}
+
+// matchesUser returns the identifier in ids that refers to u, or "".
+func matchesUser(ids []string, u User) string {
+	for _, id := range ids {
+		if id == u.AccountID || (u.UUID != "" && normalizeUUID(id) == u.UUID) {
+			return id
+		}
+	}
+	return ""
+}
+
+func sameSet(a, b []string) bool {
+	a, b = dedupeStrings(sorted(a)), dedupeStrings(sorted(b))
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sorted(in []string) []string {
+	out := append([]string(nil), in...)
+	sort.Strings(out)
+	return out
+}
+
+// ApplyBranchRestrictions carries out a plan's creates, updates, and deletes
+// in order, stopping at the first error. It returns the changes applied.
+func (c *Client) ApplyBranchRestrictions(workspace, repoSlug string, plan []BranchRestrictionChange) (int, error) {
+	applied := 0
+	for _, ch := range plan {
+		var err error
+		switch ch.Action {
+		case ChangeCreate:
+			_, err = c.CreateBranchRestriction(CreateBranchRestrictionArgs{
+				Workspace: workspace, RepoSlug: repoSlug, BranchRestrictionSpec: *ch.Desired,
+			})
+		case ChangeUpdate:
+			_, err = c.UpdateBranchRestriction(UpdateBranchRestrictionArgs{
+				BranchRestrictionArgs: BranchRestrictionArgs{Workspace: workspace, RepoSlug: repoSlug, ID: ch.Current.ID},
+				BranchRestrictionSpec: *ch.Desired,
+			})
+		case ChangeDelete:
+			err = c.DeleteBranchRestriction(BranchRestrictionArgs{Workspace: workspace, RepoSlug: repoSlug, ID: ch.Current.ID})
+		default:
+			continue
+		}
+		if err != nil {
+			return applied, fmt.Errorf("%s %s: %v", ch.Action, ch.Key(), err)
+		}
+		applied++
+	}
+	return applied, nil
+}
@@ -0,0 +1,179 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestBranchRestrictionSpecValidate(t *testing.T) {
+	for _, c := range []struct {
+		spec BranchRestrictionSpec
+		want string
+	}{
+		{BranchRestrictionSpec{Kind: "push", Pattern: "main", Groups: []string{"admins"}}, ""},
+		{BranchRestrictionSpec{Kind: "delete", BranchType: "production"}, ""},
+		{BranchRestrictionSpec{Kind: "require_approvals_to_merge", Pattern: "main", Value: intPtr(2)}, ""},
+		{BranchRestrictionSpec{Kind: "pushy", Pattern: "main"}, "unknown restriction kind"},
+		{BranchRestrictionSpec{Kind: "force"}, "pattern or branch_type is required"},
+		{BranchRestrictionSpec{Kind: "force", Pattern: "main", BranchType: "release"}, "mutually exclusive"},
+		{BranchRestrictionSpec{Kind: "force", BranchType: "trunk"}, "unknown branch_type"},
+		{BranchRestrictionSpec{Kind: "require_approvals_to_merge", Pattern: "main"}, "value is required"},
+		{BranchRestrictionSpec{Kind: "delete", Pattern: "main", Value: intPtr(1)}, "does not take a value"},
+		{BranchRestrictionSpec{Kind: "force", Pattern: "main", Users: []string{"x"}}, "does not take users"},
+	} {
+		err := c.spec.Validate()
+		if (c.want == "") != (err == nil) || (err != nil && !strings.Contains(err.Error(), c.want)) {
+			t.Errorf("%+v: err = %v, want %q", c.spec, err, c.want)
+		}
+	}
+}
+
+func TestBranchRestrictionSpecBody_UserReferences(t *testing.T) {
+	spec := BranchRestrictionSpec{Kind: "push", Pattern: "main", Users: []string{
+		"557058:6f3c2b1a-0d4e-4f5a-9b8c-7d6e5f4a3b2c",
+		"0b4a6b6e-64b5-4a4e-9a3e-1c3b2f7f2a10",
+		"{1c3b2f7f-64b5-4a4e-9a3e-0b4a6b6e2a10}",
+	}}
+	got := spec.body()["users"].([]map[string]string)
+	want := []map[string]string{
+		{"account_id": "557058:6f3c2b1a-0d4e-4f5a-9b8c-7d6e5f4a3b2c"},
+		{"uuid": "{0b4a6b6e-64b5-4a4e-9a3e-1c3b2f7f2a10}"},
+		{"uuid": "{1c3b2f7f-64b5-4a4e-9a3e-0b4a6b6e2a10}"},
+	}
+	for i := range want {
+		if len(got) != len(want) || len(got[i]) != 1 || got[i]["uuid"] != want[i]["uuid"] || got[i]["account_id"] != want[i]["account_id"] {
+			t.Fatalf("users = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestPlanBranchRestrictions(t *testing.T) {
+	current := []BranchRestriction{
+		{ID: 1, Kind: "push", BranchMatchKind: "glob", Pattern: "main",
+			Users: []User{{UUID: "{u1}", AccountID: "acc1"}}, Groups: []Group{{Slug: "admins"}}},
+		{ID: 2, Kind: "require_approvals_to_merge", BranchMatchKind: "glob", Pattern: "main", Value: intPtr(1)},
+		{ID: 3, Kind: "force", BranchMatchKind: "glob", Pattern: "main"},
+		{ID: 4, Kind: "delete", BranchMatchKind: "glob", Pattern: "legacy"},
+	}
+	desired := []BranchRestrictionSpec{
+		// Same users, referenced by UUID instead of account ID.
+		{Kind: "push", Pattern: "main", Users: []string{"u1"}, Groups: []string{"admins"}},
+		{Kind: "require_approvals_to_merge", Pattern: "main", Value: intPtr(2)},
+		{Kind: "force", Pattern: "main"},
+		{Kind: "delete", BranchType: "production"},
+	}
+
+	plan, err := PlanBranchRestrictions(current, desired, false)
+	if err != nil {
+		t.Fatalf("PlanBranchRestrictions: %v", err)
+	}
+	got := map[string]string{}
+	for _, ch := range plan {
+		got[ch.Key()] = ch.Action
+	}
+	want := map[string]string{
+		"push main":                       ChangeKeep,
+		"require_approvals_to_merge main": ChangeUpdate,
+		"force main":                      ChangeKeep,
+		"delete type:production":          ChangeCreate,
+		"delete legacy":                   ChangeKeep,
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: %s, want %s", k, got[k], v)
+		}
+	}
+	for _, ch := range plan {
+		if ch.Action == ChangeUpdate && (len(ch.Diff) != 1 || ch.Diff[0] != "value: 1 -> 2") {
+			t.Errorf("diff = %v", ch.Diff)
+		}
+	}
+
+	plan, _ = PlanBranchRestrictions(current, desired, true)
+	for _, ch := range plan {
+		if ch.Key() == "delete legacy" && ch.Action != ChangeDelete {
+			t.Errorf("prune: delete legacy is %s", ch.Action)
+		}
+	}
+
+	desired[0].Groups = nil
+	plan, _ = PlanBranchRestrictions(current, desired, false)
+	// The plan is sorted by key.
+	if plan[0].Key() != "delete legacy" || plan[3].Key() != "push main" || plan[3].Action != ChangeUpdate {
+		t.Errorf("plan = %+v", plan)
+	}
+	if d := plan[3].Diff; len(d) != 1 || d[0] != "groups: [admins] -> []" {
+		t.Errorf("diff = %v", d)
+	}
+
+	if _, err := PlanBranchRestrictions(nil, []BranchRestrictionSpec{desired[2], desired[2]}, false); err == nil {
+		t.Error("expected duplicate error")
+	}
+}
+
+func TestParseBranchPolicy(t *testing.T) {
+	p, err := ParseBranchPolicy([]byte(`
+repositories: [api, "svc-*"]
+prune: true
+restrictions:
+  - kind: push
+    pattern: main
+    groups: [release-managers]
+  - kind: require_approvals_to_merge
+    pattern: main
+    value: 2
+`))
+	if err != nil {
+		t.Fatalf("ParseBranchPolicy: %v", err)
+	}
+	if !p.Prune || len(p.Repositories) != 2 || len(p.Restrictions) != 2 || *p.Restrictions[1].Value != 2 {
+		t.Errorf("policy = %+v", p)
+	}
+
+	if _, err := ParseBranchPolicy([]byte("restrictions:\n  - kind: push\n    patern: main\n")); err == nil {
+		t.Error("expected error for an unknown field")
+	}
+	if _, err := ParseBranchPolicy([]byte("restrictions: []\n")); err == nil {
+		t.Error("expected error for an empty policy")
+	}
+}
+
+func TestApplyBranchRestrictions(t *testing.T) {
+	var calls []string
+	var created map[string]any
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPost {
+			b, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(b, &created)
+		}
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": 9}`))
+	})
+
+	plan := []BranchRestrictionChange{
+		{Action: ChangeCreate, Desired: &BranchRestrictionSpec{Kind: "delete", BranchType: "production"}},
+		{Action: ChangeKeep, Current: &BranchRestriction{ID: 3, Kind: "force", Pattern: "main"}},
+		{Action: ChangeUpdate, Current: &BranchRestriction{ID: 2}, Desired: &BranchRestrictionSpec{Kind: "require_approvals_to_merge", Pattern: "main", Value: intPtr(2)}},
+		{Action: ChangeDelete, Current: &BranchRestriction{ID: 4, Kind: "delete", Pattern: "legacy"}},
+	}
+	n, err := c.ApplyBranchRestrictions("w", "r", plan)
+	if err != nil {
+		t.Fatalf("ApplyBranchRestrictions: %v", err)
+	}
+	want := "POST /repositories/w/r/branch-restrictions|PUT /repositories/w/r/branch-restrictions/2|DELETE /repositories/w/r/branch-restrictions/4"
+	if n != 3 || strings.Join(calls, "|") != want {
+		t.Errorf("applied %d, calls = %v", n, calls)
+	}
+	if created["branch_match_kind"] != "branching_model" || created["branch_type"] != "production" {
+		t.Errorf("create body = %v", created)
+	}
+}
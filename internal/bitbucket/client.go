@@ -32,6 +32,10 @@ type Client struct {
 	scopesFetched  bool
 
 	mu sync.Mutex
+
+	// Issue tracker milestones, components, and versions, by endpoint.
+	issueOptions   map[string][]IssueOption
+	issueOptionsMu sync.Mutex
 }
 
 // NewClient creates a Bitbucket API client.
@@ -298,6 +302,24 @@ func (c *Client) Post(path string, body interface{}) ([]byte, error) {
 // PostMultipart performs a POST request using multipart/form-data.
 // It takes a map of form fields and a map of file fields (where key is the field name and value is the file content).
 func (c *Client) PostMultipart(path string, fields map[string]string, files map[string][]byte) ([]byte, error) {
+	// Bitbucket API expects the form field name to be the file path, so the
+	// key is used as both fieldname and filename.
+	parts := make([]MultipartFile, 0, len(files))
+	for key, fileBytes := range files {
+		parts = append(parts, MultipartFile{Field: key, Filename: key, Reader: bytes.NewReader(fileBytes)})
+	}
+	return c.sendMultipart(http.MethodPost, path, fields, parts)
+}
+
+// PutMultipart performs a PUT request using multipart/form-data, with each
+// file sent under its own field name.
+func (c *Client) PutMultipart(path string, fields map[string]string, files []MultipartFile) ([]byte, error) {
+	return c.sendMultipart(http.MethodPut, path, fields, files)
+}
+
+// sendMultipart buffers a multipart/form-data body and sends it. Use
+// PostMultipartStream for files too large to hold in memory.
+func (c *Client) sendMultipart(method, path string, fields map[string]string, files []MultipartFile) ([]byte, error) {
 	var b bytes.Buffer
 	w := multipart.NewWriter(&b)
 
@@ -307,15 +329,13 @@ func (c *Client) PostMultipart(path string, fields map[string]string, files map[
 		}
 	}
 
-	for key, fileBytes := range files {
-		// Bitbucket API expects the form field name to be the file path.
-		// We use CreateFormFile with the key as both fieldname and filename.
-		fw, err := w.CreateFormFile(key, key)
+	for _, f := range files {
+		fw, err := w.CreateFormFile(f.Field, f.Filename)
 		if err != nil {
-			return nil, fmt.Errorf("creating form file %s: %w", key, err)
+			return nil, fmt.Errorf("creating form file %s: %w", f.Filename, err)
 		}
-		if _, err := fw.Write(fileBytes); err != nil {
-			return nil, fmt.Errorf("writing file %s: %w", key, err)
+		if _, err := io.Copy(fw, f.Reader); err != nil {
+			return nil, fmt.Errorf("writing file %s: %w", f.Filename, err)
 		}
 	}
 
@@ -323,7 +343,7 @@ func (c *Client) PostMultipart(path string, fields map[string]string, files map[
 		return nil, fmt.Errorf("closing multipart writer: %w", err)
 	}
 
-	resp, err := c.do(http.MethodPost, path, b.Bytes(), w.FormDataContentType())
+	resp, err := c.do(method, path, b.Bytes(), w.FormDataContentType())
 	if err != nil {
 		return nil, err
 	}
@@ -413,6 +433,27 @@ func GetPaginated[T any](c *Client, path string) (*Paginated[T], error) {
 	return &result, nil
 }
 
+// listAllPages follows up to maxPages pages of path, 100 values at a time.
+// path may already carry a query string.
+func listAllPages[T any](c *Client, path string, maxPages int) ([]T, error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	out := []T{}
+	for page := 1; page <= maxPages; page++ {
+		result, err := GetPaginated[T](c, fmt.Sprintf("%s%spagelen=100&page=%d", path, sep, page))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, result.Values...)
+		if result.Next == "" {
+			break
+		}
+	}
+	return out, nil
+}
+
 // GetJSON performs a GET and unmarshals the JSON response.
 func GetJSON[T any](c *Client, path string) (*T, error) {
 	data, err := c.Get(path)
@@ -440,6 +481,12 @@ func parseAPIError(statusCode int, body []byte) error {
 	return fmt.Errorf("API error %d: %s", statusCode, string(body))
 }
 
+// isNotFound reports whether err is the parseAPIError form of a 404, for
+// endpoints where "absent" is an expected answer rather than a failure.
+func isNotFound(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "API error 404:")
+}
+
 // AuthError captures the diagnostic signature of a 401/403 from Bitbucket so
 // callers can distinguish "classic unscoped Atlassian token rejected by
 // policy" from "scoped token missing required scope" — both surface as 401
@@ -0,0 +1,164 @@
+package bitbucket
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// CodeSearchSegment is a piece of a matched line or path. Match is set on
+// the pieces that matched the query.
+type CodeSearchSegment struct {
+	Text  string `json:"text"`
+	Match bool   `json:"match,omitempty"`
+}
+
+// CodeSearchLine is one line of a content match. Lines without a matching
+// segment are context around the match.
+type CodeSearchLine struct {
+	Line     int                 `json:"line"`
+	Segments []CodeSearchSegment `json:"segments"`
+}
+
+// Text returns the full line.
+func (l CodeSearchLine) Text() string {
+	var b strings.Builder
+	for _, s := range l.Segments {
+		b.WriteString(s.Text)
+	}
+	return b.String()
+}
+
+// IsMatch reports whether the line contains a match rather than context.
+func (l CodeSearchLine) IsMatch() bool {
+	for _, s := range l.Segments {
+		if s.Match {
+			return true
+		}
+	}
+	return false
+}
+
+// CodeSearchContentMatch is a block of consecutive lines around a match.
+type CodeSearchContentMatch struct {
+	Lines []CodeSearchLine `json:"lines"`
+}
+
+// CodeSearchFile is the file a search result was found in.
+type CodeSearchFile struct {
+	Path   string `json:"path"`
+	Commit *struct {
+		Hash       string   `json:"hash"`
+		Repository *MinRepo `json:"repository,omitempty"`
+	} `json:"commit,omitempty"`
+}
+
+// CodeSearchResult is one file matching a code search.
+type CodeSearchResult struct {
+	ContentMatchCount int                      `json:"content_match_count"`
+	ContentMatches    []CodeSearchContentMatch `json:"content_matches"`
+	PathMatches       []CodeSearchSegment      `json:"path_matches"`
+	File              CodeSearchFile           `json:"file"`
+}
+
+// Repository returns the full name of the repository the file is in.
+func (r CodeSearchResult) Repository() string {
+	if r.File.Commit != nil && r.File.Commit.Repository != nil {
+		return r.File.Commit.Repository.FullName
+	}
+	return ""
+}
+
+// CodeSearchResults is a page of code search results.
+type CodeSearchResults struct {
+	Paginated[CodeSearchResult]
+	QuerySubstituted bool `json:"query_substituted"`
+}
+
+// CodeSearchMatch is a single matching line, the compact form of a result.
+// Line is 0 when only the file's path matched.
+type CodeSearchMatch struct {
+	Repository string   `json:"repository"`
+	Path       string   `json:"path"`
+	Line       int      `json:"line,omitempty"`
+	Text       string   `json:"text,omitempty"`
+	Matches    []string `json:"matches,omitempty"`
+}
+
+// Matches flattens results into one entry per matching line, dropping
+// context lines.
+func (r *CodeSearchResults) Matches() []CodeSearchMatch {
+	out := []CodeSearchMatch{}
+	for _, res := range r.Values {
+		repo := res.Repository()
+		found := false
+		for _, cm := range res.ContentMatches {
+			for _, l := range cm.Lines {
+				if !l.IsMatch() {
+					continue
+				}
+				m := CodeSearchMatch{Repository: repo, Path: res.File.Path, Line: l.Line, Text: l.Text()}
+				for _, s := range l.Segments {
+					if s.Match {
+						m.Matches = append(m.Matches, s.Text)
+					}
+				}
+				out = append(out, m)
+				found = true
+			}
+		}
+		if !found {
+			out = append(out, CodeSearchMatch{Repository: repo, Path: res.File.Path})
+		}
+	}
+	return out
+}
+
+type SearchCodeArgs struct {
+	Workspace   string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug    string `json:"repo_slug,omitempty" jsonschema:"Repository slug (omit to search the whole workspace)"`
+	SearchQuery string `json:"query" jsonschema:"Search query; may include repo:, lang:, ext:, and path: qualifiers"`
+	Language    string `json:"language,omitempty" jsonschema:"Only files in this language (adds lang:)"`
+	Extension   string `json:"extension,omitempty" jsonschema:"Only files with this extension (adds ext:)"`
+	Pagelen     int    `json:"pagelen,omitempty" jsonschema:"Results per page (default: 25)"`
+	Page        int    `json:"page,omitempty" jsonschema:"Page number"`
+}
+
+// query returns the search query with the qualifiers the args imply.
+func (a SearchCodeArgs) query() string {
+	q := a.SearchQuery
+	for _, qual := range []struct{ key, value string }{
+		{"repo", a.RepoSlug},
+		{"lang", a.Language},
+		{"ext", strings.TrimPrefix(a.Extension, ".")},
+	} {
+		if qual.value != "" && !strings.Contains(q, qual.key+":") {
+			q += " " + qual.key + ":" + qual.value
+		}
+	}
+	return q
+}
+
+// SearchCode searches code across a workspace using Bitbucket's code search,
+// narrowed to a repository when RepoSlug is set. Code search must be enabled
+// for the workspace.
+func (c *Client) SearchCode(args SearchCodeArgs) (*CodeSearchResults, error) {
+	if args.Workspace == "" || args.SearchQuery == "" {
+		return nil, fmt.Errorf("workspace and query are required")
+	}
+
+	pagelen := args.Pagelen
+	if pagelen == 0 {
+		pagelen = 25
+	}
+	page := args.Page
+	if page == 0 {
+		page = 1
+	}
+
+	// The repository a file belongs to is only returned when asked for.
+	endpoint := fmt.Sprintf("/workspaces/%s/search/code?search_query=%s&pagelen=%d&page=%d&fields=%s",
+		QueryEscape(args.Workspace), url.QueryEscape(args.query()), pagelen, page, url.QueryEscape("+values.file.commit.repository"))
+
+	return GetJSON[CodeSearchResults](c, endpoint)
+}
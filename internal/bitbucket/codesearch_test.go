@@ -0,0 +1,60 @@
+package bitbucket
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSearchCode(t *testing.T) {
+	var path, query, fields string
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		query = r.URL.Query().Get("search_query")
+		fields = r.URL.Query().Get("fields")
+		_, _ = w.Write([]byte(`{
+			"size": 2, "page": 1, "pagelen": 25,
+			"values": [
+				{
+					"content_match_count": 1,
+					"content_matches": [{"lines": [
+						{"line": 9, "segments": [{"text": "// setup"}]},
+						{"line": 10, "segments": [{"text": "func "}, {"text": "NewClient", "match": true}, {"text": "() {"}]}
+					]}],
+					"path_matches": [{"text": "client.go"}],
+					"file": {"path": "client.go", "commit": {"hash": "abc", "repository": {"full_name": "w/api"}}}
+				},
+				{
+					"content_match_count": 0,
+					"path_matches": [{"text": "newclient", "match": true}, {"text": ".md"}],
+					"file": {"path": "newclient.md", "commit": {"repository": {"full_name": "w/docs"}}}
+				}
+			]
+		}`))
+	})
+
+	res, err := c.SearchCode(SearchCodeArgs{Workspace: "w", RepoSlug: "api", SearchQuery: "NewClient lang:go", Extension: ".go"})
+	if err != nil {
+		t.Fatalf("SearchCode: %v", err)
+	}
+	if path != "/workspaces/w/search/code" || query != "NewClient lang:go repo:api ext:go" || fields != "+values.file.commit.repository" {
+		t.Errorf("request = %s %q %q", path, query, fields)
+	}
+	if res.Size != 2 || res.Values[0].Repository() != "w/api" {
+		t.Errorf("results = %+v", res)
+	}
+
+	matches := res.Matches()
+	if len(matches) != 2 {
+		t.Fatalf("matches = %+v", matches)
+	}
+	if m := matches[0]; m.Line != 10 || m.Text != "func NewClient() {" || len(m.Matches) != 1 || m.Matches[0] != "NewClient" {
+		t.Errorf("content match = %+v", m)
+	}
+	if m := matches[1]; m.Repository != "w/docs" || m.Path != "newclient.md" || m.Line != 0 {
+		t.Errorf("path match = %+v", m)
+	}
+
+	if _, err := c.SearchCode(SearchCodeArgs{Workspace: "w"}); err == nil {
+		t.Error("SearchCode without a query succeeded")
+	}
+}
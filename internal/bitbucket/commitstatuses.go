@@ -0,0 +1,231 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Commit build status states.
+const (
+	StatusSuccessful = "SUCCESSFUL"
+	StatusFailed     = "FAILED"
+	StatusInProgress = "INPROGRESS"
+	StatusStopped    = "STOPPED"
+)
+
+// CommitStatus is a build result reported against a commit, by Pipelines or
+// an external CI system.
+type CommitStatus struct {
+	UUID        string    `json:"uuid,omitempty"`
+	Key         string    `json:"key"`
+	Name        string    `json:"name,omitempty"`
+	State       string    `json:"state"`
+	URL         string    `json:"url"`
+	Description string    `json:"description,omitempty"`
+	RefName     string    `json:"refname,omitempty"`
+	CreatedOn   time.Time `json:"created_on"`
+	UpdatedOn   time.Time `json:"updated_on"`
+}
+
+type ListCommitStatusesArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+	Commit    string `json:"commit" jsonschema:"Commit hash"`
+	Pagelen   int    `json:"pagelen,omitempty" jsonschema:"Results per page"`
+	Page      int    `json:"page,omitempty" jsonschema:"Page number"`
+}
+
+// ListCommitStatuses lists the build statuses reported for a commit.
+func (c *Client) ListCommitStatuses(args ListCommitStatusesArgs) (*Paginated[CommitStatus], error) {
+	if args.Workspace == "" || args.RepoSlug == "" || args.Commit == "" {
+		return nil, fmt.Errorf("workspace, repo_slug, and commit are required")
+	}
+
+	pagelen := args.Pagelen
+	if pagelen == 0 {
+		pagelen = 25
+	}
+	page := args.Page
+	if page == 0 {
+		page = 1
+	}
+
+	return GetPaginated[CommitStatus](c, fmt.Sprintf("/repositories/%s/%s/commit/%s/statuses?pagelen=%d&page=%d&sort=-updated_on",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), QueryEscape(args.Commit), pagelen, page))
+}
+
+type ListPullRequestStatusesArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+	PRID      int    `json:"pr_id" jsonschema:"Pull request ID"`
+	Pagelen   int    `json:"pagelen,omitempty" jsonschema:"Results per page"`
+	Page      int    `json:"page,omitempty" jsonschema:"Page number"`
+}
+
+// ListPullRequestStatuses lists the build statuses reported for a pull
+// request's commits.
+func (c *Client) ListPullRequestStatuses(args ListPullRequestStatusesArgs) (*Paginated[CommitStatus], error) {
+	if args.Workspace == "" || args.RepoSlug == "" || args.PRID == 0 {
+		return nil, fmt.Errorf("workspace, repo_slug, and pr_id are required")
+	}
+
+	pagelen := args.Pagelen
+	if pagelen == 0 {
+		pagelen = 50
+	}
+	page := args.Page
+	if page == 0 {
+		page = 1
+	}
+
+	return GetPaginated[CommitStatus](c, fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/statuses?pagelen=%d&page=%d&sort=-updated_on",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), args.PRID, pagelen, page))
+}
+
+type SetCommitStatusArgs struct {
+	Workspace   string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug    string `json:"repo_slug" jsonschema:"Repository slug"`
+	Commit      string `json:"commit" jsonschema:"Commit hash"`
+	Key         string `json:"key" jsonschema:"Unique key for this build, e.g. the CI job name; reusing a key updates the status"`
+	State       string `json:"state" jsonschema:"SUCCESSFUL, FAILED, INPROGRESS, or STOPPED"`
+	URL         string `json:"url" jsonschema:"Link to the build results"`
+	Name        string `json:"name,omitempty" jsonschema:"Display name (default the key)"`
+	Description string `json:"description,omitempty" jsonschema:"Short result description"`
+	RefName     string `json:"refname,omitempty" jsonschema:"Branch or tag the build ran for"`
+}
+
+// SetCommitStatus creates a build status on a commit, or updates the one
+// with the same key.
+func (c *Client) SetCommitStatus(args SetCommitStatusArgs) (*CommitStatus, error) {
+	if args.Workspace == "" || args.RepoSlug == "" || args.Commit == "" {
+		return nil, fmt.Errorf("workspace, repo_slug, and commit are required")
+	}
+	if args.Key == "" || args.URL == "" {
+		return nil, fmt.Errorf("key and url are required")
+	}
+	state := strings.ToUpper(args.State)
+	switch state {
+	case StatusSuccessful, StatusFailed, StatusInProgress, StatusStopped:
+	default:
+		return nil, fmt.Errorf("invalid state %q: must be SUCCESSFUL, FAILED, INPROGRESS, or STOPPED", args.State)
+	}
+
+	body := map[string]string{
+		"key":   args.Key,
+		"state": state,
+		"url":   args.URL,
+	}
+	if args.Name != "" {
+		body["name"] = args.Name
+	}
+	if args.Description != "" {
+		body["description"] = args.Description
+	}
+	if args.RefName != "" {
+		body["refname"] = args.RefName
+	}
+
+	respData, err := c.Post(fmt.Sprintf("/repositories/%s/%s/commit/%s/statuses/build",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), QueryEscape(args.Commit)), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set commit status: %v", err)
+	}
+
+	var status CommitStatus
+	if err := json.Unmarshal(respData, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &status, nil
+}
+
+// BuildSummary aggregates build statuses into one overall state.
+type BuildSummary struct {
+	State      string `json:"state"`
+	Total      int    `json:"total"`
+	Successful int    `json:"successful"`
+	Failed     int    `json:"failed"`
+	InProgress int    `json:"in_progress"`
+	Stopped    int    `json:"stopped"`
+	// FailedKeys names the failing builds.
+	FailedKeys []string `json:"failed_keys,omitempty"`
+}
+
+// String renders the summary as e.g. "FAILED (1 failed, 2 passed)".
+func (s BuildSummary) String() string {
+	if s.Total == 0 {
+		return "none"
+	}
+	var parts []string
+	for _, p := range []struct {
+		n     int
+		label string
+	}{{s.Failed, "failed"}, {s.InProgress, "in progress"}, {s.Stopped, "stopped"}, {s.Successful, "passed"}} {
+		if p.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", p.n, p.label))
+		}
+	}
+	return fmt.Sprintf("%s (%s)", s.State, strings.Join(parts, ", "))
+}
+
+// SummarizeStatuses keeps the most recent status per key and derives an
+// overall state: FAILED if any build failed, else INPROGRESS if any is
+// running, else STOPPED if any was stopped, else SUCCESSFUL. State is empty
+// when there are no statuses.
+func SummarizeStatuses(statuses []CommitStatus) BuildSummary {
+	latest := map[string]CommitStatus{}
+	var keys []string
+	for _, s := range statuses {
+		prev, seen := latest[s.Key]
+		if !seen {
+			keys = append(keys, s.Key)
+		}
+		if !seen || s.UpdatedOn.After(prev.UpdatedOn) {
+			latest[s.Key] = s
+		}
+	}
+
+	var sum BuildSummary
+	for _, k := range keys {
+		sum.Total++
+		switch latest[k].State {
+		case StatusSuccessful:
+			sum.Successful++
+		case StatusFailed:
+			sum.Failed++
+			sum.FailedKeys = append(sum.FailedKeys, k)
+		case StatusInProgress:
+			sum.InProgress++
+		case StatusStopped:
+			sum.Stopped++
+		}
+	}
+
+	switch {
+	case sum.Total == 0:
+	case sum.Failed > 0:
+		sum.State = StatusFailed
+	case sum.InProgress > 0:
+		sum.State = StatusInProgress
+	case sum.Stopped > 0:
+		sum.State = StatusStopped
+	default:
+		sum.State = StatusSuccessful
+	}
+	return sum
+}
+
+// PullRequestBuildSummary fetches and summarises a pull request's statuses.
+func (c *Client) PullRequestBuildSummary(workspace, repoSlug string, prID int) (BuildSummary, error) {
+	result, err := c.ListPullRequestStatuses(ListPullRequestStatusesArgs{
+		Workspace: workspace,
+		RepoSlug:  repoSlug,
+		PRID:      prID,
+		Pagelen:   100,
+	})
+	if err != nil {
+		return BuildSummary{}, err
+	}
+	return SummarizeStatuses(result.Values), nil
+}
@@ -0,0 +1,68 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSummarizeStatuses(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	statuses := []CommitStatus{
+		{Key: "lint", State: StatusSuccessful, UpdatedOn: t0},
+		// A re-run superseded the earlier failure.
+		{Key: "unit", State: StatusFailed, UpdatedOn: t0},
+		{Key: "unit", State: StatusSuccessful, UpdatedOn: t0.Add(time.Minute)},
+		{Key: "e2e", State: StatusInProgress, UpdatedOn: t0},
+	}
+
+	sum := SummarizeStatuses(statuses)
+	if sum.State != StatusInProgress || sum.Total != 3 || sum.Successful != 2 || sum.Failed != 0 {
+		t.Errorf("summary = %+v", sum)
+	}
+
+	statuses = append(statuses, CommitStatus{Key: "e2e", State: StatusFailed, UpdatedOn: t0.Add(time.Hour)})
+	sum = SummarizeStatuses(statuses)
+	if sum.State != StatusFailed || len(sum.FailedKeys) != 1 || sum.FailedKeys[0] != "e2e" {
+		t.Errorf("summary = %+v", sum)
+	}
+	if got := sum.String(); got != "FAILED (1 failed, 2 passed)" {
+		t.Errorf("String() = %q", got)
+	}
+
+	if sum := SummarizeStatuses(nil); sum.State != "" || sum.String() != "none" {
+		t.Errorf("empty summary = %+v", sum)
+	}
+}
+
+func TestSetCommitStatus(t *testing.T) {
+	var body map[string]string
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repositories/w/r/commit/abc123/statuses/build" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		b, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(b, &body)
+		_, _ = w.Write(b)
+	})
+
+	st, err := c.SetCommitStatus(SetCommitStatusArgs{
+		Workspace: "w", RepoSlug: "r", Commit: "abc123",
+		Key: "jenkins-unit", State: "failed", URL: "https://ci.example.com/1",
+	})
+	if err != nil {
+		t.Fatalf("SetCommitStatus: %v", err)
+	}
+	if body["state"] != StatusFailed || st.Key != "jenkins-unit" {
+		t.Errorf("body = %v, status = %+v", body, st)
+	}
+
+	if _, err := c.SetCommitStatus(SetCommitStatusArgs{
+		Workspace: "w", RepoSlug: "r", Commit: "abc123",
+		Key: "k", State: "BROKEN", URL: "https://x",
+	}); err == nil {
+		t.Error("expected invalid state error")
+	}
+}
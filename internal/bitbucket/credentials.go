@@ -45,6 +45,10 @@ type Credentials struct {
 	ClientID     string `json:"client_id,omitempty"`
 	ClientSecret string `json:"client_secret,omitempty"`
 
+	// GitProtocol is the preferred clone protocol, GitProtocolSSH or
+	// GitProtocolHTTPS; empty means HTTPS.
+	GitProtocol string `json:"git_protocol,omitempty"`
+
 	// Derived cache data
 	AccessibleWorkspaces []string `json:"accessible_workspaces,omitempty"`
 }
@@ -0,0 +1,79 @@
+package bitbucket
+
+import (
+	"fmt"
+	"strings"
+)
+
+type DefaultReviewersArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+}
+
+func (a DefaultReviewersArgs) path() (string, error) {
+	if a.Workspace == "" || a.RepoSlug == "" {
+		return "", fmt.Errorf("workspace and repo_slug are required")
+	}
+	return fmt.Sprintf("/repositories/%s/%s/default-reviewers",
+		QueryEscape(a.Workspace), QueryEscape(a.RepoSlug)), nil
+}
+
+// maxDefaultReviewerPages bounds ListDefaultReviewers.
+const maxDefaultReviewerPages = 10
+
+// ListDefaultReviewers lists the users added as reviewers to every new pull
+// request in a repository.
+func (c *Client) ListDefaultReviewers(args DefaultReviewersArgs) ([]User, error) {
+	path, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+
+	out := []User{}
+	for page := 1; page <= maxDefaultReviewerPages; page++ {
+		result, err := GetPaginated[User](c, fmt.Sprintf("%s?pagelen=100&page=%d", path, page))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, result.Values...)
+		if result.Next == "" {
+			break
+		}
+	}
+	return out, nil
+}
+
+// AddDefaultReviewer adds a default reviewer by UUID or account ID.
+func (c *Client) AddDefaultReviewer(args DefaultReviewersArgs, user string) error {
+	path, err := args.path()
+	if err != nil {
+		return err
+	}
+	if user == "" {
+		return fmt.Errorf("user is required")
+	}
+	if _, err := c.Put(path+"/"+QueryEscape(normalizeReviewer(user)), map[string]any{}); err != nil {
+		return fmt.Errorf("failed to add default reviewer: %v", err)
+	}
+	return nil
+}
+
+// RemoveDefaultReviewer removes a default reviewer by UUID or account ID.
+func (c *Client) RemoveDefaultReviewer(args DefaultReviewersArgs, user string) error {
+	path, err := args.path()
+	if err != nil {
+		return err
+	}
+	if user == "" {
+		return fmt.Errorf("user is required")
+	}
+	return c.Delete(path + "/" + QueryEscape(normalizeReviewer(user)))
+}
+
+// normalizeReviewer wraps bare UUIDs in braces and leaves account IDs alone.
+func normalizeReviewer(user string) string {
+	if len(trimBraces(user)) == 36 && strings.Count(user, "-") == 4 {
+		return normalizeUUID(user)
+	}
+	return user
+}
@@ -0,0 +1,416 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Environment is a deployment environment (Test, Staging, Production, ...).
+type Environment struct {
+	UUID            string           `json:"uuid"`
+	Name            string           `json:"name"`
+	Slug            string           `json:"slug"`
+	Rank            int              `json:"rank"`
+	Hidden          bool             `json:"hidden"`
+	EnvironmentType *EnvironmentType `json:"environment_type"`
+	Lock            *PipeStage       `json:"lock"`
+}
+
+// EnvironmentType is the category an environment belongs to.
+type EnvironmentType struct {
+	Type string `json:"type,omitempty"`
+	Name string `json:"name"`
+	Rank int    `json:"rank,omitempty"`
+}
+
+// Deployment is one deployment of a release to an environment.
+type Deployment struct {
+	UUID        string             `json:"uuid"`
+	State       *DeploymentState   `json:"state"`
+	Environment *EnvironmentRef    `json:"environment"`
+	Release     *DeploymentRelease `json:"release"`
+	Deployable  *DeploymentRelease `json:"deployable"`
+}
+
+// DeploymentState is the progress and outcome of a deployment.
+type DeploymentState struct {
+	Name        string      `json:"name"` // UNDEPLOYED, IN_PROGRESS, COMPLETED
+	Status      *PipeResult `json:"status"`
+	StartedOn   *time.Time  `json:"started_on"`
+	CompletedOn *time.Time  `json:"completed_on"`
+	Deployer    *User       `json:"deployer"`
+}
+
+// EnvironmentRef points at an environment from a deployment.
+type EnvironmentRef struct {
+	UUID string `json:"uuid"`
+}
+
+// DeploymentRelease is the build being deployed: a pipeline run on a commit.
+type DeploymentRelease struct {
+	UUID      string         `json:"uuid"`
+	Name      string         `json:"name"`
+	Commit    *Commit        `json:"commit"`
+	CreatedOn *time.Time     `json:"created_on"`
+	Pipeline  *PipelineRefID `json:"pipeline"`
+}
+
+// PipelineRefID identifies a pipeline run.
+type PipelineRefID struct {
+	UUID string `json:"uuid"`
+}
+
+// CommitHash is the commit the deployment shipped.
+func (d Deployment) CommitHash() string {
+	for _, r := range []*DeploymentRelease{d.Release, d.Deployable} {
+		if r != nil && r.Commit != nil && r.Commit.Hash != "" {
+			return r.Commit.Hash
+		}
+	}
+	return ""
+}
+
+// ReleaseName is the release label, usually the pipeline build number.
+func (d Deployment) ReleaseName() string {
+	for _, r := range []*DeploymentRelease{d.Release, d.Deployable} {
+		if r != nil && r.Name != "" {
+			return r.Name
+		}
+	}
+	return ""
+}
+
+// Result is the deployment's final status, or its state while in flight.
+func (d Deployment) Result() string {
+	if d.State == nil {
+		return ""
+	}
+	if d.State.Status != nil && d.State.Status.Name != "" {
+		return d.State.Status.Name
+	}
+	return d.State.Name
+}
+
+// Succeeded reports whether the deployment completed successfully.
+func (d Deployment) Succeeded() bool {
+	return d.State != nil && d.State.Name == "COMPLETED" && d.Result() == "SUCCESSFUL"
+}
+
+func (d Deployment) startedOn() time.Time {
+	if d.State != nil && d.State.StartedOn != nil {
+		return *d.State.StartedOn
+	}
+	return time.Time{}
+}
+
+type ListEnvironmentsArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+}
+
+// ListEnvironments lists a repository's deployment environments in promotion
+// order (by type rank, then environment rank).
+func (c *Client) ListEnvironments(args ListEnvironmentsArgs) ([]Environment, error) {
+	if args.Workspace == "" || args.RepoSlug == "" {
+		return nil, fmt.Errorf("workspace and repo_slug are required")
+	}
+
+	result, err := GetPaginated[Environment](c, fmt.Sprintf("/repositories/%s/%s/environments?pagelen=100",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug)))
+	if err != nil {
+		return nil, err
+	}
+	envs := result.Values
+	sort.SliceStable(envs, func(i, j int) bool {
+		ri, rj := envTypeRank(envs[i]), envTypeRank(envs[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return envs[i].Rank < envs[j].Rank
+	})
+	return envs, nil
+}
+
+func envTypeRank(e Environment) int {
+	if e.EnvironmentType == nil {
+		return 0
+	}
+	return e.EnvironmentType.Rank
+}
+
+type GetEnvironmentArgs struct {
+	Workspace   string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug    string `json:"repo_slug" jsonschema:"Repository slug"`
+	Environment string `json:"environment" jsonschema:"Environment name, slug, or UUID"`
+}
+
+// GetEnvironment finds an environment by name, slug, or UUID.
+func (c *Client) GetEnvironment(args GetEnvironmentArgs) (*Environment, error) {
+	if args.Workspace == "" || args.RepoSlug == "" || args.Environment == "" {
+		return nil, fmt.Errorf("workspace, repo_slug, and environment are required")
+	}
+
+	envs, err := c.ListEnvironments(ListEnvironmentsArgs{Workspace: args.Workspace, RepoSlug: args.RepoSlug})
+	if err != nil {
+		return nil, err
+	}
+	for i, e := range envs {
+		if e.UUID == args.Environment || strings.EqualFold(e.Slug, args.Environment) || strings.EqualFold(e.Name, args.Environment) {
+			return &envs[i], nil
+		}
+	}
+	names := make([]string, 0, len(envs))
+	for _, e := range envs {
+		names = append(names, e.Name)
+	}
+	return nil, fmt.Errorf("environment %q not found (have: %s)", args.Environment, strings.Join(names, ", "))
+}
+
+type CreateEnvironmentArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+	Name      string `json:"name" jsonschema:"Environment name"`
+	Type      string `json:"type,omitempty" jsonschema:"Environment type: Test, Staging, or Production (default Test)"`
+}
+
+// CreateEnvironment creates a deployment environment.
+func (c *Client) CreateEnvironment(args CreateEnvironmentArgs) (*Environment, error) {
+	if args.Workspace == "" || args.RepoSlug == "" || args.Name == "" {
+		return nil, fmt.Errorf("workspace, repo_slug, and name are required")
+	}
+
+	envType := args.Type
+	if envType == "" {
+		envType = "Test"
+	}
+	valid := false
+	for _, t := range []string{"Test", "Staging", "Production"} {
+		if strings.EqualFold(envType, t) {
+			envType, valid = t, true
+		}
+	}
+	if !valid {
+		return nil, fmt.Errorf("invalid environment type %q: must be Test, Staging, or Production", args.Type)
+	}
+
+	body := map[string]any{
+		"type": "deployment_environment",
+		"name": args.Name,
+		"environment_type": EnvironmentType{
+			Type: "deployment_environment_type",
+			Name: envType,
+		},
+	}
+	respData, err := c.Post(fmt.Sprintf("/repositories/%s/%s/environments",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug)), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create environment: %v", err)
+	}
+
+	var env Environment
+	if err := json.Unmarshal(respData, &env); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &env, nil
+}
+
+type DeleteEnvironmentArgs struct {
+	Workspace   string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug    string `json:"repo_slug" jsonschema:"Repository slug"`
+	Environment string `json:"environment" jsonschema:"Environment name, slug, or UUID"`
+}
+
+// DeleteEnvironment deletes a deployment environment.
+func (c *Client) DeleteEnvironment(args DeleteEnvironmentArgs) error {
+	env, err := c.GetEnvironment(GetEnvironmentArgs(args))
+	if err != nil {
+		return err
+	}
+	return c.Delete(fmt.Sprintf("/repositories/%s/%s/environments/%s",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), env.UUID))
+}
+
+type ListDeploymentsArgs struct {
+	Workspace   string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug    string `json:"repo_slug" jsonschema:"Repository slug"`
+	Environment string `json:"environment,omitempty" jsonschema:"Only deployments to this environment (name, slug, or UUID)"`
+	Pagelen     int    `json:"pagelen,omitempty" jsonschema:"Results per page (default 25)"`
+	Page        int    `json:"page,omitempty" jsonschema:"Page number"`
+}
+
+// ListDeployments lists deployments, newest first. With Environment set this
+// is that environment's promotion history.
+func (c *Client) ListDeployments(args ListDeploymentsArgs) (*Paginated[Deployment], error) {
+	if args.Workspace == "" || args.RepoSlug == "" {
+		return nil, fmt.Errorf("workspace and repo_slug are required")
+	}
+
+	pagelen := args.Pagelen
+	if pagelen == 0 {
+		pagelen = 25
+	}
+	page := args.Page
+	if page == 0 {
+		page = 1
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/deployments?pagelen=%d&page=%d&sort=-state.started_on",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), pagelen, page)
+	envUUID := ""
+	if args.Environment != "" {
+		env, err := c.GetEnvironment(GetEnvironmentArgs{Workspace: args.Workspace, RepoSlug: args.RepoSlug, Environment: args.Environment})
+		if err != nil {
+			return nil, err
+		}
+		envUUID = env.UUID
+		path += "&environment=" + QueryEscape(envUUID)
+	}
+
+	result, err := GetPaginated[Deployment](c, path)
+	if err != nil {
+		return nil, err
+	}
+	if envUUID != "" {
+		// Guard against the filter being ignored server-side.
+		kept := result.Values[:0]
+		for _, d := range result.Values {
+			if d.Environment != nil && d.Environment.UUID == envUUID {
+				kept = append(kept, d)
+			}
+		}
+		result.Values = kept
+	}
+	sort.SliceStable(result.Values, func(i, j int) bool {
+		return result.Values[i].startedOn().After(result.Values[j].startedOn())
+	})
+	return result, nil
+}
+
+// EnvironmentStatus is what is currently deployed to an environment.
+type EnvironmentStatus struct {
+	Environment Environment `json:"environment"`
+	Current     *Deployment `json:"current,omitempty"`
+}
+
+// maxStatusPages bounds how far back GetEnvironmentStatuses looks for an
+// environment's last successful deployment.
+const maxStatusPages = 5
+
+// GetEnvironmentStatuses returns, for every environment, the most recent
+// successful deployment — i.e. which commit is live there right now.
+func (c *Client) GetEnvironmentStatuses(args ListEnvironmentsArgs) ([]EnvironmentStatus, error) {
+	envs, err := c.ListEnvironments(args)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]EnvironmentStatus, len(envs))
+	byUUID := map[string]*EnvironmentStatus{}
+	for i, e := range envs {
+		statuses[i].Environment = e
+		byUUID[e.UUID] = &statuses[i]
+	}
+
+	remaining := len(envs)
+	for page := 1; page <= maxStatusPages && remaining > 0; page++ {
+		result, err := c.ListDeployments(ListDeploymentsArgs{Workspace: args.Workspace, RepoSlug: args.RepoSlug, Pagelen: 100, Page: page})
+		if err != nil {
+			return nil, err
+		}
+		for i, d := range result.Values {
+			if !d.Succeeded() || d.Environment == nil {
+				continue
+			}
+			if s := byUUID[d.Environment.UUID]; s != nil && s.Current == nil {
+				s.Current = &result.Values[i]
+				remaining--
+			}
+		}
+		if result.Next == "" {
+			break
+		}
+	}
+	return statuses, nil
+}
+
+type DiffEnvironmentsArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+	From      string `json:"from" jsonschema:"Environment whose deployed commit is the newer side (e.g. staging)"`
+	To        string `json:"to" jsonschema:"Environment to compare against (e.g. production)"`
+	Pagelen   int    `json:"pagelen,omitempty" jsonschema:"Maximum commits to return (default 50)"`
+}
+
+// EnvironmentDiff lists the commits deployed to From but not yet to To.
+type EnvironmentDiff struct {
+	From       string   `json:"from"`
+	FromCommit string   `json:"from_commit"`
+	To         string   `json:"to"`
+	ToCommit   string   `json:"to_commit"`
+	Commits    []Commit `json:"commits"`
+	More       bool     `json:"more,omitempty"`
+}
+
+// DiffEnvironments compares the live commits of two environments — what
+// promoting From to To would ship.
+func (c *Client) DiffEnvironments(args DiffEnvironmentsArgs) (*EnvironmentDiff, error) {
+	if args.Workspace == "" || args.RepoSlug == "" || args.From == "" || args.To == "" {
+		return nil, fmt.Errorf("workspace, repo_slug, from, and to are required")
+	}
+	pagelen := args.Pagelen
+	if pagelen == 0 {
+		pagelen = 50
+	}
+
+	statuses, err := c.GetEnvironmentStatuses(ListEnvironmentsArgs{Workspace: args.Workspace, RepoSlug: args.RepoSlug})
+	if err != nil {
+		return nil, err
+	}
+	live := func(ref string) (*EnvironmentStatus, error) {
+		for i, s := range statuses {
+			e := s.Environment
+			if e.UUID == ref || strings.EqualFold(e.Slug, ref) || strings.EqualFold(e.Name, ref) {
+				if s.Current == nil || s.Current.CommitHash() == "" {
+					return nil, fmt.Errorf("environment %q has no successful deployment", e.Name)
+				}
+				return &statuses[i], nil
+			}
+		}
+		return nil, fmt.Errorf("environment %q not found", ref)
+	}
+	from, err := live(args.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := live(args.To)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &EnvironmentDiff{
+		From:       from.Environment.Name,
+		FromCommit: from.Current.CommitHash(),
+		To:         to.Environment.Name,
+		ToCommit:   to.Current.CommitHash(),
+		Commits:    []Commit{},
+	}
+	if diff.FromCommit == diff.ToCommit {
+		return diff, nil
+	}
+	commits, err := c.ListCommits(ListCommitsArgs{
+		Workspace: args.Workspace,
+		RepoSlug:  args.RepoSlug,
+		Include:   diff.FromCommit,
+		Exclude:   diff.ToCommit,
+		Pagelen:   pagelen,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits: %v", err)
+	}
+	diff.Commits = commits.Values
+	diff.More = commits.Next != ""
+	return diff, nil
+}
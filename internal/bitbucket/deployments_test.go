@@ -0,0 +1,90 @@
+package bitbucket
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const environmentsJSON = `{"values":[
+	{"uuid":"{prod}","name":"Production","slug":"production","environment_type":{"name":"Production","rank":2}},
+	{"uuid":"{test}","name":"Test","slug":"test","environment_type":{"name":"Test","rank":0}},
+	{"uuid":"{stg}","name":"Staging","slug":"staging","environment_type":{"name":"Staging","rank":1}}]}`
+
+// Newest first, as the API returns them with sort=-state.started_on.
+const deploymentsJSON = `{"values":[
+	{"uuid":"{d4}","environment":{"uuid":"{prod}"},"state":{"name":"COMPLETED","status":{"name":"FAILED"},"started_on":"2026-05-04T00:00:00Z"},"release":{"name":"#14","commit":{"hash":"ccc"}}},
+	{"uuid":"{d3}","environment":{"uuid":"{stg}"},"state":{"name":"COMPLETED","status":{"name":"SUCCESSFUL"},"started_on":"2026-05-03T00:00:00Z"},"release":{"name":"#14","commit":{"hash":"ccc"}}},
+	{"uuid":"{d2}","environment":{"uuid":"{prod}"},"state":{"name":"COMPLETED","status":{"name":"SUCCESSFUL"},"started_on":"2026-05-02T00:00:00Z"},"release":{"name":"#12","commit":{"hash":"aaa"}}},
+	{"uuid":"{d1}","environment":{"uuid":"{stg}"},"state":{"name":"COMPLETED","status":{"name":"SUCCESSFUL"},"started_on":"2026-05-01T00:00:00Z"},"release":{"name":"#12","commit":{"hash":"aaa"}}}]}`
+
+func deploymentsServer(t *testing.T, gotQuery *string) *Client {
+	return newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/environments"):
+			_, _ = w.Write([]byte(environmentsJSON))
+		case strings.HasSuffix(r.URL.Path, "/deployments"):
+			_, _ = w.Write([]byte(deploymentsJSON))
+		case strings.HasSuffix(r.URL.Path, "/commits"):
+			*gotQuery = r.URL.RawQuery
+			_, _ = w.Write([]byte(`{"values":[{"hash":"ccc","message":"feat"},{"hash":"bbb","message":"fix"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func TestGetEnvironmentStatuses(t *testing.T) {
+	c := deploymentsServer(t, new(string))
+	statuses, err := c.GetEnvironmentStatuses(ListEnvironmentsArgs{Workspace: "w", RepoSlug: "r"})
+	if err != nil {
+		t.Fatalf("GetEnvironmentStatuses: %v", err)
+	}
+
+	got := map[string]string{}
+	var order []string
+	for _, s := range statuses {
+		order = append(order, s.Environment.Name)
+		if s.Current != nil {
+			got[s.Environment.Name] = s.Current.CommitHash()
+		}
+	}
+	if strings.Join(order, ",") != "Test,Staging,Production" {
+		t.Errorf("order = %v, want promotion order", order)
+	}
+	// The failed #14 to production must not count as live.
+	if got["Production"] != "aaa" || got["Staging"] != "ccc" || got["Test"] != "" {
+		t.Errorf("live commits = %v", got)
+	}
+}
+
+func TestDiffEnvironments(t *testing.T) {
+	var query string
+	c := deploymentsServer(t, &query)
+	diff, err := c.DiffEnvironments(DiffEnvironmentsArgs{Workspace: "w", RepoSlug: "r", From: "staging", To: "Production"})
+	if err != nil {
+		t.Fatalf("DiffEnvironments: %v", err)
+	}
+	if diff.FromCommit != "ccc" || diff.ToCommit != "aaa" || len(diff.Commits) != 2 {
+		t.Errorf("diff = %+v", diff)
+	}
+	if !strings.Contains(query, "include=ccc") || !strings.Contains(query, "exclude=aaa") {
+		t.Errorf("commits query = %q, want include=ccc&exclude=aaa", query)
+	}
+
+	if _, err := c.DiffEnvironments(DiffEnvironmentsArgs{Workspace: "w", RepoSlug: "r", From: "test", To: "production"}); err == nil ||
+		!strings.Contains(err.Error(), "no successful deployment") {
+		t.Errorf("expected error for never-deployed environment, got %v", err)
+	}
+}
+
+func TestListDeployments_FiltersByEnvironment(t *testing.T) {
+	c := deploymentsServer(t, new(string))
+	result, err := c.ListDeployments(ListDeploymentsArgs{Workspace: "w", RepoSlug: "r", Environment: "production"})
+	if err != nil {
+		t.Fatalf("ListDeployments: %v", err)
+	}
+	if len(result.Values) != 2 || result.Values[0].ReleaseName() != "#14" || result.Values[1].ReleaseName() != "#12" {
+		t.Errorf("production history = %+v", result.Values)
+	}
+}
@@ -0,0 +1,105 @@
+package bitbucket
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Download is a file in a repository's Downloads section.
+type Download struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	Downloads int       `json:"downloads"`
+	CreatedOn time.Time `json:"created_on"`
+	User      *User     `json:"user"`
+	Links     Links     `json:"links"`
+}
+
+type ListDownloadsArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+	Pagelen   int    `json:"pagelen,omitempty" jsonschema:"Results per page (default 25)"`
+	Page      int    `json:"page,omitempty" jsonschema:"Page number"`
+}
+
+// ListDownloads lists the files in a repository's Downloads section.
+func (c *Client) ListDownloads(args ListDownloadsArgs) (*Paginated[Download], error) {
+	if args.Workspace == "" || args.RepoSlug == "" {
+		return nil, fmt.Errorf("workspace and repo_slug are required")
+	}
+
+	pagelen := args.Pagelen
+	if pagelen == 0 {
+		pagelen = 25
+	}
+	page := args.Page
+	if page == 0 {
+		page = 1
+	}
+
+	return GetPaginated[Download](c, fmt.Sprintf("/repositories/%s/%s/downloads?pagelen=%d&page=%d",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), pagelen, page))
+}
+
+type UploadDownloadArgs struct {
+	Workspace string
+	RepoSlug  string
+	Filename  string
+	Reader    io.Reader
+	Size      int64 // -1 if unknown
+}
+
+// UploadDownload streams a file into the repository's Downloads section.
+// Uploading a name that already exists replaces it.
+func (c *Client) UploadDownload(args UploadDownloadArgs) error {
+	if args.Workspace == "" || args.RepoSlug == "" || args.Filename == "" || args.Reader == nil {
+		return fmt.Errorf("workspace, repo_slug, filename, and content are required")
+	}
+
+	_, err := c.PostMultipartStream(fmt.Sprintf("/repositories/%s/%s/downloads",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug)), nil, []MultipartFile{{
+		Field:    "files",
+		Filename: args.Filename,
+		Reader:   args.Reader,
+		Size:     args.Size,
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %v", args.Filename, err)
+	}
+	return nil
+}
+
+type DownloadFileArgs struct {
+	Workspace string
+	RepoSlug  string
+	Name      string
+	Dest      string
+}
+
+// DownloadFile saves a Downloads entry to Dest, resuming a previous partial
+// download when the storage backend supports Range requests.
+func (c *Client) DownloadFile(args DownloadFileArgs, progress func(done, total int64)) (*DownloadResult, error) {
+	if args.Workspace == "" || args.RepoSlug == "" || args.Name == "" || args.Dest == "" {
+		return nil, fmt.Errorf("workspace, repo_slug, name, and destination are required")
+	}
+
+	return c.DownloadToFile(fmt.Sprintf("/repositories/%s/%s/downloads/%s",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), QueryEscape(args.Name)), args.Dest, progress)
+}
+
+type DeleteDownloadArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+	Name      string `json:"name" jsonschema:"File name in Downloads"`
+}
+
+// DeleteDownload removes a file from the repository's Downloads section.
+func (c *Client) DeleteDownload(args DeleteDownloadArgs) error {
+	if args.Workspace == "" || args.RepoSlug == "" || args.Name == "" {
+		return fmt.Errorf("workspace, repo_slug, and name are required")
+	}
+
+	return c.Delete(fmt.Sprintf("/repositories/%s/%s/downloads/%s",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), QueryEscape(args.Name)))
+}
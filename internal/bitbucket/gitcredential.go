@@ -0,0 +1,57 @@
+package bitbucket
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Clone protocols.
+const (
+	GitProtocolSSH   = "ssh"
+	GitProtocolHTTPS = "https"
+)
+
+// Usernames Bitbucket expects with each kind of token over HTTPS.
+const (
+	gitAPITokenUser = "x-bitbucket-api-token-auth"
+	gitOAuthUser    = "x-token-auth"
+)
+
+// GitCredentials returns the username and password git should send over
+// HTTPS for these credentials. OAuth tokens must be refreshed by the caller.
+func (c *Credentials) GitCredentials() (username, password string, err error) {
+	switch {
+	case c.IsAPIToken() && c.APIToken != "":
+		return gitAPITokenUser, c.APIToken, nil
+	case c.IsOAuth() && c.AccessToken != "":
+		return gitOAuthUser, c.AccessToken, nil
+	}
+	return "", "", fmt.Errorf("profile %q has no token usable for git", c.ProfileName)
+}
+
+// ReadGitCredentialRequest reads the key=value lines git sends a credential
+// helper, up to the first blank line or EOF.
+func ReadGitCredentialRequest(r io.Reader) (map[string]string, error) {
+	req := map[string]string{}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" {
+			break
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid credential line %q", line)
+		}
+		req[k] = v
+	}
+	return req, sc.Err()
+}
+
+// IsBitbucketGitHost reports whether a credential request's host is the
+// Bitbucket git host, so a helper does not hand tokens to other servers.
+func IsBitbucketGitHost(host string) bool {
+	return strings.EqualFold(host, gitHost())
+}
@@ -0,0 +1,44 @@
+package bitbucket
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGitCredentials(t *testing.T) {
+	cases := []struct {
+		creds    Credentials
+		user     string
+		password string
+	}{
+		{Credentials{AuthType: AuthTypeAPIToken, Email: "me@example.com", APIToken: "tok"}, "x-bitbucket-api-token-auth", "tok"},
+		{Credentials{AuthType: AuthTypeOAuth, AccessToken: "acc"}, "x-token-auth", "acc"},
+	}
+	for _, c := range cases {
+		user, password, err := c.creds.GitCredentials()
+		if err != nil || user != c.user || password != c.password {
+			t.Errorf("GitCredentials(%s) = %q, %q, %v", c.creds.AuthType, user, password, err)
+		}
+	}
+	if _, _, err := (&Credentials{AuthType: AuthTypeOAuth, ProfileName: "work"}).GitCredentials(); err == nil {
+		t.Error("expected an error for a profile without a token")
+	}
+}
+
+func TestReadGitCredentialRequest(t *testing.T) {
+	req, err := ReadGitCredentialRequest(strings.NewReader("protocol=https\nhost=bitbucket.org\npath=acme/api.git\n\nignored=1\n"))
+	if err != nil {
+		t.Fatalf("ReadGitCredentialRequest: %v", err)
+	}
+	if len(req) != 3 || req["host"] != "bitbucket.org" || req["path"] != "acme/api.git" {
+		t.Errorf("request = %v", req)
+	}
+	if _, err := ReadGitCredentialRequest(strings.NewReader("garbage\n")); err == nil {
+		t.Error("expected an error for a line without '='")
+	}
+
+	t.Setenv("BBKT_HOST", "")
+	if !IsBitbucketGitHost("Bitbucket.org") || IsBitbucketGitHost("github.com") {
+		t.Error("IsBitbucketGitHost mismatch")
+	}
+}
@@ -0,0 +1,240 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Code Insights limits enforced by Bitbucket.
+const (
+	// AnnotationBatchSize is the most annotations one bulk request may carry.
+	AnnotationBatchSize = 100
+	// MaxAnnotationsPerReport is the most annotations a report may hold.
+	MaxAnnotationsPerReport = 1000
+)
+
+// InsightReport is a Code Insights report attached to a commit.
+type InsightReport struct {
+	UUID       string              `json:"uuid,omitempty"`
+	ExternalID string              `json:"external_id,omitempty"`
+	Title      string              `json:"title"`
+	Details    string              `json:"details,omitempty"`
+	ReportType string              `json:"report_type,omitempty"`
+	Reporter   string              `json:"reporter,omitempty"`
+	Link       string              `json:"link,omitempty"`
+	Result     string              `json:"result,omitempty"`
+	Data       []InsightReportData `json:"data,omitempty"`
+	CreatedOn  *time.Time          `json:"created_on,omitempty"`
+	UpdatedOn  *time.Time          `json:"updated_on,omitempty"`
+}
+
+// InsightReportData is one metric shown on a report, e.g. a coverage
+// percentage or a failure count.
+type InsightReportData struct {
+	Title string `json:"title"`
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+// InsightAnnotation is a finding attached to a file and line in a report.
+type InsightAnnotation struct {
+	UUID           string `json:"uuid,omitempty"`
+	ExternalID     string `json:"external_id"`
+	AnnotationType string `json:"annotation_type"`
+	Path           string `json:"path,omitempty"`
+	Line           int    `json:"line,omitempty"`
+	Summary        string `json:"summary"`
+	Details        string `json:"details,omitempty"`
+	Result         string `json:"result,omitempty"`
+	Severity       string `json:"severity,omitempty"`
+	Link           string `json:"link,omitempty"`
+}
+
+type InsightReportArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+	Commit    string `json:"commit" jsonschema:"Commit hash"`
+	ReportID  string `json:"report_id,omitempty" jsonschema:"Report ID (its external ID or UUID)"`
+}
+
+func (a InsightReportArgs) reportsPath() (string, error) {
+	if a.Workspace == "" || a.RepoSlug == "" || a.Commit == "" {
+		return "", fmt.Errorf("workspace, repo_slug, and commit are required")
+	}
+	return fmt.Sprintf("/repositories/%s/%s/commit/%s/reports",
+		QueryEscape(a.Workspace), QueryEscape(a.RepoSlug), QueryEscape(a.Commit)), nil
+}
+
+func (a InsightReportArgs) reportPath(suffix string) (string, error) {
+	p, err := a.reportsPath()
+	if err != nil {
+		return "", err
+	}
+	if a.ReportID == "" {
+		return "", fmt.Errorf("report_id is required")
+	}
+	return p + "/" + QueryEscape(a.ReportID) + suffix, nil
+}
+
+// ListInsightReports lists the Code Insights reports on a commit.
+func (c *Client) ListInsightReports(args InsightReportArgs) (*Paginated[InsightReport], error) {
+	path, err := args.reportsPath()
+	if err != nil {
+		return nil, err
+	}
+	return GetPaginated[InsightReport](c, path+"?pagelen=100")
+}
+
+// GetInsightReport gets a single report.
+func (c *Client) GetInsightReport(args InsightReportArgs) (*InsightReport, error) {
+	path, err := args.reportPath("")
+	if err != nil {
+		return nil, err
+	}
+	return GetJSON[InsightReport](c, path)
+}
+
+// PutInsightReport creates or replaces a report. Replacing a report also
+// drops its annotations, so upload them afterwards.
+func (c *Client) PutInsightReport(args InsightReportArgs, report InsightReport) (*InsightReport, error) {
+	path, err := args.reportPath("")
+	if err != nil {
+		return nil, err
+	}
+	if report.Title == "" {
+		return nil, fmt.Errorf("report title is required")
+	}
+
+	respData, err := c.Put(path, report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to put report: %v", err)
+	}
+
+	var out InsightReport
+	if err := json.Unmarshal(respData, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &out, nil
+}
+
+// DeleteInsightReport deletes a report and its annotations.
+func (c *Client) DeleteInsightReport(args InsightReportArgs) error {
+	path, err := args.reportPath("")
+	if err != nil {
+		return err
+	}
+	return c.Delete(path)
+}
+
+// ListInsightAnnotations lists every annotation on a report.
+func (c *Client) ListInsightAnnotations(args InsightReportArgs) ([]InsightAnnotation, error) {
+	path, err := args.reportPath("/annotations")
+	if err != nil {
+		return nil, err
+	}
+
+	var out []InsightAnnotation
+	for page := 1; page <= MaxAnnotationsPerReport/AnnotationBatchSize; page++ {
+		result, err := GetPaginated[InsightAnnotation](c, fmt.Sprintf("%s?pagelen=%d&page=%d", path, AnnotationBatchSize, page))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, result.Values...)
+		if result.Next == "" {
+			break
+		}
+	}
+	return out, nil
+}
+
+// UploadInsightAnnotations adds annotations to a report in batches of
+// AnnotationBatchSize and returns how many were uploaded. On error the count
+// covers the batches that succeeded.
+func (c *Client) UploadInsightAnnotations(args InsightReportArgs, annotations []InsightAnnotation) (int, error) {
+	path, err := args.reportPath("/annotations")
+	if err != nil {
+		return 0, err
+	}
+	if len(annotations) > MaxAnnotationsPerReport {
+		return 0, fmt.Errorf("%d annotations exceed the limit of %d per report", len(annotations), MaxAnnotationsPerReport)
+	}
+
+	sent := 0
+	for start := 0; start < len(annotations); start += AnnotationBatchSize {
+		end := min(start+AnnotationBatchSize, len(annotations))
+		if _, err := c.Post(path, annotations[start:end]); err != nil {
+			return sent, fmt.Errorf("failed to upload annotations %d-%d: %v", start+1, end, err)
+		}
+		sent = end
+	}
+	return sent, nil
+}
+
+// InsightsUpload is a report and its annotations, ready to publish.
+type InsightsUpload struct {
+	ReportID    string              `json:"report_id"`
+	Report      InsightReport       `json:"report"`
+	Annotations []InsightAnnotation `json:"annotations"`
+	// Dropped counts findings left out to stay within MaxAnnotationsPerReport.
+	Dropped int `json:"dropped,omitempty"`
+}
+
+// PublishInsights puts the report, then uploads its annotations.
+func (c *Client) PublishInsights(args InsightReportArgs, upload *InsightsUpload) (*InsightReport, int, error) {
+	args.ReportID = upload.ReportID
+	report, err := c.PutInsightReport(args, upload.Report)
+	if err != nil {
+		return nil, 0, err
+	}
+	n, err := c.UploadInsightAnnotations(args, upload.Annotations)
+	return report, n, err
+}
+
+// CommitInsights is every report on a commit with its annotations.
+type CommitInsights struct {
+	Commit  string               `json:"commit"`
+	Reports []ReportWithFindings `json:"reports"`
+}
+
+// ReportWithFindings pairs a report with its annotations.
+type ReportWithFindings struct {
+	InsightReport
+	Annotations []InsightAnnotation `json:"annotations"`
+}
+
+// GetCommitInsights fetches all reports on a commit and their annotations.
+func (c *Client) GetCommitInsights(args InsightReportArgs) (*CommitInsights, error) {
+	reports, err := c.ListInsightReports(args)
+	if err != nil {
+		return nil, err
+	}
+	out := &CommitInsights{Commit: args.Commit, Reports: []ReportWithFindings{}}
+	for _, r := range reports.Values {
+		id := r.ExternalID
+		if id == "" {
+			id = r.UUID
+		}
+		ra := args
+		ra.ReportID = id
+		anns, err := c.ListInsightAnnotations(ra)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list annotations for report %q: %v", id, err)
+		}
+		out.Reports = append(out.Reports, ReportWithFindings{InsightReport: r, Annotations: anns})
+	}
+	return out, nil
+}
+
+// GetPullRequestInsights fetches the reports and annotations on a pull
+// request's head commit, which is where Bitbucket shows them on the diff.
+func (c *Client) GetPullRequestInsights(workspace, repoSlug string, prID int) (*CommitInsights, error) {
+	pr, err := c.GetPullRequest(GetPullRequestArgs{Workspace: workspace, RepoSlug: repoSlug, PRID: prID})
+	if err != nil {
+		return nil, err
+	}
+	if pr.Source.Commit == nil || pr.Source.Commit.Hash == "" {
+		return nil, fmt.Errorf("pull request %d has no source commit", prID)
+	}
+	return c.GetCommitInsights(InsightReportArgs{Workspace: workspace, RepoSlug: repoSlug, Commit: pr.Source.Commit.Hash})
+}
@@ -0,0 +1,85 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPublishInsightsBatchesAnnotations(t *testing.T) {
+	var putTitle string
+	var batches []int
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/repositories/w/r/commit/abc/reports/lint":
+			var rep InsightReport
+			_ = json.Unmarshal(b, &rep)
+			putTitle = rep.Title
+			_, _ = w.Write(b)
+		case r.Method == http.MethodPost && r.URL.Path == "/repositories/w/r/commit/abc/reports/lint/annotations":
+			var anns []InsightAnnotation
+			_ = json.Unmarshal(b, &anns)
+			batches = append(batches, len(anns))
+			_, _ = w.Write([]byte("[]"))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	anns := make([]InsightAnnotation, 250)
+	for i := range anns {
+		anns[i] = InsightAnnotation{ExternalID: annotationID("t", string(rune(i))), AnnotationType: "BUG", Summary: "x"}
+	}
+	_, n, err := c.PublishInsights(InsightReportArgs{Workspace: "w", RepoSlug: "r", Commit: "abc"},
+		&InsightsUpload{ReportID: "lint", Report: InsightReport{Title: "Lint"}, Annotations: anns})
+	if err != nil {
+		t.Fatalf("PublishInsights: %v", err)
+	}
+	if putTitle != "Lint" || n != 250 {
+		t.Errorf("title = %q, uploaded = %d", putTitle, n)
+	}
+	if len(batches) != 3 || batches[0] != 100 || batches[1] != 100 || batches[2] != 50 {
+		t.Errorf("batches = %v", batches)
+	}
+}
+
+func TestUploadInsightAnnotationsLimits(t *testing.T) {
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+	args := InsightReportArgs{Workspace: "w", RepoSlug: "r", Commit: "abc", ReportID: "x"}
+	_, err := c.UploadInsightAnnotations(args, make([]InsightAnnotation, MaxAnnotationsPerReport+1))
+	if err == nil || !strings.Contains(err.Error(), "exceed") {
+		t.Errorf("err = %v", err)
+	}
+	args.ReportID = ""
+	if _, err := c.UploadInsightAnnotations(args, nil); err == nil {
+		t.Error("expected error without report_id")
+	}
+}
+
+func TestGetCommitInsights(t *testing.T) {
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repositories/w/r/commit/abc/reports":
+			_, _ = w.Write([]byte(`{"values":[{"external_id":"cov","title":"Coverage"},{"uuid":"{u1}","title":"Scan"}]}`))
+		case "/repositories/w/r/commit/abc/reports/cov/annotations":
+			_, _ = w.Write([]byte(`{"values":[{"external_id":"a1","annotation_type":"CODE_SMELL","summary":"uncovered"}]}`))
+		case "/repositories/w/r/commit/abc/reports/{u1}/annotations":
+			_, _ = w.Write([]byte(`{"values":[]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+
+	got, err := c.GetCommitInsights(InsightReportArgs{Workspace: "w", RepoSlug: "r", Commit: "abc"})
+	if err != nil {
+		t.Fatalf("GetCommitInsights: %v", err)
+	}
+	if len(got.Reports) != 2 || len(got.Reports[0].Annotations) != 1 || got.Reports[0].Annotations[0].Summary != "uncovered" {
+		t.Errorf("insights = %+v", got)
+	}
+}
@@ -0,0 +1,540 @@
+package bitbucket
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Field limits for annotations; longer values are rejected by the API.
+const (
+	maxAnnotationSummary = 450
+	maxAnnotationDetails = 2000
+)
+
+// InsightsConvertOptions tunes the SARIF/JUnit/Cobertura converters.
+type InsightsConvertOptions struct {
+	// RootDir is the repository checkout root; absolute paths in the input are
+	// made relative to it so annotations land on the right files.
+	RootDir string
+	// MinCoverage fails a coverage report whose line coverage (0-100) is below
+	// it. Zero never fails.
+	MinCoverage float64
+}
+
+// ─── SARIF ───────────────────────────────────────────────────────────────────
+
+type sarifLog struct {
+	Runs []struct {
+		Tool struct {
+			Driver struct {
+				Name           string      `json:"name"`
+				InformationURI string      `json:"informationUri"`
+				Rules          []sarifRule `json:"rules"`
+			} `json:"driver"`
+		} `json:"tool"`
+		Results []sarifResult `json:"results"`
+	} `json:"runs"`
+}
+
+type sarifRule struct {
+	ID               string `json:"id"`
+	HelpURI          string `json:"helpUri"`
+	ShortDescription struct {
+		Text string `json:"text"`
+	} `json:"shortDescription"`
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+	Properties struct {
+		Tags             []string `json:"tags"`
+		SecuritySeverity any      `json:"security-severity"`
+	} `json:"properties"`
+}
+
+type sarifResult struct {
+	RuleID    string `json:"ruleId"`
+	RuleIndex *int   `json:"ruleIndex"`
+	Level     string `json:"level"`
+	Message   struct {
+		Text string `json:"text"`
+	} `json:"message"`
+	Locations []struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				URI string `json:"uri"`
+			} `json:"artifactLocation"`
+			Region struct {
+				StartLine int `json:"startLine"`
+			} `json:"region"`
+		} `json:"physicalLocation"`
+	} `json:"locations"`
+}
+
+// InsightsFromSARIF converts a SARIF 2.1 log into a report with one
+// annotation per result. Results tagged "security" or carrying a
+// security-severity become vulnerabilities; the report fails when any result
+// has level "error".
+func InsightsFromSARIF(data []byte, opts InsightsConvertOptions) (*InsightsUpload, error) {
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		return nil, fmt.Errorf("invalid SARIF: %v", err)
+	}
+	if len(log.Runs) == 0 {
+		return nil, fmt.Errorf("invalid SARIF: no runs")
+	}
+
+	var tools []string
+	var link string
+	var errorsN, vulns int
+	up := &InsightsUpload{}
+	for _, run := range log.Runs {
+		driver := run.Tool.Driver
+		if driver.Name != "" && !containsString(tools, driver.Name) {
+			tools = append(tools, driver.Name)
+		}
+		if link == "" {
+			link = driver.InformationURI
+		}
+		rules := map[string]sarifRule{}
+		for _, r := range driver.Rules {
+			rules[r.ID] = r
+		}
+
+		for _, res := range run.Results {
+			rule, ok := rules[res.RuleID]
+			if !ok && res.RuleIndex != nil && *res.RuleIndex >= 0 && *res.RuleIndex < len(driver.Rules) {
+				rule = driver.Rules[*res.RuleIndex]
+			}
+			level := res.Level
+			if level == "" {
+				level = rule.DefaultConfiguration.Level
+			}
+			if level == "" {
+				level = "warning"
+			}
+			if level == "error" {
+				errorsN++
+			}
+
+			ann := InsightAnnotation{
+				AnnotationType: "CODE_SMELL",
+				Severity:       sarifLevelSeverity(level),
+				Result:         "FAILED",
+				Link:           rule.HelpURI,
+			}
+			if sev, ok := parseSecuritySeverity(rule.Properties.SecuritySeverity); ok {
+				ann.AnnotationType = "VULNERABILITY"
+				ann.Severity = cvssSeverity(sev)
+			} else if containsString(rule.Properties.Tags, "security") {
+				ann.AnnotationType = "VULNERABILITY"
+			} else if level == "error" {
+				ann.AnnotationType = "BUG"
+			}
+			if ann.AnnotationType == "VULNERABILITY" {
+				vulns++
+			}
+			if len(res.Locations) > 0 {
+				loc := res.Locations[0].PhysicalLocation
+				ann.Path = relInsightPath(loc.ArtifactLocation.URI, opts.RootDir)
+				ann.Line = loc.Region.StartLine
+			}
+
+			summary := res.Message.Text
+			if res.RuleID != "" {
+				summary = res.RuleID + ": " + summary
+			}
+			ann.Summary = summary
+			ann.Details = rule.ShortDescription.Text
+			ann.ExternalID = annotationID(driver.Name, res.RuleID, ann.Path, strconv.Itoa(ann.Line), res.Message.Text)
+			up.Annotations = append(up.Annotations, ann)
+		}
+	}
+
+	name := strings.Join(tools, ", ")
+	if name == "" {
+		name = "Static analysis"
+	}
+	up.ReportID = "sarif-" + slugify(name)
+	up.Report = InsightReport{
+		Title:      name,
+		Details:    fmt.Sprintf("%d finding(s), %d error(s), %d vulnerabilit(ies)", len(up.Annotations), errorsN, vulns),
+		ReportType: "BUG",
+		Reporter:   name,
+		Link:       link,
+		Result:     "PASSED",
+		Data: []InsightReportData{
+			{Title: "Findings", Type: "NUMBER", Value: len(up.Annotations)},
+			{Title: "Errors", Type: "NUMBER", Value: errorsN},
+		},
+	}
+	if vulns > 0 {
+		up.Report.ReportType = "SECURITY"
+		up.Report.Data = append(up.Report.Data, InsightReportData{Title: "Vulnerabilities", Type: "NUMBER", Value: vulns})
+	}
+	if errorsN > 0 {
+		up.Report.Result = "FAILED"
+	}
+	finalizeUpload(up)
+	return up, nil
+}
+
+func sarifLevelSeverity(level string) string {
+	switch level {
+	case "error":
+		return "HIGH"
+	case "warning":
+		return "MEDIUM"
+	default:
+		return "LOW"
+	}
+}
+
+// parseSecuritySeverity reads a CVSS-style score, which tools emit as either
+// a string or a number.
+func parseSecuritySeverity(v any) (float64, bool) {
+	switch s := v.(type) {
+	case float64:
+		return s, true
+	case string:
+		f, err := strconv.ParseFloat(s, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func cvssSeverity(score float64) string {
+	switch {
+	case score >= 9:
+		return "CRITICAL"
+	case score >= 7:
+		return "HIGH"
+	case score >= 4:
+		return "MEDIUM"
+	default:
+		return "LOW"
+	}
+}
+
+// ─── JUnit XML ───────────────────────────────────────────────────────────────
+
+// junitSuite decodes both a <testsuites> root and a bare <testsuite>, since
+// suites may nest and cases may sit at any level.
+type junitSuite struct {
+	Name   string       `xml:"name,attr"`
+	Suites []junitSuite `xml:"testsuite"`
+	Cases  []junitCase  `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr"`
+	Line      int           `xml:"line,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure"`
+	Error     *junitProblem `xml:"error"`
+	Skipped   *struct{}     `xml:"skipped"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func (s junitSuite) cases() []junitCase {
+	out := append([]junitCase(nil), s.Cases...)
+	for _, sub := range s.Suites {
+		out = append(out, sub.cases()...)
+	}
+	return out
+}
+
+// InsightsFromJUnit converts JUnit XML test results into a TEST report with
+// one annotation per failing or erroring test case.
+func InsightsFromJUnit(data []byte, opts InsightsConvertOptions) (*InsightsUpload, error) {
+	var root junitSuite
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid JUnit XML: %v", err)
+	}
+	cases := root.cases()
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("invalid JUnit XML: no test cases")
+	}
+
+	up := &InsightsUpload{ReportID: "junit"}
+	var failed, skipped int
+	var durationMs float64
+	for _, tc := range cases {
+		if secs, err := strconv.ParseFloat(tc.Time, 64); err == nil {
+			durationMs += secs * 1000
+		}
+		problem := tc.Failure
+		if problem == nil {
+			problem = tc.Error
+		}
+		if problem == nil {
+			if tc.Skipped != nil {
+				skipped++
+			}
+			continue
+		}
+		failed++
+
+		name := tc.Name
+		if tc.Classname != "" {
+			name = tc.Classname + "." + tc.Name
+		}
+		msg := problem.Message
+		if msg == "" {
+			msg = firstNonEmptyLine(problem.Body)
+		}
+		summary := name + " failed"
+		if msg != "" {
+			summary += ": " + msg
+		}
+		path := ""
+		if tc.File != "" {
+			path = relInsightPath(tc.File, opts.RootDir)
+		}
+		up.Annotations = append(up.Annotations, InsightAnnotation{
+			ExternalID:     annotationID("junit", name),
+			AnnotationType: "BUG",
+			Path:           path,
+			Line:           tc.Line,
+			Summary:        summary,
+			Details:        strings.TrimSpace(problem.Body),
+			Result:         "FAILED",
+			Severity:       "HIGH",
+		})
+	}
+
+	up.Report = InsightReport{
+		Title:      "Test results",
+		Details:    fmt.Sprintf("%d test(s), %d failed, %d skipped", len(cases), failed, skipped),
+		ReportType: "TEST",
+		Reporter:   "JUnit",
+		Result:     "PASSED",
+		Data: []InsightReportData{
+			{Title: "Tests", Type: "NUMBER", Value: len(cases)},
+			{Title: "Failed", Type: "NUMBER", Value: failed},
+			{Title: "Skipped", Type: "NUMBER", Value: skipped},
+			{Title: "Duration", Type: "DURATION", Value: int64(durationMs)},
+		},
+	}
+	if failed > 0 {
+		up.Report.Result = "FAILED"
+	}
+	finalizeUpload(up)
+	return up, nil
+}
+
+// ─── Cobertura ───────────────────────────────────────────────────────────────
+
+type coberturaReport struct {
+	LineRate   float64  `xml:"line-rate,attr"`
+	BranchRate float64  `xml:"branch-rate,attr"`
+	Sources    []string `xml:"sources>source"`
+	Packages   []struct {
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Number int `xml:"number,attr"`
+				Hits   int `xml:"hits,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+// InsightsFromCobertura converts a Cobertura coverage report into a COVERAGE
+// report. Each run of consecutive uncovered lines becomes one annotation.
+func InsightsFromCobertura(data []byte, opts InsightsConvertOptions) (*InsightsUpload, error) {
+	var cov coberturaReport
+	if err := xml.Unmarshal(data, &cov); err != nil {
+		return nil, fmt.Errorf("invalid Cobertura XML: %v", err)
+	}
+
+	source := ""
+	for _, s := range cov.Sources {
+		if s = strings.TrimSpace(s); s != "" {
+			source = s
+			break
+		}
+	}
+
+	// A file may appear under several classes; merge hits per line first.
+	uncovered := map[string]map[int]bool{}
+	for _, pkg := range cov.Packages {
+		for _, cls := range pkg.Classes {
+			name := cls.Filename
+			if source != "" && !filepath.IsAbs(name) {
+				name = filepath.Join(source, name)
+			}
+			path := relInsightPath(name, opts.RootDir)
+			lines := uncovered[path]
+			if lines == nil {
+				lines = map[int]bool{}
+				uncovered[path] = lines
+			}
+			for _, l := range cls.Lines {
+				if l.Hits > 0 {
+					lines[l.Number] = false
+				} else if _, seen := lines[l.Number]; !seen {
+					lines[l.Number] = true
+				}
+			}
+		}
+	}
+
+	up := &InsightsUpload{ReportID: "coverage"}
+	paths := make([]string, 0, len(uncovered))
+	for p := range uncovered {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		var nums []int
+		for n, missed := range uncovered[path] {
+			if missed {
+				nums = append(nums, n)
+			}
+		}
+		sort.Ints(nums)
+		for i := 0; i < len(nums); {
+			j := i
+			for j+1 < len(nums) && nums[j+1] == nums[j]+1 {
+				j++
+			}
+			summary := fmt.Sprintf("Line %d is not covered by tests", nums[i])
+			if j > i {
+				summary = fmt.Sprintf("Lines %d-%d are not covered by tests", nums[i], nums[j])
+			}
+			up.Annotations = append(up.Annotations, InsightAnnotation{
+				ExternalID:     annotationID("coverage", path, strconv.Itoa(nums[i])),
+				AnnotationType: "CODE_SMELL",
+				Path:           path,
+				Line:           nums[i],
+				Summary:        summary,
+				Severity:       "LOW",
+			})
+			i = j + 1
+		}
+	}
+
+	pct := math.Round(cov.LineRate*10000) / 100
+	up.Report = InsightReport{
+		Title:      "Coverage",
+		Details:    fmt.Sprintf("%.2f%% of lines covered", pct),
+		ReportType: "COVERAGE",
+		Reporter:   "Cobertura",
+		Result:     "PASSED",
+		Data: []InsightReportData{
+			{Title: "Line coverage", Type: "PERCENTAGE", Value: pct},
+		},
+	}
+	if cov.BranchRate > 0 {
+		up.Report.Data = append(up.Report.Data, InsightReportData{
+			Title: "Branch coverage", Type: "PERCENTAGE", Value: math.Round(cov.BranchRate*10000) / 100,
+		})
+	}
+	if opts.MinCoverage > 0 {
+		up.Report.Details += fmt.Sprintf(" (minimum %.2f%%)", opts.MinCoverage)
+		if pct < opts.MinCoverage {
+			up.Report.Result = "FAILED"
+		}
+	}
+	finalizeUpload(up)
+	return up, nil
+}
+
+// ─── Helpers ─────────────────────────────────────────────────────────────────
+
+// finalizeUpload drops duplicate findings, trims over-long fields, and caps
+// the annotations at MaxAnnotationsPerReport.
+func finalizeUpload(up *InsightsUpload) {
+	seen := map[string]bool{}
+	anns := up.Annotations[:0]
+	for _, a := range up.Annotations {
+		if seen[a.ExternalID] {
+			continue
+		}
+		seen[a.ExternalID] = true
+		a.Summary = truncateRunes(a.Summary, maxAnnotationSummary)
+		a.Details = truncateRunes(a.Details, maxAnnotationDetails)
+		anns = append(anns, a)
+	}
+	if len(anns) > MaxAnnotationsPerReport {
+		up.Dropped = len(anns) - MaxAnnotationsPerReport
+		anns = anns[:MaxAnnotationsPerReport]
+	}
+	if anns == nil {
+		anns = []InsightAnnotation{}
+	}
+	up.Annotations = anns
+}
+
+// relInsightPath turns a file URI or path from a tool's output into a
+// repository-relative, slash-separated path.
+func relInsightPath(p, root string) string {
+	if strings.HasPrefix(p, "file://") {
+		if u, err := url.Parse(p); err == nil {
+			p = u.Path
+		}
+	} else if unescaped, err := url.PathUnescape(p); err == nil {
+		p = unescaped
+	}
+	if root != "" && filepath.IsAbs(p) {
+		if rel, err := filepath.Rel(root, p); err == nil && filepath.IsLocal(rel) {
+			p = rel
+		}
+	}
+	return strings.TrimPrefix(filepath.ToSlash(p), "./")
+}
+
+// annotationID derives a stable external ID so re-uploading the same finding
+// does not duplicate it.
+func annotationID(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+func slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+func firstNonEmptyLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
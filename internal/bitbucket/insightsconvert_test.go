@@ -0,0 +1,167 @@
+package bitbucket
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const testSARIF = `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "gosec", "informationUri": "https://github.com/securego/gosec", "rules": [
+      {"id": "G101", "helpUri": "https://example.com/G101", "shortDescription": {"text": "Hardcoded credentials"},
+       "properties": {"tags": ["security"], "security-severity": "9.1"}},
+      {"id": "S1000", "defaultConfiguration": {"level": "note"}}
+    ]}},
+    "results": [
+      {"ruleId": "G101", "level": "error", "message": {"text": "Potential hardcoded credentials"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "file:///src/app/internal/auth.go"}, "region": {"startLine": 12}}}]},
+      {"ruleId": "S1000", "message": {"text": "Use plain channel send"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "cmd/main.go"}, "region": {"startLine": 3}}}]},
+      {"ruleId": "S1000", "message": {"text": "Use plain channel send"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "cmd/main.go"}, "region": {"startLine": 3}}}]}
+    ]
+  }]
+}`
+
+func TestInsightsFromSARIF(t *testing.T) {
+	up, err := InsightsFromSARIF([]byte(testSARIF), InsightsConvertOptions{RootDir: "/src/app"})
+	if err != nil {
+		t.Fatalf("InsightsFromSARIF: %v", err)
+	}
+	if up.ReportID != "sarif-gosec" || up.Report.ReportType != "SECURITY" || up.Report.Result != "FAILED" {
+		t.Errorf("report = %s %+v", up.ReportID, up.Report)
+	}
+	// The duplicate result collapses into one annotation.
+	if len(up.Annotations) != 2 {
+		t.Fatalf("annotations = %+v", up.Annotations)
+	}
+	vuln := up.Annotations[0]
+	if vuln.AnnotationType != "VULNERABILITY" || vuln.Severity != "CRITICAL" || vuln.Path != "internal/auth.go" || vuln.Line != 12 {
+		t.Errorf("vulnerability = %+v", vuln)
+	}
+	if vuln.Summary != "G101: Potential hardcoded credentials" || vuln.Link != "https://example.com/G101" {
+		t.Errorf("vulnerability = %+v", vuln)
+	}
+	smell := up.Annotations[1]
+	if smell.AnnotationType != "CODE_SMELL" || smell.Severity != "LOW" || smell.Path != "cmd/main.go" {
+		t.Errorf("smell = %+v", smell)
+	}
+
+	again, _ := InsightsFromSARIF([]byte(testSARIF), InsightsConvertOptions{RootDir: "/src/app"})
+	if again.Annotations[0].ExternalID != vuln.ExternalID {
+		t.Error("external IDs are not stable across runs")
+	}
+
+	// ruleIndex -1 is SARIF's "no rule"; the result keeps its own level.
+	noRule, err := InsightsFromSARIF([]byte(`{"runs": [{"tool": {"driver": {"name": "lint", "rules": [{"id": "R1"}]}},
+	  "results": [{"ruleId": "R9", "ruleIndex": -1, "level": "note", "message": {"text": "Unknown rule"}}]}]}`), InsightsConvertOptions{})
+	if err != nil {
+		t.Fatalf("InsightsFromSARIF with ruleIndex -1: %v", err)
+	}
+	if len(noRule.Annotations) != 1 || noRule.Annotations[0].Severity != "LOW" || noRule.Annotations[0].Link != "" {
+		t.Errorf("annotations = %+v", noRule.Annotations)
+	}
+
+	if _, err := InsightsFromSARIF([]byte(`{"runs":[]}`), InsightsConvertOptions{}); err == nil {
+		t.Error("expected error for a log without runs")
+	}
+}
+
+func TestInsightsFromJUnit(t *testing.T) {
+	const doc = `<?xml version="1.0"?>
+<testsuites>
+  <testsuite name="pkg">
+    <testcase classname="pkg" name="TestOK" time="0.5"/>
+    <testcase classname="pkg" name="TestSkip" time="0"><skipped/></testcase>
+    <testsuite name="nested">
+      <testcase classname="pkg.nested" name="TestBad" file="/repo/pkg/bad_test.go" line="42" time="1.25">
+        <failure message="expected 2, got 3">bad_test.go:42: expected 2, got 3</failure>
+      </testcase>
+    </testsuite>
+  </testsuite>
+</testsuites>`
+
+	up, err := InsightsFromJUnit([]byte(doc), InsightsConvertOptions{RootDir: "/repo"})
+	if err != nil {
+		t.Fatalf("InsightsFromJUnit: %v", err)
+	}
+	if up.Report.ReportType != "TEST" || up.Report.Result != "FAILED" {
+		t.Errorf("report = %+v", up.Report)
+	}
+	if len(up.Annotations) != 1 {
+		t.Fatalf("annotations = %+v", up.Annotations)
+	}
+	a := up.Annotations[0]
+	if a.Path != "pkg/bad_test.go" || a.Line != 42 || a.Summary != "pkg.nested.TestBad failed: expected 2, got 3" {
+		t.Errorf("annotation = %+v", a)
+	}
+	want := map[string]any{"Tests": 3, "Failed": 1, "Skipped": 1, "Duration": int64(1750)}
+	for _, d := range up.Report.Data {
+		if fmt.Sprint(want[d.Title]) != fmt.Sprint(d.Value) {
+			t.Errorf("%s = %v, want %v", d.Title, d.Value, want[d.Title])
+		}
+	}
+
+	// A bare <testsuite> root with only passing tests.
+	up, err = InsightsFromJUnit([]byte(`<testsuite><testcase name="a"/></testsuite>`), InsightsConvertOptions{})
+	if err != nil || up.Report.Result != "PASSED" || len(up.Annotations) != 0 {
+		t.Errorf("passing suite = %+v, %v", up, err)
+	}
+}
+
+func TestInsightsFromCobertura(t *testing.T) {
+	const doc = `<?xml version="1.0"?>
+<coverage line-rate="0.625" branch-rate="0">
+  <sources><source>/repo</source></sources>
+  <packages><package name="app"><classes>
+    <class filename="app/main.go"><lines>
+      <line number="1" hits="1"/><line number="2" hits="0"/><line number="3" hits="0"/>
+      <line number="5" hits="0"/><line number="6" hits="4"/>
+    </lines></class>
+    <class filename="app/main.go"><lines><line number="5" hits="2"/><line number="9" hits="0"/></lines></class>
+  </classes></package></packages>
+</coverage>`
+
+	up, err := InsightsFromCobertura([]byte(doc), InsightsConvertOptions{RootDir: "/repo", MinCoverage: 80})
+	if err != nil {
+		t.Fatalf("InsightsFromCobertura: %v", err)
+	}
+	if up.Report.ReportType != "COVERAGE" || up.Report.Result != "FAILED" {
+		t.Errorf("report = %+v", up.Report)
+	}
+	if len(up.Report.Data) != 1 || up.Report.Data[0].Value != 62.5 {
+		t.Errorf("data = %+v", up.Report.Data)
+	}
+	var got []string
+	for _, a := range up.Annotations {
+		got = append(got, fmt.Sprintf("%s:%d %s", a.Path, a.Line, a.Summary))
+	}
+	want := "app/main.go:2 Lines 2-3 are not covered by tests|app/main.go:9 Line 9 is not covered by tests"
+	if strings.Join(got, "|") != want {
+		t.Errorf("annotations = %q", got)
+	}
+
+	up, _ = InsightsFromCobertura([]byte(doc), InsightsConvertOptions{MinCoverage: 50})
+	if up.Report.Result != "PASSED" {
+		t.Errorf("result = %s, want PASSED", up.Report.Result)
+	}
+}
+
+func TestFinalizeUploadCapsAnnotations(t *testing.T) {
+	up := &InsightsUpload{}
+	for i := range MaxAnnotationsPerReport + 20 {
+		up.Annotations = append(up.Annotations, InsightAnnotation{
+			ExternalID: fmt.Sprint(i),
+			Summary:    strings.Repeat("x", maxAnnotationSummary+10),
+		})
+	}
+	finalizeUpload(up)
+	if len(up.Annotations) != MaxAnnotationsPerReport || up.Dropped != 20 {
+		t.Errorf("kept %d, dropped %d", len(up.Annotations), up.Dropped)
+	}
+	if n := len([]rune(up.Annotations[0].Summary)); n != maxAnnotationSummary {
+		t.Errorf("summary length = %d", n)
+	}
+}
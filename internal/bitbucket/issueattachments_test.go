@@ -0,0 +1,83 @@
+package bitbucket
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIssueAttachments(t *testing.T) {
+	const content = "panic: runtime error\n"
+	var calls []string
+	var gotName, gotBody string
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.EscapedPath())
+		switch r.Method {
+		case http.MethodPost:
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Errorf("ParseMultipartForm: %v", err)
+				return
+			}
+			fh := r.MultipartForm.File["file"]
+			if len(fh) != 1 {
+				t.Errorf("file parts = %d, want 1", len(fh))
+				return
+			}
+			gotName = fh[0].Filename
+			f, _ := fh[0].Open()
+			b, _ := io.ReadAll(f)
+			gotBody = string(b)
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			if strings.HasSuffix(r.URL.Path, "/attachments") {
+				_, _ = w.Write([]byte(`{"values": [{"name": "crash log.txt"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(content))
+		}
+	})
+	args := IssueArgs{Workspace: "w", RepoSlug: "r", IssueID: 17}
+
+	if err := c.UploadIssueAttachment(args, "crash log.txt", strings.NewReader(content), int64(len(content))); err != nil {
+		t.Fatalf("UploadIssueAttachment: %v", err)
+	}
+	if gotName != "crash log.txt" || gotBody != content {
+		t.Errorf("server got %q: %q", gotName, gotBody)
+	}
+
+	list, err := c.ListIssueAttachments(args, 0, 0)
+	if err != nil || len(list.Values) != 1 || list.Values[0].Name != "crash log.txt" {
+		t.Fatalf("ListIssueAttachments = %+v, %v", list, err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "crash.txt")
+	res, err := c.DownloadIssueAttachment(args, "crash log.txt", dest, nil)
+	if err != nil {
+		t.Fatalf("DownloadIssueAttachment: %v", err)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != content || res.Bytes != int64(len(content)) {
+		t.Errorf("downloaded %q (%d bytes)", data, res.Bytes)
+	}
+	if data, err := c.GetIssueAttachment(args, "crash log.txt"); err != nil || string(data) != content {
+		t.Errorf("GetIssueAttachment = %q, %v", data, err)
+	}
+
+	if err := c.DeleteIssueAttachment(args, "crash log.txt"); err != nil {
+		t.Fatalf("DeleteIssueAttachment: %v", err)
+	}
+	want := []string{
+		"POST /repositories/w/r/issues/17/attachments",
+		"GET /repositories/w/r/issues/17/attachments",
+		"GET /repositories/w/r/issues/17/attachments/crash%20log.txt",
+		"GET /repositories/w/r/issues/17/attachments/crash%20log.txt",
+		"DELETE /repositories/w/r/issues/17/attachments/crash%20log.txt",
+	}
+	if strings.Join(calls, "|") != strings.Join(want, "|") {
+		t.Errorf("calls = %v", calls)
+	}
+}
@@ -0,0 +1,150 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// IssueComment is a comment on an issue. Comments recording a change to
+// the issue have empty content; see ListIssueChanges for what changed.
+type IssueComment struct {
+	ID        int       `json:"id"`
+	Content   Content   `json:"content"`
+	User      *User     `json:"user"`
+	CreatedOn time.Time `json:"created_on"`
+	UpdatedOn time.Time `json:"updated_on,omitzero"`
+	Links     Links     `json:"links"`
+}
+
+// ListIssueComments lists the comments on an issue, oldest first.
+func (c *Client) ListIssueComments(args IssueArgs, page, pagelen int) (*Paginated[IssueComment], error) {
+	p, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	if pagelen == 0 {
+		pagelen = 25
+	}
+	if page == 0 {
+		page = 1
+	}
+	return GetPaginated[IssueComment](c, fmt.Sprintf("%s/comments?pagelen=%d&page=%d", p, pagelen, page))
+}
+
+// AddIssueComment comments on an issue.
+func (c *Client) AddIssueComment(args IssueArgs, content string) (*IssueComment, error) {
+	p, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	if content == "" {
+		return nil, fmt.Errorf("content is required")
+	}
+
+	respData, err := c.Post(p+"/comments", map[string]any{"content": map[string]string{"raw": content}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add comment: %v", err)
+	}
+	var comment IssueComment
+	if err := json.Unmarshal(respData, &comment); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &comment, nil
+}
+
+// IssueChange is one entry in an issue's history: a set of field changes
+// made together, with the comment that accompanied them, if any.
+type IssueChange struct {
+	ID        int                         `json:"id"`
+	User      *User                       `json:"user"`
+	CreatedOn time.Time                   `json:"created_on"`
+	Message   Content                     `json:"message"`
+	Changes   map[string]IssueFieldChange `json:"changes"`
+	Links     Links                       `json:"links"`
+}
+
+// IssueFieldChange is the old and new value of one field.
+type IssueFieldChange struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// Fields returns the names of the changed fields in order.
+func (ch IssueChange) Fields() []string {
+	names := make([]string, 0, len(ch.Changes))
+	for name := range ch.Changes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ListIssueChanges lists the changes made to an issue, oldest first.
+func (c *Client) ListIssueChanges(args IssueArgs, page, pagelen int) (*Paginated[IssueChange], error) {
+	p, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	if pagelen == 0 {
+		pagelen = 25
+	}
+	if page == 0 {
+		page = 1
+	}
+	return GetPaginated[IssueChange](c, fmt.Sprintf("%s/changes?pagelen=%d&page=%d&sort=created_on", p, pagelen, page))
+}
+
+// HasVotedIssue reports whether the caller has voted for an issue.
+func (c *Client) HasVotedIssue(args IssueArgs) (bool, error) {
+	return c.issueFlag(args, "vote")
+}
+
+// VoteIssue votes for an issue, or retracts the caller's vote.
+func (c *Client) VoteIssue(args IssueArgs, vote bool) error {
+	return c.setIssueFlag(args, "vote", vote)
+}
+
+// IsWatchingIssue reports whether the caller is watching an issue.
+func (c *Client) IsWatchingIssue(args IssueArgs) (bool, error) {
+	return c.issueFlag(args, "watch")
+}
+
+// WatchIssue starts or stops watching an issue.
+func (c *Client) WatchIssue(args IssueArgs, watch bool) error {
+	return c.setIssueFlag(args, "watch", watch)
+}
+
+// issueFlag reads a per-user issue flag: the endpoint answers 204 when it
+// is set and 404 when it is not.
+func (c *Client) issueFlag(args IssueArgs, flag string) (bool, error) {
+	p, err := args.path()
+	if err != nil {
+		return false, err
+	}
+	_, err = c.Get(p + "/" + flag)
+	if isNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get %s: %v", flag, err)
+	}
+	return true, nil
+}
+
+func (c *Client) setIssueFlag(args IssueArgs, flag string, on bool) error {
+	p, err := args.path()
+	if err != nil {
+		return err
+	}
+	if on {
+		_, err = c.Put(p+"/"+flag, nil)
+	} else {
+		err = c.Delete(p + "/" + flag)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %v", flag, err)
+	}
+	return nil
+}
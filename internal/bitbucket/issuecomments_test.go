@@ -0,0 +1,111 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestIssueComments(t *testing.T) {
+	var calls []string
+	var body map[string]any
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPost {
+			b, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(b, &body)
+			_, _ = w.Write([]byte(`{"id": 9, "content": {"raw": "Reproduced on 1.4"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"values": [{"id": 9, "content": {"raw": "Reproduced on 1.4"}, "user": {"display_name": "Alice"}}]}`))
+	})
+	args := IssueArgs{Workspace: "w", RepoSlug: "r", IssueID: 17}
+
+	comment, err := c.AddIssueComment(args, "Reproduced on 1.4")
+	if err != nil || comment.ID != 9 {
+		t.Fatalf("AddIssueComment = %+v, %v", comment, err)
+	}
+	content, _ := body["content"].(map[string]any)
+	if content["raw"] != "Reproduced on 1.4" {
+		t.Errorf("body = %v", body)
+	}
+	comments, err := c.ListIssueComments(args, 0, 0)
+	if err != nil || len(comments.Values) != 1 || comments.Values[0].User.DisplayName != "Alice" {
+		t.Fatalf("ListIssueComments = %+v, %v", comments, err)
+	}
+	if strings.Join(calls, "|") != "POST /repositories/w/r/issues/17/comments|GET /repositories/w/r/issues/17/comments" {
+		t.Errorf("calls = %v", calls)
+	}
+
+	if _, err := c.AddIssueComment(args, ""); err == nil {
+		t.Error("AddIssueComment with no content succeeded")
+	}
+	if _, err := c.ListIssueComments(IssueArgs{Workspace: "w", RepoSlug: "r"}, 0, 0); err == nil {
+		t.Error("ListIssueComments without issue_id succeeded")
+	}
+}
+
+func TestListIssueChanges(t *testing.T) {
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/w/r/issues/17/changes" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"values": [{"id": 3, "changes": {
+			"state": {"old": "new", "new": "resolved"},
+			"assignee_account_id": {"old": "", "new": "557058:abc"}
+		}, "message": {"raw": "Fixed in 1.5"}}]}`))
+	})
+
+	result, err := c.ListIssueChanges(IssueArgs{Workspace: "w", RepoSlug: "r", IssueID: 17}, 0, 0)
+	if err != nil {
+		t.Fatalf("ListIssueChanges: %v", err)
+	}
+	ch := result.Values[0]
+	if got := strings.Join(ch.Fields(), ","); got != "assignee_account_id,state" {
+		t.Errorf("Fields = %s", got)
+	}
+	if ch.Changes["state"].New != "resolved" || ch.Message.Raw != "Fixed in 1.5" {
+		t.Errorf("change = %+v", ch)
+	}
+}
+
+func TestIssueVoteAndWatch(t *testing.T) {
+	voted := false
+	var calls []string
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case http.MethodPut:
+			voted = true
+		case http.MethodDelete:
+			voted = false
+		case http.MethodGet:
+			if !voted {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"type": "error", "error": {"message": "Not found"}}`))
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	args := IssueArgs{Workspace: "w", RepoSlug: "r", IssueID: 17}
+
+	if ok, err := c.HasVotedIssue(args); err != nil || ok {
+		t.Fatalf("HasVotedIssue before voting = %v, %v", ok, err)
+	}
+	if err := c.VoteIssue(args, true); err != nil {
+		t.Fatalf("VoteIssue: %v", err)
+	}
+	if ok, err := c.HasVotedIssue(args); err != nil || !ok {
+		t.Fatalf("HasVotedIssue after voting = %v, %v", ok, err)
+	}
+	if err := c.WatchIssue(args, false); err != nil {
+		t.Fatalf("WatchIssue: %v", err)
+	}
+	want := "GET /repositories/w/r/issues/17/vote|PUT /repositories/w/r/issues/17/vote|GET /repositories/w/r/issues/17/vote|DELETE /repositories/w/r/issues/17/watch"
+	if strings.Join(calls, "|") != want {
+		t.Errorf("calls = %v", calls)
+	}
+}
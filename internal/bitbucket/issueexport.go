@@ -0,0 +1,481 @@
+package bitbucket
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExportedIssue is an issue with its comments and attachments, the unit
+// ExportIssues produces and ImportIssues consumes.
+type ExportedIssue struct {
+	Issue
+	Comments    []IssueComment    `json:"comments"`
+	Attachments []IssueAttachment `json:"attachments"`
+	// ExternalID identifies the issue in the tracker it came from. Exports
+	// leave it empty; readers default it to the exported issue ID.
+	ExternalID string `json:"external_id,omitempty"`
+}
+
+type ExportIssuesArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+	State     string `json:"state,omitempty" jsonschema:"Only export issues in this state"`
+	Parallel  int    `json:"parallel,omitempty" jsonschema:"Issues whose comments and attachments are fetched at once (default 4)"`
+}
+
+// Bounds on how far an export walks. At 100 per page they are far beyond
+// any tracker Bitbucket will host, and only stop a runaway loop.
+const (
+	maxExportIssuePages   = 1000
+	maxExportCommentPages = 100
+)
+
+// ExportIssues fetches every issue in a repository with all of its
+// comments and attachments, oldest issue first.
+func (c *Client) ExportIssues(args ExportIssuesArgs) ([]ExportedIssue, error) {
+	if args.Workspace == "" || args.RepoSlug == "" {
+		return nil, fmt.Errorf("workspace and repo_slug are required")
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/issues?sort=id", QueryEscape(args.Workspace), QueryEscape(args.RepoSlug))
+	if args.State != "" {
+		path += "&q=" + QueryEscape(fmt.Sprintf("state=%q", args.State))
+	}
+	issues, err := listAllPages[Issue](c, path, maxExportIssuePages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %v", err)
+	}
+
+	out := make([]ExportedIssue, len(issues))
+	errs := make([]error, len(issues))
+	forEachParallel(len(issues), args.Parallel, func(i int) {
+		out[i].Issue = issues[i]
+		p, _ := IssueArgs{Workspace: args.Workspace, RepoSlug: args.RepoSlug, IssueID: issues[i].ID}.path()
+		if out[i].Comments, errs[i] = listAllPages[IssueComment](c, p+"/comments", maxExportCommentPages); errs[i] != nil {
+			errs[i] = fmt.Errorf("failed to list comments on #%d: %v", issues[i].ID, errs[i])
+			return
+		}
+		if out[i].Attachments, errs[i] = listAllPages[IssueAttachment](c, p+"/attachments", maxExportCommentPages); errs[i] != nil {
+			errs[i] = fmt.Errorf("failed to list attachments on #%d: %v", issues[i].ID, errs[i])
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// forEachParallel calls fn(0) to fn(n-1) on at most parallel goroutines
+// (4 if parallel is not positive) and waits for them all.
+func forEachParallel(n, parallel int, fn func(i int)) {
+	if parallel < 1 {
+		parallel = 4
+	}
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i := range n {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			fn(i)
+		})
+	}
+	wg.Wait()
+}
+
+// ReadIssuesJSON reads issues in the format `bbkt issues export --format
+// json` writes: an array of ExportedIssue.
+func ReadIssuesJSON(r io.Reader) ([]ExportedIssue, error) {
+	var issues []ExportedIssue
+	if err := json.NewDecoder(r).Decode(&issues); err != nil {
+		return nil, fmt.Errorf("failed to parse issues: %v", err)
+	}
+	for i := range issues {
+		if issues[i].ExternalID == "" && issues[i].ID != 0 {
+			issues[i].ExternalID = strconv.Itoa(issues[i].ID)
+		}
+	}
+	return issues, nil
+}
+
+// IssueCSVColumns are the columns WriteIssuesCSV writes.
+var IssueCSVColumns = []string{
+	"id", "title", "state", "kind", "priority", "assignee", "assignee_account_id", "reporter",
+	"milestone", "component", "version", "votes", "created_on", "updated_on",
+	"content", "comments", "attachments",
+}
+
+// csvCommentSeparator separates comments within the comments column.
+const csvCommentSeparator = "\n\n---\n\n"
+
+// WriteIssuesCSV writes one row per issue. Comments with text are joined
+// into one column, each headed with its author and time, and attachments
+// are listed by name.
+func WriteIssuesCSV(w io.Writer, issues []ExportedIssue) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(IssueCSVColumns); err != nil {
+		return err
+	}
+	for _, is := range issues {
+		var comments, attachments []string
+		for _, cm := range is.Comments {
+			if cm.Content.Raw != "" {
+				comments = append(comments, attributedComment(cm))
+			}
+		}
+		for _, a := range is.Attachments {
+			attachments = append(attachments, a.Name)
+		}
+		var assignee, assigneeID string
+		if is.Assignee != nil {
+			assignee, assigneeID = is.Assignee.DisplayName, is.Assignee.AccountID
+		}
+		if err := cw.Write([]string{
+			strconv.Itoa(is.ID),
+			is.Title,
+			is.State,
+			is.Kind,
+			is.Priority,
+			assignee,
+			assigneeID,
+			userName(is.Reporter),
+			optionName(is.Milestone),
+			optionName(is.Component),
+			optionName(is.Version),
+			strconv.Itoa(is.Votes),
+			formatExportTime(is.CreatedOn),
+			formatExportTime(is.UpdatedOn),
+			is.Content.Raw,
+			strings.Join(comments, csvCommentSeparator),
+			strings.Join(attachments, "; "),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// attributedComment prefixes a comment with its author and time, for
+// comments copied into another tracker under the importer's name.
+func attributedComment(cm IssueComment) string {
+	if cm.User == nil && cm.CreatedOn.IsZero() {
+		return cm.Content.Raw
+	}
+	by := userName(cm.User)
+	if by == "" {
+		by = "unknown"
+	}
+	return fmt.Sprintf("*%s, %s:*\n\n%s", by, formatExportTime(cm.CreatedOn), cm.Content.Raw)
+}
+
+func userName(u *User) string {
+	if u == nil {
+		return ""
+	}
+	return u.DisplayName
+}
+
+func optionName(o *IssueOption) string {
+	if o == nil {
+		return ""
+	}
+	return o.Name
+}
+
+func formatExportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// IssueImportFields are the issue fields ReadIssuesCSV can fill from a
+// column.
+var IssueImportFields = []string{
+	"title", "content", "kind", "priority", "state", "assignee",
+	"milestone", "component", "version", "comments", "external_id",
+}
+
+// issueCSVDefaults are the columns tried, in order, for a field with no
+// explicit mapping, beyond the field's own name. They let a file written
+// by WriteIssuesCSV be read back unmapped.
+var issueCSVDefaults = map[string][]string{
+	"assignee":    {"assignee_account_id", "assignee"},
+	"external_id": {"external_id", "id"},
+}
+
+// ReadIssuesCSV reads issues from a CSV file with a header row. mapping
+// maps fields in IssueImportFields to the header of the column holding
+// them; unmapped fields use the column named after the field, if any.
+// Headers match case-insensitively and a title column is required.
+// The assignee column must hold account IDs, and the comments column
+// holds comments separated as WriteIssuesCSV writes them.
+func ReadIssuesCSV(r io.Reader, mapping map[string]string) ([]ExportedIssue, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %v", err)
+	}
+	index := map[string]int{}
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if _, dup := index[h]; !dup {
+			index[h] = i
+		}
+	}
+
+	columns := map[string]int{}
+	for field, column := range mapping {
+		if !isIssueImportField(field) {
+			return nil, fmt.Errorf("unknown field %q: expected one of %s", field, strings.Join(IssueImportFields, ", "))
+		}
+		i, ok := index[strings.ToLower(column)]
+		if !ok {
+			return nil, fmt.Errorf("column %q (mapped to %s) is not in the CSV header", column, field)
+		}
+		columns[field] = i
+	}
+	for _, field := range IssueImportFields {
+		if _, ok := columns[field]; ok {
+			continue
+		}
+		candidates := issueCSVDefaults[field]
+		if candidates == nil {
+			candidates = []string{field}
+		}
+		for _, name := range candidates {
+			if i, ok := index[name]; ok {
+				columns[field] = i
+				break
+			}
+		}
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("no title column: name one \"title\" or map it with title=<column>")
+	}
+
+	var issues []ExportedIssue
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %v", err)
+		}
+		get := func(field string) string {
+			i, ok := columns[field]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		if get("title") == "" {
+			return nil, fmt.Errorf("line %d: title is empty", line)
+		}
+
+		is := ExportedIssue{ExternalID: get("external_id")}
+		is.Title = get("title")
+		is.Content.Raw = get("content")
+		is.Kind = get("kind")
+		is.Priority = get("priority")
+		is.State = get("state")
+		if v := get("assignee"); v != "" {
+			is.Assignee = &User{AccountID: v}
+		}
+		if v := get("milestone"); v != "" {
+			is.Milestone = &IssueOption{Name: v}
+		}
+		if v := get("component"); v != "" {
+			is.Component = &IssueOption{Name: v}
+		}
+		if v := get("version"); v != "" {
+			is.Version = &IssueOption{Name: v}
+		}
+		if v := get("comments"); v != "" {
+			for _, text := range strings.Split(v, csvCommentSeparator) {
+				is.Comments = append(is.Comments, IssueComment{Content: Content{Raw: text}})
+			}
+		}
+		issues = append(issues, is)
+	}
+	return issues, nil
+}
+
+func isIssueImportField(field string) bool {
+	for _, f := range IssueImportFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// IssueArchiveArgs identifies the repository whose tracker is exported to
+// or imported from Bitbucket's zip archive format.
+type IssueArchiveArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+}
+
+func (a IssueArchiveArgs) path() (string, error) {
+	if a.Workspace == "" || a.RepoSlug == "" {
+		return "", fmt.Errorf("workspace and repo_slug are required")
+	}
+	return fmt.Sprintf("/repositories/%s/%s/issues", QueryEscape(a.Workspace), QueryEscape(a.RepoSlug)), nil
+}
+
+// IssueJobStatus reports the progress of a Bitbucket issue export or
+// import job.
+type IssueJobStatus struct {
+	Type   string  `json:"type"`
+	Status string  `json:"status"`
+	Phase  string  `json:"phase"`
+	Total  int     `json:"total"`
+	Count  int     `json:"count"`
+	Pct    float64 `json:"pct"`
+}
+
+// Running reports whether the job has yet to finish.
+func (s IssueJobStatus) Running() bool {
+	switch s.Status {
+	case "ACCEPTED", "STARTED", "RUNNING":
+		return true
+	}
+	return false
+}
+
+// How often archive jobs are polled, and how long they may take.
+var (
+	issueJobPollInterval = 2 * time.Second
+	issueJobTimeout      = 30 * time.Minute
+)
+
+// ExportIssuesArchive starts a Bitbucket export of the issue tracker,
+// waits for it, and saves the zip archive, attachments included, to dest.
+// status, if non-nil, is called each time the job is polled, and progress
+// as the archive downloads.
+func (c *Client) ExportIssuesArchive(args IssueArchiveArgs, dest string, status func(IssueJobStatus), progress func(done, total int64)) (*DownloadResult, error) {
+	p, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	body, _ := json.Marshal(map[string]any{"send_email": false, "include_attachments": true})
+	resp, err := c.do(http.MethodPost, p+"/export", body, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	data, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("failed to start export: %v", parseAuthError(resp, data))
+	}
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil, fmt.Errorf("failed to start export: no archive location in response")
+	}
+	if strings.HasPrefix(location, c.baseURL) {
+		location = strings.TrimPrefix(location, c.baseURL)
+	} else if !strings.HasPrefix(location, "/") {
+		return nil, fmt.Errorf("failed to start export: unexpected archive location %s", location)
+	}
+
+	deadline := time.Now().Add(issueJobTimeout)
+	for {
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("export did not finish within %s", issueJobTimeout)
+		}
+		resp, err := c.do(http.MethodGet, location, nil, "", "*/*")
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusAccepted {
+			data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+			if resp.StatusCode >= 400 {
+				return nil, fmt.Errorf("export failed: %v", parseAuthError(resp, data))
+			}
+			break
+		}
+		var s IssueJobStatus
+		err = json.NewDecoder(resp.Body).Decode(&s)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse response: %v", err)
+		}
+		if status != nil {
+			status(s)
+		}
+		if !s.Running() {
+			return nil, fmt.Errorf("export failed: unexpected job status %q", s.Status)
+		}
+		time.Sleep(issueJobPollInterval)
+	}
+	return c.DownloadToFile(location, dest, progress)
+}
+
+// ImportIssuesArchive uploads a zip archive in Bitbucket's export format
+// and waits for Bitbucket to import it. The import replaces every issue
+// in the repository. status, if non-nil, is called each time the job is
+// polled.
+func (c *Client) ImportIssuesArchive(args IssueArchiveArgs, r io.Reader, size int64, status func(IssueJobStatus)) (*IssueJobStatus, error) {
+	p, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, fmt.Errorf("archive is required")
+	}
+	if _, err := c.PostMultipartStream(p+"/import", nil, []MultipartFile{{
+		Field:    "archive",
+		Filename: "issues.zip",
+		Reader:   r,
+		Size:     size,
+	}}); err != nil {
+		return nil, fmt.Errorf("failed to upload archive: %v", err)
+	}
+
+	deadline := time.Now().Add(issueJobTimeout)
+	for {
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("import did not finish within %s", issueJobTimeout)
+		}
+		s, err := GetJSON[IssueJobStatus](c, p+"/import")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get import status: %v", err)
+		}
+		if status != nil {
+			status(*s)
+		}
+		switch {
+		case s.Status == "SUCCESS":
+			return s, nil
+		case s.Status == "FAILURE":
+			return s, fmt.Errorf("import failed during %s", orUnknown(s.Phase))
+		case !s.Running():
+			return s, fmt.Errorf("import failed: unexpected job status %q", s.Status)
+		}
+		time.Sleep(issueJobPollInterval)
+	}
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "an unknown phase"
+	}
+	return s
+}
@@ -0,0 +1,206 @@
+package bitbucket
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExportIssues_WalksPagesWithCommentsAndAttachments(t *testing.T) {
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/issues") && page == "1":
+			_, _ = w.Write([]byte(`{"values": [{"id": 1, "title": "One"}], "next": "page2"}`))
+		case strings.HasSuffix(r.URL.Path, "/issues"):
+			_, _ = w.Write([]byte(`{"values": [{"id": 2, "title": "Two"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/1/comments"):
+			_, _ = w.Write([]byte(`{"values": [{"id": 10, "content": {"raw": "first"}}]}`))
+		case strings.HasSuffix(r.URL.Path, "/1/attachments"):
+			_, _ = w.Write([]byte(`{"values": [{"name": "log.txt"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"values": []}`))
+		}
+	})
+
+	issues, err := c.ExportIssues(ExportIssuesArgs{Workspace: "w", RepoSlug: "r", Parallel: 2})
+	if err != nil {
+		t.Fatalf("ExportIssues: %v", err)
+	}
+	if len(issues) != 2 || issues[0].Title != "One" || issues[1].Title != "Two" {
+		t.Fatalf("issues = %+v", issues)
+	}
+	if len(issues[0].Comments) != 1 || issues[0].Comments[0].Content.Raw != "first" {
+		t.Errorf("comments = %+v", issues[0].Comments)
+	}
+	if len(issues[0].Attachments) != 1 || len(issues[1].Attachments) != 0 {
+		t.Errorf("attachments = %+v, %+v", issues[0].Attachments, issues[1].Attachments)
+	}
+}
+
+func TestIssuesCSV_RoundTrip(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	in := []ExportedIssue{{
+		Issue: Issue{
+			ID: 7, Title: "Crash, on save", State: "open", Kind: "bug",
+			Content:   Content{Raw: "line one\nline two"},
+			Assignee:  &User{DisplayName: "Ada", AccountID: "acc-1"},
+			Milestone: &IssueOption{Name: "v1.0"},
+		},
+		Comments: []IssueComment{
+			{Content: Content{Raw: "seen it"}, User: &User{DisplayName: "Bob"}, CreatedOn: created},
+			{}, // a change record
+		},
+		Attachments: []IssueAttachment{{Name: "a.log"}, {Name: "b.png"}},
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteIssuesCSV(&buf, in); err != nil {
+		t.Fatalf("WriteIssuesCSV: %v", err)
+	}
+	out, err := ReadIssuesCSV(&buf, nil)
+	if err != nil {
+		t.Fatalf("ReadIssuesCSV: %v", err)
+	}
+	if len(out) != 1 {
+		t.Fatalf("out = %+v", out)
+	}
+	got := out[0]
+	if got.ExternalID != "7" || got.Title != "Crash, on save" || got.Content.Raw != "line one\nline two" || got.State != "open" {
+		t.Errorf("issue = %+v", got)
+	}
+	if got.Assignee == nil || got.Assignee.AccountID != "acc-1" || got.Milestone == nil || got.Milestone.Name != "v1.0" {
+		t.Errorf("assignee/milestone = %+v, %+v", got.Assignee, got.Milestone)
+	}
+	if len(got.Comments) != 1 || got.Comments[0].Content.Raw != "*Bob, 2026-03-01T12:00:00Z:*\n\nseen it" {
+		t.Errorf("comments = %+v", got.Comments)
+	}
+}
+
+func TestReadIssuesCSV_Mapping(t *testing.T) {
+	data := "Summary,Details,Key\nLogin fails,Steps...,JIRA-1\n"
+	out, err := ReadIssuesCSV(strings.NewReader(data), map[string]string{
+		"title": "summary", "content": "Details", "external_id": "KEY",
+	})
+	if err != nil {
+		t.Fatalf("ReadIssuesCSV: %v", err)
+	}
+	if len(out) != 1 || out[0].Title != "Login fails" || out[0].Content.Raw != "Steps..." || out[0].ExternalID != "JIRA-1" {
+		t.Errorf("out = %+v", out)
+	}
+
+	if _, err := ReadIssuesCSV(strings.NewReader(data), nil); err == nil || !strings.Contains(err.Error(), "no title column") {
+		t.Errorf("unmapped title error = %v", err)
+	}
+	if _, err := ReadIssuesCSV(strings.NewReader(data), map[string]string{"title": "Name"}); err == nil || !strings.Contains(err.Error(), `"Name"`) {
+		t.Errorf("missing column error = %v", err)
+	}
+	if _, err := ReadIssuesCSV(strings.NewReader(data), map[string]string{"summary": "Summary"}); err == nil || !strings.Contains(err.Error(), "unknown field") {
+		t.Errorf("unknown field error = %v", err)
+	}
+}
+
+func TestReadIssuesJSON_DefaultsExternalID(t *testing.T) {
+	out, err := ReadIssuesJSON(strings.NewReader(`[{"id": 3, "title": "A"}, {"id": 4, "title": "B", "external_id": "X-4"}]`))
+	if err != nil {
+		t.Fatalf("ReadIssuesJSON: %v", err)
+	}
+	if out[0].ExternalID != "3" || out[1].ExternalID != "X-4" {
+		t.Errorf("out = %+v", out)
+	}
+}
+
+func TestExportIssuesArchive_PollsThenDownloads(t *testing.T) {
+	issueJobPollInterval = time.Millisecond
+	var polls atomic.Int32
+	var srvURL string
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repositories/w/r/issues/export":
+			w.Header().Set("Location", srvURL+"/repositories/w/r/issues/export/r-issues.zip")
+			w.WriteHeader(http.StatusAccepted)
+		case r.URL.Path == "/repositories/w/r/issues/export/r-issues.zip" && polls.Add(1) < 3:
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"type": "issue_job_status", "status": "RUNNING", "pct": 50}`))
+		case r.URL.Path == "/repositories/w/r/issues/export/r-issues.zip":
+			_, _ = w.Write([]byte("PK-zip-bytes"))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	srvURL = c.baseURL
+
+	dest := filepath.Join(t.TempDir(), "r.zip")
+	var statuses int
+	res, err := c.ExportIssuesArchive(IssueArchiveArgs{Workspace: "w", RepoSlug: "r"}, dest, func(IssueJobStatus) { statuses++ }, nil)
+	if err != nil {
+		t.Fatalf("ExportIssuesArchive: %v", err)
+	}
+	if statuses != 2 || res.Bytes != int64(len("PK-zip-bytes")) {
+		t.Errorf("statuses = %d, result = %+v", statuses, res)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "PK-zip-bytes" {
+		t.Errorf("archive = %q", data)
+	}
+}
+
+func TestImportIssuesArchive_UploadsAndWaits(t *testing.T) {
+	issueJobPollInterval = time.Millisecond
+	var uploaded string
+	var polls atomic.Int32
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			f, _, err := r.FormFile("archive")
+			if err == nil {
+				b, _ := io.ReadAll(f)
+				uploaded = string(b)
+			}
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"status": "ACCEPTED"}`))
+			return
+		}
+		if polls.Add(1) < 2 {
+			_, _ = w.Write([]byte(`{"status": "RUNNING", "phase": "Importing issues"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status": "FAILURE", "phase": "Importing comments"}`))
+	})
+
+	s, err := c.ImportIssuesArchive(IssueArchiveArgs{Workspace: "w", RepoSlug: "r"}, strings.NewReader("zip"), 3, nil)
+	if uploaded != "zip" {
+		t.Errorf("uploaded = %q", uploaded)
+	}
+	if err == nil || !strings.Contains(err.Error(), "Importing comments") || s == nil || s.Status != "FAILURE" {
+		t.Errorf("status = %+v, err = %v", s, err)
+	}
+}
+
+func TestImportIssuesArchive_StopsOnUnknownStatusOrTimeout(t *testing.T) {
+	issueJobPollInterval = time.Millisecond
+	status := `{"status": "PAUSED"}`
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		_, _ = w.Write([]byte(status))
+	})
+	args := IssueArchiveArgs{Workspace: "w", RepoSlug: "r"}
+
+	if _, err := c.ImportIssuesArchive(args, strings.NewReader("zip"), 3, nil); err == nil || !strings.Contains(err.Error(), `"PAUSED"`) {
+		t.Errorf("unknown status err = %v", err)
+	}
+
+	status = `{"status": "RUNNING"}`
+	issueJobTimeout = 20 * time.Millisecond
+	t.Cleanup(func() { issueJobTimeout = 30 * time.Minute })
+	if _, err := c.ImportIssuesArchive(args, strings.NewReader("zip"), 3, nil); err == nil || !strings.Contains(err.Error(), "did not finish") {
+		t.Errorf("timeout err = %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package bitbucket
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// How ImportIssues decides an issue is already in the repository.
+const (
+	DedupeByTitle      = "title"
+	DedupeByExternalID = "external-id"
+	DedupeNone         = "none"
+)
+
+// Import outcomes reported in ImportResult.Status.
+const (
+	ImportCreated     = "created"
+	ImportSkipped     = "skipped"
+	ImportFailed      = "failed"
+	ImportWouldCreate = "would create"
+)
+
+type ImportIssuesArgs struct {
+	Workspace string          `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string          `json:"repo_slug" jsonschema:"Repository slug"`
+	Issues    []ExportedIssue `json:"issues" jsonschema:"Issues to create"`
+	Dedupe    string          `json:"dedupe,omitempty" jsonschema:"Skip issues already present by 'title' (default), 'external-id', or 'none'"`
+	Parallel  int             `json:"parallel,omitempty" jsonschema:"Issues created at once (default 4)"`
+	DryRun    bool            `json:"dry_run,omitempty" jsonschema:"Report what would be created without creating anything"`
+}
+
+// ImportResult is the outcome for one issue given to ImportIssues.
+type ImportResult struct {
+	ExternalID string `json:"external_id,omitempty"`
+	Title      string `json:"title"`
+	Status     string `json:"status"`
+	// IssueID is the issue created, or the existing issue it duplicates.
+	IssueID int    `json:"issue_id,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// importIDPattern finds the external ID ImportIssues records at the end
+// of an imported issue's description.
+var importIDPattern = regexp.MustCompile(`\*Imported from: ([^*\n]+)\*\s*$`)
+
+// ImportIssues creates each issue with its comments, skipping those the
+// repository already has according to args.Dedupe. Issues with an
+// ExternalID record it in their description so a later import with
+// external-id de-duplication recognises them. Comments by someone are
+// posted headed with their author and time; the importer is the author
+// Bitbucket shows. Attachments are not copied.
+//
+// The returned results are in the order of args.Issues; a failure to
+// create one issue is reported in its result, not as an error.
+func (c *Client) ImportIssues(args ImportIssuesArgs) ([]ImportResult, error) {
+	if args.Workspace == "" || args.RepoSlug == "" {
+		return nil, fmt.Errorf("workspace and repo_slug are required")
+	}
+	dedupe := args.Dedupe
+	if dedupe == "" {
+		dedupe = DedupeByTitle
+	}
+	if dedupe != DedupeByTitle && dedupe != DedupeByExternalID && dedupe != DedupeNone {
+		return nil, fmt.Errorf("unknown dedupe %q: expected title, external-id, or none", dedupe)
+	}
+
+	// Issues already in the repository, and those earlier in the input,
+	// by de-duplication key.
+	seen := map[string]int{}
+	if dedupe != DedupeNone {
+		existing, err := listAllPages[Issue](c, fmt.Sprintf("/repositories/%s/%s/issues",
+			QueryEscape(args.Workspace), QueryEscape(args.RepoSlug)), maxExportIssuePages)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list existing issues: %v", err)
+		}
+		for _, is := range existing {
+			key := importKey(dedupe, is.Title, importedID(is.Content.Raw))
+			if _, dup := seen[key]; key != "" && !dup {
+				seen[key] = is.ID
+			}
+		}
+	}
+
+	results := make([]ImportResult, len(args.Issues))
+	var todo []int
+	for i, is := range args.Issues {
+		results[i] = ImportResult{ExternalID: is.ExternalID, Title: is.Title}
+		if strings.TrimSpace(is.Title) == "" {
+			results[i].Status, results[i].Message = ImportFailed, "title is empty"
+			continue
+		}
+		key := importKey(dedupe, is.Title, is.ExternalID)
+		if id, dup := seen[key]; dup && key != "" {
+			results[i].Status, results[i].IssueID = ImportSkipped, id
+			results[i].Message = "already exists"
+			if id == 0 {
+				results[i].Message = "duplicate in input"
+			}
+			continue
+		}
+		if key != "" {
+			seen[key] = 0
+		}
+		todo = append(todo, i)
+	}
+
+	forEachParallel(len(todo), args.Parallel, func(n int) {
+		i := todo[n]
+		var err error
+		if args.DryRun {
+			err = c.checkImport(args.Workspace, args.RepoSlug, args.Issues[i])
+			results[i].Status = ImportWouldCreate
+		} else {
+			results[i].IssueID, err = c.importIssue(args.Workspace, args.RepoSlug, args.Issues[i])
+			results[i].Status = ImportCreated
+		}
+		if err != nil {
+			results[i].Status, results[i].Message = ImportFailed, err.Error()
+		}
+	})
+	return results, nil
+}
+
+// importKey is the de-duplication key for an issue, or "" if it has none.
+func importKey(dedupe, title, externalID string) string {
+	switch dedupe {
+	case DedupeByTitle:
+		return strings.ToLower(strings.Join(strings.Fields(title), " "))
+	case DedupeByExternalID:
+		return externalID
+	}
+	return ""
+}
+
+// importedID returns the external ID recorded in an imported issue's
+// description, if any.
+func importedID(content string) string {
+	if m := importIDPattern.FindStringSubmatch(content); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	return ""
+}
+
+// checkImport validates what importIssue would send without writing.
+func (c *Client) checkImport(workspace, repoSlug string, is ExportedIssue) error {
+	for list, o := range map[string]*IssueOption{IssueMilestones: is.Milestone, IssueComponents: is.Component, IssueVersions: is.Version} {
+		if o != nil && o.Name != "" {
+			if _, err := c.resolveIssueOption(workspace, repoSlug, list, o.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// importIssue creates one issue, sets its state, and adds its comments,
+// returning the new issue's ID even if a later step fails.
+func (c *Client) importIssue(workspace, repoSlug string, is ExportedIssue) (int, error) {
+	content := is.Content.Raw
+	if is.ExternalID != "" {
+		content = strings.TrimRight(content, "\n")
+		if content != "" {
+			content += "\n\n"
+		}
+		content += "*Imported from: " + is.ExternalID + "*"
+	}
+	create := CreateIssueArgs{
+		Workspace: workspace,
+		RepoSlug:  repoSlug,
+		Title:     is.Title,
+		Content:   content,
+		Kind:      is.Kind,
+		Priority:  is.Priority,
+		Milestone: optionName(is.Milestone),
+		Component: optionName(is.Component),
+		Version:   optionName(is.Version),
+	}
+	if is.Assignee != nil {
+		create.Assignee = is.Assignee.AccountID
+	}
+	created, err := c.CreateIssue(create)
+	if err != nil {
+		return 0, err
+	}
+
+	// New issues always start out "new".
+	if is.State != "" && is.State != "new" {
+		state := is.State
+		if _, err := c.UpdateIssue(UpdateIssueArgs{Workspace: workspace, RepoSlug: repoSlug, IssueID: created.ID, State: &state}); err != nil {
+			return created.ID, fmt.Errorf("created #%d but failed to set state: %v", created.ID, err)
+		}
+	}
+
+	target := IssueArgs{Workspace: workspace, RepoSlug: repoSlug, IssueID: created.ID}
+	for n, cm := range is.Comments {
+		if strings.TrimSpace(cm.Content.Raw) == "" {
+			continue
+		}
+		if _, err := c.AddIssueComment(target, attributedComment(cm)); err != nil {
+			return created.ID, fmt.Errorf("created #%d but failed to add comment %d: %v", created.ID, n+1, err)
+		}
+	}
+	return created.ID, nil
+}
@@ -0,0 +1,126 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// importServer serves one existing issue and records the bodies of the
+// requests that create, update, and comment on issues, by path.
+func importServer(t *testing.T) (*Client, func() map[string][]map[string]any) {
+	var mu sync.Mutex
+	writes := map[string][]map[string]any{}
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			switch {
+			case strings.HasSuffix(r.URL.Path, "/issues"):
+				_, _ = w.Write([]byte(`{"values": [{"id": 1, "title": "Existing  issue", "content": {"raw": "x\n\n*Imported from: JIRA-1*"}}]}`))
+			case strings.HasSuffix(r.URL.Path, "/milestones"):
+				_, _ = w.Write([]byte(`{"values": [{"name": "v1.0"}]}`))
+			default:
+				_, _ = w.Write([]byte(`{"values": []}`))
+			}
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		_ = json.Unmarshal(b, &body)
+		mu.Lock()
+		writes[r.Method+" "+r.URL.Path] = append(writes[r.Method+" "+r.URL.Path], body)
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"id": 9}`))
+	})
+	return c, func() map[string][]map[string]any {
+		mu.Lock()
+		defer mu.Unlock()
+		return writes
+	}
+}
+
+func TestImportIssues_DedupesByTitle(t *testing.T) {
+	c, writes := importServer(t)
+
+	results, err := c.ImportIssues(ImportIssuesArgs{Workspace: "w", RepoSlug: "r", Issues: []ExportedIssue{
+		{Issue: Issue{Title: "existing issue"}},
+		{Issue: Issue{Title: "New one", State: "resolved"}, ExternalID: "JIRA-2", Comments: []IssueComment{
+			{Content: Content{Raw: "hello"}, User: &User{DisplayName: "Ada"}},
+		}},
+		{Issue: Issue{Title: "new ONE"}},
+	}})
+	if err != nil {
+		t.Fatalf("ImportIssues: %v", err)
+	}
+
+	if results[0].Status != ImportSkipped || results[0].IssueID != 1 {
+		t.Errorf("existing = %+v", results[0])
+	}
+	if results[1].Status != ImportCreated || results[1].IssueID != 9 {
+		t.Errorf("new = %+v", results[1])
+	}
+	if results[2].Status != ImportSkipped || results[2].Message != "duplicate in input" {
+		t.Errorf("duplicate = %+v", results[2])
+	}
+
+	w := writes()
+	creates := w["POST /repositories/w/r/issues"]
+	if len(creates) != 1 {
+		t.Fatalf("creates = %v", creates)
+	}
+	if content, _ := creates[0]["content"].(map[string]any); content["raw"] != "*Imported from: JIRA-2*" {
+		t.Errorf("content = %v", creates[0]["content"])
+	}
+	if u := w["PUT /repositories/w/r/issues/9"]; len(u) != 1 || u[0]["state"] != "resolved" {
+		t.Errorf("updates = %v", u)
+	}
+	comments := w["POST /repositories/w/r/issues/9/comments"]
+	if len(comments) != 1 || !strings.HasPrefix(comments[0]["content"].(map[string]any)["raw"].(string), "*Ada, ") {
+		t.Errorf("comments = %v", comments)
+	}
+}
+
+func TestImportIssues_DedupesByExternalID(t *testing.T) {
+	c, _ := importServer(t)
+
+	results, err := c.ImportIssues(ImportIssuesArgs{Workspace: "w", RepoSlug: "r", Dedupe: DedupeByExternalID, DryRun: true, Issues: []ExportedIssue{
+		{Issue: Issue{Title: "Renamed"}, ExternalID: "JIRA-1"},
+		{Issue: Issue{Title: "Existing issue"}, ExternalID: "JIRA-3"},
+	}})
+	if err != nil {
+		t.Fatalf("ImportIssues: %v", err)
+	}
+	if results[0].Status != ImportSkipped || results[0].IssueID != 1 {
+		t.Errorf("JIRA-1 = %+v", results[0])
+	}
+	if results[1].Status != ImportWouldCreate {
+		t.Errorf("JIRA-3 = %+v", results[1])
+	}
+}
+
+func TestImportIssues_DryRunWritesNothing(t *testing.T) {
+	c, writes := importServer(t)
+
+	results, err := c.ImportIssues(ImportIssuesArgs{Workspace: "w", RepoSlug: "r", Dedupe: DedupeNone, DryRun: true, Issues: []ExportedIssue{
+		{Issue: Issue{Title: "A", Milestone: &IssueOption{Name: "V1.0"}}},
+		{Issue: Issue{Title: "B", Milestone: &IssueOption{Name: "v9"}}},
+	}})
+	if err != nil {
+		t.Fatalf("ImportIssues: %v", err)
+	}
+	if results[0].Status != ImportWouldCreate {
+		t.Errorf("A = %+v", results[0])
+	}
+	if results[1].Status != ImportFailed || !strings.Contains(results[1].Message, "unknown milestone") {
+		t.Errorf("B = %+v", results[1])
+	}
+	if w := writes(); len(w) != 0 {
+		t.Errorf("dry run wrote %v", w)
+	}
+
+	if _, err := c.ImportIssues(ImportIssuesArgs{Workspace: "w", RepoSlug: "r", Dedupe: "body"}); err == nil {
+		t.Error("expected error for unknown dedupe")
+	}
+}
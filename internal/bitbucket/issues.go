@@ -8,19 +8,22 @@ import (
 
 // Issue represents a Bitbucket issue.
 type Issue struct {
-	ID        int       `json:"id"`
-	Title     string    `json:"title"`
-	Content   Content   `json:"content"`
-	State     string    `json:"state"`
-	Priority  string    `json:"priority"`
-	Kind      string    `json:"kind"`
-	Assignee  *User     `json:"assignee,omitempty"`
-	Reporter  *User     `json:"reporter,omitempty"`
-	CreatedOn time.Time `json:"created_on"`
-	UpdatedOn time.Time `json:"updated_on"`
-	Votes     int       `json:"votes"`
-	Watches   int       `json:"watches"`
-	Links     Links     `json:"links"`
+	ID        int          `json:"id"`
+	Title     string       `json:"title"`
+	Content   Content      `json:"content"`
+	State     string       `json:"state"`
+	Priority  string       `json:"priority"`
+	Kind      string       `json:"kind"`
+	Assignee  *User        `json:"assignee,omitempty"`
+	Reporter  *User        `json:"reporter,omitempty"`
+	Milestone *IssueOption `json:"milestone,omitempty"`
+	Component *IssueOption `json:"component,omitempty"`
+	Version   *IssueOption `json:"version,omitempty"`
+	CreatedOn time.Time    `json:"created_on"`
+	UpdatedOn time.Time    `json:"updated_on"`
+	Votes     int          `json:"votes"`
+	Watches   int          `json:"watches"`
+	Links     Links        `json:"links"`
 }
 
 type ListIssuesArgs struct {
@@ -29,6 +32,9 @@ type ListIssuesArgs struct {
 	State     string `json:"state,omitempty" jsonschema:"Filter by state (new, open, resolved, on hold, invalid, duplicate, wontfix, closed)"`
 	Kind      string `json:"kind,omitempty" jsonschema:"Filter by kind (bug, enhancement, proposal, task)"`
 	Priority  string `json:"priority,omitempty" jsonschema:"Filter by priority (trivial, minor, major, critical, blocker)"`
+	Milestone string `json:"milestone,omitempty" jsonschema:"Filter by milestone name"`
+	Component string `json:"component,omitempty" jsonschema:"Filter by component name"`
+	Version   string `json:"version,omitempty" jsonschema:"Filter by version name"`
 	Search    string `json:"search,omitempty" jsonschema:"Search query"`
 	Sort      string `json:"sort,omitempty" jsonschema:"Sort field"`
 	Pagelen   int    `json:"pagelen,omitempty" jsonschema:"Results per page"`
@@ -64,6 +70,15 @@ func (c *Client) ListIssues(args ListIssuesArgs) (*Paginated[Issue], error) {
 	if args.Priority != "" {
 		queries = append(queries, fmt.Sprintf("priority=%q", args.Priority))
 	}
+	if args.Milestone != "" {
+		queries = append(queries, fmt.Sprintf("milestone.name=%q", args.Milestone))
+	}
+	if args.Component != "" {
+		queries = append(queries, fmt.Sprintf("component.name=%q", args.Component))
+	}
+	if args.Version != "" {
+		queries = append(queries, fmt.Sprintf("version.name=%q", args.Version))
+	}
 
 	if len(queries) > 0 {
 		path += "&q=" + QueryEscape(joinQueries(queries, " AND "))
@@ -109,6 +124,21 @@ func (c *Client) GetIssue(args GetIssueArgs) (*Issue, error) {
 		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), args.IssueID))
 }
 
+// IssueArgs identifies an issue for the comment, attachment, change,
+// vote, and watch endpoints.
+type IssueArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+	IssueID   int    `json:"issue_id" jsonschema:"Issue ID"`
+}
+
+func (a IssueArgs) path() (string, error) {
+	if a.Workspace == "" || a.RepoSlug == "" || a.IssueID == 0 {
+		return "", fmt.Errorf("workspace, repo_slug, and issue_id are required")
+	}
+	return fmt.Sprintf("/repositories/%s/%s/issues/%d", QueryEscape(a.Workspace), QueryEscape(a.RepoSlug), a.IssueID), nil
+}
+
 type CreateIssueArgs struct {
 	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
 	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
@@ -117,6 +147,9 @@ type CreateIssueArgs struct {
 	Kind      string `json:"kind,omitempty" jsonschema:"Kind: bug, enhancement, proposal, task (default: bug)"`
 	Priority  string `json:"priority,omitempty" jsonschema:"Priority: trivial, minor, major, critical, blocker (default: major)"`
 	Assignee  string `json:"assignee,omitempty" jsonschema:"Assignee account ID"`
+	Milestone string `json:"milestone,omitempty" jsonschema:"Milestone name (must exist in the repository's issue tracker)"`
+	Component string `json:"component,omitempty" jsonschema:"Component name (must exist in the repository's issue tracker)"`
+	Version   string `json:"version,omitempty" jsonschema:"Version name (must exist in the repository's issue tracker)"`
 }
 
 // CreateIssue creates a new issue.
@@ -148,6 +181,14 @@ func (c *Client) CreateIssue(args CreateIssueArgs) (*Issue, error) {
 		body["assignee"] = map[string]string{"account_id": args.Assignee}
 	}
 
+	if err := c.issueOptionFields(args.Workspace, args.RepoSlug, body, map[string]*string{
+		"milestone": nonEmpty(args.Milestone),
+		"component": nonEmpty(args.Component),
+		"version":   nonEmpty(args.Version),
+	}); err != nil {
+		return nil, err
+	}
+
 	respData, err := c.Post(fmt.Sprintf("/repositories/%s/%s/issues",
 		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug)), body)
 	if err != nil {
@@ -172,6 +213,9 @@ type UpdateIssueArgs struct {
 	Kind      *string `json:"kind,omitempty" jsonschema:"New kind"`
 	Priority  *string `json:"priority,omitempty" jsonschema:"New priority"`
 	Assignee  *string `json:"assignee,omitempty" jsonschema:"New assignee account ID (or empty string to unassign)"`
+	Milestone *string `json:"milestone,omitempty" jsonschema:"New milestone name (or empty string to clear)"`
+	Component *string `json:"component,omitempty" jsonschema:"New component name (or empty string to clear)"`
+	Version   *string `json:"version,omitempty" jsonschema:"New version name (or empty string to clear)"`
 }
 
 // UpdateIssue updates an existing issue.
@@ -203,6 +247,13 @@ func (c *Client) UpdateIssue(args UpdateIssueArgs) (*Issue, error) {
 			body["assignee"] = map[string]string{"account_id": *args.Assignee}
 		}
 	}
+	if err := c.issueOptionFields(args.Workspace, args.RepoSlug, body, map[string]*string{
+		"milestone": args.Milestone,
+		"component": args.Component,
+		"version":   args.Version,
+	}); err != nil {
+		return nil, err
+	}
 
 	respData, err := c.Put(fmt.Sprintf("/repositories/%s/%s/issues/%d",
 		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), args.IssueID), body)
@@ -217,3 +268,11 @@ func (c *Client) UpdateIssue(args UpdateIssueArgs) (*Issue, error) {
 
 	return &issue, nil
 }
+
+// nonEmpty returns a pointer to s, or nil if s is empty.
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,136 @@
+package bitbucket
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IssueOption is one of a repository's issue milestones, components, or
+// versions. The values are configured in the repository's issue tracker
+// settings; issues refer to them by name.
+type IssueOption struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Links Links  `json:"links,omitempty"`
+}
+
+// The issue tracker's option lists, named after their endpoints.
+const (
+	IssueMilestones = "milestones"
+	IssueComponents = "components"
+	IssueVersions   = "versions"
+)
+
+type ListIssueOptionsArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+	Pagelen   int    `json:"pagelen,omitempty" jsonschema:"Results per page (default 25)"`
+	Page      int    `json:"page,omitempty" jsonschema:"Page number"`
+}
+
+// ListMilestones lists the milestones issues can be assigned to.
+func (c *Client) ListMilestones(args ListIssueOptionsArgs) (*Paginated[IssueOption], error) {
+	return c.listIssueOptions(args, IssueMilestones)
+}
+
+// ListComponents lists the components issues can be assigned to.
+func (c *Client) ListComponents(args ListIssueOptionsArgs) (*Paginated[IssueOption], error) {
+	return c.listIssueOptions(args, IssueComponents)
+}
+
+// ListVersions lists the versions issues can be assigned to.
+func (c *Client) ListVersions(args ListIssueOptionsArgs) (*Paginated[IssueOption], error) {
+	return c.listIssueOptions(args, IssueVersions)
+}
+
+func (c *Client) listIssueOptions(args ListIssueOptionsArgs, list string) (*Paginated[IssueOption], error) {
+	if args.Workspace == "" || args.RepoSlug == "" {
+		return nil, fmt.Errorf("workspace and repo_slug are required")
+	}
+
+	pagelen := args.Pagelen
+	if pagelen == 0 {
+		pagelen = 25
+	}
+	page := args.Page
+	if page == 0 {
+		page = 1
+	}
+
+	return GetPaginated[IssueOption](c, fmt.Sprintf("/repositories/%s/%s/%s?pagelen=%d&page=%d",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), list, pagelen, page))
+}
+
+// maxIssueOptionPages bounds resolveIssueOption.
+const maxIssueOptionPages = 10
+
+// resolveIssueOption checks name against the repository's milestones,
+// components, or versions (list) and returns it as the tracker spells it,
+// so a value the tracker does not have fails before the issue is written.
+// Names match case-insensitively. Lists are cached per client; a name not
+// in the cached list fetches it again, so values added since are found.
+func (c *Client) resolveIssueOption(workspace, repoSlug, list, name string) (string, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/%s", QueryEscape(workspace), QueryEscape(repoSlug), list)
+
+	c.issueOptionsMu.Lock()
+	cached := c.issueOptions[path]
+	c.issueOptionsMu.Unlock()
+	if found, ok := matchIssueOption(cached, name); ok {
+		return found, nil
+	}
+
+	options, err := listAllPages[IssueOption](c, path, maxIssueOptionPages)
+	if err != nil {
+		return "", fmt.Errorf("failed to list %s: %v", list, err)
+	}
+	c.issueOptionsMu.Lock()
+	if c.issueOptions == nil {
+		c.issueOptions = map[string][]IssueOption{}
+	}
+	c.issueOptions[path] = options
+	c.issueOptionsMu.Unlock()
+
+	if found, ok := matchIssueOption(options, name); ok {
+		return found, nil
+	}
+	kind := strings.TrimSuffix(list, "s")
+	if len(options) == 0 {
+		return "", fmt.Errorf("cannot set %s %q: %s/%s has no %s", kind, name, workspace, repoSlug, list)
+	}
+	names := make([]string, 0, len(options))
+	for _, o := range options {
+		names = append(names, o.Name)
+	}
+	return "", fmt.Errorf("unknown %s %q: expected one of %s", kind, name, strings.Join(names, ", "))
+}
+
+func matchIssueOption(options []IssueOption, name string) (string, bool) {
+	for _, o := range options {
+		if strings.EqualFold(o.Name, name) {
+			return o.Name, true
+		}
+	}
+	return "", false
+}
+
+// issueOptionFields validates the milestone, component, and version being
+// set on an issue and adds them to body. A nil value is left unchanged and
+// an empty one clears the field.
+func (c *Client) issueOptionFields(workspace, repoSlug string, body map[string]interface{}, values map[string]*string) error {
+	for _, list := range []string{IssueMilestones, IssueComponents, IssueVersions} {
+		kind := strings.TrimSuffix(list, "s")
+		v := values[kind]
+		switch {
+		case v == nil:
+		case *v == "":
+			body[kind] = nil
+		default:
+			name, err := c.resolveIssueOption(workspace, repoSlug, list, *v)
+			if err != nil {
+				return err
+			}
+			body[kind] = map[string]string{"name": name}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// trackerServer serves milestones and components for w/r and records the
+// body of the last issue create or update.
+func trackerServer(t *testing.T, body *map[string]any) *Client {
+	return newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/milestones"):
+			_, _ = w.Write([]byte(`{"values": [{"id": 1, "name": "v1.0"}, {"id": 2, "name": "v2.0"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/components"):
+			_, _ = w.Write([]byte(`{"values": [{"id": 5, "name": "API"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/versions"):
+			_, _ = w.Write([]byte(`{"values": []}`))
+		default:
+			b, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(b, body)
+			_, _ = w.Write([]byte(`{"id": 17, "milestone": {"id": 2, "name": "v2.0"}}`))
+		}
+	})
+}
+
+func TestCreateIssue_ValidatesTrackerOptions(t *testing.T) {
+	var body map[string]any
+	c := trackerServer(t, &body)
+
+	issue, err := c.CreateIssue(CreateIssueArgs{Workspace: "w", RepoSlug: "r", Title: "Crash", Milestone: "V2.0", Component: "api"})
+	if err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	milestone, _ := body["milestone"].(map[string]any)
+	component, _ := body["component"].(map[string]any)
+	if milestone["name"] != "v2.0" || component["name"] != "API" {
+		t.Errorf("body = %v", body)
+	}
+	if _, ok := body["version"]; ok {
+		t.Errorf("unset version sent: %v", body)
+	}
+	if issue.Milestone == nil || issue.Milestone.Name != "v2.0" {
+		t.Errorf("issue = %+v", issue)
+	}
+
+	_, err = c.CreateIssue(CreateIssueArgs{Workspace: "w", RepoSlug: "r", Title: "Crash", Milestone: "v3.0"})
+	if err == nil || !strings.Contains(err.Error(), "v1.0, v2.0") {
+		t.Errorf("unknown milestone error = %v", err)
+	}
+	_, err = c.CreateIssue(CreateIssueArgs{Workspace: "w", RepoSlug: "r", Title: "Crash", Version: "1.0"})
+	if err == nil || !strings.Contains(err.Error(), "has no versions") {
+		t.Errorf("no versions error = %v", err)
+	}
+}
+
+func TestUpdateIssue_ClearsTrackerOption(t *testing.T) {
+	var body map[string]any
+	c := trackerServer(t, &body)
+
+	none := ""
+	if _, err := c.UpdateIssue(UpdateIssueArgs{Workspace: "w", RepoSlug: "r", IssueID: 17, Milestone: &none}); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	if v, ok := body["milestone"]; !ok || v != nil {
+		t.Errorf("body = %v, want milestone: null", body)
+	}
+}
+
+func TestListIssues_TrackerFilters(t *testing.T) {
+	var q string
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q = r.URL.Query().Get("q")
+		_, _ = w.Write([]byte(`{"values": []}`))
+	})
+
+	if _, err := c.ListIssues(ListIssuesArgs{Workspace: "w", RepoSlug: "r", State: "open", Milestone: "v2.0", Component: "api"}); err != nil {
+		t.Fatalf("ListIssues: %v", err)
+	}
+	if want := `state="open" AND milestone.name="v2.0" AND component.name="api"`; q != want {
+		t.Errorf("q = %s, want %s", q, want)
+	}
+}
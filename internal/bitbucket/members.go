@@ -0,0 +1,109 @@
+package bitbucket
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// WorkspaceMember is a user's membership of a workspace.
+type WorkspaceMember struct {
+	User User `json:"user"`
+}
+
+type ListMembersArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	Pagelen   int    `json:"pagelen,omitempty" jsonschema:"Results per page (default 25)"`
+	Page      int    `json:"page,omitempty" jsonschema:"Page number"`
+}
+
+// ListMembers lists the members of a workspace.
+func (c *Client) ListMembers(args ListMembersArgs) (*Paginated[WorkspaceMember], error) {
+	if args.Workspace == "" {
+		return nil, fmt.Errorf("workspace is required")
+	}
+
+	pagelen := args.Pagelen
+	if pagelen == 0 {
+		pagelen = 25
+	}
+	page := args.Page
+	if page == 0 {
+		page = 1
+	}
+
+	return GetPaginated[WorkspaceMember](c, fmt.Sprintf("/workspaces/%s/members?pagelen=%d&page=%d",
+		QueryEscape(args.Workspace), pagelen, page))
+}
+
+// maxMemberPages bounds FindMembers.
+const maxMemberPages = 50
+
+// FindMembers finds workspace members whose display name, nickname, account
+// ID, or UUID contains query, ignoring case and a leading "@". Exact matches
+// sort first. A query containing "@" past its first character is also
+// looked up as an email address, which Bitbucket only allows workspace
+// admins to do.
+func (c *Client) FindMembers(workspace, query string) ([]User, error) {
+	if workspace == "" || query == "" {
+		return nil, fmt.Errorf("workspace and query are required")
+	}
+	q := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(query), "@"))
+	if q == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+
+	base := fmt.Sprintf("/workspaces/%s/members", QueryEscape(workspace))
+	if strings.Contains(q, "@") {
+		members, err := listAllPages[WorkspaceMember](c, base+"?q="+QueryEscape(fmt.Sprintf("user.email=%q", q)), 1)
+		if err == nil && len(members) > 0 {
+			return memberUsers(members), nil
+		}
+	}
+
+	members, err := listAllPages[WorkspaceMember](c, base, maxMemberPages)
+	if err != nil {
+		return nil, err
+	}
+
+	type ranked struct {
+		user  User
+		exact bool
+	}
+	var found []ranked
+	for _, m := range members {
+		u := m.User
+		exact := false
+		match := false
+		for _, field := range []string{u.DisplayName, u.Nickname, u.AccountID, trimBraces(u.UUID)} {
+			f := strings.ToLower(field)
+			if f == "" {
+				continue
+			}
+			if f == q || f == trimBraces(q) {
+				exact = true
+			}
+			if strings.Contains(f, trimBraces(q)) {
+				match = true
+			}
+		}
+		if match {
+			found = append(found, ranked{u, exact})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].exact && !found[j].exact })
+
+	out := make([]User, 0, len(found))
+	for _, r := range found {
+		out = append(out, r.user)
+	}
+	return out, nil
+}
+
+func memberUsers(members []WorkspaceMember) []User {
+	out := make([]User, 0, len(members))
+	for _, m := range members {
+		out = append(out, m.User)
+	}
+	return out
+}
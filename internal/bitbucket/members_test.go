@@ -0,0 +1,43 @@
+package bitbucket
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestFindMembers(t *testing.T) {
+	var queries []string
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		if r.URL.Query().Get("q") != "" {
+			_, _ = w.Write([]byte(`{"values": []}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"values": [
+			{"user": {"display_name": "Alice Smith", "nickname": "asmith", "account_id": "557058:a"}},
+			{"user": {"display_name": "Bob", "nickname": "alice", "account_id": "557058:b"}},
+			{"user": {"display_name": "Carol", "nickname": "carol", "account_id": "557058:c"}}
+		]}`))
+	})
+
+	users, err := c.FindMembers("w", "@Alice")
+	if err != nil {
+		t.Fatalf("FindMembers: %v", err)
+	}
+	if len(users) != 2 || users[0].AccountID != "557058:b" || users[1].AccountID != "557058:a" {
+		t.Errorf("users = %+v, want the exact nickname match first", users)
+	}
+
+	queries = nil
+	users, err = c.FindMembers("w", "carol@example.com")
+	if err != nil {
+		t.Fatalf("FindMembers by email: %v", err)
+	}
+	if len(queries) != 2 || queries[0] != `user.email="carol@example.com"` || len(users) != 0 {
+		t.Errorf("queries = %q, users = %+v", queries, users)
+	}
+
+	if _, err := c.FindMembers("w", "@"); err == nil {
+		t.Error("FindMembers with an empty query succeeded")
+	}
+}
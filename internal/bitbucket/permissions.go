@@ -0,0 +1,183 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Permission subjects.
+const (
+	PermissionUser  = "user"
+	PermissionGroup = "group"
+)
+
+// GroupRef is the group a permission is granted to.
+type GroupRef struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// Permission is an explicit permission granted to a user or group on a
+// repository or project.
+type Permission struct {
+	Permission string    `json:"permission"`
+	User       *User     `json:"user,omitempty"`
+	Group      *GroupRef `json:"group,omitempty"`
+}
+
+// Subject returns "user" or "group" and a display name for the grantee.
+func (p Permission) Subject() (kind, name string) {
+	if p.Group != nil {
+		return PermissionGroup, p.Group.Slug
+	}
+	if p.User != nil {
+		name = p.User.DisplayName
+		if name == "" {
+			name = p.User.AccountID
+		}
+		return PermissionUser, name
+	}
+	return "", ""
+}
+
+// maxPermissionPages bounds the permission list-all loops.
+const maxPermissionPages = 10
+
+// listPermissionConfigs lists the explicit user permissions followed by the
+// group permissions under a repository or project path.
+func (c *Client) listPermissionConfigs(base string) ([]Permission, error) {
+	users, err := listAllPages[Permission](c, base+"/permissions-config/users", maxPermissionPages)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := listAllPages[Permission](c, base+"/permissions-config/groups", maxPermissionPages)
+	if err != nil {
+		return nil, err
+	}
+	return append(users, groups...), nil
+}
+
+// permissionConfigPath returns the permissions-config path under base for a
+// user (UUID or account ID) or group slug.
+func permissionConfigPath(base, kind, subject string) (string, error) {
+	if subject == "" {
+		return "", fmt.Errorf("a user or group is required")
+	}
+	switch kind {
+	case PermissionUser:
+		return base + "/permissions-config/users/" + QueryEscape(normalizeReviewer(subject)), nil
+	case PermissionGroup:
+		return base + "/permissions-config/groups/" + QueryEscape(subject), nil
+	}
+	return "", fmt.Errorf("invalid permission subject %q: expected user or group", kind)
+}
+
+func (c *Client) setPermissionConfig(base, kind, subject, permission string, valid []string) (*Permission, error) {
+	path, err := permissionConfigPath(base, kind, subject)
+	if err != nil {
+		return nil, err
+	}
+	if !containsString(valid, permission) {
+		return nil, fmt.Errorf("invalid permission %q: expected one of %s", permission, strings.Join(valid, ", "))
+	}
+	data, err := c.Put(path, map[string]string{"permission": permission})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set permission: %v", err)
+	}
+	var out Permission
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &out, nil
+}
+
+func (c *Client) removePermissionConfig(base, kind, subject string) error {
+	path, err := permissionConfigPath(base, kind, subject)
+	if err != nil {
+		return err
+	}
+	return c.Delete(path)
+}
+
+// RepositoryPermissions are the levels a user or group can be granted on a
+// repository.
+var RepositoryPermissions = []string{"read", "write", "admin"}
+
+type RepositoryPermissionArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+}
+
+func (a RepositoryPermissionArgs) path() (string, error) {
+	if a.Workspace == "" || a.RepoSlug == "" {
+		return "", fmt.Errorf("workspace and repo_slug are required")
+	}
+	return fmt.Sprintf("/repositories/%s/%s", QueryEscape(a.Workspace), QueryEscape(a.RepoSlug)), nil
+}
+
+// ListRepositoryPermissions lists the explicit user and group permissions on
+// a repository. Requires admin access to the repository.
+func (c *Client) ListRepositoryPermissions(args RepositoryPermissionArgs) ([]Permission, error) {
+	path, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	return c.listPermissionConfigs(path)
+}
+
+// SetRepositoryPermission grants a user or group a permission on a
+// repository, replacing any explicit permission it already has.
+func (c *Client) SetRepositoryPermission(args RepositoryPermissionArgs, kind, subject, permission string) (*Permission, error) {
+	path, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	return c.setPermissionConfig(path, kind, subject, permission, RepositoryPermissions)
+}
+
+// RemoveRepositoryPermission removes a user's or group's explicit
+// permission on a repository.
+func (c *Client) RemoveRepositoryPermission(args RepositoryPermissionArgs, kind, subject string) error {
+	path, err := args.path()
+	if err != nil {
+		return err
+	}
+	return c.removePermissionConfig(path, kind, subject)
+}
+
+// WorkspacePermission is a member's role in a workspace: owner, collaborator,
+// or member.
+type WorkspacePermission struct {
+	Permission string `json:"permission"`
+	User       User   `json:"user"`
+}
+
+type ListWorkspacePermissionsArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	Query     string `json:"query,omitempty" jsonschema:"Bitbucket query filter (e.g. permission=\"owner\")"`
+	Pagelen   int    `json:"pagelen,omitempty" jsonschema:"Results per page (default 25)"`
+	Page      int    `json:"page,omitempty" jsonschema:"Page number"`
+}
+
+// ListWorkspacePermissions lists each member's permission in a workspace.
+func (c *Client) ListWorkspacePermissions(args ListWorkspacePermissionsArgs) (*Paginated[WorkspacePermission], error) {
+	if args.Workspace == "" {
+		return nil, fmt.Errorf("workspace is required")
+	}
+
+	pagelen := args.Pagelen
+	if pagelen == 0 {
+		pagelen = 25
+	}
+	page := args.Page
+	if page == 0 {
+		page = 1
+	}
+
+	path := fmt.Sprintf("/workspaces/%s/permissions?pagelen=%d&page=%d", QueryEscape(args.Workspace), pagelen, page)
+	if args.Query != "" {
+		path += "&q=" + QueryEscape(args.Query)
+	}
+	return GetPaginated[WorkspacePermission](c, path)
+}
@@ -0,0 +1,75 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRepositoryPermissions(t *testing.T) {
+	var calls []string
+	var body map[string]any
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodPut:
+			b, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(b, &body)
+			_, _ = w.Write([]byte(`{"permission": "admin", "user": {"account_id": "557058:a"}}`))
+		case strings.HasSuffix(r.URL.Path, "/users"):
+			_, _ = w.Write([]byte(`{"values": [{"permission": "write", "user": {"display_name": "Alice"}}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"values": []}`))
+		}
+	})
+	args := RepositoryPermissionArgs{Workspace: "w", RepoSlug: "api"}
+
+	perms, err := c.ListRepositoryPermissions(args)
+	if err != nil {
+		t.Fatalf("ListRepositoryPermissions: %v", err)
+	}
+	if len(perms) != 1 || perms[0].Permission != "write" {
+		t.Errorf("perms = %+v", perms)
+	}
+	if strings.Join(calls, "|") != "GET /repositories/w/api/permissions-config/users|GET /repositories/w/api/permissions-config/groups" {
+		t.Errorf("calls = %v", calls)
+	}
+
+	calls = nil
+	p, err := c.SetRepositoryPermission(args, PermissionUser, "557058:a", "admin")
+	if err != nil {
+		t.Fatalf("SetRepositoryPermission: %v", err)
+	}
+	if calls[0] != "PUT /repositories/w/api/permissions-config/users/557058:a" || body["permission"] != "admin" {
+		t.Errorf("request = %v %v", calls, body)
+	}
+	if kind, name := p.Subject(); kind != PermissionUser || name != "557058:a" {
+		t.Errorf("subject = %s %s", kind, name)
+	}
+
+	// create-repo is a project permission only.
+	if _, err := c.SetRepositoryPermission(args, PermissionGroup, "devs", "create-repo"); err == nil {
+		t.Error("create-repo accepted on a repository")
+	}
+}
+
+func TestListWorkspacePermissions(t *testing.T) {
+	var path, query string
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path, query = r.URL.Path, r.URL.Query().Get("q")
+		_, _ = w.Write([]byte(`{"values": [{"permission": "owner", "user": {"display_name": "Alice"}}]}`))
+	})
+
+	result, err := c.ListWorkspacePermissions(ListWorkspacePermissionsArgs{Workspace: "w", Query: `permission="owner"`})
+	if err != nil {
+		t.Fatalf("ListWorkspacePermissions: %v", err)
+	}
+	if path != "/workspaces/w/permissions" || query != `permission="owner"` {
+		t.Errorf("request = %s %q", path, query)
+	}
+	if len(result.Values) != 1 || result.Values[0].User.DisplayName != "Alice" {
+		t.Errorf("result = %+v", result.Values)
+	}
+}
@@ -0,0 +1,97 @@
+package bitbucket
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PipelineArtifact is a file a step declared under `artifacts:`.
+type PipelineArtifact struct {
+	UUID          string     `json:"uuid"`
+	Name          string     `json:"name,omitempty"`
+	Path          string     `json:"path,omitempty"`
+	FileSizeBytes int64      `json:"file_size_bytes"`
+	CreatedOn     *time.Time `json:"created_on,omitempty"`
+	StepUUID      string     `json:"step_uuid"`
+	StepName      string     `json:"step_name,omitempty"`
+}
+
+// DisplayName is the artifact's path within the build, falling back to its name.
+func (a PipelineArtifact) DisplayName() string {
+	if a.Path != "" {
+		return a.Path
+	}
+	return a.Name
+}
+
+type ListPipelineArtifactsArgs struct {
+	Workspace    string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug     string `json:"repo_slug" jsonschema:"Repository slug"`
+	PipelineUUID string `json:"pipeline_uuid" jsonschema:"Pipeline UUID"`
+	Step         string `json:"step,omitempty" jsonschema:"Limit to one step (UUID or name)"`
+}
+
+// ListPipelineArtifacts lists the artifacts produced by each step of a
+// pipeline run. Steps that produced none (the endpoint 404s for them) are
+// skipped.
+func (c *Client) ListPipelineArtifacts(args ListPipelineArtifactsArgs) ([]PipelineArtifact, error) {
+	if args.Workspace == "" || args.RepoSlug == "" || args.PipelineUUID == "" {
+		return nil, fmt.Errorf("workspace, repo_slug, and pipeline_uuid are required")
+	}
+
+	steps, err := c.ListPipelineSteps(ListPipelineStepsArgs{
+		Workspace:    args.Workspace,
+		RepoSlug:     args.RepoSlug,
+		PipelineUUID: args.PipelineUUID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list steps: %v", err)
+	}
+
+	artifacts := []PipelineArtifact{}
+	matched := false
+	for _, step := range steps.Values {
+		if args.Step != "" && step.UUID != args.Step && !strings.EqualFold(step.Name, args.Step) {
+			continue
+		}
+		matched = true
+		page, err := GetPaginated[PipelineArtifact](c, fmt.Sprintf("/repositories/%s/%s/pipelines/%s/steps/%s/artifacts?pagelen=100",
+			QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), args.PipelineUUID, step.UUID))
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list artifacts for step %s: %v", step.Name, err)
+		}
+		for _, a := range page.Values {
+			a.StepUUID = step.UUID
+			a.StepName = step.Name
+			artifacts = append(artifacts, a)
+		}
+	}
+	if args.Step != "" && !matched {
+		return nil, fmt.Errorf("step %q not found in pipeline %s", args.Step, args.PipelineUUID)
+	}
+	return artifacts, nil
+}
+
+type DownloadPipelineArtifactArgs struct {
+	Workspace    string
+	RepoSlug     string
+	PipelineUUID string
+	StepUUID     string
+	ArtifactUUID string
+	Dest         string
+}
+
+// DownloadPipelineArtifact saves one artifact to Dest, resuming a partial
+// download when possible.
+func (c *Client) DownloadPipelineArtifact(args DownloadPipelineArtifactArgs, progress func(done, total int64)) (*DownloadResult, error) {
+	if args.Workspace == "" || args.RepoSlug == "" || args.PipelineUUID == "" || args.StepUUID == "" || args.ArtifactUUID == "" || args.Dest == "" {
+		return nil, fmt.Errorf("workspace, repo_slug, pipeline_uuid, step_uuid, artifact_uuid, and destination are required")
+	}
+
+	return c.DownloadToFile(fmt.Sprintf("/repositories/%s/%s/pipelines/%s/steps/%s/artifacts/%s/content",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), args.PipelineUUID, args.StepUUID, args.ArtifactUUID), args.Dest, progress)
+}
@@ -0,0 +1,91 @@
+package bitbucket
+
+import (
+	"fmt"
+	"time"
+)
+
+// PipelineCache is one saved version of a step cache. A named cache may have
+// several versions when its key files changed between runs.
+type PipelineCache struct {
+	UUID          string     `json:"uuid"`
+	Name          string     `json:"name"`
+	Path          string     `json:"path"`
+	KeyHash       string     `json:"key_hash,omitempty"`
+	FileSizeBytes int64      `json:"file_size_bytes"`
+	CreatedOn     *time.Time `json:"created_on"`
+	PipelineUUID  string     `json:"pipeline_uuid,omitempty"`
+	StepUUID      string     `json:"step_uuid,omitempty"`
+}
+
+type ListPipelineCachesArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+}
+
+// maxCachePages bounds the cache listing; repositories rarely have more than
+// a handful of caches, so this is only a guard against a runaway cursor.
+const maxCachePages = 10
+
+// ListPipelineCaches lists every cache saved for the repository's pipelines.
+func (c *Client) ListPipelineCaches(args ListPipelineCachesArgs) ([]PipelineCache, error) {
+	if args.Workspace == "" || args.RepoSlug == "" {
+		return nil, fmt.Errorf("workspace and repo_slug are required")
+	}
+
+	caches := []PipelineCache{}
+	for page := 1; page <= maxCachePages; page++ {
+		result, err := GetPaginated[PipelineCache](c, fmt.Sprintf("/repositories/%s/%s/pipelines-config/caches?pagelen=100&page=%d",
+			QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), page))
+		if err != nil {
+			return nil, err
+		}
+		caches = append(caches, result.Values...)
+		if result.Next == "" {
+			break
+		}
+	}
+	return caches, nil
+}
+
+type DeletePipelineCacheArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+	Name      string `json:"name" jsonschema:"Cache name, e.g. node or a custom cache from definitions.caches"`
+}
+
+// DeletePipelineCache deletes every version of the named cache, so the next
+// run that uses it starts cold.
+func (c *Client) DeletePipelineCache(args DeletePipelineCacheArgs) error {
+	if args.Workspace == "" || args.RepoSlug == "" || args.Name == "" {
+		return fmt.Errorf("workspace, repo_slug, and name are required")
+	}
+
+	return c.Delete(fmt.Sprintf("/repositories/%s/%s/pipelines-config/caches?name=%s",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), QueryEscape(args.Name)))
+}
+
+// ClearPipelineCaches deletes all of the repository's caches and returns the
+// names that were cleared.
+func (c *Client) ClearPipelineCaches(args ListPipelineCachesArgs) ([]string, error) {
+	caches, err := c.ListPipelineCaches(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, cache := range caches {
+		names = append(names, cache.Name)
+	}
+	names = dedupeStrings(names)
+	for _, name := range names {
+		if err := c.DeletePipelineCache(DeletePipelineCacheArgs{
+			Workspace: args.Workspace,
+			RepoSlug:  args.RepoSlug,
+			Name:      name,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to delete cache %q: %v", name, err)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,30 @@
+package bitbucket
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClearPipelineCaches_DeletesEachNameOnce(t *testing.T) {
+	var deleted []string
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted = append(deleted, r.URL.Query().Get("name"))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = w.Write([]byte(`{"values":[
+			{"uuid":"{1}","name":"node","file_size_bytes":100},
+			{"uuid":"{2}","name":"node","key_hash":"abc","file_size_bytes":120},
+			{"uuid":"{3}","name":"gradle","file_size_bytes":50}]}`))
+	})
+
+	names, err := c.ClearPipelineCaches(ListPipelineCachesArgs{Workspace: "w", RepoSlug: "r"})
+	if err != nil {
+		t.Fatalf("ClearPipelineCaches: %v", err)
+	}
+	if strings.Join(names, ",") != "node,gradle" || strings.Join(deleted, ",") != "node,gradle" {
+		t.Errorf("cleared %v, DELETE names %v; want node,gradle once each", names, deleted)
+	}
+}
@@ -0,0 +1,159 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PipelinesConfig is a repository's Pipelines settings.
+type PipelinesConfig struct {
+	Enabled    bool        `json:"enabled"`
+	Repository *Repository `json:"repository,omitempty"`
+}
+
+type PipelinesConfigArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+}
+
+func (a PipelinesConfigArgs) path(suffix string) (string, error) {
+	if a.Workspace == "" || a.RepoSlug == "" {
+		return "", fmt.Errorf("workspace and repo_slug are required")
+	}
+	return fmt.Sprintf("/repositories/%s/%s/pipelines_config%s",
+		QueryEscape(a.Workspace), QueryEscape(a.RepoSlug), suffix), nil
+}
+
+// GetPipelinesConfig gets whether Pipelines is enabled for a repository.
+func (c *Client) GetPipelinesConfig(args PipelinesConfigArgs) (*PipelinesConfig, error) {
+	path, err := args.path("")
+	if err != nil {
+		return nil, err
+	}
+	return GetJSON[PipelinesConfig](c, path)
+}
+
+// SetPipelinesEnabled turns Pipelines on or off for a repository.
+func (c *Client) SetPipelinesEnabled(args PipelinesConfigArgs, enabled bool) (*PipelinesConfig, error) {
+	path, err := args.path("")
+	if err != nil {
+		return nil, err
+	}
+
+	respData, err := c.Put(path, map[string]any{"enabled": enabled})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update pipelines config: %v", err)
+	}
+
+	var cfg PipelinesConfig
+	if err := json.Unmarshal(respData, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &cfg, nil
+}
+
+// SetPipelinesBuildNumber sets the number the next pipeline run will get.
+// Bitbucket only accepts values above the current build number.
+func (c *Client) SetPipelinesBuildNumber(args PipelinesConfigArgs, next int) (int, error) {
+	path, err := args.path("/build_number")
+	if err != nil {
+		return 0, err
+	}
+	if next < 1 {
+		return 0, fmt.Errorf("build number must be positive")
+	}
+
+	respData, err := c.Put(path, map[string]any{"next": next})
+	if err != nil {
+		return 0, fmt.Errorf("failed to set build number: %v", err)
+	}
+
+	var out struct {
+		Next int `json:"next"`
+	}
+	if err := json.Unmarshal(respData, &out); err != nil {
+		return 0, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return out.Next, nil
+}
+
+// pipelinesTemplates are starter bitbucket-pipelines.yml files offered when
+// creating a repository. Each must lint clean.
+var pipelinesTemplates = map[string]string{
+	"default": `image: atlassian/default-image:4
+
+pipelines:
+  default:
+    - step:
+        name: Build
+        script:
+          - echo "Add your build commands here"
+`,
+	"node": `image: node:22
+
+pipelines:
+  default:
+    - step:
+        name: Build and test
+        caches:
+          - node
+        script:
+          - npm ci
+          - npm test
+`,
+	"go": `image: golang:1.26
+
+pipelines:
+  default:
+    - step:
+        name: Build and test
+        script:
+          - go build ./...
+          - go vet ./...
+          - go test ./...
+`,
+	"python": `image: python:3.13
+
+pipelines:
+  default:
+    - step:
+        name: Test
+        caches:
+          - pip
+        script:
+          - pip install -r requirements.txt
+          - python -m pytest
+`,
+	"docker": `image: atlassian/default-image:4
+
+pipelines:
+  default:
+    - step:
+        name: Build image
+        services:
+          - docker
+        script:
+          - docker build -t "$BITBUCKET_REPO_SLUG:$BITBUCKET_COMMIT" .
+`,
+}
+
+// PipelinesTemplateNames lists the available starter templates.
+func PipelinesTemplateNames() []string {
+	names := make([]string, 0, len(pipelinesTemplates))
+	for name := range pipelinesTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PipelinesTemplate returns the named starter bitbucket-pipelines.yml.
+func PipelinesTemplate(name string) (string, error) {
+	tmpl, ok := pipelinesTemplates[strings.ToLower(name)]
+	if !ok {
+		return "", fmt.Errorf("unknown pipelines template %q (available: %s)", name, strings.Join(PipelinesTemplateNames(), ", "))
+	}
+	return tmpl, nil
+}
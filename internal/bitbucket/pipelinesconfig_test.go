@@ -0,0 +1,93 @@
+package bitbucket
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPipelinesTemplates_LintClean(t *testing.T) {
+	for _, name := range PipelinesTemplateNames() {
+		tmpl, err := PipelinesTemplate(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res := LintPipelinesYAML([]byte(tmpl)); res.Errors > 0 || res.Warnings > 0 {
+			t.Errorf("template %q: %s: %v", name, res.Summary(), res.Diagnostics)
+		}
+	}
+	if _, err := PipelinesTemplate("cobol"); err == nil || !strings.Contains(err.Error(), "node") {
+		t.Errorf("unknown template error should list the available ones, got %v", err)
+	}
+}
+
+func TestCreateRepository_EnablesPipelinesWithTemplate(t *testing.T) {
+	var got []string
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/repositories/w/r/pipelines_config":
+			b, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(b), `"enabled":true`) {
+				t.Errorf("pipelines_config body = %s", b)
+			}
+			_, _ = w.Write([]byte(`{"enabled":true}`))
+		case "/repositories/w/r/src":
+			b, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(b), PipelinesConfigPath) || !strings.Contains(string(b), "npm ci") {
+				t.Errorf("commit body does not carry the node template:\n%s", b)
+			}
+			w.WriteHeader(http.StatusCreated)
+		default:
+			_, _ = w.Write([]byte(`{"slug":"r","full_name":"w/r"}`))
+		}
+	})
+
+	repo, err := c.CreateRepository(CreateRepositoryArgs{
+		Workspace:         "w",
+		RepoSlug:          "r",
+		EnablePipelines:   true,
+		PipelinesTemplate: "node",
+	})
+	if err != nil {
+		t.Fatalf("CreateRepository: %v", err)
+	}
+	if repo.FullName != "w/r" {
+		t.Errorf("repo = %+v", repo)
+	}
+	want := "POST /repositories/w/r\nPOST /repositories/w/r/src\nPUT /repositories/w/r/pipelines_config"
+	if strings.Join(got, "\n") != want {
+		t.Errorf("requests:\n%s\nwant:\n%s", strings.Join(got, "\n"), want)
+	}
+}
+
+func TestCreateRepository_UnknownTemplateFailsBeforeCreating(t *testing.T) {
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+	if _, err := c.CreateRepository(CreateRepositoryArgs{Workspace: "w", RepoSlug: "r", PipelinesTemplate: "nope"}); err == nil {
+		t.Error("expected error for unknown template")
+	}
+}
+
+func TestCreateRepository_ReportsFailedSetupStep(t *testing.T) {
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repositories/w/r/pipelines_config" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"slug":"r","full_name":"w/r"}`))
+	})
+
+	repo, err := c.CreateRepository(CreateRepositoryArgs{Workspace: "w", RepoSlug: "r", EnablePipelines: true, PipelinesTemplate: "go"})
+	var setupErr *RepositorySetupError
+	if !errors.As(err, &setupErr) || setupErr.Step != SetupEnablePipelines {
+		t.Fatalf("err = %v, want setup error for %s", err, SetupEnablePipelines)
+	}
+	if repo == nil || repo.FullName != "w/r" {
+		t.Errorf("repo = %+v", repo)
+	}
+}
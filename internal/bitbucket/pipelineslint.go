@@ -0,0 +1,739 @@
+package bitbucket
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	yaml "go.yaml.in/yaml/v4"
+)
+
+// PipelinesConfigPath is where Bitbucket looks for the pipelines config in a
+// repository.
+const PipelinesConfigPath = "bitbucket-pipelines.yml"
+
+// Lint severities.
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+//go:embed pipelines_schema.yaml
+var pipelinesSchemaYAML []byte
+
+// LintDiagnostic is a single finding in a bitbucket-pipelines.yml file.
+// Line and Column are 1-based and point at the offending YAML node; Path is a
+// dotted locator (e.g. pipelines.branches.main[0].step.caches[1]) for callers
+// that can't show source positions.
+type LintDiagnostic struct {
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"`
+	Path     string `json:"path,omitempty"`
+	Message  string `json:"message"`
+}
+
+// String renders the diagnostic in the compiler-style "line:col: severity: msg"
+// form, without a file name prefix.
+func (d LintDiagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s", d.Line, d.Column, d.Severity, d.Message)
+}
+
+// LintResult is the outcome of linting a pipelines config.
+type LintResult struct {
+	Valid       bool             `json:"valid"`
+	Errors      int              `json:"errors"`
+	Warnings    int              `json:"warnings"`
+	Diagnostics []LintDiagnostic `json:"diagnostics"`
+}
+
+// Summary is a one-line human description of the result.
+func (r *LintResult) Summary() string {
+	if r.Errors == 0 && r.Warnings == 0 {
+		return "no problems found"
+	}
+	return fmt.Sprintf("%d error(s), %d warning(s)", r.Errors, r.Warnings)
+}
+
+// lintSchema mirrors pipelines_schema.yaml; see the header of that file for
+// the meaning of each field.
+type lintSchema struct {
+	Root  string                     `yaml:"root"`
+	Types map[string]*lintSchemaType `yaml:"types"`
+}
+
+type lintSchemaType struct {
+	Kind       string            `yaml:"kind"`
+	Fields     map[string]string `yaml:"fields"`
+	Values     string            `yaml:"values"`
+	Items      string            `yaml:"items"`
+	Variants   []string          `yaml:"variants"`
+	Required   []string          `yaml:"required"`
+	ExactlyOne []string          `yaml:"exactly_one"`
+	Enum       []string          `yaml:"enum"`
+	Min        *int              `yaml:"min"`
+	Max        *int              `yaml:"max"`
+	Open       bool              `yaml:"open"`
+}
+
+var (
+	lintSchemaOnce   sync.Once
+	lintSchemaParsed *lintSchema
+	lintSchemaErr    error
+)
+
+// loadLintSchema parses the embedded schema once per process.
+func loadLintSchema() (*lintSchema, error) {
+	lintSchemaOnce.Do(func() {
+		var s lintSchema
+		if err := yaml.Unmarshal(pipelinesSchemaYAML, &s); err != nil {
+			lintSchemaErr = fmt.Errorf("parsing embedded pipelines schema: %w", err)
+			return
+		}
+		lintSchemaParsed = &s
+	})
+	return lintSchemaParsed, lintSchemaErr
+}
+
+// Bitbucket ships these caches and services without a definitions entry.
+var (
+	predefinedCaches = map[string]bool{
+		"composer": true, "dotnetcore": true, "docker": true, "gradle": true, "ivy2": true,
+		"maven": true, "node": true, "pip": true, "sbt": true,
+	}
+	predefinedServices = map[string]bool{"docker": true}
+)
+
+// maxServicesPerStep is Bitbucket's cap on services attached to one step.
+const maxServicesPerStep = 5
+
+// customNameRegex is deliberately conservative: Bitbucket accepts more, but
+// names outside this set are awkward to pass to `pipelines trigger --pattern`
+// and to the API selector.
+var customNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.\-]*$`)
+
+// pipelineSections lists the keys under `pipelines:` in the order Bitbucket
+// documents them. default holds a pipeline directly; the rest map a name or
+// glob to a pipeline.
+var pipelineSections = []string{"default", "branches", "tags", "bookmarks", "pull-requests", "custom"}
+
+// LintPipelinesYAML validates the contents of a bitbucket-pipelines.yml file
+// without touching the network. It checks YAML syntax, the document structure
+// against the embedded schema (known keys, types, step/parallel/stage nesting,
+// size and max-time limits) and cross-references the schema can't express:
+// cache and service names against definitions, manual triggers on first
+// steps, parallel group sizes and custom pipeline names.
+func LintPipelinesYAML(data []byte) *LintResult {
+	l := &pipelinesLinter{}
+
+	schema, err := loadLintSchema()
+	if err != nil {
+		l.report(nil, LintError, "", "%v", err)
+		return l.result()
+	}
+	l.schema = schema
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		line, col := 1, 1
+		var perr *yaml.ParserError
+		if errors.As(err, &perr) && perr.Line > 0 {
+			line, col = perr.Line, max(perr.Column, 1)
+		}
+		l.diags = append(l.diags, LintDiagnostic{
+			Line: line, Column: col, Severity: LintError,
+			Message: strings.TrimPrefix(err.Error(), "yaml: "),
+		})
+		return l.result()
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		l.report(nil, LintError, "", "file is empty")
+		return l.result()
+	}
+
+	root := resolveYAMLAlias(doc.Content[0])
+	l.check(root, schema.Root, "")
+	if root.Kind == yaml.MappingNode {
+		l.checkReferences(root)
+	}
+	return l.result()
+}
+
+type pipelinesLinter struct {
+	schema *lintSchema
+	diags  []LintDiagnostic
+}
+
+func (l *pipelinesLinter) report(n *yaml.Node, severity, path, format string, args ...any) {
+	d := LintDiagnostic{Line: 1, Column: 1, Severity: severity, Path: path, Message: fmt.Sprintf(format, args...)}
+	if n != nil && n.Line > 0 {
+		d.Line, d.Column = n.Line, n.Column
+	}
+	l.diags = append(l.diags, d)
+}
+
+func (l *pipelinesLinter) result() *LintResult {
+	sort.SliceStable(l.diags, func(i, j int) bool {
+		if l.diags[i].Line != l.diags[j].Line {
+			return l.diags[i].Line < l.diags[j].Line
+		}
+		return l.diags[i].Column < l.diags[j].Column
+	})
+	r := &LintResult{Diagnostics: l.diags}
+	if r.Diagnostics == nil {
+		r.Diagnostics = []LintDiagnostic{}
+	}
+	for _, d := range r.Diagnostics {
+		if d.Severity == LintError {
+			r.Errors++
+		} else {
+			r.Warnings++
+		}
+	}
+	r.Valid = r.Errors == 0
+	return r
+}
+
+// check validates n against the named schema type, recursing into children.
+func (l *pipelinesLinter) check(n *yaml.Node, typeName, path string) {
+	n = resolveYAMLAlias(n)
+	label := path
+	if label == "" {
+		label = "document"
+	}
+
+	switch typeName {
+	case "any":
+		return
+	case "string":
+		if n.Kind != yaml.ScalarNode || n.Tag == "!!null" {
+			l.report(n, LintError, path, "%s must be a string", label)
+		}
+		return
+	case "int":
+		if n.Kind != yaml.ScalarNode || n.Tag != "!!int" {
+			l.report(n, LintError, path, "%s must be an integer", label)
+		}
+		return
+	case "bool":
+		if n.Kind != yaml.ScalarNode || n.Tag != "!!bool" {
+			l.report(n, LintError, path, "%s must be true or false", label)
+		}
+		return
+	}
+
+	t, ok := l.schema.Types[typeName]
+	if !ok {
+		l.report(n, LintError, path, "internal: schema type %q is not defined", typeName)
+		return
+	}
+
+	switch t.Kind {
+	case "union":
+		l.checkUnion(n, t, path, label)
+	case "map":
+		l.checkMap(n, t, path, label)
+	case "seq":
+		if n.Kind != yaml.SequenceNode {
+			l.report(n, LintError, path, "%s must be a list", label)
+			return
+		}
+		for i, item := range n.Content {
+			l.check(item, t.Items, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case "string":
+		if n.Kind != yaml.ScalarNode || n.Tag == "!!null" {
+			l.report(n, LintError, path, "%s must be a string", label)
+			return
+		}
+		if len(t.Enum) > 0 && !containsString(t.Enum, n.Value) {
+			l.report(n, LintError, path, "%s: %q is not one of %s", label, n.Value, strings.Join(t.Enum, ", "))
+		}
+	case "int":
+		if n.Kind != yaml.ScalarNode || n.Tag != "!!int" {
+			l.report(n, LintError, path, "%s must be an integer", label)
+			return
+		}
+		v, err := strconv.Atoi(n.Value)
+		if err != nil {
+			l.report(n, LintError, path, "%s: %q is not a valid integer", label, n.Value)
+			return
+		}
+		if t.Min != nil && v < *t.Min || t.Max != nil && v > *t.Max {
+			l.report(n, LintError, path, "%s: %d is out of range (%d-%d)", label, v, derefInt(t.Min), derefInt(t.Max))
+		}
+	default:
+		l.report(n, LintError, path, "internal: schema type %q has unknown kind %q", typeName, t.Kind)
+	}
+}
+
+// checkUnion picks the first variant whose shape matches n cleanly. When
+// every shape-compatible variant reports problems, the first one's findings
+// are kept since it is the most common spelling.
+func (l *pipelinesLinter) checkUnion(n *yaml.Node, t *lintSchemaType, path, label string) {
+	var first []LintDiagnostic
+	matched := false
+	for _, v := range t.Variants {
+		if !l.kindMatches(n, v) {
+			continue
+		}
+		sub := &pipelinesLinter{schema: l.schema}
+		sub.check(n, v, path)
+		if len(sub.diags) == 0 {
+			return
+		}
+		if !matched {
+			first = sub.diags
+			matched = true
+		}
+	}
+	if !matched {
+		shapes := make([]string, 0, len(t.Variants))
+		for _, v := range t.Variants {
+			shapes = append(shapes, l.shapeName(v))
+		}
+		l.report(n, LintError, path, "%s must be %s", label, strings.Join(dedupeStrings(shapes), " or "))
+		return
+	}
+	l.diags = append(l.diags, first...)
+}
+
+func (l *pipelinesLinter) checkMap(n *yaml.Node, t *lintSchemaType, path, label string) {
+	if n.Kind != yaml.MappingNode {
+		l.report(n, LintError, path, "%s must be a map", label)
+		return
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k := n.Content[i]
+		if k.Value == "<<" {
+			continue
+		}
+		if seen[k.Value] {
+			l.report(k, LintError, joinYAMLPath(path, k.Value), "duplicate key %q", k.Value)
+		}
+		seen[k.Value] = true
+	}
+
+	present := map[string]bool{}
+	for _, p := range yamlMappingPairs(n) {
+		key := p.Key.Value
+		present[key] = true
+		childPath := joinYAMLPath(path, key)
+		switch {
+		case t.Fields[key] != "":
+			l.check(p.Value, t.Fields[key], childPath)
+		case t.Values != "":
+			l.check(p.Value, t.Values, childPath)
+		default:
+			severity := LintError
+			if t.Open {
+				severity = LintWarning
+			}
+			msg := fmt.Sprintf("unknown key %q in %s", key, label)
+			if s := suggestKey(key, t.Fields); s != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", s)
+			}
+			l.report(p.Key, severity, childPath, "%s", msg)
+		}
+	}
+
+	for _, r := range t.Required {
+		if !present[r] {
+			l.report(n, LintError, path, "%s is missing required key %q", label, r)
+		}
+	}
+	if len(t.ExactlyOne) > 0 {
+		var got []string
+		for _, k := range t.ExactlyOne {
+			if present[k] {
+				got = append(got, k)
+			}
+		}
+		switch len(got) {
+		case 0:
+			l.report(n, LintError, path, "%s must contain one of: %s", label, strings.Join(t.ExactlyOne, ", "))
+		case 1:
+		default:
+			l.report(n, LintError, path, "%s must contain only one of %s (found %s)", label,
+				strings.Join(t.ExactlyOne, ", "), strings.Join(got, ", "))
+		}
+	}
+}
+
+// kindMatches reports whether a union variant could plausibly describe n,
+// judged by YAML node kind alone.
+func (l *pipelinesLinter) kindMatches(n *yaml.Node, typeName string) bool {
+	kind := typeName
+	if t, ok := l.schema.Types[typeName]; ok {
+		kind = t.Kind
+	}
+	switch kind {
+	case "map":
+		return n.Kind == yaml.MappingNode
+	case "seq":
+		return n.Kind == yaml.SequenceNode
+	case "union":
+		for _, v := range l.schema.Types[typeName].Variants {
+			if l.kindMatches(n, v) {
+				return true
+			}
+		}
+		return false
+	case "any":
+		return true
+	default:
+		return n.Kind == yaml.ScalarNode
+	}
+}
+
+func (l *pipelinesLinter) shapeName(typeName string) string {
+	kind := typeName
+	if t, ok := l.schema.Types[typeName]; ok {
+		kind = t.Kind
+	}
+	switch kind {
+	case "map":
+		return "a map"
+	case "seq":
+		return "a list"
+	case "int":
+		return "an integer"
+	case "bool":
+		return "true or false"
+	default:
+		return "a string"
+	}
+}
+
+// checkReferences runs the cross-reference checks over every pipeline in the
+// document. It is deliberately tolerant of malformed structure — check has
+// already reported that — and only looks at nodes with the expected shape.
+func (l *pipelinesLinter) checkReferences(root *yaml.Node) {
+	caches := map[string]bool{}
+	services := map[string]bool{}
+	if defs := yamlMapGet(root, "definitions"); defs != nil {
+		for _, p := range yamlMappingPairs(yamlMapGet(defs, "caches")) {
+			caches[p.Key.Value] = true
+		}
+		for _, p := range yamlMappingPairs(yamlMapGet(defs, "services")) {
+			services[p.Key.Value] = true
+		}
+	}
+
+	pipes := yamlMapGet(root, "pipelines")
+	if pipes == nil || pipes.Kind != yaml.MappingNode {
+		return
+	}
+	if len(yamlMappingPairs(pipes)) == 0 {
+		l.report(pipes, LintError, "pipelines", "pipelines must define at least one of: %s", strings.Join(pipelineSections, ", "))
+		return
+	}
+
+	for _, section := range pipelineSections {
+		node := yamlMapGet(pipes, section)
+		if node == nil {
+			continue
+		}
+		if section == "default" {
+			l.checkPipeline(node, "pipelines.default", false, caches, services)
+			continue
+		}
+		for _, p := range yamlMappingPairs(node) {
+			path := joinYAMLPath("pipelines."+section, p.Key.Value)
+			if section == "custom" && !customNameRegex.MatchString(p.Key.Value) {
+				l.report(p.Key, LintWarning, path,
+					"custom pipeline name %q contains characters other than letters, digits, '-', '_' and '.'; it may be hard to trigger by name", p.Key.Value)
+			}
+			l.checkPipeline(p.Value, path, section == "custom", caches, services)
+		}
+	}
+}
+
+func (l *pipelinesLinter) checkPipeline(n *yaml.Node, path string, custom bool, caches, services map[string]bool) {
+	n = resolveYAMLAlias(n)
+	if n.Kind != yaml.SequenceNode {
+		return
+	}
+	firstStep := true
+	for i, raw := range n.Content {
+		item := resolveYAMLAlias(raw)
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+
+		if v := yamlMapGet(item, "variables"); v != nil {
+			if !custom {
+				l.report(v, LintError, itemPath+".variables", "variables can only be declared in custom pipelines")
+			} else if i != 0 {
+				l.report(v, LintError, itemPath+".variables", "variables must be the first entry of a custom pipeline")
+			}
+			continue
+		}
+
+		if step := yamlMapGet(item, "step"); step != nil {
+			l.checkStep(step, itemPath+".step", firstStep, caches, services)
+		}
+		if par := yamlMapGet(item, "parallel"); par != nil {
+			steps := resolveYAMLAlias(par)
+			if steps.Kind == yaml.MappingNode {
+				steps = resolveYAMLAlias(yamlMapGet(steps, "steps"))
+			}
+			if steps != nil && steps.Kind == yaml.SequenceNode {
+				if len(steps.Content) < 2 {
+					l.report(par, LintWarning, itemPath+".parallel", "a parallel group should contain at least 2 steps")
+				}
+				for j, s := range steps.Content {
+					if step := yamlMapGet(resolveYAMLAlias(s), "step"); step != nil {
+						l.checkStep(step, fmt.Sprintf("%s.parallel[%d].step", itemPath, j), firstStep, caches, services)
+					}
+				}
+			}
+		}
+		if stage := yamlMapGet(item, "stage"); stage != nil {
+			if trig := yamlMapGet(stage, "trigger"); firstStep && trig != nil && trig.Value == "manual" {
+				l.report(trig, LintError, itemPath+".stage.trigger", "the first stage of a pipeline cannot be manual")
+			}
+			if steps := resolveYAMLAlias(yamlMapGet(stage, "steps")); steps != nil && steps.Kind == yaml.SequenceNode {
+				for j, s := range steps.Content {
+					if step := yamlMapGet(resolveYAMLAlias(s), "step"); step != nil {
+						if trig := yamlMapGet(step, "trigger"); trig != nil && j > 0 {
+							l.report(trig, LintError, fmt.Sprintf("%s.stage.steps[%d].step.trigger", itemPath, j),
+								"only the first step of a stage may set a trigger")
+						}
+						l.checkStep(step, fmt.Sprintf("%s.stage.steps[%d].step", itemPath, j), firstStep && j == 0, caches, services)
+					}
+				}
+			}
+		}
+		firstStep = false
+	}
+}
+
+func (l *pipelinesLinter) checkStep(n *yaml.Node, path string, first bool, caches, services map[string]bool) {
+	n = resolveYAMLAlias(n)
+	if n.Kind != yaml.MappingNode {
+		return
+	}
+
+	if trig := yamlMapGet(n, "trigger"); first && trig != nil && trig.Value == "manual" {
+		l.report(trig, LintError, path+".trigger", "the first step of a pipeline cannot be manual")
+	}
+
+	if list := resolveYAMLAlias(yamlMapGet(n, "caches")); list != nil && list.Kind == yaml.SequenceNode {
+		for i, c := range list.Content {
+			c = resolveYAMLAlias(c)
+			if c.Kind == yaml.ScalarNode && !predefinedCaches[c.Value] && !caches[c.Value] {
+				l.report(c, LintError, fmt.Sprintf("%s.caches[%d]", path, i),
+					"cache %q is neither predefined nor declared under definitions.caches", c.Value)
+			}
+		}
+	}
+
+	if list := resolveYAMLAlias(yamlMapGet(n, "services")); list != nil && list.Kind == yaml.SequenceNode {
+		if len(list.Content) > maxServicesPerStep {
+			l.report(list, LintError, path+".services", "a step can use at most %d services (found %d)", maxServicesPerStep, len(list.Content))
+		}
+		for i, s := range list.Content {
+			s = resolveYAMLAlias(s)
+			if s.Kind == yaml.ScalarNode && !predefinedServices[s.Value] && !services[s.Value] {
+				l.report(s, LintError, fmt.Sprintf("%s.services[%d]", path, i),
+					"service %q is not declared under definitions.services", s.Value)
+			}
+		}
+	}
+}
+
+// yamlPair is one key/value entry of a mapping node.
+type yamlPair struct {
+	Key   *yaml.Node
+	Value *yaml.Node
+}
+
+// resolveYAMLAlias follows alias nodes (*anchor) to the node they point at and
+// unwraps document nodes, so callers only ever see maps, sequences and scalars.
+func resolveYAMLAlias(n *yaml.Node) *yaml.Node {
+	for n != nil {
+		switch {
+		case n.Kind == yaml.AliasNode && n.Alias != nil:
+			n = n.Alias
+		case n.Kind == yaml.DocumentNode && len(n.Content) == 1:
+			n = n.Content[0]
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// yamlMappingPairs returns the entries of a mapping with YAML merge keys
+// (`<<: *anchor`, `<<: [*a, *b]`) expanded in place. Explicit keys override
+// merged ones, and earlier merge sources win over later ones, per the YAML
+// merge-key spec. Returns nil for anything that isn't a mapping.
+func yamlMappingPairs(n *yaml.Node) []yamlPair {
+	n = resolveYAMLAlias(n)
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	explicit := map[string]bool{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value != "<<" {
+			explicit[n.Content[i].Value] = true
+		}
+	}
+
+	var out []yamlPair
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if k.Value != "<<" || k.Tag != "!!merge" {
+			if !seen[k.Value] {
+				seen[k.Value] = true
+				out = append(out, yamlPair{Key: k, Value: v})
+			}
+			continue
+		}
+
+		var sources []*yaml.Node
+		src := resolveYAMLAlias(v)
+		if src != nil && src.Kind == yaml.SequenceNode {
+			sources = src.Content
+		} else {
+			sources = []*yaml.Node{src}
+		}
+		for _, s := range sources {
+			for _, p := range yamlMappingPairs(s) {
+				if explicit[p.Key.Value] || seen[p.Key.Value] {
+					continue
+				}
+				seen[p.Key.Value] = true
+				out = append(out, p)
+			}
+		}
+	}
+	return out
+}
+
+// yamlMapGet returns the (merge-aware) value for key in a mapping, or nil.
+func yamlMapGet(n *yaml.Node, key string) *yaml.Node {
+	for _, p := range yamlMappingPairs(n) {
+		if p.Key.Value == key {
+			return p.Value
+		}
+	}
+	return nil
+}
+
+// joinYAMLPath appends key to a dotted locator, bracket-quoting keys that
+// would otherwise be ambiguous (branch globs such as "feature/*").
+func joinYAMLPath(path, key string) string {
+	if !plainYAMLKey.MatchString(key) {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+var plainYAMLKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// suggestKey returns the known key closest to key by edit distance, or "" if
+// nothing is close enough to be a plausible typo.
+func suggestKey(key string, known map[string]string) string {
+	best, bestDist := "", 3
+	for k := range known {
+		if d := editDistance(key, k); d < bestDist || d == bestDist && best != "" && k < best {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func dedupeStrings(in []string) []string {
+	seen := map[string]bool{}
+	out := in[:0]
+	for _, s := range in {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func derefInt(p *int) int {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
+
+type LintPipelinesConfigArgs struct {
+	Workspace string `json:"workspace,omitempty" jsonschema:"Workspace slug (when linting the committed file)"`
+	RepoSlug  string `json:"repo_slug,omitempty" jsonschema:"Repository slug (when linting the committed file)"`
+	Ref       string `json:"ref,omitempty" jsonschema:"Branch, tag, or commit to read the file from (default: HEAD)"`
+	Path      string `json:"path,omitempty" jsonschema:"Path of the config file in the repository (default: bitbucket-pipelines.yml)"`
+	Content   string `json:"content,omitempty" jsonschema:"Raw YAML to lint instead of fetching the committed file"`
+}
+
+// LintPipelinesConfig lints a pipelines config. When Content is set it is
+// linted as-is (no API call); otherwise the file is fetched from the
+// repository at Ref.
+func (c *Client) LintPipelinesConfig(args LintPipelinesConfigArgs) (*LintResult, error) {
+	if args.Content != "" {
+		return LintPipelinesYAML([]byte(args.Content)), nil
+	}
+	if args.Workspace == "" || args.RepoSlug == "" {
+		return nil, fmt.Errorf("workspace and repo_slug are required when content is not provided")
+	}
+
+	path := args.Path
+	if path == "" {
+		path = PipelinesConfigPath
+	}
+	raw, _, err := c.GetFileContent(GetFileContentArgs{
+		Workspace: args.Workspace,
+		RepoSlug:  args.RepoSlug,
+		Path:      path,
+		Ref:       args.Ref,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", path, err)
+	}
+	return LintPipelinesYAML(raw), nil
+}
@@ -0,0 +1,241 @@
+package bitbucket
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// The embedded schema is data, so a typo in a type name would only surface
+// at lint time. Pin that every reference resolves.
+func TestPipelinesSchema_ReferencesResolve(t *testing.T) {
+	s, err := loadLintSchema()
+	if err != nil {
+		t.Fatalf("loadLintSchema: %v", err)
+	}
+	builtin := map[string]bool{"string": true, "int": true, "bool": true, "any": true}
+	known := func(name string) bool { return builtin[name] || s.Types[name] != nil }
+
+	if !known(s.Root) {
+		t.Fatalf("root type %q is not defined", s.Root)
+	}
+	for name, typ := range s.Types {
+		refs := append([]string{}, typ.Variants...)
+		for _, f := range typ.Fields {
+			refs = append(refs, f)
+		}
+		if typ.Values != "" {
+			refs = append(refs, typ.Values)
+		}
+		if typ.Items != "" {
+			refs = append(refs, typ.Items)
+		}
+		for _, r := range refs {
+			if !known(r) {
+				t.Errorf("type %q references undefined type %q", name, r)
+			}
+		}
+	}
+}
+
+func TestLintPipelinesYAML_ValidConfig(t *testing.T) {
+	src := `
+image: golang:1.26
+definitions:
+  caches:
+    gomod: ~/go/pkg/mod
+  services:
+    postgres:
+      image: postgres:16
+  steps:
+    - step: &test
+        name: Test
+        caches: [gomod]
+        services: [postgres, docker]
+        script:
+          - go test ./...
+pipelines:
+  default:
+    - step: *test
+  branches:
+    'release/*':
+      - parallel:
+          fail-fast: true
+          steps:
+            - step: *test
+            - step:
+                <<: *test
+                name: Lint
+                script: [golangci-lint run]
+      - stage:
+          name: Deploy
+          deployment: production
+          steps:
+            - step:
+                trigger: automatic
+                script:
+                  - pipe: atlassian/aws-s3-deploy:1.1.0
+                    variables:
+                      S3_BUCKET: x
+  custom:
+    nightly-build:
+      - variables:
+          - name: TARGET
+            default: all
+      - step:
+          size: 2x
+          max-time: 60
+          script: [make]
+`
+	r := LintPipelinesYAML([]byte(src))
+	if !r.Valid || len(r.Diagnostics) != 0 {
+		t.Fatalf("expected no diagnostics, got %+v", r.Diagnostics)
+	}
+}
+
+func TestLintPipelinesYAML_Diagnostics(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		line     int
+		severity string
+		contains string
+	}{
+		{
+			name:     "syntax error",
+			src:      "pipelines:\n  default: [\n",
+			severity: LintError,
+			contains: "did not find expected",
+		},
+		{
+			name:     "missing pipelines",
+			src:      "image: alpine\n",
+			line:     1,
+			severity: LintError,
+			contains: `missing required key "pipelines"`,
+		},
+		{
+			name:     "unknown step key with suggestion",
+			src:      "pipelines:\n  default:\n    - step:\n        scirpt: [make]\n        script: [make]\n",
+			line:     4,
+			severity: LintError,
+			contains: `did you mean "script"`,
+		},
+		{
+			name:     "step without script",
+			src:      "pipelines:\n  default:\n    - step:\n        name: x\n",
+			line:     4,
+			severity: LintError,
+			contains: `missing required key "script"`,
+		},
+		{
+			name:     "stage nested in parallel",
+			src:      "pipelines:\n  default:\n    - parallel:\n        - stage:\n            steps: []\n        - step: {script: [a]}\n",
+			line:     4,
+			severity: LintError,
+			contains: `unknown key "stage"`,
+		},
+		{
+			name:     "item with two kinds",
+			src:      "pipelines:\n  default:\n    - step: {script: [a]}\n      parallel: []\n",
+			line:     3,
+			severity: LintError,
+			contains: "must contain only one of",
+		},
+		{
+			name:     "undefined cache",
+			src:      "pipelines:\n  default:\n    - step:\n        caches: [node, mycache]\n        script: [a]\n",
+			line:     4,
+			severity: LintError,
+			contains: `cache "mycache"`,
+		},
+		{
+			name:     "undefined service",
+			src:      "pipelines:\n  default:\n    - step:\n        services: [redis]\n        script: [a]\n",
+			line:     4,
+			severity: LintError,
+			contains: `service "redis"`,
+		},
+		{
+			name:     "max-time out of range",
+			src:      "pipelines:\n  default:\n    - step:\n        max-time: 1000\n        script: [a]\n",
+			line:     4,
+			severity: LintError,
+			contains: "out of range",
+		},
+		{
+			name:     "bad size",
+			src:      "options:\n  size: 3x\npipelines:\n  default:\n    - step: {script: [a]}\n",
+			line:     2,
+			severity: LintError,
+			contains: `"3x" is not one of`,
+		},
+		{
+			name:     "manual first step",
+			src:      "pipelines:\n  default:\n    - step:\n        trigger: manual\n        script: [a]\n",
+			line:     4,
+			severity: LintError,
+			contains: "first step of a pipeline cannot be manual",
+		},
+		{
+			name:     "single-step parallel",
+			src:      "pipelines:\n  default:\n    - parallel:\n        - step: {script: [a]}\n",
+			line:     4,
+			severity: LintWarning,
+			contains: "at least 2 steps",
+		},
+		{
+			name:     "variables outside custom",
+			src:      "pipelines:\n  branches:\n    main:\n      - variables: [{name: X}]\n      - step: {script: [a]}\n",
+			line:     4,
+			severity: LintError,
+			contains: "only be declared in custom pipelines",
+		},
+		{
+			name:     "odd custom name",
+			src:      "pipelines:\n  custom:\n    'deploy prod!':\n      - step: {script: [a]}\n",
+			line:     3,
+			severity: LintWarning,
+			contains: "custom pipeline name",
+		},
+		{
+			name:     "duplicate key",
+			src:      "pipelines:\n  custom:\n    a:\n      - step: {script: [a]}\n    a:\n      - step: {script: [b]}\n",
+			line:     5,
+			severity: LintError,
+			contains: `duplicate key "a"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := LintPipelinesYAML([]byte(tc.src))
+			for _, d := range r.Diagnostics {
+				if d.Severity == tc.severity && strings.Contains(d.Message, tc.contains) {
+					if tc.line != 0 && d.Line != tc.line {
+						t.Errorf("diagnostic %q on line %d, want line %d", d.Message, d.Line, tc.line)
+					}
+					return
+				}
+			}
+			t.Fatalf("no %s containing %q; got %+v", tc.severity, tc.contains, r.Diagnostics)
+		})
+	}
+}
+
+func TestLintPipelinesConfig_FetchesCommittedFile(t *testing.T) {
+	var gotPath string
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_, _ = w.Write([]byte("pipelines:\n  default:\n    - step: {script: [a]}\n"))
+	})
+	r, err := c.LintPipelinesConfig(LintPipelinesConfigArgs{Workspace: "w", RepoSlug: "r", Ref: "main"})
+	if err != nil {
+		t.Fatalf("LintPipelinesConfig: %v", err)
+	}
+	if gotPath != "/repositories/w/r/src/main/bitbucket-pipelines.yml" {
+		t.Errorf("fetched %q, want the committed bitbucket-pipelines.yml at main", gotPath)
+	}
+	if !r.Valid {
+		t.Errorf("expected valid result, got %+v", r.Diagnostics)
+	}
+}
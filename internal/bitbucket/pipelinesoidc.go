@@ -0,0 +1,68 @@
+package bitbucket
+
+import "fmt"
+
+// OIDCConfiguration is the OpenID Connect discovery document for a
+// workspace's Pipelines identity provider.
+type OIDCConfiguration struct {
+	Issuer                 string   `json:"issuer"`
+	JwksURI                string   `json:"jwks_uri"`
+	ResponseTypesSupported []string `json:"response_types_supported,omitempty"`
+	SubjectTypesSupported  []string `json:"subject_types_supported,omitempty"`
+	SigningAlgsSupported   []string `json:"id_token_signing_alg_values_supported,omitempty"`
+	ClaimsSupported        []string `json:"claims_supported,omitempty"`
+}
+
+// OIDCKey is one JSON Web Key used to sign Pipelines OIDC tokens.
+type OIDCKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+}
+
+// PipelinesOIDC bundles what a cloud provider needs to trust Pipelines OIDC
+// tokens: the discovery document, the signing keys, and the audience.
+type PipelinesOIDC struct {
+	Configuration *OIDCConfiguration `json:"configuration"`
+	Keys          []OIDCKey          `json:"keys"`
+	Audience      string             `json:"audience,omitempty"`
+}
+
+type GetPipelinesOIDCArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+}
+
+// GetPipelinesOIDC fetches the workspace's Pipelines OIDC configuration and
+// signing keys. The audience is derived from the workspace UUID, which is the
+// value identity providers must be configured to accept.
+func (c *Client) GetPipelinesOIDC(args GetPipelinesOIDCArgs) (*PipelinesOIDC, error) {
+	if args.Workspace == "" {
+		return nil, fmt.Errorf("workspace is required")
+	}
+	base := fmt.Sprintf("/workspaces/%s/pipelines-config/identity/oidc", QueryEscape(args.Workspace))
+
+	cfg, err := GetJSON[OIDCConfiguration](c, base+"/.well-known/openid-configuration")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OIDC configuration: %v", err)
+	}
+	jwks, err := GetJSON[struct {
+		Keys []OIDCKey `json:"keys"`
+	}](c, base+"/keys.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OIDC keys: %v", err)
+	}
+
+	out := &PipelinesOIDC{Configuration: cfg, Keys: jwks.Keys}
+	if ws, err := c.GetWorkspace(GetWorkspaceArgs{Workspace: args.Workspace}); err == nil && ws.UUID != "" {
+		out.Audience = "ari:cloud:bitbucket::workspace/" + trimBraces(ws.UUID)
+	}
+	return out, nil
+}
+
+func trimBraces(s string) string {
+	if len(s) >= 2 && s[0] == '{' && s[len(s)-1] == '}' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,442 @@
+package bitbucket
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	yaml "go.yaml.in/yaml/v4"
+)
+
+// Bitbucket's defaults for steps that don't set size or max-time.
+const (
+	defaultStepSize    = "1x"
+	defaultStepMaxTime = 120
+)
+
+// PipelinePlanTarget describes the event to simulate when resolving which
+// pipeline in bitbucket-pipelines.yml would run. Set exactly one of Custom,
+// PullRequest, Tag, or (the default) a plain branch push via Ref.
+type PipelinePlanTarget struct {
+	Ref         string `json:"ref,omitempty"`
+	PullRequest bool   `json:"pull_request,omitempty"`
+	Tag         bool   `json:"tag,omitempty"`
+	Custom      string `json:"custom,omitempty"`
+}
+
+// PipelinePlan is the effective pipeline for a target after glob matching,
+// YAML anchors/merge keys and global options have been applied.
+type PipelinePlan struct {
+	Section   string            `json:"section"`
+	Pattern   string            `json:"pattern,omitempty"`
+	Ref       string            `json:"ref,omitempty"`
+	Matched   bool              `json:"matched"`
+	Note      string            `json:"note,omitempty"`
+	Variables []PlannedVariable `json:"variables,omitempty"`
+	Steps     []PlannedStep     `json:"steps"`
+}
+
+// PlannedVariable is a variable a custom pipeline prompts for.
+type PlannedVariable struct {
+	Name          string   `json:"name"`
+	Default       string   `json:"default,omitempty"`
+	AllowedValues []string `json:"allowed_values,omitempty"`
+}
+
+// PlannedStep is one step of a resolved pipeline with inherited settings
+// (image, size, max-time, stage trigger) filled in.
+type PlannedStep struct {
+	Index       int              `json:"index"`
+	Name        string           `json:"name"`
+	Group       string           `json:"group,omitempty"`
+	GroupKind   string           `json:"group_kind,omitempty"` // parallel | stage
+	Trigger     string           `json:"trigger"`
+	Image       string           `json:"image,omitempty"`
+	Size        string           `json:"size"`
+	MaxTime     int              `json:"max_time"`
+	Deployment  string           `json:"deployment,omitempty"`
+	Services    []PlannedService `json:"services,omitempty"`
+	Caches      []string         `json:"caches,omitempty"`
+	Artifacts   []string         `json:"artifacts,omitempty"`
+	Condition   []string         `json:"condition_paths,omitempty"`
+	Script      []string         `json:"script"`
+	AfterScript []string         `json:"after_script,omitempty"`
+}
+
+// PlannedService is a service container attached to a step.
+type PlannedService struct {
+	Name  string `json:"name"`
+	Image string `json:"image,omitempty"`
+}
+
+// PlanPipelinesYAML resolves which pipeline in a bitbucket-pipelines.yml would
+// run for target and expands it into a flat step list. It works entirely
+// offline. A target that matches no section yields a plan with Matched=false
+// rather than an error, since "nothing runs" is a legitimate answer.
+//
+// Section selection mirrors Bitbucket:
+//   - custom: pipelines.custom[name]
+//   - pull request: pipelines.pull-requests, matched on the source branch
+//   - tag: pipelines.tags
+//   - branch: pipelines.branches, falling back to pipelines.default
+//
+// Within a section an exact name beats any glob; among globs, the most
+// specific (most literal characters) wins, with ties going to file order.
+func PlanPipelinesYAML(data []byte, target PipelinePlanTarget) (*PipelinePlan, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing pipelines config: %w", err)
+	}
+	root := resolveYAMLAlias(&doc)
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil, errors.New("pipelines config is empty or not a map")
+	}
+	pipes := yamlMapGet(root, "pipelines")
+	if pipes == nil {
+		return nil, errors.New("pipelines config has no 'pipelines' section")
+	}
+
+	plan := &PipelinePlan{Ref: target.Ref, Steps: []PlannedStep{}}
+	var items *yaml.Node
+	switch {
+	case target.Custom != "":
+		plan.Section = "custom"
+		plan.Pattern = target.Custom
+		items = yamlMapGet(yamlMapGet(pipes, "custom"), target.Custom)
+		if items == nil {
+			plan.Note = fmt.Sprintf("no custom pipeline named %q", target.Custom)
+		}
+	case target.PullRequest:
+		plan.Section = "pull-requests"
+		plan.Pattern, items = matchPipelineSection(yamlMapGet(pipes, "pull-requests"), target.Ref)
+		if items == nil {
+			plan.Note = fmt.Sprintf("no pull-requests pattern matches source branch %q; only the branch pipeline runs", target.Ref)
+		}
+	case target.Tag:
+		plan.Section = "tags"
+		plan.Pattern, items = matchPipelineSection(yamlMapGet(pipes, "tags"), target.Ref)
+		if items == nil {
+			plan.Note = fmt.Sprintf("no tags pattern matches %q; pushing this tag runs nothing", target.Ref)
+		}
+	default:
+		plan.Section = "branches"
+		plan.Pattern, items = matchPipelineSection(yamlMapGet(pipes, "branches"), target.Ref)
+		if items == nil {
+			plan.Section = "default"
+			plan.Pattern = ""
+			items = yamlMapGet(pipes, "default")
+			if items == nil {
+				plan.Note = fmt.Sprintf("no branches pattern matches %q and there is no default pipeline", target.Ref)
+			}
+		}
+	}
+	if items == nil {
+		return plan, nil
+	}
+	plan.Matched = true
+
+	p := &planner{root: root, plan: plan}
+	p.expand(resolveYAMLAlias(items))
+	return plan, nil
+}
+
+// matchPipelineSection picks the entry of a branches/tags/pull-requests map
+// that applies to name.
+func matchPipelineSection(section *yaml.Node, name string) (pattern string, items *yaml.Node) {
+	bestScore := -1
+	for _, p := range yamlMappingPairs(section) {
+		key := p.Key.Value
+		if key == name {
+			return key, p.Value
+		}
+		if !matchPipelineGlob(key, name) {
+			continue
+		}
+		if score := globSpecificity(key); score > bestScore {
+			bestScore = score
+			pattern, items = key, p.Value
+		}
+	}
+	return pattern, items
+}
+
+// matchPipelineGlob reports whether name matches a Bitbucket Pipelines glob:
+// '*' matches within one path segment, '**' across segments, '?' one
+// non-separator character, and '{a,b}' any of the listed alternatives.
+func matchPipelineGlob(pattern, name string) bool {
+	re, err := regexp.Compile("^" + globToRegex(pattern) + "$")
+	if err != nil {
+		return false
+	}
+	return re.MatchString(name)
+}
+
+func globToRegex(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+		switch ch {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '{':
+			end := strings.IndexByte(pattern[i:], '}')
+			if end < 0 {
+				b.WriteString(regexp.QuoteMeta(pattern[i:]))
+				return b.String()
+			}
+			alts := strings.Split(pattern[i+1:i+end], ",")
+			for j, a := range alts {
+				alts[j] = globToRegex(a)
+			}
+			b.WriteString("(?:" + strings.Join(alts, "|") + ")")
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	return b.String()
+}
+
+// globSpecificity scores a glob by its literal characters, so "feature/*"
+// outranks "**" for the branch feature/x.
+func globSpecificity(pattern string) int {
+	n := 0
+	for _, ch := range pattern {
+		switch ch {
+		case '*', '?', '{', '}', ',':
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+type planner struct {
+	root *yaml.Node
+	plan *PipelinePlan
+}
+
+func (p *planner) expand(items *yaml.Node) {
+	if items == nil || items.Kind != yaml.SequenceNode {
+		return
+	}
+	group := 0
+	for _, raw := range items.Content {
+		item := resolveYAMLAlias(raw)
+
+		if vars := resolveYAMLAlias(yamlMapGet(item, "variables")); vars != nil {
+			for _, v := range vars.Content {
+				pv := PlannedVariable{
+					Name:    yamlScalar(yamlMapGet(v, "name")),
+					Default: yamlScalar(yamlMapGet(v, "default")),
+				}
+				pv.AllowedValues = yamlStringList(yamlMapGet(v, "allowed-values"))
+				p.plan.Variables = append(p.plan.Variables, pv)
+			}
+			continue
+		}
+		if step := yamlMapGet(item, "step"); step != nil {
+			p.addStep(step, "", "", "")
+			continue
+		}
+		if par := resolveYAMLAlias(yamlMapGet(item, "parallel")); par != nil {
+			group++
+			steps := par
+			if par.Kind == yaml.MappingNode {
+				steps = resolveYAMLAlias(yamlMapGet(par, "steps"))
+			}
+			label := fmt.Sprintf("parallel #%d", group)
+			for _, s := range yamlSeq(steps) {
+				if step := yamlMapGet(s, "step"); step != nil {
+					p.addStep(step, label, "parallel", "")
+				}
+			}
+			continue
+		}
+		if stage := resolveYAMLAlias(yamlMapGet(item, "stage")); stage != nil {
+			group++
+			label := yamlScalar(yamlMapGet(stage, "name"))
+			if label == "" {
+				label = fmt.Sprintf("stage #%d", group)
+			}
+			stageTrigger := yamlScalar(yamlMapGet(stage, "trigger"))
+			deployment := yamlScalar(yamlMapGet(stage, "deployment"))
+			for i, s := range yamlSeq(resolveYAMLAlias(yamlMapGet(stage, "steps"))) {
+				step := yamlMapGet(s, "step")
+				if step == nil {
+					continue
+				}
+				trigger := ""
+				if i == 0 {
+					trigger = stageTrigger
+				}
+				p.addStep(step, label, "stage", trigger)
+				if deployment != "" {
+					p.plan.Steps[len(p.plan.Steps)-1].Deployment = deployment
+				}
+			}
+		}
+	}
+}
+
+func (p *planner) addStep(n *yaml.Node, group, groupKind, inheritedTrigger string) {
+	options := yamlMapGet(p.root, "options")
+
+	s := PlannedStep{
+		Index:       len(p.plan.Steps) + 1,
+		Name:        yamlScalar(yamlMapGet(n, "name")),
+		Group:       group,
+		GroupKind:   groupKind,
+		Trigger:     firstNonEmpty(yamlScalar(yamlMapGet(n, "trigger")), inheritedTrigger, "automatic"),
+		Image:       firstNonEmpty(yamlImage(yamlMapGet(n, "image")), yamlImage(yamlMapGet(p.root, "image"))),
+		Size:        firstNonEmpty(yamlScalar(yamlMapGet(n, "size")), yamlScalar(yamlMapGet(options, "size")), defaultStepSize),
+		MaxTime:     defaultStepMaxTime,
+		Deployment:  yamlScalar(yamlMapGet(n, "deployment")),
+		Caches:      yamlStringList(yamlMapGet(n, "caches")),
+		Script:      yamlScript(yamlMapGet(n, "script")),
+		AfterScript: yamlScript(yamlMapGet(n, "after-script")),
+	}
+	if s.Name == "" {
+		s.Name = fmt.Sprintf("Step %d", s.Index)
+	}
+	for _, src := range []*yaml.Node{yamlMapGet(n, "max-time"), yamlMapGet(options, "max-time")} {
+		if v, err := strconv.Atoi(yamlScalar(src)); err == nil {
+			s.MaxTime = v
+			break
+		}
+	}
+
+	serviceDefs := yamlMapGet(yamlMapGet(p.root, "definitions"), "services")
+	for _, name := range yamlStringList(yamlMapGet(n, "services")) {
+		svc := PlannedService{Name: name, Image: yamlImage(yamlMapGet(yamlMapGet(serviceDefs, name), "image"))}
+		if svc.Image == "" && name == "docker" {
+			svc.Image = "(built-in)"
+		}
+		s.Services = append(s.Services, svc)
+	}
+
+	artifacts := resolveYAMLAlias(yamlMapGet(n, "artifacts"))
+	if artifacts != nil && artifacts.Kind == yaml.MappingNode {
+		artifacts = yamlMapGet(artifacts, "paths")
+	}
+	s.Artifacts = yamlStringList(artifacts)
+
+	s.Condition = yamlStringList(yamlMapGet(yamlMapGet(yamlMapGet(n, "condition"), "changesets"), "includePaths"))
+
+	p.plan.Steps = append(p.plan.Steps, s)
+}
+
+// yamlScalar returns a scalar's value, or "" for anything else.
+func yamlScalar(n *yaml.Node) string {
+	n = resolveYAMLAlias(n)
+	if n == nil || n.Kind != yaml.ScalarNode || n.Tag == "!!null" {
+		return ""
+	}
+	return n.Value
+}
+
+// yamlSeq returns a sequence's (alias-resolved) elements.
+func yamlSeq(n *yaml.Node) []*yaml.Node {
+	n = resolveYAMLAlias(n)
+	if n == nil || n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	out := make([]*yaml.Node, 0, len(n.Content))
+	for _, c := range n.Content {
+		out = append(out, resolveYAMLAlias(c))
+	}
+	return out
+}
+
+func yamlStringList(n *yaml.Node) []string {
+	var out []string
+	for _, c := range yamlSeq(n) {
+		if v := yamlScalar(c); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// yamlImage accepts both image spellings: a bare string or a map with name.
+func yamlImage(n *yaml.Node) string {
+	if v := yamlScalar(n); v != "" {
+		return v
+	}
+	return yamlScalar(yamlMapGet(n, "name"))
+}
+
+// yamlScript flattens a script list, rendering pipes as "pipe: <name>".
+func yamlScript(n *yaml.Node) []string {
+	var out []string
+	for _, c := range yamlSeq(n) {
+		if v := yamlScalar(c); v != "" {
+			out = append(out, v)
+			continue
+		}
+		if pipe := yamlScalar(yamlMapGet(c, "pipe")); pipe != "" {
+			out = append(out, "pipe: "+pipe)
+		}
+	}
+	return out
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+type PlanPipelinesConfigArgs struct {
+	Workspace   string `json:"workspace,omitempty" jsonschema:"Workspace slug (when planning from the committed file)"`
+	RepoSlug    string `json:"repo_slug,omitempty" jsonschema:"Repository slug (when planning from the committed file)"`
+	Ref         string `json:"ref,omitempty" jsonschema:"Branch or tag to simulate; the config is also read from this ref"`
+	PullRequest bool   `json:"pull_request,omitempty" jsonschema:"Simulate a pull request from ref instead of a push"`
+	Tag         bool   `json:"tag,omitempty" jsonschema:"Treat ref as a tag"`
+	Custom      string `json:"custom,omitempty" jsonschema:"Name of a custom pipeline to plan"`
+	Content     string `json:"content,omitempty" jsonschema:"Raw YAML to plan from instead of fetching the committed file"`
+}
+
+// PlanPipelinesConfig resolves the effective pipeline for a ref. When Content
+// is empty the config is fetched from the repository at Ref (HEAD if unset).
+func (c *Client) PlanPipelinesConfig(args PlanPipelinesConfigArgs) (*PipelinePlan, error) {
+	if args.Ref == "" && args.Custom == "" {
+		return nil, fmt.Errorf("ref or custom is required")
+	}
+
+	data := []byte(args.Content)
+	if args.Content == "" {
+		if args.Workspace == "" || args.RepoSlug == "" {
+			return nil, fmt.Errorf("workspace and repo_slug are required when content is not provided")
+		}
+		raw, _, err := c.GetFileContent(GetFileContentArgs{
+			Workspace: args.Workspace,
+			RepoSlug:  args.RepoSlug,
+			Path:      PipelinesConfigPath,
+			Ref:       args.Ref,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", PipelinesConfigPath, err)
+		}
+		data = raw
+	}
+
+	return PlanPipelinesYAML(data, PipelinePlanTarget{
+		Ref:         args.Ref,
+		PullRequest: args.PullRequest,
+		Tag:         args.Tag,
+		Custom:      args.Custom,
+	})
+}
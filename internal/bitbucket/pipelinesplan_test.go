@@ -0,0 +1,180 @@
+package bitbucket
+
+import (
+	"reflect"
+	"testing"
+)
+
+const planFixture = `
+image: golang:1.26
+options:
+  max-time: 30
+definitions:
+  services:
+    postgres:
+      image: postgres:16
+  steps:
+    - step: &test
+        name: Test
+        caches: [gomod]
+        services: [postgres]
+        script:
+          - go test ./...
+pipelines:
+  default:
+    - step: *test
+  branches:
+    '**':
+      - step:
+          name: Catch-all
+          script: [echo all]
+    'feature/*':
+      - step:
+          <<: *test
+          name: Feature test
+          size: 2x
+    main:
+      - parallel:
+          - step: *test
+          - step:
+              name: Lint
+              image: golangci/golangci-lint
+              script: [golangci-lint run]
+      - stage:
+          name: Production
+          deployment: production
+          trigger: manual
+          steps:
+            - step:
+                name: Deploy
+                script:
+                  - pipe: atlassian/aws-s3-deploy:1.1.0
+            - step:
+                name: Smoke
+                script: [curl -f https://example.com]
+  tags:
+    'v*':
+      - step: {name: Release, script: [make release]}
+  pull-requests:
+    '{feature,bugfix}/**':
+      - step: {name: PR check, script: [make check]}
+  custom:
+    nightly:
+      - variables:
+          - name: TARGET
+            default: all
+            allowed-values: [all, api]
+      - step: {name: Nightly, script: [make nightly]}
+`
+
+func TestMatchPipelineGlob(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*", "main", true},
+		{"*", "feature/x", false},
+		{"**", "feature/x/y", true},
+		{"feature/*", "feature/x", true},
+		{"feature/*", "feature/x/y", false},
+		{"feature/**", "feature/x/y", true},
+		{"{feature,bugfix}/*", "bugfix/1", true},
+		{"{feature,bugfix}/*", "hotfix/1", false},
+		{"release-?", "release-1", true},
+		{"release-?", "release-10", false},
+		{"v1.0", "v1x0", false},
+	}
+	for _, tc := range tests {
+		if got := matchPipelineGlob(tc.pattern, tc.name); got != tc.want {
+			t.Errorf("matchPipelineGlob(%q, %q) = %v, want %v", tc.pattern, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestPlanPipelinesYAML_SectionSelection(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      PipelinePlanTarget
+		wantSection string
+		wantPattern string
+		wantSteps   []string
+	}{
+		{"exact branch beats globs", PipelinePlanTarget{Ref: "main"}, "branches", "main", []string{"Test", "Lint", "Deploy", "Smoke"}},
+		{"specific glob beats **", PipelinePlanTarget{Ref: "feature/x"}, "branches", "feature/*", []string{"Feature test"}},
+		{"catch-all glob", PipelinePlanTarget{Ref: "feature/x/y"}, "branches", "**", []string{"Catch-all"}},
+		{"tag", PipelinePlanTarget{Ref: "v1.2.0", Tag: true}, "tags", "v*", []string{"Release"}},
+		{"pull request", PipelinePlanTarget{Ref: "bugfix/a/b", PullRequest: true}, "pull-requests", "{feature,bugfix}/**", []string{"PR check"}},
+		{"custom", PipelinePlanTarget{Custom: "nightly"}, "custom", "nightly", []string{"Nightly"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			plan, err := PlanPipelinesYAML([]byte(planFixture), tc.target)
+			if err != nil {
+				t.Fatalf("PlanPipelinesYAML: %v", err)
+			}
+			if !plan.Matched || plan.Section != tc.wantSection || plan.Pattern != tc.wantPattern {
+				t.Fatalf("got section=%q pattern=%q matched=%v, want %q/%q", plan.Section, plan.Pattern, plan.Matched, tc.wantSection, tc.wantPattern)
+			}
+			var names []string
+			for _, s := range plan.Steps {
+				names = append(names, s.Name)
+			}
+			if !reflect.DeepEqual(names, tc.wantSteps) {
+				t.Errorf("steps = %v, want %v", names, tc.wantSteps)
+			}
+		})
+	}
+}
+
+func TestPlanPipelinesYAML_ExpandsAnchorsAndInheritance(t *testing.T) {
+	plan, err := PlanPipelinesYAML([]byte(planFixture), PipelinePlanTarget{Ref: "feature/x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := plan.Steps[0]
+	if s.Image != "golang:1.26" || s.Size != "2x" || s.MaxTime != 30 || s.Trigger != "automatic" {
+		t.Errorf("inherited settings wrong: %+v", s)
+	}
+	if !reflect.DeepEqual(s.Caches, []string{"gomod"}) || !reflect.DeepEqual(s.Script, []string{"go test ./..."}) {
+		t.Errorf("merge key not applied: caches=%v script=%v", s.Caches, s.Script)
+	}
+	if len(s.Services) != 1 || s.Services[0] != (PlannedService{Name: "postgres", Image: "postgres:16"}) {
+		t.Errorf("services = %+v, want postgres (postgres:16)", s.Services)
+	}
+
+	plan, err = PlanPipelinesYAML([]byte(planFixture), PipelinePlanTarget{Ref: "main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lint, deploy, smoke := plan.Steps[1], plan.Steps[2], plan.Steps[3]
+	if lint.GroupKind != "parallel" || lint.Image != "golangci/golangci-lint" {
+		t.Errorf("lint step = %+v", lint)
+	}
+	if deploy.Group != "Production" || deploy.Trigger != "manual" || deploy.Deployment != "production" {
+		t.Errorf("deploy step should inherit stage trigger/deployment: %+v", deploy)
+	}
+	if smoke.Trigger != "automatic" {
+		t.Errorf("only the first step of a stage carries its trigger, got %q", smoke.Trigger)
+	}
+	if !reflect.DeepEqual(deploy.Script, []string{"pipe: atlassian/aws-s3-deploy:1.1.0"}) {
+		t.Errorf("pipe rendering = %v", deploy.Script)
+	}
+}
+
+func TestPlanPipelinesYAML_Unmatched(t *testing.T) {
+	plan, err := PlanPipelinesYAML([]byte(planFixture), PipelinePlanTarget{Ref: "main", PullRequest: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Matched || plan.Note == "" || len(plan.Steps) != 0 {
+		t.Errorf("expected unmatched plan with a note, got %+v", plan)
+	}
+
+	plan, err = PlanPipelinesYAML([]byte(planFixture), PipelinePlanTarget{Custom: "nightly"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Variables) != 1 || plan.Variables[0].Name != "TARGET" || len(plan.Variables[0].AllowedValues) != 2 {
+		t.Errorf("custom variables = %+v", plan.Variables)
+	}
+}
@@ -0,0 +1,252 @@
+package bitbucket
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PipelineSSHKeyPair is the repository's Pipelines SSH key. Bitbucket only
+// ever returns the public half.
+type PipelineSSHKeyPair struct {
+	PublicKey string `json:"public_key"`
+}
+
+// Fingerprint is the SHA256 fingerprint of the public key, or "" if it
+// cannot be parsed.
+func (k PipelineSSHKeyPair) Fingerprint() string {
+	_, key, err := ParseSSHPublicKey(k.PublicKey)
+	if err != nil {
+		return ""
+	}
+	fp, _ := SSHKeyFingerprint(key)
+	return fp
+}
+
+type PipelineSSHArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+}
+
+func (a PipelineSSHArgs) path(suffix string) (string, error) {
+	if a.Workspace == "" || a.RepoSlug == "" {
+		return "", fmt.Errorf("workspace and repo_slug are required")
+	}
+	return fmt.Sprintf("/repositories/%s/%s/pipelines_config/ssh/%s",
+		QueryEscape(a.Workspace), QueryEscape(a.RepoSlug), suffix), nil
+}
+
+// GetPipelineSSHKeyPair gets the repository's Pipelines SSH public key.
+func (c *Client) GetPipelineSSHKeyPair(args PipelineSSHArgs) (*PipelineSSHKeyPair, error) {
+	path, err := args.path("key_pair")
+	if err != nil {
+		return nil, err
+	}
+	return GetJSON[PipelineSSHKeyPair](c, path)
+}
+
+type SetPipelineSSHKeyPairArgs struct {
+	PipelineSSHArgs
+	PrivateKey string `json:"-"`
+	PublicKey  string `json:"-"`
+}
+
+// SetPipelineSSHKeyPair replaces the repository's Pipelines SSH key pair.
+// The private key is sent once and never echoed back.
+func (c *Client) SetPipelineSSHKeyPair(args SetPipelineSSHKeyPairArgs) (*PipelineSSHKeyPair, error) {
+	path, err := args.path("key_pair")
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(args.PrivateKey, "PRIVATE KEY-----") {
+		return nil, fmt.Errorf("private key is not in PEM/OpenSSH format")
+	}
+	if strings.Contains(args.PrivateKey, "ENCRYPTED") {
+		return nil, fmt.Errorf("private key is passphrase-protected; Pipelines needs an unencrypted key")
+	}
+	if _, _, err := ParseSSHPublicKey(args.PublicKey); err != nil {
+		return nil, fmt.Errorf("invalid public key: %v", err)
+	}
+
+	respData, err := c.Put(path, map[string]string{
+		"private_key": args.PrivateKey,
+		"public_key":  strings.TrimSpace(args.PublicKey),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set SSH key pair: %v", err)
+	}
+
+	var kp PipelineSSHKeyPair
+	if err := json.Unmarshal(respData, &kp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &kp, nil
+}
+
+// DeletePipelineSSHKeyPair removes the repository's Pipelines SSH key pair.
+func (c *Client) DeletePipelineSSHKeyPair(args PipelineSSHArgs) error {
+	path, err := args.path("key_pair")
+	if err != nil {
+		return err
+	}
+	return c.Delete(path)
+}
+
+// KnownHost is a host key Pipelines trusts when steps connect over SSH.
+type KnownHost struct {
+	UUID      string        `json:"uuid"`
+	Hostname  string        `json:"hostname"`
+	PublicKey *KnownHostKey `json:"public_key"`
+}
+
+// KnownHostKey is a known host's public key and its fingerprints.
+type KnownHostKey struct {
+	KeyType           string `json:"key_type"`
+	Key               string `json:"key"`
+	MD5Fingerprint    string `json:"md5_fingerprint,omitempty"`
+	SHA256Fingerprint string `json:"sha256_fingerprint,omitempty"`
+}
+
+// Fingerprint returns the SHA256 fingerprint, computing it from the key when
+// the API did not include one.
+func (h KnownHost) Fingerprint() string {
+	if h.PublicKey == nil {
+		return ""
+	}
+	if fp := h.PublicKey.SHA256Fingerprint; fp != "" {
+		if !strings.HasPrefix(fp, "SHA256:") {
+			fp = "SHA256:" + fp
+		}
+		return fp
+	}
+	fp, _ := SSHKeyFingerprint(h.PublicKey.Key)
+	return fp
+}
+
+// ListKnownHosts lists the repository's Pipelines known hosts.
+func (c *Client) ListKnownHosts(args PipelineSSHArgs) (*Paginated[KnownHost], error) {
+	path, err := args.path("known_hosts?pagelen=100")
+	if err != nil {
+		return nil, err
+	}
+	return GetPaginated[KnownHost](c, path)
+}
+
+type AddKnownHostArgs struct {
+	PipelineSSHArgs
+	Hostname string `json:"hostname" jsonschema:"Host name, optionally with port as [host]:port"`
+	// Key is an OpenSSH public key ("ssh-ed25519 AAAA...") or a known_hosts
+	// line ("host ssh-ed25519 AAAA...").
+	Key string `json:"key" jsonschema:"Host public key, in authorized_keys or known_hosts format"`
+}
+
+// AddKnownHost trusts a host key for Pipelines SSH connections.
+func (c *Client) AddKnownHost(args AddKnownHostArgs) (*KnownHost, error) {
+	path, err := args.path("known_hosts")
+	if err != nil {
+		return nil, err
+	}
+	if args.Hostname == "" {
+		return nil, fmt.Errorf("hostname is required")
+	}
+	keyType, key, err := ParseSSHPublicKey(args.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	respData, err := c.Post(path, map[string]any{
+		"type":     "pipeline_known_host",
+		"hostname": args.Hostname,
+		"public_key": map[string]string{
+			"type":     "pipeline_ssh_public_key",
+			"key_type": keyType,
+			"key":      key,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add known host: %v", err)
+	}
+
+	var host KnownHost
+	if err := json.Unmarshal(respData, &host); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &host, nil
+}
+
+type DeleteKnownHostArgs struct {
+	PipelineSSHArgs
+	// Host is a known host UUID or hostname.
+	Host string `json:"host" jsonschema:"Known host UUID or hostname"`
+}
+
+// DeleteKnownHost removes a known host, looked up by UUID or hostname.
+func (c *Client) DeleteKnownHost(args DeleteKnownHostArgs) error {
+	if args.Host == "" {
+		return fmt.Errorf("host is required")
+	}
+	uuid := args.Host
+	if !strings.HasPrefix(uuid, "{") {
+		hosts, err := c.ListKnownHosts(args.PipelineSSHArgs)
+		if err != nil {
+			return err
+		}
+		uuid = ""
+		for _, h := range hosts.Values {
+			if h.Hostname == args.Host {
+				uuid = h.UUID
+				break
+			}
+		}
+		if uuid == "" {
+			return fmt.Errorf("known host %q not found", args.Host)
+		}
+	}
+
+	path, err := args.path("known_hosts/" + QueryEscape(uuid))
+	if err != nil {
+		return err
+	}
+	return c.Delete(path)
+}
+
+// ParseSSHPublicKey extracts the key type and base64 body from an
+// authorized_keys-style line ("ssh-ed25519 AAAA... comment") or a
+// known_hosts/ssh-keyscan line ("host ssh-ed25519 AAAA..."). Blank lines and
+// # comments are skipped; the first key found wins.
+func ParseSSHPublicKey(s string) (keyType, key string, err error) {
+	for _, line := range strings.Split(s, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if !isSSHKeyType(fields[0]) && len(fields) > 1 {
+			fields = fields[1:] // leading host column
+		}
+		if len(fields) < 2 || !isSSHKeyType(fields[0]) {
+			return "", "", fmt.Errorf("unrecognised SSH public key line %q", strings.TrimSpace(line))
+		}
+		if _, err := base64.StdEncoding.DecodeString(fields[1]); err != nil {
+			return "", "", fmt.Errorf("SSH public key is not valid base64: %v", err)
+		}
+		return fields[0], fields[1], nil
+	}
+	return "", "", fmt.Errorf("no SSH public key found")
+}
+
+func isSSHKeyType(s string) bool {
+	return strings.HasPrefix(s, "ssh-") || strings.HasPrefix(s, "ecdsa-sha2-") || strings.HasPrefix(s, "sk-")
+}
+
+// SSHKeyFingerprint returns the OpenSSH-style SHA256 fingerprint
+// ("SHA256:...") of a base64 key body.
+func SSHKeyFingerprint(key string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:]), nil
+}
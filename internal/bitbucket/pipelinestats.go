@@ -0,0 +1,379 @@
+package bitbucket
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PipelineStats summarises a window of pipeline runs.
+type PipelineStats struct {
+	From         *time.Time           `json:"from,omitempty"`
+	To           *time.Time           `json:"to,omitempty"`
+	Runs         int                  `json:"runs"`
+	Successful   int                  `json:"successful"`
+	Failed       int                  `json:"failed"`
+	Stopped      int                  `json:"stopped"`
+	InProgress   int                  `json:"in_progress"`
+	SuccessRate  float64              `json:"success_rate"`
+	P50Secs      int                  `json:"p50_seconds"`
+	P95Secs      int                  `json:"p95_seconds"`
+	BuildMinutes float64              `json:"build_minutes"`
+	Pipelines    []PipelineGroupStats `json:"pipelines"`
+	Steps        []StepStats          `json:"steps,omitempty"`
+	FailingSteps []StepStats          `json:"failing_steps,omitempty"`
+	FlakySteps   []FlakyStep          `json:"flaky_steps,omitempty"`
+}
+
+// PipelineGroupStats are the stats for one pipeline definition, e.g.
+// "branches: main" or "custom: deploy".
+type PipelineGroupStats struct {
+	Name         string  `json:"name"`
+	Runs         int     `json:"runs"`
+	Successful   int     `json:"successful"`
+	Failed       int     `json:"failed"`
+	SuccessRate  float64 `json:"success_rate"`
+	P50Secs      int     `json:"p50_seconds"`
+	P95Secs      int     `json:"p95_seconds"`
+	BuildMinutes float64 `json:"build_minutes"`
+}
+
+// StepStats are the stats for one step name across runs.
+type StepStats struct {
+	Name        string  `json:"name"`
+	Runs        int     `json:"runs"`
+	Failures    int     `json:"failures"`
+	FailureRate float64 `json:"failure_rate"`
+	P50Secs     int     `json:"p50_seconds"`
+	P95Secs     int     `json:"p95_seconds"`
+}
+
+// FlakyStep is a step that both failed and passed on the same commit.
+type FlakyStep struct {
+	Name     string `json:"name"`
+	Commit   string `json:"commit"`
+	Failures int    `json:"failures"`
+	Passes   int    `json:"passes"`
+}
+
+// PipelineDefinitionName names the pipeline definition a run came from, from
+// its target selector: "default", "branches: main", "custom: deploy", ...
+func PipelineDefinitionName(p Pipeline) string {
+	if p.Target == nil || p.Target.Selector == nil || p.Target.Selector.Type == "" || p.Target.Selector.Type == "default" {
+		return "default"
+	}
+	sel := p.Target.Selector
+	if sel.Pattern == "" {
+		return sel.Type
+	}
+	return sel.Type + ": " + sel.Pattern
+}
+
+// pipelineResult returns a run's or step's terminal result name, or "" while
+// it is still pending or running.
+func pipelineResult(s *PipeState) string {
+	if s == nil || s.Result == nil {
+		return ""
+	}
+	return s.Result.Name
+}
+
+type PipelineStatsArgs struct {
+	Workspace string    `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string    `json:"repo_slug" jsonschema:"Repository slug"`
+	Branch    string    `json:"branch,omitempty" jsonschema:"Only runs on this branch"`
+	Since     time.Time `json:"-"`
+	// Limit caps how many runs are analysed (default 100, newest first).
+	Limit int `json:"limit,omitempty" jsonschema:"Maximum runs to analyse (default 100)"`
+	// SkipSteps leaves out per-step, failing-step and flaky-step stats, which
+	// cost one request per run.
+	SkipSteps bool `json:"skip_steps,omitempty" jsonschema:"Skip per-step stats (faster)"`
+}
+
+// statsConcurrency bounds the parallel step fetches.
+const statsConcurrency = 6
+
+// GetPipelineStats fetches recent runs (and, unless SkipSteps, their steps)
+// and computes their stats.
+func (c *Client) GetPipelineStats(args PipelineStatsArgs) (*PipelineStats, error) {
+	if args.Workspace == "" || args.RepoSlug == "" {
+		return nil, fmt.Errorf("workspace and repo_slug are required")
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	var runs []Pipeline
+	for page := 1; len(runs) < limit; page++ {
+		path := fmt.Sprintf("/repositories/%s/%s/pipelines?pagelen=100&page=%d&sort=-created_on",
+			QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), page)
+		if args.Branch != "" {
+			path += "&target.ref_name=" + QueryEscape(args.Branch)
+		}
+		result, err := GetPaginated[Pipeline](c, path)
+		if err != nil {
+			return nil, err
+		}
+		done := result.Next == "" || len(result.Values) == 0
+		for _, p := range result.Values {
+			if !args.Since.IsZero() && p.CreatedOn.Before(args.Since) {
+				done = true
+				break
+			}
+			if args.Branch != "" && (p.Target == nil || p.Target.RefName != args.Branch) {
+				continue
+			}
+			runs = append(runs, p)
+			if len(runs) == limit {
+				break
+			}
+		}
+		if done {
+			break
+		}
+	}
+
+	var steps map[string][]PipelineStep
+	if !args.SkipSteps {
+		var err error
+		if steps, err = c.fetchRunSteps(args.Workspace, args.RepoSlug, runs); err != nil {
+			return nil, err
+		}
+	}
+	return ComputePipelineStats(runs, steps), nil
+}
+
+// fetchRunSteps lists the steps of every completed run, statsConcurrency at a
+// time.
+func (c *Client) fetchRunSteps(workspace, repoSlug string, runs []Pipeline) (map[string][]PipelineStep, error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		sem      = make(chan struct{}, statsConcurrency)
+		steps    = make(map[string][]PipelineStep, len(runs))
+	)
+	for _, p := range runs {
+		if pipelineResult(p.State) == "" {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(uuid string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result, err := c.ListPipelineSteps(ListPipelineStepsArgs{Workspace: workspace, RepoSlug: repoSlug, PipelineUUID: uuid})
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to list steps for %s: %v", uuid, err)
+				}
+				return
+			}
+			steps[uuid] = result.Values
+		}(p.UUID)
+	}
+	wg.Wait()
+	return steps, firstErr
+}
+
+// ComputePipelineStats aggregates runs and their steps (keyed by pipeline
+// UUID; may be nil). Success rates count only runs that finished as
+// SUCCESSFUL or FAILED/ERROR — stopped and in-progress runs say nothing
+// about health. Build minutes use build_seconds_used, falling back to the
+// run duration.
+func ComputePipelineStats(runs []Pipeline, steps map[string][]PipelineStep) *PipelineStats {
+	st := &PipelineStats{Runs: len(runs), Pipelines: []PipelineGroupStats{}}
+
+	type group struct {
+		stats     PipelineGroupStats
+		durations []int
+	}
+	groups := map[string]*group{}
+	var durations []int
+	var buildSecs int
+
+	for _, p := range runs {
+		created := p.CreatedOn
+		if st.From == nil || created.Before(*st.From) {
+			st.From = &created
+		}
+		if st.To == nil || created.After(*st.To) {
+			st.To = &created
+		}
+
+		name := PipelineDefinitionName(p)
+		g := groups[name]
+		if g == nil {
+			g = &group{stats: PipelineGroupStats{Name: name}}
+			groups[name] = g
+		}
+		g.stats.Runs++
+
+		secs := p.BuildSecs
+		if secs == 0 {
+			secs = p.DurationSecs
+		}
+		buildSecs += secs
+		g.stats.BuildMinutes += float64(secs) / 60
+
+		switch pipelineResult(p.State) {
+		case "SUCCESSFUL":
+			st.Successful++
+			g.stats.Successful++
+		case "FAILED", "ERROR":
+			st.Failed++
+			g.stats.Failed++
+		case "STOPPED":
+			st.Stopped++
+			continue
+		case "":
+			st.InProgress++
+			continue
+		default:
+			continue
+		}
+		durations = append(durations, p.DurationSecs)
+		g.durations = append(g.durations, p.DurationSecs)
+	}
+
+	st.SuccessRate = rate(st.Successful, st.Successful+st.Failed)
+	st.P50Secs, st.P95Secs = percentile(durations, 50), percentile(durations, 95)
+	st.BuildMinutes = roundTo(float64(buildSecs)/60, 1)
+
+	for _, g := range groups {
+		g.stats.SuccessRate = rate(g.stats.Successful, g.stats.Successful+g.stats.Failed)
+		g.stats.P50Secs, g.stats.P95Secs = percentile(g.durations, 50), percentile(g.durations, 95)
+		g.stats.BuildMinutes = roundTo(g.stats.BuildMinutes, 1)
+		st.Pipelines = append(st.Pipelines, g.stats)
+	}
+	sort.Slice(st.Pipelines, func(i, j int) bool {
+		if st.Pipelines[i].Runs != st.Pipelines[j].Runs {
+			return st.Pipelines[i].Runs > st.Pipelines[j].Runs
+		}
+		return st.Pipelines[i].Name < st.Pipelines[j].Name
+	})
+
+	if steps != nil {
+		computeStepStats(st, runs, steps)
+	}
+	return st
+}
+
+func computeStepStats(st *PipelineStats, runs []Pipeline, steps map[string][]PipelineStep) {
+	type stepAcc struct {
+		stats     StepStats
+		durations []int
+	}
+	type outcome struct{ fails, passes int }
+	byName := map[string]*stepAcc{}
+	byCommit := map[[2]string]*outcome{}
+
+	for _, p := range runs {
+		commit := ""
+		if p.Target != nil && p.Target.Commit != nil {
+			commit = p.Target.Commit.Hash
+		}
+		for _, s := range steps[p.UUID] {
+			res := pipelineResult(s.State)
+			failed := res == "FAILED" || res == "ERROR"
+			if !failed && res != "SUCCESSFUL" {
+				continue
+			}
+			name := s.Name
+			if name == "" {
+				name = "(unnamed)"
+			}
+			acc := byName[name]
+			if acc == nil {
+				acc = &stepAcc{stats: StepStats{Name: name}}
+				byName[name] = acc
+			}
+			acc.stats.Runs++
+			acc.durations = append(acc.durations, s.DurationSecs)
+			if failed {
+				acc.stats.Failures++
+			}
+
+			if commit == "" {
+				continue
+			}
+			key := [2]string{name, commit}
+			o := byCommit[key]
+			if o == nil {
+				o = &outcome{}
+				byCommit[key] = o
+			}
+			if failed {
+				o.fails++
+			} else {
+				o.passes++
+			}
+		}
+	}
+
+	for _, acc := range byName {
+		acc.stats.FailureRate = rate(acc.stats.Failures, acc.stats.Runs)
+		acc.stats.P50Secs, acc.stats.P95Secs = percentile(acc.durations, 50), percentile(acc.durations, 95)
+		st.Steps = append(st.Steps, acc.stats)
+		if acc.stats.Failures > 0 {
+			st.FailingSteps = append(st.FailingSteps, acc.stats)
+		}
+	}
+	sort.Slice(st.Steps, func(i, j int) bool { return st.Steps[i].Name < st.Steps[j].Name })
+	sort.Slice(st.FailingSteps, func(i, j int) bool {
+		a, b := st.FailingSteps[i], st.FailingSteps[j]
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		return a.Name < b.Name
+	})
+	if len(st.FailingSteps) > 10 {
+		st.FailingSteps = st.FailingSteps[:10]
+	}
+
+	for key, o := range byCommit {
+		if o.fails > 0 && o.passes > 0 {
+			st.FlakySteps = append(st.FlakySteps, FlakyStep{Name: key[0], Commit: key[1], Failures: o.fails, Passes: o.passes})
+		}
+	}
+	sort.Slice(st.FlakySteps, func(i, j int) bool {
+		a, b := st.FlakySteps[i], st.FlakySteps[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Commit < b.Commit
+	})
+}
+
+// percentile returns the nearest-rank p-th percentile of values, or 0 when
+// there are none.
+func percentile(values []int, p float64) int {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// rate returns n/total as a fraction rounded to three places, or 0.
+func rate(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return roundTo(float64(n)/float64(total), 3)
+}
+
+func roundTo(v float64, places int) float64 {
+	f := math.Pow(10, float64(places))
+	return math.Round(v*f) / f
+}
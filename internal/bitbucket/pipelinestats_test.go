@@ -0,0 +1,87 @@
+package bitbucket
+
+import (
+	"testing"
+	"time"
+)
+
+func statsRun(uuid, result, commit string, secs int, selector *PipelineSelector) Pipeline {
+	p := Pipeline{
+		UUID:         uuid,
+		CreatedOn:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		DurationSecs: secs,
+		Target:       &PipeTarget{RefName: "main", Commit: &Commit{Hash: commit}, Selector: selector},
+		State:        &PipeState{Name: "COMPLETED"},
+	}
+	if result != "" {
+		p.State.Result = &PipeResult{Name: result}
+	}
+	return p
+}
+
+func statsStep(name, result string, secs int) PipelineStep {
+	return PipelineStep{Name: name, DurationSecs: secs, State: &PipeState{Result: &PipeResult{Name: result}}}
+}
+
+func TestComputePipelineStats(t *testing.T) {
+	custom := &PipelineSelector{Type: "custom", Pattern: "deploy"}
+	runs := []Pipeline{
+		statsRun("1", "SUCCESSFUL", "aaa", 100, nil),
+		statsRun("2", "FAILED", "aaa", 200, nil),
+		statsRun("3", "SUCCESSFUL", "bbb", 300, nil),
+		statsRun("4", "STOPPED", "ccc", 50, nil),
+		statsRun("5", "", "ddd", 0, nil),
+		statsRun("6", "SUCCESSFUL", "eee", 400, custom),
+	}
+	runs[0].BuildSecs = 120
+	steps := map[string][]PipelineStep{
+		"1": {statsStep("test", "SUCCESSFUL", 60)},
+		"2": {statsStep("test", "FAILED", 90), statsStep("lint", "NOT_RUN", 0)},
+		"3": {statsStep("test", "FAILED", 30)},
+	}
+
+	st := ComputePipelineStats(runs, steps)
+
+	if st.Runs != 6 || st.Successful != 3 || st.Failed != 1 || st.Stopped != 1 || st.InProgress != 1 {
+		t.Errorf("counts = %+v", st)
+	}
+	if st.SuccessRate != 0.75 {
+		t.Errorf("SuccessRate = %v, want 0.75 (stopped and running excluded)", st.SuccessRate)
+	}
+	if st.P50Secs != 200 || st.P95Secs != 400 {
+		t.Errorf("p50/p95 = %d/%d, want 200/400", st.P50Secs, st.P95Secs)
+	}
+	// 120 (build_seconds_used) + 200 + 300 + 50 + 0 + 400 seconds.
+	if st.BuildMinutes != 17.8 {
+		t.Errorf("BuildMinutes = %v, want 17.8", st.BuildMinutes)
+	}
+	if len(st.Pipelines) != 2 || st.Pipelines[0].Name != "default" || st.Pipelines[1].Name != "custom: deploy" {
+		t.Errorf("Pipelines = %+v", st.Pipelines)
+	}
+
+	if len(st.Steps) != 1 || st.Steps[0].Runs != 3 || st.Steps[0].Failures != 2 {
+		t.Errorf("Steps = %+v (NOT_RUN should be ignored)", st.Steps)
+	}
+	if len(st.FailingSteps) != 1 || st.FailingSteps[0].Name != "test" {
+		t.Errorf("FailingSteps = %+v", st.FailingSteps)
+	}
+	if len(st.FlakySteps) != 1 || st.FlakySteps[0].Commit != "aaa" || st.FlakySteps[0].Failures != 1 || st.FlakySteps[0].Passes != 1 {
+		t.Errorf("FlakySteps = %+v, want test on aaa", st.FlakySteps)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	vals := []int{5, 1, 4, 2, 3}
+	if got := percentile(vals, 50); got != 3 {
+		t.Errorf("p50 = %d, want 3", got)
+	}
+	if got := percentile(vals, 95); got != 5 {
+		t.Errorf("p95 = %d, want 5", got)
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("p50 of nothing = %d", got)
+	}
+	if vals[0] != 5 {
+		t.Error("percentile must not reorder its input")
+	}
+}
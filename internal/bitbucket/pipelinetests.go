@@ -0,0 +1,269 @@
+package bitbucket
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TestReportSummary is the per-step totals from the test_reports endpoint.
+type TestReportSummary struct {
+	Total   int `json:"total_number_of_test_cases"`
+	Passed  int `json:"number_of_successful_test_cases"`
+	Failed  int `json:"number_of_failed_test_cases"`
+	Errored int `json:"number_of_error_test_cases"`
+	Skipped int `json:"number_of_skipped_test_cases"`
+}
+
+// TestCase is a single test result parsed from a step's JUnit reports.
+type TestCase struct {
+	UUID               string           `json:"uuid"`
+	Name               string           `json:"name"`
+	FullyQualifiedName string           `json:"fully_qualified_name,omitempty"`
+	PackageName        string           `json:"package_name,omitempty"`
+	Status             string           `json:"status"` // PASSED, FAILED, ERROR, SKIPPED
+	Reasons            []TestCaseReason `json:"reasons,omitempty"`
+}
+
+// TestCaseReason is the failure message (and usually a stack trace) recorded
+// for a failed or errored test case.
+type TestCaseReason struct {
+	Message    string `json:"message"`
+	StackTrace string `json:"stack_trace,omitempty"`
+}
+
+// LogFailure is what the heuristic log extractor could recover from a step
+// that published no test reports.
+type LogFailure struct {
+	ExitCode      *int     `json:"exit_code,omitempty"`
+	ExitLine      string   `json:"exit_line,omitempty"`
+	FailedCommand string   `json:"failed_command,omitempty"`
+	ErrorBlock    []string `json:"error_block,omitempty"`
+}
+
+// StepTestResult combines a step's state with either its test report or, when
+// the step failed without one, the failure extracted from its log.
+type StepTestResult struct {
+	StepUUID    string             `json:"step_uuid"`
+	StepName    string             `json:"step_name"`
+	State       string             `json:"state"`
+	Source      string             `json:"source,omitempty"` // test_reports, log, or empty when nothing was found
+	Summary     *TestReportSummary `json:"summary,omitempty"`
+	FailedTests []TestCase         `json:"failed_tests,omitempty"`
+	Truncated   bool               `json:"truncated,omitempty"`
+	Log         *LogFailure        `json:"log,omitempty"`
+	Note        string             `json:"note,omitempty"`
+}
+
+// Failing reports whether the step failed or has failing tests.
+func (s *StepTestResult) Failing() bool {
+	if s.Summary != nil && s.Summary.Failed+s.Summary.Errored > 0 {
+		return true
+	}
+	return stepFailed(s.State)
+}
+
+type GetPipelineTestResultsArgs struct {
+	Workspace    string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug     string `json:"repo_slug" jsonschema:"Repository slug"`
+	PipelineUUID string `json:"pipeline_uuid" jsonschema:"Pipeline UUID"`
+	Step         string `json:"step,omitempty" jsonschema:"Limit to one step (UUID or name)"`
+	MaxFailures  int    `json:"max_failures,omitempty" jsonschema:"Maximum failed test cases to return per step (default 50)"`
+}
+
+// maxTestCasePages bounds the walk over a step's test cases. Reports with
+// more than a few thousand cases are rare, and we only keep the failures.
+const maxTestCasePages = 20
+
+// GetPipelineTestResults returns test results for each step of a pipeline.
+// Steps with test reports get a summary plus their failed test cases and
+// reasons; failed steps without reports fall back to ExtractLogFailure over
+// the step log.
+func (c *Client) GetPipelineTestResults(args GetPipelineTestResultsArgs) ([]StepTestResult, error) {
+	if args.Workspace == "" || args.RepoSlug == "" || args.PipelineUUID == "" {
+		return nil, fmt.Errorf("workspace, repo_slug, and pipeline_uuid are required")
+	}
+	maxFailures := args.MaxFailures
+	if maxFailures == 0 {
+		maxFailures = 50
+	}
+
+	steps, err := c.ListPipelineSteps(ListPipelineStepsArgs{
+		Workspace:    args.Workspace,
+		RepoSlug:     args.RepoSlug,
+		PipelineUUID: args.PipelineUUID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list steps: %v", err)
+	}
+
+	var results []StepTestResult
+	for _, step := range steps.Values {
+		if args.Step != "" && step.UUID != args.Step && !strings.EqualFold(step.Name, args.Step) {
+			continue
+		}
+		r, err := c.stepTestResult(args, step, maxFailures)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *r)
+	}
+	if args.Step != "" && len(results) == 0 {
+		return nil, fmt.Errorf("step %q not found in pipeline %s", args.Step, args.PipelineUUID)
+	}
+	return results, nil
+}
+
+func (c *Client) stepTestResult(args GetPipelineTestResultsArgs, step PipelineStep, maxFailures int) (*StepTestResult, error) {
+	r := &StepTestResult{StepUUID: step.UUID, StepName: step.Name, State: stepStateName(step.State)}
+	base := fmt.Sprintf("/repositories/%s/%s/pipelines/%s/steps/%s/test_reports",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), args.PipelineUUID, step.UUID)
+
+	summary, err := GetJSON[TestReportSummary](c, base)
+	switch {
+	case err != nil && !isNotFound(err):
+		return nil, fmt.Errorf("failed to get test report for step %s: %v", step.Name, err)
+	case err == nil && summary.Total > 0:
+		r.Source = "test_reports"
+		r.Summary = summary
+		if summary.Failed+summary.Errored > 0 {
+			if err := c.collectFailedTests(r, base, maxFailures); err != nil {
+				return nil, err
+			}
+		}
+		return r, nil
+	}
+
+	if !stepFailed(r.State) {
+		return r, nil
+	}
+	raw, err := c.GetPipelineStepLog(GetPipelineStepLogArgs{
+		Workspace:    args.Workspace,
+		RepoSlug:     args.RepoSlug,
+		PipelineUUID: args.PipelineUUID,
+		StepUUID:     step.UUID,
+	})
+	if err != nil {
+		r.Note = fmt.Sprintf("no test reports, and the step log is unavailable: %v", err)
+		return r, nil
+	}
+	r.Source = "log"
+	r.Log = ExtractLogFailure(raw)
+	return r, nil
+}
+
+func (c *Client) collectFailedTests(r *StepTestResult, base string, maxFailures int) error {
+	for page := 1; page <= maxTestCasePages; page++ {
+		cases, err := GetPaginated[TestCase](c, fmt.Sprintf("%s/test_cases?pagelen=100&page=%d", base, page))
+		if err != nil {
+			return fmt.Errorf("failed to list test cases for step %s: %v", r.StepName, err)
+		}
+		for _, tc := range cases.Values {
+			if tc.Status != "FAILED" && tc.Status != "ERROR" {
+				continue
+			}
+			if len(r.FailedTests) == maxFailures {
+				r.Truncated = true
+				return nil
+			}
+			reasons, err := GetPaginated[TestCaseReason](c, fmt.Sprintf("%s/test_cases/%s/test_case_reasons", base, tc.UUID))
+			if err == nil {
+				tc.Reasons = reasons.Values
+			}
+			r.FailedTests = append(r.FailedTests, tc)
+		}
+		if cases.Next == "" {
+			return nil
+		}
+	}
+	r.Truncated = true
+	return nil
+}
+
+func stepStateName(s *PipeState) string {
+	if s == nil {
+		return ""
+	}
+	if s.Result != nil {
+		return s.Result.Name
+	}
+	return s.Name
+}
+
+func stepFailed(state string) bool {
+	return state == "FAILED" || state == "ERROR"
+}
+
+var (
+	ansiEscape  = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+	exitLineRe  = regexp.MustCompile(`(?i)(?:exit(?:ed)?(?: with)?(?: status| code)|exit code|returned)\s*[:=]?\s*(\d+)`)
+	errorLineRe = regexp.MustCompile(`(?i)\b(error|errors|fail|failed|failure|fatal|panic|exception|traceback)\b`)
+)
+
+// maxErrorBlockLines caps the error block so agents get a compact excerpt
+// rather than the whole tail of a multi-megabyte log.
+const maxErrorBlockLines = 40
+
+// ExtractLogFailure pulls the interesting part out of a failed step's log: the
+// last non-zero exit code line, the command that produced it ("+ cmd" lines
+// are how Pipelines echoes script commands), and the last block of
+// error-looking output before it. It is a heuristic; when nothing matches it
+// returns the last few lines of output.
+func ExtractLogFailure(log []byte) *LogFailure {
+	text := ansiEscape.ReplaceAllString(string(log), "")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+
+	out := &LogFailure{}
+	end := len(lines)
+	for i := len(lines) - 1; i >= 0; i-- {
+		m := exitLineRe.FindStringSubmatch(lines[i])
+		if m == nil || m[1] == "0" {
+			continue
+		}
+		code := 0
+		fmt.Sscanf(m[1], "%d", &code)
+		out.ExitCode = &code
+		out.ExitLine = strings.TrimSpace(lines[i])
+		end = i
+		break
+	}
+
+	// The failed command is the last one echoed before the exit line.
+	cmdStart := 0
+	for i := end - 1; i >= 0; i-- {
+		if strings.HasPrefix(lines[i], "+ ") {
+			out.FailedCommand = strings.TrimPrefix(lines[i], "+ ")
+			cmdStart = i + 1
+			break
+		}
+	}
+
+	// Anchor the block on the last error-looking line of that command's
+	// output, then widen backwards to the paragraph it belongs to.
+	last := -1
+	for i := end - 1; i >= cmdStart; i-- {
+		if errorLineRe.MatchString(lines[i]) {
+			last = i
+			break
+		}
+	}
+	var from, to int
+	if last >= 0 {
+		from, to = last, last+1
+		for from > cmdStart && strings.TrimSpace(lines[from-1]) != "" && last-from < maxErrorBlockLines/2 {
+			from--
+		}
+		for to < end && strings.TrimSpace(lines[to]) != "" && to-from < maxErrorBlockLines {
+			to++
+		}
+	} else {
+		from, to = max(cmdStart, end-maxErrorBlockLines/2), end
+	}
+	for _, l := range lines[from:to] {
+		if strings.TrimSpace(l) != "" {
+			out.ErrorBlock = append(out.ErrorBlock, l)
+		}
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+package bitbucket
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestExtractLogFailure(t *testing.T) {
+	log := strings.Join([]string{
+		"+ go mod download",
+		"+ go test ./...",
+		"ok  \texample.com/a\t0.01s",
+		"--- FAIL: TestThing (0.00s)",
+		"    thing_test.go:12: got 1, want 2",
+		"FAIL",
+		"FAIL\texample.com/b\t0.02s",
+		"",
+		"\x1b[31mScript exited with exit code 1\x1b[0m",
+		"Searching for test report files in directories named [test-results]",
+	}, "\r\n")
+
+	f := ExtractLogFailure([]byte(log))
+	if f.ExitCode == nil || *f.ExitCode != 1 {
+		t.Fatalf("exit code = %v, want 1", f.ExitCode)
+	}
+	if f.ExitLine != "Script exited with exit code 1" {
+		t.Errorf("exit line = %q (ANSI codes should be stripped)", f.ExitLine)
+	}
+	if f.FailedCommand != "go test ./..." {
+		t.Errorf("failed command = %q", f.FailedCommand)
+	}
+	if len(f.ErrorBlock) == 0 || f.ErrorBlock[0] != "ok  \texample.com/a\t0.01s" || f.ErrorBlock[len(f.ErrorBlock)-1] != "FAIL\texample.com/b\t0.02s" {
+		t.Errorf("error block = %q", f.ErrorBlock)
+	}
+}
+
+func TestExtractLogFailure_NoMarkers(t *testing.T) {
+	f := ExtractLogFailure([]byte("+ ./deploy.sh\nuploading\ndone?\n"))
+	if f.ExitCode != nil {
+		t.Errorf("exit code = %d, want none", *f.ExitCode)
+	}
+	if f.FailedCommand != "./deploy.sh" || len(f.ErrorBlock) != 2 {
+		t.Errorf("got %+v, want the tail after the last command", f)
+	}
+}
+
+func TestGetPipelineTestResults(t *testing.T) {
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		p := r.URL.Path
+		switch {
+		case strings.HasSuffix(p, "/pipelines/{p}/steps"):
+			_, _ = w.Write([]byte(`{"values":[
+				{"uuid":"{s1}","name":"Unit","state":{"name":"COMPLETED","result":{"name":"FAILED"}}},
+				{"uuid":"{s2}","name":"Build","state":{"name":"COMPLETED","result":{"name":"FAILED"}}},
+				{"uuid":"{s3}","name":"Lint","state":{"name":"COMPLETED","result":{"name":"SUCCESSFUL"}}}]}`))
+		case strings.HasSuffix(p, "/steps/{s1}/test_reports"):
+			_, _ = w.Write([]byte(`{"total_number_of_test_cases":3,"number_of_successful_test_cases":2,"number_of_failed_test_cases":1}`))
+		case strings.HasSuffix(p, "/steps/{s1}/test_reports/test_cases"):
+			_, _ = w.Write([]byte(`{"values":[
+				{"uuid":"{t1}","name":"TestOK","status":"PASSED"},
+				{"uuid":"{t2}","name":"TestBad","fully_qualified_name":"pkg.TestBad","status":"FAILED"}]}`))
+		case strings.HasSuffix(p, "/test_cases/{t2}/test_case_reasons"):
+			_, _ = w.Write([]byte(`{"values":[{"message":"expected 2, got 1"}]}`))
+		case strings.HasSuffix(p, "/steps/{s2}/log"):
+			_, _ = w.Write([]byte("+ make\nmake: *** [all] Error 2\nexit code 2\n"))
+		default:
+			http.Error(w, `{"error":{"message":"not found"}}`, http.StatusNotFound)
+		}
+	})
+
+	results, err := c.GetPipelineTestResults(GetPipelineTestResultsArgs{Workspace: "w", RepoSlug: "r", PipelineUUID: "{p}"})
+	if err != nil {
+		t.Fatalf("GetPipelineTestResults: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d steps, want 3", len(results))
+	}
+
+	unit := results[0]
+	if unit.Source != "test_reports" || unit.Summary.Failed != 1 || len(unit.FailedTests) != 1 {
+		t.Fatalf("unit step = %+v", unit)
+	}
+	if tc := unit.FailedTests[0]; tc.Name != "TestBad" || len(tc.Reasons) != 1 || tc.Reasons[0].Message != "expected 2, got 1" {
+		t.Errorf("failed test = %+v", tc)
+	}
+
+	build := results[1]
+	if build.Source != "log" || build.Log == nil || build.Log.ExitCode == nil || *build.Log.ExitCode != 2 || build.Log.FailedCommand != "make" {
+		t.Errorf("build step should fall back to the log: %+v", build)
+	}
+
+	lint := results[2]
+	if lint.Source != "" || lint.Failing() {
+		t.Errorf("passing step without reports should be empty: %+v", lint)
+	}
+
+	only, err := c.GetPipelineTestResults(GetPipelineTestResultsArgs{Workspace: "w", RepoSlug: "r", PipelineUUID: "{p}", Step: "build"})
+	if err != nil || len(only) != 1 || only[0].StepUUID != "{s2}" {
+		t.Errorf("step filter by name: %v %+v", err, only)
+	}
+}
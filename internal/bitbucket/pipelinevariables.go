@@ -0,0 +1,88 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// maxVariablePages bounds ListPipelineVariables.
+const maxVariablePages = 10
+
+// ListPipelineVariables lists a repository's Pipelines variables. Values of
+// secured variables are not returned.
+func (c *Client) ListPipelineVariables(args PipelinesConfigArgs) ([]PipelineVariable, error) {
+	path, err := args.path("/variables")
+	if err != nil {
+		return nil, err
+	}
+
+	out := []PipelineVariable{}
+	for page := 1; page <= maxVariablePages; page++ {
+		result, err := GetPaginated[PipelineVariable](c, fmt.Sprintf("%s?pagelen=100&page=%d", path, page))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, result.Values...)
+		if result.Next == "" {
+			break
+		}
+	}
+	return out, nil
+}
+
+// CreatePipelineVariable adds a repository variable.
+func (c *Client) CreatePipelineVariable(args PipelinesConfigArgs, v PipelineVariable) (*PipelineVariable, error) {
+	path, err := args.path("/variables")
+	if err != nil {
+		return nil, err
+	}
+	if v.Key == "" {
+		return nil, fmt.Errorf("key is required")
+	}
+
+	respData, err := c.Post(path, map[string]any{"key": v.Key, "value": v.Value, "secured": v.Secured})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create variable: %v", err)
+	}
+
+	var out PipelineVariable
+	if err := json.Unmarshal(respData, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &out, nil
+}
+
+// UpdatePipelineVariable replaces the value and secured flag of the variable
+// identified by v.UUID.
+func (c *Client) UpdatePipelineVariable(args PipelinesConfigArgs, v PipelineVariable) (*PipelineVariable, error) {
+	if v.UUID == "" {
+		return nil, fmt.Errorf("variable uuid is required")
+	}
+	path, err := args.path("/variables/" + QueryEscape(normalizeUUID(v.UUID)))
+	if err != nil {
+		return nil, err
+	}
+
+	respData, err := c.Put(path, map[string]any{"key": v.Key, "value": v.Value, "secured": v.Secured})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update variable: %v", err)
+	}
+
+	var out PipelineVariable
+	if err := json.Unmarshal(respData, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &out, nil
+}
+
+// DeletePipelineVariable deletes a repository variable by UUID.
+func (c *Client) DeletePipelineVariable(args PipelinesConfigArgs, uuid string) error {
+	if uuid == "" {
+		return fmt.Errorf("variable uuid is required")
+	}
+	path, err := args.path("/variables/" + QueryEscape(normalizeUUID(uuid)))
+	if err != nil {
+		return err
+	}
+	return c.Delete(path)
+}
@@ -0,0 +1,251 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type ListProjectsArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	Query     string `json:"query,omitempty" jsonschema:"Bitbucket query filter (e.g. name~\"platform\")"`
+	Sort      string `json:"sort,omitempty" jsonschema:"Sort field (e.g. -updated_on)"`
+	Pagelen   int    `json:"pagelen,omitempty" jsonschema:"Results per page (default 25)"`
+	Page      int    `json:"page,omitempty" jsonschema:"Page number"`
+}
+
+// ListProjects lists the projects in a workspace.
+func (c *Client) ListProjects(args ListProjectsArgs) (*Paginated[Project], error) {
+	if args.Workspace == "" {
+		return nil, fmt.Errorf("workspace is required")
+	}
+
+	pagelen := args.Pagelen
+	if pagelen == 0 {
+		pagelen = 25
+	}
+	page := args.Page
+	if page == 0 {
+		page = 1
+	}
+
+	path := fmt.Sprintf("/workspaces/%s/projects?pagelen=%d&page=%d", QueryEscape(args.Workspace), pagelen, page)
+	if args.Query != "" {
+		path += "&q=" + QueryEscape(args.Query)
+	}
+	if args.Sort != "" {
+		path += "&sort=" + QueryEscape(args.Sort)
+	}
+
+	return GetPaginated[Project](c, path)
+}
+
+type ProjectArgs struct {
+	Workspace  string `json:"workspace" jsonschema:"Workspace slug"`
+	ProjectKey string `json:"project_key" jsonschema:"Project key"`
+}
+
+func (a ProjectArgs) path() (string, error) {
+	if a.Workspace == "" || a.ProjectKey == "" {
+		return "", fmt.Errorf("workspace and project_key are required")
+	}
+	return fmt.Sprintf("/workspaces/%s/projects/%s", QueryEscape(a.Workspace), QueryEscape(a.ProjectKey)), nil
+}
+
+// GetProject returns a single project.
+func (c *Client) GetProject(args ProjectArgs) (*Project, error) {
+	path, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	return GetJSON[Project](c, path)
+}
+
+type CreateProjectArgs struct {
+	Workspace   string `json:"workspace" jsonschema:"Workspace slug"`
+	Key         string `json:"key" jsonschema:"Project key (letters, numbers and underscores, e.g. PLAT)"`
+	Name        string `json:"name" jsonschema:"Project name"`
+	Description string `json:"description,omitempty" jsonschema:"Project description"`
+	IsPrivate   *bool  `json:"is_private,omitempty" jsonschema:"Whether the project is private (default true)"`
+}
+
+// CreateProject creates a project in a workspace.
+func (c *Client) CreateProject(args CreateProjectArgs) (*Project, error) {
+	if args.Workspace == "" || args.Key == "" || args.Name == "" {
+		return nil, fmt.Errorf("workspace, key, and name are required")
+	}
+
+	body := map[string]any{
+		"key":        args.Key,
+		"name":       args.Name,
+		"is_private": true,
+	}
+	if args.IsPrivate != nil {
+		body["is_private"] = *args.IsPrivate
+	}
+	if args.Description != "" {
+		body["description"] = args.Description
+	}
+
+	data, err := c.Post(fmt.Sprintf("/workspaces/%s/projects", QueryEscape(args.Workspace)), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create project: %v", err)
+	}
+	return unmarshalProject(data)
+}
+
+type UpdateProjectArgs struct {
+	Workspace   string  `json:"workspace" jsonschema:"Workspace slug"`
+	ProjectKey  string  `json:"project_key" jsonschema:"Project key"`
+	Key         *string `json:"key,omitempty" jsonschema:"New project key"`
+	Name        *string `json:"name,omitempty" jsonschema:"New project name"`
+	Description *string `json:"description,omitempty" jsonschema:"New description (empty clears it)"`
+	IsPrivate   *bool   `json:"is_private,omitempty" jsonschema:"Make the project private or public"`
+}
+
+// UpdateProject changes the fields that are set. Bitbucket's PUT replaces
+// the project, so the current key and name are sent when not changing.
+func (c *Client) UpdateProject(args UpdateProjectArgs) (*Project, error) {
+	path, err := ProjectArgs{Workspace: args.Workspace, ProjectKey: args.ProjectKey}.path()
+	if err != nil {
+		return nil, err
+	}
+	if args.Key == nil && args.Name == nil && args.Description == nil && args.IsPrivate == nil {
+		return nil, fmt.Errorf("nothing to update")
+	}
+
+	cur, err := GetJSON[Project](c, path)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]any{"key": cur.Key, "name": cur.Name}
+	if args.Key != nil {
+		body["key"] = *args.Key
+	}
+	if args.Name != nil {
+		body["name"] = *args.Name
+	}
+	if args.Description != nil {
+		body["description"] = *args.Description
+	}
+	if args.IsPrivate != nil {
+		body["is_private"] = *args.IsPrivate
+	}
+
+	data, err := c.Put(path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update project: %v", err)
+	}
+	return unmarshalProject(data)
+}
+
+// DeleteProject deletes an empty project.
+func (c *Client) DeleteProject(args ProjectArgs) error {
+	path, err := args.path()
+	if err != nil {
+		return err
+	}
+	return c.Delete(path)
+}
+
+// ListProjectRepositories lists the repositories in a project.
+func (c *Client) ListProjectRepositories(args ProjectArgs, page, pagelen int) (*Paginated[Repository], error) {
+	if _, err := args.path(); err != nil {
+		return nil, err
+	}
+	return c.ListRepositories(ListRepositoriesArgs{
+		Workspace: args.Workspace,
+		Query:     fmt.Sprintf("project.key=%q", args.ProjectKey),
+		Page:      page,
+		Pagelen:   pagelen,
+	})
+}
+
+// ProjectDefaultReviewer is a user added to new pull requests in every
+// repository of a project. ReviewerType is "project" or "repository".
+type ProjectDefaultReviewer struct {
+	ReviewerType string `json:"reviewer_type"`
+	User         User   `json:"user"`
+}
+
+// maxProjectPages bounds the project list-all loops.
+const maxProjectPages = 10
+
+// ListProjectDefaultReviewers lists a project's default reviewers.
+func (c *Client) ListProjectDefaultReviewers(args ProjectArgs) ([]ProjectDefaultReviewer, error) {
+	path, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	return listAllPages[ProjectDefaultReviewer](c, path+"/default-reviewers", maxProjectPages)
+}
+
+// AddProjectDefaultReviewer adds a project default reviewer by UUID or
+// account ID.
+func (c *Client) AddProjectDefaultReviewer(args ProjectArgs, user string) error {
+	path, err := args.path()
+	if err != nil {
+		return err
+	}
+	if user == "" {
+		return fmt.Errorf("user is required")
+	}
+	if _, err := c.Put(path+"/default-reviewers/"+QueryEscape(normalizeReviewer(user)), map[string]any{}); err != nil {
+		return fmt.Errorf("failed to add default reviewer: %v", err)
+	}
+	return nil
+}
+
+// RemoveProjectDefaultReviewer removes a project default reviewer by UUID
+// or account ID.
+func (c *Client) RemoveProjectDefaultReviewer(args ProjectArgs, user string) error {
+	path, err := args.path()
+	if err != nil {
+		return err
+	}
+	if user == "" {
+		return fmt.Errorf("user is required")
+	}
+	return c.Delete(path + "/default-reviewers/" + QueryEscape(normalizeReviewer(user)))
+}
+
+// ProjectPermissions are the levels a user or group can be granted on a
+// project.
+var ProjectPermissions = []string{"read", "write", "create-repo", "admin"}
+
+// ListProjectPermissions lists the explicit user permissions followed by
+// the group permissions on a project.
+func (c *Client) ListProjectPermissions(args ProjectArgs) ([]Permission, error) {
+	path, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	return c.listPermissionConfigs(path)
+}
+
+// SetProjectPermission grants a user or group a permission on a project,
+// replacing any explicit permission it already has.
+func (c *Client) SetProjectPermission(args ProjectArgs, kind, subject, permission string) (*Permission, error) {
+	path, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	return c.setPermissionConfig(path, kind, subject, permission, ProjectPermissions)
+}
+
+// RemoveProjectPermission removes a user's or group's explicit permission
+// on a project.
+func (c *Client) RemoveProjectPermission(args ProjectArgs, kind, subject string) error {
+	path, err := args.path()
+	if err != nil {
+		return err
+	}
+	return c.removePermissionConfig(path, kind, subject)
+}
+
+func unmarshalProject(data []byte) (*Project, error) {
+	var p Project
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &p, nil
+}
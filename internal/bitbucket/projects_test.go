@@ -0,0 +1,121 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCreateProject_DefaultsToPrivate(t *testing.T) {
+	var bodies []map[string]any
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		_ = json.Unmarshal(b, &body)
+		bodies = append(bodies, body)
+		_, _ = w.Write([]byte(`{"key": "PLAT", "name": "Platform"}`))
+	})
+
+	public := false
+	for _, args := range []CreateProjectArgs{
+		{Workspace: "w", Key: "PLAT", Name: "Platform"},
+		{Workspace: "w", Key: "PLAT", Name: "Platform", IsPrivate: &public},
+	} {
+		if _, err := c.CreateProject(args); err != nil {
+			t.Fatalf("CreateProject: %v", err)
+		}
+	}
+	if bodies[0]["is_private"] != true || bodies[1]["is_private"] != false {
+		t.Errorf("bodies = %v", bodies)
+	}
+}
+
+func TestUpdateProject(t *testing.T) {
+	var calls []string
+	var body map[string]any
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPut {
+			b, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(b, &body)
+		}
+		_, _ = w.Write([]byte(`{"key": "PLAT", "name": "Platform", "is_private": true}`))
+	})
+
+	desc := "Shared services"
+	p, err := c.UpdateProject(UpdateProjectArgs{Workspace: "w", ProjectKey: "PLAT", Description: &desc})
+	if err != nil {
+		t.Fatalf("UpdateProject: %v", err)
+	}
+	if strings.Join(calls, "|") != "GET /workspaces/w/projects/PLAT|PUT /workspaces/w/projects/PLAT" {
+		t.Errorf("calls = %v", calls)
+	}
+	if body["key"] != "PLAT" || body["name"] != "Platform" || body["description"] != desc || body["is_private"] != nil {
+		t.Errorf("body = %v", body)
+	}
+	if !p.IsPrivate {
+		t.Errorf("project = %+v", p)
+	}
+
+	if _, err := c.UpdateProject(UpdateProjectArgs{Workspace: "w", ProjectKey: "PLAT"}); err == nil {
+		t.Error("UpdateProject with nothing to update succeeded")
+	}
+}
+
+func TestProjectPermissions(t *testing.T) {
+	var calls []string
+	var body map[string]any
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.EscapedPath())
+		switch {
+		case r.Method == http.MethodPut:
+			b, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(b, &body)
+			_, _ = w.Write([]byte(`{"permission": "write", "group": {"slug": "devs"}}`))
+		case strings.HasSuffix(r.URL.Path, "/users"):
+			_, _ = w.Write([]byte(`{"values": [{"permission": "admin", "user": {"display_name": "Alice"}}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"values": [{"permission": "read", "group": {"slug": "everyone"}}]}`))
+		}
+	})
+	args := ProjectArgs{Workspace: "w", ProjectKey: "PLAT"}
+
+	perms, err := c.ListProjectPermissions(args)
+	if err != nil {
+		t.Fatalf("ListProjectPermissions: %v", err)
+	}
+	if len(perms) != 2 {
+		t.Fatalf("perms = %+v", perms)
+	}
+	if kind, name := perms[0].Subject(); kind != PermissionUser || name != "Alice" {
+		t.Errorf("first subject = %s %s", kind, name)
+	}
+	if kind, name := perms[1].Subject(); kind != PermissionGroup || name != "everyone" {
+		t.Errorf("second subject = %s %s", kind, name)
+	}
+
+	calls = nil
+	if _, err := c.SetProjectPermission(args, PermissionGroup, "devs", "write"); err != nil {
+		t.Fatalf("SetProjectPermission: %v", err)
+	}
+	if calls[0] != "PUT /workspaces/w/projects/PLAT/permissions-config/groups/devs" || body["permission"] != "write" {
+		t.Errorf("request = %v %v", calls, body)
+	}
+
+	calls = nil
+	if err := c.RemoveProjectPermission(args, PermissionUser, "0b4a6b6e-64b5-4a4e-9a3e-1c3b2f7f2a10"); err != nil {
+		t.Fatalf("RemoveProjectPermission: %v", err)
+	}
+	if calls[0] != "DELETE /workspaces/w/projects/PLAT/permissions-config/users/%7B0b4a6b6e-64b5-4a4e-9a3e-1c3b2f7f2a10%7D" {
+		t.Errorf("request = %v", calls)
+	}
+
+	if _, err := c.SetProjectPermission(args, PermissionUser, "x", "owner"); err == nil {
+		t.Error("invalid permission accepted")
+	}
+	if _, err := c.SetProjectPermission(args, "team", "x", "read"); err == nil {
+		t.Error("invalid subject accepted")
+	}
+}
@@ -3,6 +3,8 @@ package bitbucket
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 )
 
 type ListRepositoriesArgs struct {
@@ -65,14 +67,51 @@ type CreateRepositoryArgs struct {
 	Language    string `json:"language,omitempty" jsonschema:"Primary programming language"`
 	IsPrivate   *bool  `json:"is_private,omitempty" jsonschema:"Whether the repo is private (default true)"`
 	ProjectKey  string `json:"project_key,omitempty" jsonschema:"Project key to assign the repo to"`
+	// EnablePipelines turns Pipelines on once the repository exists.
+	EnablePipelines bool `json:"enable_pipelines,omitempty" jsonschema:"Enable Pipelines on the new repository"`
+	// PipelinesTemplate, if set, commits a starter bitbucket-pipelines.yml.
+	PipelinesTemplate string `json:"pipelines_template,omitempty" jsonschema:"Starter bitbucket-pipelines.yml to commit: default, node, go, python, docker"`
 }
 
-// CreateRepository creates a new repository in a workspace.
+// Follow-up steps of CreateRepository, as reported by RepositorySetupError.
+const (
+	SetupPipelinesConfig = "pipelines-config"
+	SetupEnablePipelines = "enable-pipelines"
+)
+
+// RepositorySetupError reports a repository that was created but whose
+// follow-up Step failed. Steps after it were not attempted; steps before it
+// succeeded.
+type RepositorySetupError struct {
+	Step string
+	Err  error
+}
+
+func (e *RepositorySetupError) Error() string {
+	if e.Step == SetupPipelinesConfig {
+		return fmt.Sprintf("repository created, but committing %s failed: %v", PipelinesConfigPath, e.Err)
+	}
+	return fmt.Sprintf("repository created, but enabling Pipelines failed: %v", e.Err)
+}
+
+func (e *RepositorySetupError) Unwrap() error { return e.Err }
+
+// CreateRepository creates a new repository in a workspace. When a pipelines
+// template or EnablePipelines is requested and that follow-up step fails, the
+// created repository is still returned alongside a *RepositorySetupError.
 func (c *Client) CreateRepository(args CreateRepositoryArgs) (*Repository, error) {
 	if args.Workspace == "" || args.RepoSlug == "" {
 		return nil, fmt.Errorf("workspace and repo_slug are required")
 	}
 
+	var template string
+	if args.PipelinesTemplate != "" {
+		var err error
+		if template, err = PipelinesTemplate(args.PipelinesTemplate); err != nil {
+			return nil, err
+		}
+	}
+
 	body := map[string]interface{}{
 		"scm": "git",
 	}
@@ -105,6 +144,26 @@ func (c *Client) CreateRepository(args CreateRepositoryArgs) (*Repository, error
 		return nil, fmt.Errorf("failed to parse response: %v", err)
 	}
 
+	if template != "" {
+		if err := c.WriteFile(WriteFileArgs{
+			Workspace: args.Workspace,
+			RepoSlug:  args.RepoSlug,
+			Path:      PipelinesConfigPath,
+			Content:   template,
+			Message:   "Add bitbucket-pipelines.yml",
+		}); err != nil {
+			return &repo, &RepositorySetupError{Step: SetupPipelinesConfig, Err: err}
+		}
+	}
+	if args.EnablePipelines {
+		if _, err := c.SetPipelinesEnabled(PipelinesConfigArgs{
+			Workspace: args.Workspace,
+			RepoSlug:  args.RepoSlug,
+		}, true); err != nil {
+			return &repo, &RepositorySetupError{Step: SetupEnablePipelines, Err: err}
+		}
+	}
+
 	return &repo, nil
 }
 
@@ -122,3 +181,202 @@ func (c *Client) DeleteRepository(args DeleteRepositoryArgs) error {
 	return c.Delete(fmt.Sprintf("/repositories/%s/%s",
 		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug)))
 }
+
+// ForkPolicies are the values a repository's fork_policy can take.
+var ForkPolicies = []string{"allow_forks", "no_public_forks", "no_forks"}
+
+type UpdateRepositoryArgs struct {
+	Workspace   string  `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug    string  `json:"repo_slug" jsonschema:"Repository slug"`
+	Description *string `json:"description,omitempty" jsonschema:"New description"`
+	IsPrivate   *bool   `json:"is_private,omitempty" jsonschema:"Make the repository private or public"`
+	MainBranch  *string `json:"main_branch,omitempty" jsonschema:"New main branch (must exist)"`
+	Language    *string `json:"language,omitempty" jsonschema:"Primary programming language"`
+	ProjectKey  *string `json:"project_key,omitempty" jsonschema:"Move the repository to this project"`
+	ForkPolicy  *string `json:"fork_policy,omitempty" jsonschema:"allow_forks, no_public_forks, or no_forks"`
+	Website     *string `json:"website,omitempty" jsonschema:"Website URL"`
+}
+
+// UpdateRepository changes the given settings of a repository; only fields
+// that are set are sent.
+func (c *Client) UpdateRepository(args UpdateRepositoryArgs) (*Repository, error) {
+	if args.Workspace == "" || args.RepoSlug == "" {
+		return nil, fmt.Errorf("workspace and repo_slug are required")
+	}
+
+	body := map[string]interface{}{}
+	if args.Description != nil {
+		body["description"] = *args.Description
+	}
+	if args.IsPrivate != nil {
+		body["is_private"] = *args.IsPrivate
+	}
+	if args.MainBranch != nil {
+		if *args.MainBranch == "" {
+			return nil, fmt.Errorf("main_branch cannot be empty")
+		}
+		body["mainbranch"] = map[string]string{"name": *args.MainBranch}
+	}
+	if args.Language != nil {
+		body["language"] = *args.Language
+	}
+	if args.ProjectKey != nil {
+		if *args.ProjectKey == "" {
+			return nil, fmt.Errorf("project_key cannot be empty")
+		}
+		body["project"] = map[string]string{"key": *args.ProjectKey}
+	}
+	if args.ForkPolicy != nil {
+		if !containsString(ForkPolicies, *args.ForkPolicy) {
+			return nil, fmt.Errorf("invalid fork_policy %q: expected one of %s", *args.ForkPolicy, strings.Join(ForkPolicies, ", "))
+		}
+		body["fork_policy"] = *args.ForkPolicy
+	}
+	if args.Website != nil {
+		body["website"] = *args.Website
+	}
+	if len(body) == 0 {
+		return nil, fmt.Errorf("nothing to update")
+	}
+
+	respData, err := c.Put(fmt.Sprintf("/repositories/%s/%s",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug)), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update repository: %v", err)
+	}
+
+	var repo Repository
+	if err := json.Unmarshal(respData, &repo); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &repo, nil
+}
+
+// CloneURL returns the repository's clone URL for protocol "https" or
+// "ssh", or "" if the API did not return one.
+func (r *Repository) CloneURL(protocol string) string {
+	return r.Links.Clone(protocol)
+}
+
+type ForkRepositoryArgs struct {
+	Workspace       string `json:"workspace" jsonschema:"Workspace of the repository to fork"`
+	RepoSlug        string `json:"repo_slug" jsonschema:"Repository to fork"`
+	TargetWorkspace string `json:"target_workspace,omitempty" jsonschema:"Workspace to create the fork in (default your personal workspace)"`
+	Name            string `json:"name,omitempty" jsonschema:"Name of the fork (default the source repository's name)"`
+	Description     string `json:"description,omitempty" jsonschema:"Description of the fork"`
+	IsPrivate       *bool  `json:"is_private,omitempty" jsonschema:"Whether the fork is private (default the source's privacy)"`
+	ProjectKey      string `json:"project_key,omitempty" jsonschema:"Project for the fork in the target workspace"`
+}
+
+// ForkRepository forks a repository. The fork's git data may take a few
+// seconds to appear after it is returned.
+func (c *Client) ForkRepository(args ForkRepositoryArgs) (*Repository, error) {
+	if args.Workspace == "" || args.RepoSlug == "" {
+		return nil, fmt.Errorf("workspace and repo_slug are required")
+	}
+
+	body := map[string]interface{}{}
+	if args.TargetWorkspace != "" {
+		body["workspace"] = map[string]string{"slug": args.TargetWorkspace}
+	}
+	if args.Name != "" {
+		body["name"] = args.Name
+	}
+	if args.Description != "" {
+		body["description"] = args.Description
+	}
+	if args.IsPrivate != nil {
+		body["is_private"] = *args.IsPrivate
+	}
+	if args.ProjectKey != "" {
+		body["project"] = map[string]string{"key": args.ProjectKey}
+	}
+
+	respData, err := c.Post(fmt.Sprintf("/repositories/%s/%s/forks",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug)), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fork repository: %v", err)
+	}
+
+	var repo Repository
+	if err := json.Unmarshal(respData, &repo); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &repo, nil
+}
+
+type ListForksArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
+	Pagelen   int    `json:"pagelen,omitempty" jsonschema:"Results per page (default 25)"`
+	Page      int    `json:"page,omitempty" jsonschema:"Page number"`
+}
+
+// ListForks lists the forks of a repository.
+func (c *Client) ListForks(args ListForksArgs) (*Paginated[Repository], error) {
+	if args.Workspace == "" || args.RepoSlug == "" {
+		return nil, fmt.Errorf("workspace and repo_slug are required")
+	}
+
+	pagelen := args.Pagelen
+	if pagelen == 0 {
+		pagelen = 25
+	}
+	page := args.Page
+	if page == 0 {
+		page = 1
+	}
+
+	return GetPaginated[Repository](c, fmt.Sprintf("/repositories/%s/%s/forks?pagelen=%d&page=%d",
+		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), pagelen, page))
+}
+
+// maxRepositoryPages bounds ListAllRepositories at 10,000 repositories.
+const maxRepositoryPages = 100
+
+// ListAllRepositories lists every repository in a workspace matching a
+// Bitbucket query (e.g. language="go"); an empty query matches all.
+func (c *Client) ListAllRepositories(workspace, query string) ([]Repository, error) {
+	out := []Repository{}
+	for page := 1; page <= maxRepositoryPages; page++ {
+		result, err := c.ListRepositories(ListRepositoriesArgs{Workspace: workspace, Query: query, Pagelen: 100, Page: page})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, result.Values...)
+		if result.Next == "" {
+			break
+		}
+	}
+	return out, nil
+}
+
+// SelectRepositories lists the slugs of a workspace's repositories that
+// match a Bitbucket query (e.g. project.key="PLAT") and any of the slug
+// patterns. An empty query or pattern list matches everything. Without a
+// query, patterns that are plain slugs are used as-is, and the workspace
+// is only listed when a pattern is a glob.
+func (c *Client) SelectRepositories(workspace, query string, patterns []string) ([]string, error) {
+	var out, globs []string
+	for _, p := range patterns {
+		if query == "" && !strings.ContainsAny(p, "*?[") {
+			out = append(out, p)
+		} else {
+			globs = append(globs, p)
+		}
+	}
+	if len(globs) > 0 || len(patterns) == 0 {
+		repos, err := c.ListAllRepositories(workspace, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range repos {
+			if matchAny(globs, r.Slug) {
+				out = append(out, r.Slug)
+			}
+		}
+	}
+	out = dedupeStrings(out)
+	sort.Strings(out)
+	return out, nil
+}
@@ -0,0 +1,127 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUpdateRepository(t *testing.T) {
+	var method, path string
+	var body map[string]any
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		b, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(b, &body)
+		_, _ = w.Write([]byte(`{"slug": "api", "fork_policy": "no_forks", "website": "https://x"}`))
+	})
+
+	desc, branch, policy := "", "trunk", "no_forks"
+	repo, err := c.UpdateRepository(UpdateRepositoryArgs{
+		Workspace:   "w",
+		RepoSlug:    "api",
+		Description: &desc,
+		MainBranch:  &branch,
+		ForkPolicy:  &policy,
+	})
+	if err != nil {
+		t.Fatalf("UpdateRepository: %v", err)
+	}
+	if method != http.MethodPut || path != "/repositories/w/api" {
+		t.Errorf("request = %s %s", method, path)
+	}
+	if len(body) != 3 || body["description"] != "" || body["fork_policy"] != "no_forks" {
+		t.Errorf("body = %v", body)
+	}
+	if mb, _ := body["mainbranch"].(map[string]any); mb["name"] != "trunk" {
+		t.Errorf("mainbranch = %v", body["mainbranch"])
+	}
+	if repo.ForkPolicy != "no_forks" || repo.Website != "https://x" {
+		t.Errorf("repo = %+v", repo)
+	}
+
+	bad := "sometimes"
+	for _, args := range []UpdateRepositoryArgs{
+		{Workspace: "w", RepoSlug: "api"},
+		{Workspace: "w", RepoSlug: "api", ForkPolicy: &bad},
+	} {
+		if _, err := c.UpdateRepository(args); err == nil {
+			t.Errorf("UpdateRepository(%+v) succeeded", args)
+		}
+	}
+}
+
+func TestForkRepository(t *testing.T) {
+	var path string
+	var body map[string]any
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		b, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(b, &body)
+		_, _ = w.Write([]byte(`{
+			"full_name": "me/api-fork",
+			"parent": {"full_name": "w/api"},
+			"links": {"clone": [
+				{"name": "https", "href": "https://bitbucket.org/me/api-fork.git"},
+				{"name": "ssh", "href": "git@bitbucket.org:me/api-fork.git"}
+			]}
+		}`))
+	})
+
+	repo, err := c.ForkRepository(ForkRepositoryArgs{Workspace: "w", RepoSlug: "api", TargetWorkspace: "me", Name: "api-fork"})
+	if err != nil {
+		t.Fatalf("ForkRepository: %v", err)
+	}
+	if path != "/repositories/w/api/forks" || body["name"] != "api-fork" {
+		t.Errorf("request = %s %v", path, body)
+	}
+	if ws, _ := body["workspace"].(map[string]any); ws["slug"] != "me" {
+		t.Errorf("workspace = %v", body["workspace"])
+	}
+	if repo.Parent == nil || repo.Parent.FullName != "w/api" {
+		t.Errorf("parent = %+v", repo.Parent)
+	}
+	if got := repo.CloneURL("ssh"); got != "git@bitbucket.org:me/api-fork.git" {
+		t.Errorf("ssh clone URL = %q", got)
+	}
+	if got := repo.CloneURL("https"); !strings.HasPrefix(got, "https://") {
+		t.Errorf("https clone URL = %q", got)
+	}
+	if got := (&Repository{}).CloneURL("ssh"); got != "" {
+		t.Errorf("clone URL without links = %q", got)
+	}
+}
+
+func TestSelectRepositories(t *testing.T) {
+	var queries []string
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		_, _ = w.Write([]byte(`{"values": [{"slug": "api"}, {"slug": "api-gateway"}, {"slug": "web"}]}`))
+	})
+
+	for _, tc := range []struct {
+		query    string
+		patterns []string
+		want     string
+		lookups  int
+	}{
+		{"", []string{"web", "legacy"}, "legacy,web", 0},
+		{"", []string{"api-*", "legacy"}, "api-gateway,legacy", 1},
+		{"", nil, "api,api-gateway,web", 1},
+		{`project.key="PLAT"`, []string{"web", "legacy"}, "web", 1},
+	} {
+		queries = nil
+		got, err := c.SelectRepositories("w", tc.query, tc.patterns)
+		if err != nil {
+			t.Fatalf("SelectRepositories(%q, %v): %v", tc.query, tc.patterns, err)
+		}
+		if strings.Join(got, ",") != tc.want || len(queries) != tc.lookups {
+			t.Errorf("SelectRepositories(%q, %v) = %v after %d lookups, want %s after %d", tc.query, tc.patterns, got, len(queries), tc.want, tc.lookups)
+		}
+		if tc.lookups > 0 && queries[0] != tc.query {
+			t.Errorf("query = %q, want %q", queries[0], tc.query)
+		}
+	}
+}
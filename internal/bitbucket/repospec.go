@@ -0,0 +1,767 @@
+package bitbucket
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	yaml "go.yaml.in/yaml/v4"
+)
+
+// RepoSpec is a repository settings file: the state a repository should be
+// in. Every field is optional and fields left out are not managed. A list
+// that is present, even empty, is managed: missing entries are created and,
+// with Prune, entries it does not name are deleted.
+type RepoSpec struct {
+	Description *string `yaml:"description,omitempty" json:"description,omitempty"`
+	Private     *bool   `yaml:"private,omitempty" json:"private,omitempty"`
+	MainBranch  *string `yaml:"main_branch,omitempty" json:"main_branch,omitempty"`
+	ForkPolicy  *string `yaml:"fork_policy,omitempty" json:"fork_policy,omitempty"`
+	Project     *string `yaml:"project,omitempty" json:"project,omitempty"`
+	Language    *string `yaml:"language,omitempty" json:"language,omitempty"`
+	Website     *string `yaml:"website,omitempty" json:"website,omitempty"`
+	Pipelines   *bool   `yaml:"pipelines,omitempty" json:"pipelines,omitempty"`
+
+	BranchingModel     *BranchingModelSpec     `yaml:"branching_model,omitempty" json:"branching_model,omitempty"`
+	DefaultReviewers   []string                `yaml:"default_reviewers,omitempty" json:"default_reviewers,omitempty"`
+	BranchRestrictions []BranchRestrictionSpec `yaml:"branch_restrictions,omitempty" json:"branch_restrictions,omitempty"`
+	Webhooks           []WebhookSpec           `yaml:"webhooks,omitempty" json:"webhooks,omitempty"`
+	Variables          []VariableSpec          `yaml:"variables,omitempty" json:"variables,omitempty"`
+
+	// Prune deletes default reviewers, branch restrictions, webhooks, and
+	// variables the spec does not list.
+	Prune bool `yaml:"prune,omitempty" json:"prune,omitempty"`
+}
+
+// BranchingModelSpec is the branching model part of a RepoSpec.
+type BranchingModelSpec struct {
+	Development *BranchingModelBranchSpec `yaml:"development,omitempty" json:"development,omitempty"`
+	Production  *BranchingModelBranchSpec `yaml:"production,omitempty" json:"production,omitempty"`
+	// Prefixes maps feature, bugfix, release, and hotfix to their branch
+	// prefix; an empty prefix disables the type.
+	Prefixes map[string]string `yaml:"prefixes,omitempty" json:"prefixes,omitempty"`
+}
+
+// BranchingModelBranchSpec names the development or production branch. An
+// empty Branch means the repository's main branch.
+type BranchingModelBranchSpec struct {
+	Branch string `yaml:"branch,omitempty" json:"branch,omitempty"`
+	// Enabled turns the production branch off when false.
+	Enabled *bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+}
+
+// WebhookSpec is a repository webhook in a RepoSpec, identified by URL.
+type WebhookSpec struct {
+	URL         string   `yaml:"url" json:"url"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Events      []string `yaml:"events" json:"events"`
+	// Active defaults to true.
+	Active               *bool `yaml:"active,omitempty" json:"active,omitempty"`
+	SkipCertVerification bool  `yaml:"skip_cert_verification,omitempty" json:"skip_cert_verification,omitempty"`
+	// Secret is only written when the webhook has none, since Bitbucket
+	// does not return it to compare.
+	Secret string `yaml:"secret,omitempty" json:"-"`
+}
+
+// VariableSpec is a repository Pipelines variable in a RepoSpec.
+type VariableSpec struct {
+	Key   string `yaml:"key" json:"key"`
+	Value string `yaml:"value" json:"-"`
+	// Secured values cannot be read back, so they are only written when the
+	// variable is created or its secured flag changes.
+	Secured bool `yaml:"secured,omitempty" json:"secured,omitempty"`
+}
+
+var specEnvRe = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandSpecEnv replaces ${NAME} references with environment variables so
+// secrets can stay out of the file. Unset variables are an error.
+func expandSpecEnv(s string) (string, error) {
+	var missing []string
+	out := specEnvRe.ReplaceAllStringFunc(s, func(ref string) string {
+		name := specEnvRe.FindStringSubmatch(ref)[1]
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return v
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+// ParseRepoSpec parses and validates a repository settings file. Unknown
+// fields are rejected, webhook events are expanded, and ${NAME} references
+// in webhook secrets and variable values are read from the environment.
+func ParseRepoSpec(data []byte) (*RepoSpec, error) {
+	var s RepoSpec
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid spec: %v", err)
+	}
+	if err := s.validate(); err != nil {
+		return nil, fmt.Errorf("invalid spec: %v", err)
+	}
+	return &s, nil
+}
+
+func (s *RepoSpec) validate() error {
+	if s.Description == nil && s.Private == nil && s.MainBranch == nil && s.ForkPolicy == nil &&
+		s.Project == nil && s.Language == nil && s.Website == nil && s.Pipelines == nil &&
+		s.BranchingModel == nil && s.DefaultReviewers == nil && s.BranchRestrictions == nil &&
+		s.Webhooks == nil && s.Variables == nil {
+		return fmt.Errorf("nothing to manage")
+	}
+	if s.MainBranch != nil && *s.MainBranch == "" {
+		return fmt.Errorf("main_branch cannot be empty")
+	}
+	if s.Project != nil && *s.Project == "" {
+		return fmt.Errorf("project cannot be empty")
+	}
+	if s.ForkPolicy != nil && !containsString(ForkPolicies, *s.ForkPolicy) {
+		return fmt.Errorf("fork_policy %q: expected one of %s", *s.ForkPolicy, strings.Join(ForkPolicies, ", "))
+	}
+	if bm := s.BranchingModel; bm != nil {
+		if bm.Development != nil && bm.Development.Enabled != nil {
+			return fmt.Errorf("branching_model.development cannot be disabled")
+		}
+		for kind := range bm.Prefixes {
+			if !containsString(BranchTypeKinds, kind) {
+				return fmt.Errorf("branching_model.prefixes: unknown type %q; must be one of %s", kind, strings.Join(BranchTypeKinds, ", "))
+			}
+		}
+	}
+	if _, err := PlanBranchRestrictions(nil, s.BranchRestrictions, false); err != nil {
+		return fmt.Errorf("branch_restrictions: %v", err)
+	}
+
+	seen := map[string]bool{}
+	for i := range s.Webhooks {
+		w := &s.Webhooks[i]
+		if err := validateWebhookURL(w.URL); err != nil {
+			return fmt.Errorf("webhooks: %v", err)
+		}
+		if seen[w.URL] {
+			return fmt.Errorf("webhooks: duplicate url %s", w.URL)
+		}
+		seen[w.URL] = true
+		events, err := ExpandWebhookEvents(w.Events)
+		if err != nil {
+			return fmt.Errorf("webhooks: %s: %v", w.URL, err)
+		}
+		w.Events = events
+		if w.Secret, err = expandSpecEnv(w.Secret); err != nil {
+			return fmt.Errorf("webhooks: %s: secret: %v", w.URL, err)
+		}
+	}
+
+	seen = map[string]bool{}
+	for i := range s.Variables {
+		v := &s.Variables[i]
+		if v.Key == "" {
+			return fmt.Errorf("variables: key is required")
+		}
+		if seen[v.Key] {
+			return fmt.Errorf("variables: duplicate key %s", v.Key)
+		}
+		seen[v.Key] = true
+		var err error
+		if v.Value, err = expandSpecEnv(v.Value); err != nil {
+			return fmt.Errorf("variables: %s: %v", v.Key, err)
+		}
+	}
+	return nil
+}
+
+// RepoState is the live state of the parts of a repository a RepoSpec
+// manages. Repository is nil when the repository does not exist.
+type RepoState struct {
+	Workspace          string
+	RepoSlug           string
+	Repository         *Repository
+	PipelinesEnabled   bool
+	BranchingModel     *BranchingModelSettings
+	DefaultReviewers   []User
+	BranchRestrictions []BranchRestriction
+	Webhooks           []Webhook
+	Variables          []PipelineVariable
+}
+
+// GetRepoState fetches what spec manages of a repository.
+func (c *Client) GetRepoState(workspace, repoSlug string, spec *RepoSpec) (*RepoState, error) {
+	st := &RepoState{Workspace: workspace, RepoSlug: repoSlug}
+	repo, err := c.GetRepository(GetRepositoryArgs{Workspace: workspace, RepoSlug: repoSlug})
+	if isNotFound(err) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	st.Repository = repo
+
+	if spec.Pipelines != nil {
+		cfg, err := c.GetPipelinesConfig(PipelinesConfigArgs{Workspace: workspace, RepoSlug: repoSlug})
+		if err != nil && !isNotFound(err) {
+			return nil, fmt.Errorf("failed to get pipelines config: %v", err)
+		}
+		st.PipelinesEnabled = cfg != nil && cfg.Enabled
+	}
+	if spec.BranchingModel != nil {
+		if st.BranchingModel, err = c.GetBranchingModel(BranchingModelArgs{Workspace: workspace, RepoSlug: repoSlug}); err != nil {
+			return nil, fmt.Errorf("failed to get branching model: %v", err)
+		}
+	}
+	if spec.DefaultReviewers != nil {
+		if st.DefaultReviewers, err = c.ListDefaultReviewers(DefaultReviewersArgs{Workspace: workspace, RepoSlug: repoSlug}); err != nil {
+			return nil, fmt.Errorf("failed to list default reviewers: %v", err)
+		}
+	}
+	if spec.BranchRestrictions != nil {
+		if st.BranchRestrictions, err = c.ListBranchRestrictions(ListBranchRestrictionsArgs{Workspace: workspace, RepoSlug: repoSlug}); err != nil {
+			return nil, fmt.Errorf("failed to list branch restrictions: %v", err)
+		}
+	}
+	if spec.Webhooks != nil {
+		if st.Webhooks, err = c.ListAllWebhooks(WebhookScopeArgs{Workspace: workspace, RepoSlug: repoSlug}); err != nil {
+			return nil, fmt.Errorf("failed to list webhooks: %v", err)
+		}
+	}
+	if spec.Variables != nil {
+		if st.Variables, err = c.ListPipelineVariables(PipelinesConfigArgs{Workspace: workspace, RepoSlug: repoSlug}); err != nil {
+			return nil, fmt.Errorf("failed to list variables: %v", err)
+		}
+	}
+	return st, nil
+}
+
+// Resources a RepoChange can be about.
+const (
+	ResourceRepository        = "repository"
+	ResourcePipelines         = "pipelines"
+	ResourceBranchingModel    = "branching_model"
+	ResourceDefaultReviewer   = "default_reviewer"
+	ResourceBranchRestriction = "branch_restriction"
+	ResourceWebhook           = "webhook"
+	ResourceVariable          = "variable"
+)
+
+// RepoChange is one step of a repository plan, e.g. updating the settings or
+// creating a webhook. Unchanged and unmanaged entries are ChangeKeep.
+type RepoChange struct {
+	Resource string   `json:"resource"`
+	Action   string   `json:"action"`
+	Key      string   `json:"key"`
+	Diff     []string `json:"diff,omitempty"`
+	// Unmanaged marks a kept entry the spec does not list.
+	Unmanaged bool `json:"unmanaged,omitempty"`
+
+	apply func(c *Client) error
+}
+
+// String is the change as "resource key", e.g. "webhook https://ci/hook".
+func (ch RepoChange) String() string {
+	if ch.Key == "" {
+		return ch.Resource
+	}
+	return ch.Resource + " " + ch.Key
+}
+
+// PlanRepo compares a repository's state with a spec and returns the
+// changes that make it match, repository settings first. Nothing is changed
+// until the plan is applied.
+func PlanRepo(st *RepoState, spec *RepoSpec, prune bool) ([]RepoChange, error) {
+	ws, slug := st.Workspace, st.RepoSlug
+	plan := planRepoSettings(st, spec)
+
+	if spec.Pipelines != nil {
+		ch := RepoChange{Resource: ResourcePipelines, Action: ChangeKeep, Key: "enabled"}
+		if st.PipelinesEnabled != *spec.Pipelines {
+			enabled := *spec.Pipelines
+			ch.Action = ChangeUpdate
+			ch.Diff = []string{fmt.Sprintf("enabled: %t -> %t", st.PipelinesEnabled, enabled)}
+			ch.apply = func(c *Client) error {
+				_, err := c.SetPipelinesEnabled(PipelinesConfigArgs{Workspace: ws, RepoSlug: slug}, enabled)
+				return err
+			}
+		}
+		plan = append(plan, ch)
+	}
+
+	if spec.BranchingModel != nil {
+		plan = append(plan, planBranchingModel(st, spec.BranchingModel))
+	}
+
+	if spec.DefaultReviewers != nil {
+		plan = append(plan, planDefaultReviewers(st, spec.DefaultReviewers, prune)...)
+	}
+
+	if spec.BranchRestrictions != nil {
+		changes, err := PlanBranchRestrictions(st.BranchRestrictions, spec.BranchRestrictions, prune)
+		if err != nil {
+			return nil, err
+		}
+		for _, brc := range changes {
+			ch := RepoChange{
+				Resource:  ResourceBranchRestriction,
+				Action:    brc.Action,
+				Key:       brc.Key(),
+				Diff:      brc.Diff,
+				Unmanaged: brc.Desired == nil && brc.Action == ChangeKeep,
+			}
+			if brc.Action != ChangeKeep {
+				ch.apply = func(c *Client) error {
+					_, err := c.ApplyBranchRestrictions(ws, slug, []BranchRestrictionChange{brc})
+					return err
+				}
+			}
+			plan = append(plan, ch)
+		}
+	}
+
+	if spec.Webhooks != nil {
+		plan = append(plan, planWebhooks(st, spec.Webhooks, prune)...)
+	}
+
+	if spec.Variables != nil {
+		plan = append(plan, planVariables(st, spec.Variables, prune)...)
+	}
+	return plan, nil
+}
+
+func planRepoSettings(st *RepoState, spec *RepoSpec) []RepoChange {
+	ws, slug := st.Workspace, st.RepoSlug
+
+	if st.Repository == nil {
+		create := CreateRepositoryArgs{Workspace: ws, RepoSlug: slug}
+		diff := []string{}
+		if spec.Description != nil {
+			create.Description = *spec.Description
+			diff = append(diff, fmt.Sprintf("description: %q", create.Description))
+		}
+		if spec.Language != nil {
+			create.Language = *spec.Language
+			diff = append(diff, fmt.Sprintf("language: %q", create.Language))
+		}
+		if spec.Private != nil {
+			create.IsPrivate = spec.Private
+			diff = append(diff, fmt.Sprintf("private: %t", *spec.Private))
+		}
+		if spec.Project != nil {
+			create.ProjectKey = *spec.Project
+			diff = append(diff, fmt.Sprintf("project: %s", create.ProjectKey))
+		}
+		update := UpdateRepositoryArgs{Workspace: ws, RepoSlug: slug, ForkPolicy: spec.ForkPolicy, Website: spec.Website}
+		if spec.ForkPolicy != nil {
+			diff = append(diff, fmt.Sprintf("fork_policy: %s", *spec.ForkPolicy))
+		}
+		if spec.Website != nil {
+			diff = append(diff, fmt.Sprintf("website: %q", *spec.Website))
+		}
+		if spec.MainBranch != nil {
+			diff = append(diff, fmt.Sprintf("main_branch: %s (skipped until the branch is pushed)", *spec.MainBranch))
+		}
+		return []RepoChange{{
+			Resource: ResourceRepository,
+			Action:   ChangeCreate,
+			Key:      ws + "/" + slug,
+			Diff:     diff,
+			apply: func(c *Client) error {
+				if _, err := c.CreateRepository(create); err != nil {
+					return err
+				}
+				if update.ForkPolicy == nil && update.Website == nil {
+					return nil
+				}
+				_, err := c.UpdateRepository(update)
+				return err
+			},
+		}}
+	}
+
+	cur := st.Repository
+	update := UpdateRepositoryArgs{Workspace: ws, RepoSlug: slug}
+	var diff []string
+	if d := spec.Description; d != nil && *d != cur.Description {
+		update.Description = d
+		diff = append(diff, fmt.Sprintf("description: %q -> %q", cur.Description, *d))
+	}
+	if p := spec.Private; p != nil && *p != cur.IsPrivate {
+		update.IsPrivate = p
+		diff = append(diff, fmt.Sprintf("private: %t -> %t", cur.IsPrivate, *p))
+	}
+	curMain := ""
+	if cur.MainBranch != nil {
+		curMain = cur.MainBranch.Name
+	}
+	if b := spec.MainBranch; b != nil && *b != curMain {
+		update.MainBranch = b
+		diff = append(diff, fmt.Sprintf("main_branch: %s -> %s", orNone(curMain), *b))
+	}
+	if f := spec.ForkPolicy; f != nil && *f != cur.ForkPolicy {
+		update.ForkPolicy = f
+		diff = append(diff, fmt.Sprintf("fork_policy: %s -> %s", orNone(cur.ForkPolicy), *f))
+	}
+	curProject := ""
+	if cur.Project != nil {
+		curProject = cur.Project.Key
+	}
+	if p := spec.Project; p != nil && !strings.EqualFold(*p, curProject) {
+		update.ProjectKey = p
+		diff = append(diff, fmt.Sprintf("project: %s -> %s", orNone(curProject), *p))
+	}
+	// Bitbucket stores languages in lower case.
+	if l := spec.Language; l != nil && !strings.EqualFold(*l, cur.Language) {
+		update.Language = l
+		diff = append(diff, fmt.Sprintf("language: %q -> %q", cur.Language, *l))
+	}
+	if w := spec.Website; w != nil && *w != cur.Website {
+		update.Website = w
+		diff = append(diff, fmt.Sprintf("website: %q -> %q", cur.Website, *w))
+	}
+
+	managed := spec.Description != nil || spec.Private != nil || spec.MainBranch != nil || spec.ForkPolicy != nil ||
+		spec.Project != nil || spec.Language != nil || spec.Website != nil
+	if !managed {
+		return nil
+	}
+	ch := RepoChange{Resource: ResourceRepository, Action: ChangeKeep, Key: "settings"}
+	if len(diff) > 0 {
+		ch.Action = ChangeUpdate
+		ch.Diff = diff
+		ch.apply = func(c *Client) error {
+			_, err := c.UpdateRepository(update)
+			return err
+		}
+	}
+	return []RepoChange{ch}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+func planBranchingModel(st *RepoState, spec *BranchingModelSpec) RepoChange {
+	cur := st.BranchingModel
+	if cur == nil {
+		cur = &BranchingModelSettings{}
+	}
+	var update BranchingModelSettings
+	var diff []string
+
+	describe := func(b *BranchingModelBranch) string {
+		switch {
+		case b == nil || (b.Enabled != nil && !*b.Enabled):
+			return "disabled"
+		case b.UseMainBranch:
+			return "main branch"
+		}
+		return orNone(b.Name)
+	}
+	want := func(s *BranchingModelBranchSpec) *BranchingModelBranch {
+		b := &BranchingModelBranch{Name: s.Branch, UseMainBranch: s.Branch == ""}
+		if s.Enabled != nil && !*s.Enabled {
+			b = &BranchingModelBranch{}
+		}
+		return b
+	}
+
+	if s := spec.Development; s != nil {
+		w := want(s)
+		if describe(cur.Development) != describe(w) {
+			update.Development = w
+			diff = append(diff, fmt.Sprintf("development: %s -> %s", describe(cur.Development), describe(w)))
+		}
+	}
+	if s := spec.Production; s != nil {
+		w := want(s)
+		enabled := s.Enabled == nil || *s.Enabled
+		w.Enabled = &enabled
+		if describe(cur.Production) != describe(w) {
+			update.Production = w
+			diff = append(diff, fmt.Sprintf("production: %s -> %s", describe(cur.Production), describe(w)))
+		}
+	}
+
+	kinds := make([]string, 0, len(spec.Prefixes))
+	for kind := range spec.Prefixes {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		prefix := spec.Prefixes[kind]
+		curPrefix := ""
+		for _, bt := range cur.BranchTypes {
+			if bt.Kind == kind && bt.Enabled {
+				curPrefix = bt.Prefix
+			}
+		}
+		if curPrefix == prefix {
+			continue
+		}
+		update.BranchTypes = append(update.BranchTypes, BranchType{Kind: kind, Enabled: prefix != "", Prefix: prefix})
+		from, to := curPrefix, prefix
+		if from == "" {
+			from = "disabled"
+		}
+		if to == "" {
+			to = "disabled"
+		}
+		diff = append(diff, fmt.Sprintf("%s: %s -> %s", kind, from, to))
+	}
+
+	ch := RepoChange{Resource: ResourceBranchingModel, Action: ChangeKeep}
+	if len(diff) > 0 {
+		ws, slug := st.Workspace, st.RepoSlug
+		ch.Action = ChangeUpdate
+		ch.Diff = diff
+		ch.apply = func(c *Client) error {
+			_, err := c.UpdateBranchingModel(BranchingModelArgs{Workspace: ws, RepoSlug: slug}, update)
+			return err
+		}
+	}
+	return ch
+}
+
+func planDefaultReviewers(st *RepoState, desired []string, prune bool) []RepoChange {
+	args := DefaultReviewersArgs{Workspace: st.Workspace, RepoSlug: st.RepoSlug}
+	var plan []RepoChange
+	matched := map[int]bool{}
+	for _, id := range dedupeStrings(desired) {
+		found := false
+		for i, u := range st.DefaultReviewers {
+			if !matched[i] && matchesUser([]string{id}, u) != "" {
+				matched[i] = true
+				found = true
+				break
+			}
+		}
+		ch := RepoChange{Resource: ResourceDefaultReviewer, Action: ChangeKeep, Key: id}
+		if !found {
+			ch.Action = ChangeCreate
+			ch.apply = func(c *Client) error { return c.AddDefaultReviewer(args, id) }
+		}
+		plan = append(plan, ch)
+	}
+	for i, u := range st.DefaultReviewers {
+		if matched[i] {
+			continue
+		}
+		ch := RepoChange{Resource: ResourceDefaultReviewer, Action: ChangeKeep, Key: reviewerKey(u), Unmanaged: true}
+		if prune {
+			ch.Action = ChangeDelete
+			ch.Unmanaged = false
+			ch.apply = func(c *Client) error {
+				id := u.UUID
+				if id == "" {
+					id = u.AccountID
+				}
+				return c.RemoveDefaultReviewer(args, id)
+			}
+		}
+		plan = append(plan, ch)
+	}
+	sortRepoChanges(plan)
+	return plan
+}
+
+func reviewerKey(u User) string {
+	id := u.AccountID
+	if id == "" {
+		id = u.UUID
+	}
+	if u.DisplayName != "" {
+		return fmt.Sprintf("%s (%s)", id, u.DisplayName)
+	}
+	return id
+}
+
+func planWebhooks(st *RepoState, desired []WebhookSpec, prune bool) []RepoChange {
+	scope := WebhookScopeArgs{Workspace: st.Workspace, RepoSlug: st.RepoSlug}
+	var plan []RepoChange
+	matched := map[int]bool{}
+	for i := range desired {
+		w := desired[i]
+		active := w.Active == nil || *w.Active
+		var cur *Webhook
+		for j := range st.Webhooks {
+			if !matched[j] && st.Webhooks[j].URL == w.URL {
+				cur = &st.Webhooks[j]
+				matched[j] = true
+				break
+			}
+		}
+		if cur == nil {
+			plan = append(plan, RepoChange{
+				Resource: ResourceWebhook,
+				Action:   ChangeCreate,
+				Key:      w.URL,
+				Diff:     []string{"events: " + strings.Join(w.Events, ", ")},
+				apply: func(c *Client) error {
+					_, err := c.CreateWebhook(CreateWebhookArgs{
+						WebhookScopeArgs:     scope,
+						URL:                  w.URL,
+						Description:          w.Description,
+						Events:               w.Events,
+						Secret:               w.Secret,
+						Inactive:             !active,
+						SkipCertVerification: w.SkipCertVerification,
+					})
+					return err
+				},
+			})
+			continue
+		}
+
+		update := UpdateWebhookArgs{WebhookArgs: WebhookArgs{WebhookScopeArgs: scope, UID: cur.UUID}}
+		var diff []string
+		if w.Description != "" && w.Description != cur.Description {
+			update.Description = &w.Description
+			diff = append(diff, fmt.Sprintf("description: %q -> %q", cur.Description, w.Description))
+		}
+		if !sameSet(cur.Events, w.Events) {
+			update.Events = w.Events
+			diff = append(diff, fmt.Sprintf("events: [%s] -> [%s]", strings.Join(sorted(cur.Events), ", "), strings.Join(sorted(w.Events), ", ")))
+		}
+		if active != cur.Active {
+			update.Active = &active
+			diff = append(diff, fmt.Sprintf("active: %t -> %t", cur.Active, active))
+		}
+		if w.SkipCertVerification != cur.SkipCertVerification {
+			update.SkipCertVerification = &w.SkipCertVerification
+			diff = append(diff, fmt.Sprintf("skip_cert_verification: %t -> %t", cur.SkipCertVerification, w.SkipCertVerification))
+		}
+		if w.Secret != "" && !cur.SecretSet {
+			update.Secret = &w.Secret
+			diff = append(diff, "secret: none -> set")
+		}
+		ch := RepoChange{Resource: ResourceWebhook, Action: ChangeKeep, Key: w.URL}
+		if len(diff) > 0 {
+			ch.Action = ChangeUpdate
+			ch.Diff = diff
+			ch.apply = func(c *Client) error {
+				_, err := c.UpdateWebhook(update)
+				return err
+			}
+		}
+		plan = append(plan, ch)
+	}
+	for j, h := range st.Webhooks {
+		if matched[j] {
+			continue
+		}
+		ch := RepoChange{Resource: ResourceWebhook, Action: ChangeKeep, Key: h.URL, Unmanaged: true}
+		if prune {
+			uid := h.UUID
+			ch.Action = ChangeDelete
+			ch.Unmanaged = false
+			ch.apply = func(c *Client) error {
+				return c.DeleteWebhook(WebhookArgs{WebhookScopeArgs: scope, UID: uid})
+			}
+		}
+		plan = append(plan, ch)
+	}
+	sortRepoChanges(plan)
+	return plan
+}
+
+func planVariables(st *RepoState, desired []VariableSpec, prune bool) []RepoChange {
+	args := PipelinesConfigArgs{Workspace: st.Workspace, RepoSlug: st.RepoSlug}
+	var plan []RepoChange
+	matched := map[int]bool{}
+	for _, v := range desired {
+		want := PipelineVariable{Key: v.Key, Value: v.Value, Secured: v.Secured}
+		var cur *PipelineVariable
+		for j := range st.Variables {
+			if !matched[j] && st.Variables[j].Key == v.Key {
+				cur = &st.Variables[j]
+				matched[j] = true
+				break
+			}
+		}
+		if cur == nil {
+			plan = append(plan, RepoChange{
+				Resource: ResourceVariable,
+				Action:   ChangeCreate,
+				Key:      v.Key,
+				Diff:     []string{variableValue(want)},
+				apply: func(c *Client) error {
+					_, err := c.CreatePipelineVariable(args, want)
+					return err
+				},
+			})
+			continue
+		}
+
+		var diff []string
+		switch {
+		case cur.Secured != v.Secured:
+			diff = append(diff, fmt.Sprintf("secured: %t -> %t", cur.Secured, v.Secured))
+		case !v.Secured && cur.Value != v.Value:
+			diff = append(diff, fmt.Sprintf("value: %q -> %q", cur.Value, v.Value))
+		}
+		ch := RepoChange{Resource: ResourceVariable, Action: ChangeKeep, Key: v.Key}
+		if len(diff) > 0 {
+			want.UUID = cur.UUID
+			ch.Action = ChangeUpdate
+			ch.Diff = diff
+			ch.apply = func(c *Client) error {
+				_, err := c.UpdatePipelineVariable(args, want)
+				return err
+			}
+		}
+		plan = append(plan, ch)
+	}
+	for j, v := range st.Variables {
+		if matched[j] {
+			continue
+		}
+		ch := RepoChange{Resource: ResourceVariable, Action: ChangeKeep, Key: v.Key, Unmanaged: true}
+		if prune {
+			uuid := v.UUID
+			ch.Action = ChangeDelete
+			ch.Unmanaged = false
+			ch.apply = func(c *Client) error { return c.DeletePipelineVariable(args, uuid) }
+		}
+		plan = append(plan, ch)
+	}
+	sortRepoChanges(plan)
+	return plan
+}
+
+func variableValue(v PipelineVariable) string {
+	if v.Secured {
+		return "value: (secured)"
+	}
+	return fmt.Sprintf("value: %q", v.Value)
+}
+
+func sortRepoChanges(plan []RepoChange) {
+	sort.SliceStable(plan, func(a, b int) bool { return plan[a].Key < plan[b].Key })
+}
+
+// ApplyRepoChanges carries out a plan's creates, updates, and deletes in
+// order, stopping at the first error. It returns the changes applied.
+func (c *Client) ApplyRepoChanges(plan []RepoChange) (int, error) {
+	applied := 0
+	for _, ch := range plan {
+		if ch.apply == nil {
+			continue
+		}
+		if err := ch.apply(c); err != nil {
+			return applied, fmt.Errorf("%s %s: %v", ch.Action, ch, err)
+		}
+		applied++
+	}
+	return applied, nil
+}
@@ -0,0 +1,234 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseRepoSpec(t *testing.T) {
+	t.Setenv("HOOK_SECRET", "s3cret")
+	spec, err := ParseRepoSpec([]byte(`
+description: Payments API
+private: true
+fork_policy: no_public_forks
+default_reviewers: []
+webhooks:
+  - url: https://ci.example.com/hook
+    events: ["pullrequest:*"]
+    secret: ${HOOK_SECRET}
+variables:
+  - key: REGION
+    value: eu-west-1
+`))
+	if err != nil {
+		t.Fatalf("ParseRepoSpec: %v", err)
+	}
+	if spec.DefaultReviewers == nil {
+		t.Error("empty default_reviewers should be managed")
+	}
+	if spec.BranchRestrictions != nil {
+		t.Error("missing branch_restrictions should be unmanaged")
+	}
+	if w := spec.Webhooks[0]; w.Secret != "s3cret" || len(w.Events) < 5 || !containsString(w.Events, "pullrequest:created") {
+		t.Errorf("webhook = %+v", w)
+	}
+
+	for _, c := range []struct{ in, want string }{
+		{``, "nothing to manage"},
+		{`descripton: typo`, "field descripton not found"},
+		{`fork_policy: sometimes`, "fork_policy"},
+		{"branching_model:\n  prefixes: {topic: topic/}", "unknown type"},
+		{"webhooks:\n  - url: https://a/h\n    events: [repo:pushed]", "repo:pushed"},
+		{"variables:\n  - key: A\n  - key: A", "duplicate key A"},
+		{"variables:\n  - key: A\n    value: ${BBKT_TEST_UNSET_VAR}", "BBKT_TEST_UNSET_VAR is not set"},
+		{"branch_restrictions:\n  - kind: force", "pattern or branch_type"},
+	} {
+		if _, err := ParseRepoSpec([]byte(c.in)); err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("ParseRepoSpec(%q) error = %v, want %q", c.in, err, c.want)
+		}
+	}
+}
+
+func TestPlanRepo(t *testing.T) {
+	spec, err := ParseRepoSpec([]byte(`
+description: Payments API
+private: true
+main_branch: main
+language: Go
+project: PAY
+pipelines: true
+branching_model:
+  development: {}
+  production: {branch: production}
+  prefixes: {feature: feature/, hotfix: ""}
+default_reviewers: ["557058:alice"]
+branch_restrictions:
+  - kind: force
+    pattern: main
+webhooks:
+  - url: https://ci.example.com/hook
+    events: [repo:push]
+variables:
+  - key: REGION
+    value: eu-west-1
+  - key: TOKEN
+    value: abc
+    secured: true
+`))
+	if err != nil {
+		t.Fatalf("ParseRepoSpec: %v", err)
+	}
+	st := &RepoState{
+		Workspace: "w",
+		RepoSlug:  "r",
+		Repository: &Repository{
+			Description: "old",
+			IsPrivate:   true,
+			Language:    "go",
+			MainBranch:  &Branch{Name: "master"},
+			Project:     &Project{Key: "PAY"},
+		},
+		BranchingModel: &BranchingModelSettings{
+			Development: &BranchingModelBranch{UseMainBranch: true},
+			Production:  &BranchingModelBranch{Enabled: new(false)},
+			BranchTypes: []BranchType{{Kind: "feature", Enabled: true, Prefix: "feature/"}, {Kind: "hotfix", Enabled: true, Prefix: "hotfix/"}},
+		},
+		DefaultReviewers:   []User{{AccountID: "557058:bob", DisplayName: "Bob"}},
+		BranchRestrictions: []BranchRestriction{{ID: 1, Kind: "force", BranchMatchKind: "glob", Pattern: "main"}},
+		Webhooks:           []Webhook{{UUID: "{h1}", URL: "https://ci.example.com/hook", Events: []string{"repo:push"}, Active: false}},
+		Variables: []PipelineVariable{
+			{UUID: "{v1}", Key: "REGION", Value: "us-east-1"},
+			{UUID: "{v2}", Key: "TOKEN", Secured: true},
+			{UUID: "{v3}", Key: "OLD", Value: "x"},
+		},
+	}
+
+	plan, err := PlanRepo(st, spec, true)
+	if err != nil {
+		t.Fatalf("PlanRepo: %v", err)
+	}
+	got := map[string]RepoChange{}
+	for _, ch := range plan {
+		got[ch.String()] = ch
+	}
+	for key, action := range map[string]string{
+		"repository settings":                 ChangeUpdate,
+		"pipelines enabled":                   ChangeUpdate,
+		"branching_model":                     ChangeUpdate,
+		"default_reviewer 557058:alice":       ChangeCreate,
+		"default_reviewer 557058:bob (Bob)":   ChangeDelete,
+		"branch_restriction force main":       ChangeKeep,
+		"webhook https://ci.example.com/hook": ChangeUpdate,
+		"variable REGION":                     ChangeUpdate,
+		"variable TOKEN":                      ChangeKeep,
+		"variable OLD":                        ChangeDelete,
+	} {
+		if ch, ok := got[key]; !ok || ch.Action != action {
+			t.Errorf("%s: got %+v, want %s", key, ch, action)
+		}
+	}
+	if len(plan) != 10 {
+		t.Errorf("plan has %d changes, want 10", len(plan))
+	}
+	if d := strings.Join(got["repository settings"].Diff, "; "); d != `description: "old" -> "Payments API"; main_branch: master -> main` {
+		t.Errorf("settings diff = %s", d)
+	}
+	if d := strings.Join(got["branching_model"].Diff, "; "); d != "production: disabled -> production; hotfix: hotfix/ -> disabled" {
+		t.Errorf("branching model diff = %s", d)
+	}
+
+	// Without prune, unlisted entries are kept and flagged as unmanaged.
+	plan, _ = PlanRepo(st, spec, false)
+	for _, ch := range plan {
+		if ch.Key == "OLD" && (ch.Action != ChangeKeep || !ch.Unmanaged) {
+			t.Errorf("OLD without prune = %+v", ch)
+		}
+	}
+}
+
+func TestPlanRepoMissingRepository(t *testing.T) {
+	spec, err := ParseRepoSpec([]byte("description: New\nfork_policy: no_forks\nmain_branch: main\n"))
+	if err != nil {
+		t.Fatalf("ParseRepoSpec: %v", err)
+	}
+	plan, err := PlanRepo(&RepoState{Workspace: "w", RepoSlug: "r"}, spec, false)
+	if err != nil {
+		t.Fatalf("PlanRepo: %v", err)
+	}
+	if len(plan) != 1 || plan[0].Action != ChangeCreate || plan[0].Key != "w/r" {
+		t.Fatalf("plan = %+v", plan)
+	}
+
+	var calls []string
+	var bodies []map[string]any
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		b, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		_ = json.Unmarshal(b, &body)
+		bodies = append(bodies, body)
+		_, _ = w.Write([]byte(`{"slug": "r"}`))
+	})
+	n, err := c.ApplyRepoChanges(plan)
+	if err != nil {
+		t.Fatalf("ApplyRepoChanges: %v", err)
+	}
+	if n != 1 || strings.Join(calls, "|") != "POST /repositories/w/r|PUT /repositories/w/r" {
+		t.Errorf("applied %d, calls = %v", n, calls)
+	}
+	if bodies[0]["description"] != "New" || bodies[1]["fork_policy"] != "no_forks" || bodies[1]["mainbranch"] != nil {
+		t.Errorf("bodies = %v", bodies)
+	}
+}
+
+func TestApplyRepoChanges(t *testing.T) {
+	spec, err := ParseRepoSpec([]byte(`
+pipelines: true
+default_reviewers: ["{0b4a6b6e-64b5-4a4e-9a3e-1c3b2f7f2a10}"]
+variables:
+  - key: REGION
+    value: eu-west-1
+`))
+	if err != nil {
+		t.Fatalf("ParseRepoSpec: %v", err)
+	}
+	plan, err := PlanRepo(&RepoState{Workspace: "w", RepoSlug: "r", Repository: &Repository{}}, spec, false)
+	if err != nil {
+		t.Fatalf("PlanRepo: %v", err)
+	}
+
+	var calls []string
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.EscapedPath())
+		_, _ = w.Write([]byte(`{}`))
+	})
+	n, err := c.ApplyRepoChanges(plan)
+	if err != nil {
+		t.Fatalf("ApplyRepoChanges: %v", err)
+	}
+	want := "PUT /repositories/w/r/pipelines_config|" +
+		"PUT /repositories/w/r/default-reviewers/%7B0b4a6b6e-64b5-4a4e-9a3e-1c3b2f7f2a10%7D|" +
+		"POST /repositories/w/r/pipelines_config/variables"
+	if n != 3 || strings.Join(calls, "|") != want {
+		t.Errorf("applied %d, calls = %v", n, calls)
+	}
+
+	// A second plan against the applied state has nothing to do.
+	st := &RepoState{
+		Workspace:        "w",
+		RepoSlug:         "r",
+		Repository:       &Repository{},
+		PipelinesEnabled: true,
+		DefaultReviewers: []User{{UUID: "{0b4a6b6e-64b5-4a4e-9a3e-1c3b2f7f2a10}"}},
+		Variables:        []PipelineVariable{{UUID: "{v}", Key: "REGION", Value: "eu-west-1"}},
+	}
+	plan, _ = PlanRepo(st, spec, false)
+	for _, ch := range plan {
+		if ch.Action != ChangeKeep {
+			t.Errorf("second plan has %+v", ch)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Runner is a self-hosted Pipelines runner registered to a workspace or a
+// repository.
+type Runner struct {
+	UUID        string             `json:"uuid"`
+	Name        string             `json:"name"`
+	Labels      []string           `json:"labels"`
+	State       *RunnerState       `json:"state"`
+	CreatedOn   *time.Time         `json:"created_on"`
+	UpdatedOn   *time.Time         `json:"updated_on"`
+	OAuthClient *RunnerOAuthClient `json:"oauth_client,omitempty"`
+}
+
+// RunnerState is a runner's status as last reported by the runner itself.
+type RunnerState struct {
+	Status    string         `json:"status"` // ONLINE, OFFLINE, UNREGISTERED, DISABLED
+	UpdatedOn *time.Time     `json:"updated_on"`
+	Cordoned  bool           `json:"cordoned"`
+	Version   *RunnerVersion `json:"version,omitempty"`
+}
+
+// RunnerVersion is the runner software version.
+type RunnerVersion struct {
+	Current string `json:"current"`
+}
+
+// RunnerOAuthClient holds the credentials a runner container authenticates
+// with. Bitbucket returns the secret only once, in the create response.
+type RunnerOAuthClient struct {
+	ID            string `json:"id"`
+	Secret        string `json:"secret,omitempty"`
+	TokenEndpoint string `json:"token_endpoint,omitempty"`
+	Audience      string `json:"audience,omitempty"`
+}
+
+// LastSeen is when the runner last reported its state.
+func (r Runner) LastSeen() *time.Time {
+	if r.State != nil && r.State.UpdatedOn != nil {
+		return r.State.UpdatedOn
+	}
+	return r.UpdatedOn
+}
+
+// Status is the runner's reported status, or "" if it never registered.
+func (r Runner) Status() string {
+	if r.State == nil {
+		return ""
+	}
+	return r.State.Status
+}
+
+// RunnerScopeArgs addresses the runners of a workspace, or of a single
+// repository when RepoSlug is set.
+type RunnerScopeArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug,omitempty" jsonschema:"Repository slug (omit for workspace runners)"`
+}
+
+func (a RunnerScopeArgs) basePath() (string, error) {
+	if a.Workspace == "" {
+		return "", fmt.Errorf("workspace is required")
+	}
+	if a.RepoSlug != "" {
+		return fmt.Sprintf("/repositories/%s/%s/pipelines-config/runners",
+			QueryEscape(a.Workspace), QueryEscape(a.RepoSlug)), nil
+	}
+	return fmt.Sprintf("/workspaces/%s/pipelines-config/runners", QueryEscape(a.Workspace)), nil
+}
+
+type ListRunnersArgs struct {
+	RunnerScopeArgs
+	Pagelen int `json:"pagelen,omitempty" jsonschema:"Results per page (default 25)"`
+	Page    int `json:"page,omitempty" jsonschema:"Page number"`
+}
+
+// ListRunners lists workspace or repository runners.
+func (c *Client) ListRunners(args ListRunnersArgs) (*Paginated[Runner], error) {
+	base, err := args.basePath()
+	if err != nil {
+		return nil, err
+	}
+
+	pagelen := args.Pagelen
+	if pagelen == 0 {
+		pagelen = 25
+	}
+	page := args.Page
+	if page == 0 {
+		page = 1
+	}
+
+	return GetPaginated[Runner](c, fmt.Sprintf("%s?pagelen=%d&page=%d", base, pagelen, page))
+}
+
+type RunnerArgs struct {
+	RunnerScopeArgs
+	RunnerUUID string `json:"runner_uuid" jsonschema:"Runner UUID"`
+}
+
+func (a RunnerArgs) path() (string, error) {
+	base, err := a.basePath()
+	if err != nil {
+		return "", err
+	}
+	if a.RunnerUUID == "" {
+		return "", fmt.Errorf("runner_uuid is required")
+	}
+	return base + "/" + QueryEscape(normalizeUUID(a.RunnerUUID)), nil
+}
+
+// normalizeUUID wraps a bare UUID in the braces Bitbucket expects.
+func normalizeUUID(u string) string {
+	if strings.HasPrefix(u, "{") {
+		return u
+	}
+	return "{" + u + "}"
+}
+
+// GetRunner gets a single runner.
+func (c *Client) GetRunner(args RunnerArgs) (*Runner, error) {
+	path, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	return GetJSON[Runner](c, path)
+}
+
+type CreateRunnerArgs struct {
+	RunnerScopeArgs
+	Name   string   `json:"name" jsonschema:"Runner name"`
+	Labels []string `json:"labels,omitempty" jsonschema:"Runner labels (default self.hosted, linux)"`
+}
+
+// CreateRunner registers a runner. The response's OAuthClient carries the
+// one-time secret the runner container needs; it cannot be fetched again.
+func (c *Client) CreateRunner(args CreateRunnerArgs) (*Runner, error) {
+	base, err := args.basePath()
+	if err != nil {
+		return nil, err
+	}
+	if args.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	labels := args.Labels
+	if len(labels) == 0 {
+		labels = []string{"self.hosted", "linux"}
+	}
+	if !containsString(labels, "self.hosted") {
+		labels = append([]string{"self.hosted"}, labels...)
+	}
+
+	respData, err := c.Post(base, map[string]any{
+		"name":   args.Name,
+		"labels": labels,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runner: %v", err)
+	}
+
+	var runner Runner
+	if err := json.Unmarshal(respData, &runner); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &runner, nil
+}
+
+// DeleteRunner unregisters a runner.
+func (c *Client) DeleteRunner(args RunnerArgs) error {
+	path, err := args.path()
+	if err != nil {
+		return err
+	}
+	return c.Delete(path)
+}
+
+// SetRunnerEnabled enables or disables a runner. A disabled runner stays
+// registered but is not scheduled any steps.
+func (c *Client) SetRunnerEnabled(args RunnerArgs, enabled bool) (*Runner, error) {
+	path, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := GetJSON[Runner](c, path)
+	if err != nil {
+		return nil, err
+	}
+	status := "DISABLED"
+	if enabled {
+		status = "ENABLED"
+	}
+	respData, err := c.Put(path, map[string]any{
+		"name":   current.Name,
+		"labels": current.Labels,
+		"state":  map[string]string{"status": status},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update runner: %v", err)
+	}
+
+	var runner Runner
+	if err := json.Unmarshal(respData, &runner); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &runner, nil
+}
+
+// RunnerDockerCommand renders the `docker run` invocation for a Linux Docker
+// runner from a freshly created runner's credentials. repositoryUUID is empty
+// for workspace runners.
+func RunnerDockerCommand(r *Runner, workspaceUUID, repositoryUUID string) string {
+	if r.OAuthClient == nil {
+		return ""
+	}
+	env := []string{
+		"ACCOUNT_UUID=" + workspaceUUID,
+	}
+	if repositoryUUID != "" {
+		env = append(env, "REPOSITORY_UUID="+repositoryUUID)
+	}
+	env = append(env,
+		"RUNNER_UUID="+r.UUID,
+		"RUNTIME_PREREQUISITES_ENABLED=true",
+		"OAUTH_CLIENT_ID="+r.OAuthClient.ID,
+		"OAUTH_CLIENT_SECRET="+r.OAuthClient.Secret,
+		"WORKING_DIRECTORY=/tmp",
+	)
+
+	var b strings.Builder
+	b.WriteString("docker container run -it \\\n")
+	b.WriteString("  -v /tmp:/tmp \\\n")
+	b.WriteString("  -v /var/run/docker.sock:/var/run/docker.sock \\\n")
+	b.WriteString("  -v /var/lib/docker/containers:/var/lib/docker/containers:ro \\\n")
+	for _, e := range env {
+		fmt.Fprintf(&b, "  -e %s \\\n", e)
+	}
+	fmt.Fprintf(&b, "  --name runner-%s \\\n", strings.Trim(r.UUID, "{}"))
+	b.WriteString("  docker-public.packages.atlassian.com/sox/atlassian/bitbucket-pipelines-runner:1")
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRunners_Paths(t *testing.T) {
+	var got []string
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.EscapedPath())
+		_, _ = w.Write([]byte(`{"uuid":"{r1}","name":"x"}`))
+	})
+
+	_, _ = c.ListRunners(ListRunnersArgs{RunnerScopeArgs: RunnerScopeArgs{Workspace: "w"}})
+	_, _ = c.ListRunners(ListRunnersArgs{RunnerScopeArgs: RunnerScopeArgs{Workspace: "w", RepoSlug: "r"}})
+	_, _ = c.GetRunner(RunnerArgs{RunnerScopeArgs: RunnerScopeArgs{Workspace: "w"}, RunnerUUID: "abc"})
+	_ = c.DeleteRunner(RunnerArgs{RunnerScopeArgs: RunnerScopeArgs{Workspace: "w", RepoSlug: "r"}, RunnerUUID: "{abc}"})
+
+	want := []string{
+		"GET /workspaces/w/pipelines-config/runners",
+		"GET /repositories/w/r/pipelines-config/runners",
+		"GET /workspaces/w/pipelines-config/runners/%7Babc%7D",
+		"DELETE /repositories/w/r/pipelines-config/runners/%7Babc%7D",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCreateRunner_DefaultLabelsAndDockerCommand(t *testing.T) {
+	var body map[string]any
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(b, &body)
+		_, _ = w.Write([]byte(`{"uuid":"{r1}","name":"build-1","labels":["self.hosted","linux","gpu"],
+			"oauth_client":{"id":"cid","secret":"s3cret"}}`))
+	})
+
+	r, err := c.CreateRunner(CreateRunnerArgs{RunnerScopeArgs: RunnerScopeArgs{Workspace: "w"}, Name: "build-1", Labels: []string{"linux", "gpu"}})
+	if err != nil {
+		t.Fatalf("CreateRunner: %v", err)
+	}
+	labels, _ := body["labels"].([]any)
+	if len(labels) != 3 || labels[0] != "self.hosted" {
+		t.Errorf("labels sent = %v, want self.hosted prepended", body["labels"])
+	}
+
+	cmd := RunnerDockerCommand(r, "{ws}", "")
+	for _, want := range []string{"ACCOUNT_UUID={ws}", "RUNNER_UUID={r1}", "OAUTH_CLIENT_ID=cid", "OAUTH_CLIENT_SECRET=s3cret", "--name runner-r1"} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("docker command missing %q:\n%s", want, cmd)
+		}
+	}
+	if strings.Contains(cmd, "REPOSITORY_UUID") {
+		t.Errorf("workspace runner command should not set REPOSITORY_UUID")
+	}
+}
@@ -0,0 +1,313 @@
+package bitbucket
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Snippet is a Bitbucket snippet: a small git repository of one or more
+// files, owned by a workspace.
+type Snippet struct {
+	ID        int                    `json:"id"`
+	Title     string                 `json:"title"`
+	SCM       string                 `json:"scm"`
+	IsPrivate bool                   `json:"is_private"`
+	Owner     *User                  `json:"owner,omitempty"`
+	Creator   *User                  `json:"creator,omitempty"`
+	CreatedOn time.Time              `json:"created_on"`
+	UpdatedOn time.Time              `json:"updated_on"`
+	Files     map[string]SnippetFile `json:"files,omitempty"`
+	Links     Links                  `json:"links"`
+}
+
+// SnippetFile is one file of a snippet, as listed by GetSnippet.
+type SnippetFile struct {
+	Links Links `json:"links"`
+}
+
+// EncodedID returns the ID the API and web UI use for the snippet (e.g.
+// "kypj9"), which differs from the numeric ID.
+func (s *Snippet) EncodedID() string {
+	if self := s.Links.Href("self"); self != "" {
+		return path.Base(self)
+	}
+	return strconv.Itoa(s.ID)
+}
+
+// URL returns the snippet's web page.
+func (s *Snippet) URL() string {
+	return s.Links.Href("html")
+}
+
+// FileNames returns the snippet's file names in order.
+func (s *Snippet) FileNames() []string {
+	names := make([]string, 0, len(s.Files))
+	for name := range s.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SnippetComment is a comment on a snippet.
+type SnippetComment struct {
+	ID        int       `json:"id"`
+	Content   Content   `json:"content"`
+	User      *User     `json:"user"`
+	CreatedOn time.Time `json:"created_on"`
+	UpdatedOn time.Time `json:"updated_on"`
+	Links     Links     `json:"links"`
+}
+
+// SnippetRoles filter ListSnippets by the caller's relationship to a snippet.
+var SnippetRoles = []string{"owner", "contributor", "member"}
+
+type ListSnippetsArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	Role      string `json:"role,omitempty" jsonschema:"Only snippets you are the owner, contributor, or member of"`
+	Pagelen   int    `json:"pagelen,omitempty" jsonschema:"Results per page (default 25)"`
+	Page      int    `json:"page,omitempty" jsonschema:"Page number"`
+}
+
+// ListSnippets lists the snippets in a workspace that the caller can see.
+func (c *Client) ListSnippets(args ListSnippetsArgs) (*Paginated[Snippet], error) {
+	if args.Workspace == "" {
+		return nil, fmt.Errorf("workspace is required")
+	}
+	if args.Role != "" && !containsString(SnippetRoles, args.Role) {
+		return nil, fmt.Errorf("invalid role %q: expected owner, contributor, or member", args.Role)
+	}
+
+	pagelen := args.Pagelen
+	if pagelen == 0 {
+		pagelen = 25
+	}
+	page := args.Page
+	if page == 0 {
+		page = 1
+	}
+
+	p := fmt.Sprintf("/snippets/%s?pagelen=%d&page=%d", QueryEscape(args.Workspace), pagelen, page)
+	if args.Role != "" {
+		p += "&role=" + QueryEscape(args.Role)
+	}
+	return GetPaginated[Snippet](c, p)
+}
+
+// SnippetArgs identifies a snippet by workspace and encoded ID.
+type SnippetArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	SnippetID string `json:"snippet_id" jsonschema:"Snippet ID as shown in its URL (e.g. kypj9)"`
+}
+
+func (a SnippetArgs) path() (string, error) {
+	if a.Workspace == "" || a.SnippetID == "" {
+		return "", fmt.Errorf("workspace and snippet_id are required")
+	}
+	return fmt.Sprintf("/snippets/%s/%s", QueryEscape(a.Workspace), QueryEscape(a.SnippetID)), nil
+}
+
+// GetSnippet gets a snippet and the names of its files.
+func (c *Client) GetSnippet(args SnippetArgs) (*Snippet, error) {
+	p, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	return GetJSON[Snippet](c, p)
+}
+
+// GetSnippetFile returns the current content of one of a snippet's files.
+func (c *Client) GetSnippetFile(args SnippetArgs, name string) ([]byte, error) {
+	p, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, fmt.Errorf("file name is required")
+	}
+	data, _, err := c.GetRaw(p + "/files/" + QueryEscape(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snippet file %s: %v", name, err)
+	}
+	return data, nil
+}
+
+type CreateSnippetArgs struct {
+	Workspace string            `json:"workspace" jsonschema:"Workspace slug"`
+	Title     string            `json:"title,omitempty" jsonschema:"Snippet title"`
+	IsPrivate bool              `json:"is_private,omitempty" jsonschema:"Only workspace members can see the snippet"`
+	Files     map[string]string `json:"files" jsonschema:"File contents keyed by file name"`
+}
+
+// CreateSnippet creates a snippet from one or more files.
+func (c *Client) CreateSnippet(args CreateSnippetArgs) (*Snippet, error) {
+	if args.Workspace == "" {
+		return nil, fmt.Errorf("workspace is required")
+	}
+	if len(args.Files) == 0 {
+		return nil, fmt.Errorf("at least one file is required")
+	}
+
+	fields := map[string]string{
+		"title":      args.Title,
+		"is_private": strconv.FormatBool(args.IsPrivate),
+	}
+	respData, err := c.sendMultipart(http.MethodPost, fmt.Sprintf("/snippets/%s", QueryEscape(args.Workspace)), fields, snippetParts(args.Files))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snippet: %v", err)
+	}
+	return unmarshalSnippet(respData)
+}
+
+type UpdateSnippetArgs struct {
+	Workspace string            `json:"workspace" jsonschema:"Workspace slug"`
+	SnippetID string            `json:"snippet_id" jsonschema:"Snippet ID as shown in its URL"`
+	Title     *string           `json:"title,omitempty" jsonschema:"New title"`
+	IsPrivate *bool             `json:"is_private,omitempty" jsonschema:"New privacy"`
+	Files     map[string]string `json:"files,omitempty" jsonschema:"Files to add or replace, keyed by file name; other files are kept"`
+}
+
+// UpdateSnippet changes a snippet's title or privacy and adds or replaces
+// files. Each change with files is a new revision of the snippet.
+func (c *Client) UpdateSnippet(args UpdateSnippetArgs) (*Snippet, error) {
+	p, err := SnippetArgs{Workspace: args.Workspace, SnippetID: args.SnippetID}.path()
+	if err != nil {
+		return nil, err
+	}
+	if args.Title == nil && args.IsPrivate == nil && len(args.Files) == 0 {
+		return nil, fmt.Errorf("nothing to update")
+	}
+
+	var respData []byte
+	if len(args.Files) == 0 {
+		body := map[string]any{}
+		if args.Title != nil {
+			body["title"] = *args.Title
+		}
+		if args.IsPrivate != nil {
+			body["is_private"] = *args.IsPrivate
+		}
+		respData, err = c.Put(p, body)
+	} else {
+		fields := map[string]string{}
+		if args.Title != nil {
+			fields["title"] = *args.Title
+		}
+		if args.IsPrivate != nil {
+			fields["is_private"] = strconv.FormatBool(*args.IsPrivate)
+		}
+		respData, err = c.PutMultipart(p, fields, snippetParts(args.Files))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update snippet: %v", err)
+	}
+	return unmarshalSnippet(respData)
+}
+
+// snippetParts turns file contents into the "file" parts the snippets API
+// expects, which take their names from the part's filename.
+func snippetParts(files map[string]string) []MultipartFile {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]MultipartFile, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, MultipartFile{Field: "file", Filename: name, Reader: bytes.NewReader([]byte(files[name]))})
+	}
+	return parts
+}
+
+// DeleteSnippet deletes a snippet and its history.
+func (c *Client) DeleteSnippet(args SnippetArgs) error {
+	p, err := args.path()
+	if err != nil {
+		return err
+	}
+	if err := c.Delete(p); err != nil {
+		return fmt.Errorf("failed to delete snippet: %v", err)
+	}
+	return nil
+}
+
+// ListSnippetComments lists the comments on a snippet.
+func (c *Client) ListSnippetComments(args SnippetArgs, page, pagelen int) (*Paginated[SnippetComment], error) {
+	p, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	if pagelen == 0 {
+		pagelen = 25
+	}
+	if page == 0 {
+		page = 1
+	}
+	return GetPaginated[SnippetComment](c, fmt.Sprintf("%s/comments?pagelen=%d&page=%d", p, pagelen, page))
+}
+
+// AddSnippetComment comments on a snippet.
+func (c *Client) AddSnippetComment(args SnippetArgs, content string) (*SnippetComment, error) {
+	p, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	if content == "" {
+		return nil, fmt.Errorf("content is required")
+	}
+
+	respData, err := c.Post(p+"/comments", map[string]any{"content": map[string]string{"raw": content}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add comment: %v", err)
+	}
+	var comment SnippetComment
+	if err := json.Unmarshal(respData, &comment); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &comment, nil
+}
+
+// DeleteSnippetComment deletes one of the caller's comments on a snippet.
+func (c *Client) DeleteSnippetComment(args SnippetArgs, commentID int) error {
+	p, err := args.path()
+	if err != nil {
+		return err
+	}
+	if commentID == 0 {
+		return fmt.Errorf("comment_id is required")
+	}
+	if err := c.Delete(fmt.Sprintf("%s/comments/%d", p, commentID)); err != nil {
+		return fmt.Errorf("failed to delete comment: %v", err)
+	}
+	return nil
+}
+
+// ListSnippetCommits lists a snippet's revisions, newest first.
+func (c *Client) ListSnippetCommits(args SnippetArgs, page, pagelen int) (*Paginated[Commit], error) {
+	p, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	if pagelen == 0 {
+		pagelen = 25
+	}
+	if page == 0 {
+		page = 1
+	}
+	return GetPaginated[Commit](c, fmt.Sprintf("%s/commits?pagelen=%d&page=%d", p, pagelen, page))
+}
+
+func unmarshalSnippet(data []byte) (*Snippet, error) {
+	var s Snippet
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &s, nil
+}
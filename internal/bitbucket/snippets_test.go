@@ -0,0 +1,157 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const snippetJSON = `{
+	"id": 123, "title": "Queries", "is_private": true,
+	"files": {"b.sql": {}, "a.sql": {}},
+	"links": {
+		"self": {"href": "https://api.bitbucket.org/2.0/snippets/w/kypj9"},
+		"html": {"href": "https://bitbucket.org/w/workspace/snippets/kypj9"}
+	}
+}`
+
+func TestCreateSnippet(t *testing.T) {
+	type part struct{ field, filename, content string }
+	var (
+		method string
+		path   string
+		fields = map[string]string{}
+		parts  []part
+	)
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		mr, err := r.MultipartReader()
+		if err != nil {
+			t.Fatalf("MultipartReader: %v", err)
+		}
+		for {
+			p, err := mr.NextPart()
+			if err != nil {
+				break
+			}
+			b, _ := io.ReadAll(p)
+			if p.FileName() == "" {
+				fields[p.FormName()] = string(b)
+			} else {
+				parts = append(parts, part{p.FormName(), p.FileName(), string(b)})
+			}
+		}
+		_, _ = w.Write([]byte(snippetJSON))
+	})
+
+	s, err := c.CreateSnippet(CreateSnippetArgs{
+		Workspace: "w",
+		Title:     "Queries",
+		IsPrivate: true,
+		Files:     map[string]string{"b.sql": "select 2;", "a.sql": "select 1;"},
+	})
+	if err != nil {
+		t.Fatalf("CreateSnippet: %v", err)
+	}
+	if method != http.MethodPost || path != "/snippets/w" {
+		t.Errorf("request = %s %s", method, path)
+	}
+	if fields["title"] != "Queries" || fields["is_private"] != "true" {
+		t.Errorf("fields = %v", fields)
+	}
+	want := []part{{"file", "a.sql", "select 1;"}, {"file", "b.sql", "select 2;"}}
+	if len(parts) != 2 || parts[0] != want[0] || parts[1] != want[1] {
+		t.Errorf("parts = %v, want %v", parts, want)
+	}
+	if s.EncodedID() != "kypj9" || s.URL() != "https://bitbucket.org/w/workspace/snippets/kypj9" {
+		t.Errorf("snippet id %q url %q", s.EncodedID(), s.URL())
+	}
+	if got := strings.Join(s.FileNames(), ","); got != "a.sql,b.sql" {
+		t.Errorf("FileNames = %s", got)
+	}
+
+	if _, err := c.CreateSnippet(CreateSnippetArgs{Workspace: "w"}); err == nil {
+		t.Error("CreateSnippet without files succeeded")
+	}
+}
+
+func TestUpdateSnippet(t *testing.T) {
+	var contentType string
+	var body map[string]any
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/snippets/w/kypj9" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		contentType = r.Header.Get("Content-Type")
+		if strings.HasPrefix(contentType, "application/json") {
+			b, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(b, &body)
+		}
+		_, _ = w.Write([]byte(snippetJSON))
+	})
+
+	// Metadata-only changes are sent as JSON.
+	title := "Renamed"
+	if _, err := c.UpdateSnippet(UpdateSnippetArgs{Workspace: "w", SnippetID: "kypj9", Title: &title}); err != nil {
+		t.Fatalf("UpdateSnippet: %v", err)
+	}
+	if body["title"] != "Renamed" || body["is_private"] != nil {
+		t.Errorf("body = %v", body)
+	}
+
+	// File changes go as multipart.
+	if _, err := c.UpdateSnippet(UpdateSnippetArgs{Workspace: "w", SnippetID: "kypj9", Files: map[string]string{"a.sql": "select 3;"}}); err != nil {
+		t.Fatalf("UpdateSnippet with files: %v", err)
+	}
+	if !strings.HasPrefix(contentType, "multipart/form-data") {
+		t.Errorf("Content-Type = %s", contentType)
+	}
+
+	if _, err := c.UpdateSnippet(UpdateSnippetArgs{Workspace: "w", SnippetID: "kypj9"}); err == nil {
+		t.Error("UpdateSnippet with nothing to update succeeded")
+	}
+}
+
+func TestSnippetFilesCommentsAndCommits(t *testing.T) {
+	var calls []string
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.EscapedPath())
+		switch {
+		case strings.Contains(r.URL.Path, "/files/"):
+			_, _ = w.Write([]byte("select 1;"))
+		case strings.HasSuffix(r.URL.Path, "/commits"):
+			_, _ = w.Write([]byte(`{"values": [{"hash": "abc123", "message": "edit"}]}`))
+		case r.Method == http.MethodPost:
+			_, _ = w.Write([]byte(`{"id": 7, "content": {"raw": "nice"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"values": [{"id": 7, "content": {"raw": "nice"}}]}`))
+		}
+	})
+	args := SnippetArgs{Workspace: "w", SnippetID: "kypj9"}
+
+	data, err := c.GetSnippetFile(args, "my query.sql")
+	if err != nil || string(data) != "select 1;" {
+		t.Fatalf("GetSnippetFile = %q, %v", data, err)
+	}
+	if calls[0] != "GET /snippets/w/kypj9/files/my%20query.sql" {
+		t.Errorf("file request = %s", calls[0])
+	}
+
+	comment, err := c.AddSnippetComment(args, "nice")
+	if err != nil || comment.ID != 7 {
+		t.Fatalf("AddSnippetComment = %+v, %v", comment, err)
+	}
+	comments, err := c.ListSnippetComments(args, 0, 0)
+	if err != nil || len(comments.Values) != 1 || comments.Values[0].Content.Raw != "nice" {
+		t.Fatalf("ListSnippetComments = %+v, %v", comments, err)
+	}
+	commits, err := c.ListSnippetCommits(args, 0, 0)
+	if err != nil || len(commits.Values) != 1 || commits.Values[0].Hash != "abc123" {
+		t.Fatalf("ListSnippetCommits = %+v, %v", commits, err)
+	}
+	if _, err := c.ListSnippetCommits(SnippetArgs{Workspace: "w"}, 0, 0); err == nil {
+		t.Error("ListSnippetCommits without snippet_id succeeded")
+	}
+}
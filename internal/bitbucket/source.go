@@ -108,35 +108,6 @@ func (c *Client) GetFileHistory(args GetFileHistoryArgs) (*Paginated[json.RawMes
 	return GetPaginated[json.RawMessage](c, endpoint)
 }
 
-type SearchCodeArgs struct {
-	Workspace   string `json:"workspace" jsonschema:"Workspace slug"`
-	RepoSlug    string `json:"repo_slug" jsonschema:"Repository slug"`
-	SearchQuery string `json:"query" jsonschema:"Search query"`
-	Pagelen     int    `json:"pagelen,omitempty" jsonschema:"Results per page (default: 25)"`
-	Page        int    `json:"page,omitempty" jsonschema:"Page number"`
-}
-
-// SearchCode searches for code in a repository using Bitbucket's code search.
-func (c *Client) SearchCode(args SearchCodeArgs) ([]byte, error) {
-	if args.Workspace == "" || args.RepoSlug == "" || args.SearchQuery == "" {
-		return nil, fmt.Errorf("workspace, repo_slug, and query are required")
-	}
-
-	pagelen := args.Pagelen
-	if pagelen == 0 {
-		pagelen = 25
-	}
-	page := args.Page
-	if page == 0 {
-		page = 1
-	}
-
-	endpoint := fmt.Sprintf("/repositories/%s/%s/search/code?search_query=%s&pagelen=%d&page=%d",
-		QueryEscape(args.Workspace), QueryEscape(args.RepoSlug), QueryEscape(args.SearchQuery), pagelen, page)
-
-	return c.Get(endpoint)
-}
-
 type WriteFileArgs struct {
 	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
 	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
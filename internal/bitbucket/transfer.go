@@ -0,0 +1,315 @@
+package bitbucket
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// doStream executes a request whose body is streamed from r rather than held
+// in memory. Unlike do, it cannot replay the body, so there is no retry on a
+// 401; the token is refreshed up front instead. The client-wide timeout is
+// also lifted: a multi-gigabyte upload or download legitimately takes longer
+// than the 30s that bounds ordinary API calls.
+func (c *Client) doStream(method, path string, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	if err := c.ensureValidToken(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	} else if c.username != "" && c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	hc := *c.http
+	hc.Timeout = 0
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	return resp, nil
+}
+
+// MultipartFile is one streamed file part for PostMultipartStream. Size may be
+// -1 when unknown, in which case the request is sent chunked.
+type MultipartFile struct {
+	Field    string
+	Filename string
+	Reader   io.Reader
+	Size     int64
+}
+
+// PostMultipartStream is PostMultipart for large files: parts are read from
+// their readers as the request is sent instead of being buffered. The
+// multipart framing is generated up front so that, when every part's size
+// is known, the request carries an exact Content-Length.
+func (c *Client) PostMultipartStream(path string, fields map[string]string, files []MultipartFile) ([]byte, error) {
+	var (
+		buf      bytes.Buffer
+		segments []io.Reader
+		size     int64
+	)
+	w := multipart.NewWriter(&buf)
+	for key, val := range fields {
+		if err := w.WriteField(key, val); err != nil {
+			return nil, fmt.Errorf("writing field %s: %w", key, err)
+		}
+	}
+	for _, f := range files {
+		if _, err := w.CreateFormFile(f.Field, f.Filename); err != nil {
+			return nil, fmt.Errorf("creating form file %s: %w", f.Filename, err)
+		}
+		// Everything written so far is the framing that precedes this part.
+		header := bytes.Clone(buf.Bytes())
+		buf.Reset()
+		segments = append(segments, bytes.NewReader(header), f.Reader)
+		if size >= 0 && f.Size >= 0 {
+			size += int64(len(header)) + f.Size
+		} else {
+			size = -1
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("closing multipart writer: %w", err)
+	}
+	segments = append(segments, bytes.NewReader(bytes.Clone(buf.Bytes())))
+	if size >= 0 {
+		size += int64(buf.Len())
+	}
+
+	resp, err := c.doStream(http.MethodPost, path, io.MultiReader(segments...), size,
+		http.Header{"Content-Type": {w.FormDataContentType()}})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, parseAuthError(resp, respData)
+	}
+	return respData, nil
+}
+
+// DownloadStream is an open download, possibly resumed part-way through.
+type DownloadStream struct {
+	Body io.ReadCloser
+	// Offset is where Body starts in the file. It is 0 when the server
+	// ignored the Range request and is sending the whole file again.
+	Offset int64
+	// Total is the full file size, or -1 if the server did not say.
+	Total int64
+	// Complete is set when the requested offset is already the end of the
+	// file (HTTP 416); Body is empty.
+	Complete bool
+	// Validator identifies this version of the file for a later If-Range:
+	// a strong ETag, else Last-Modified, else "".
+	Validator string
+}
+
+// OpenDownload GETs path (following redirects, e.g. to the storage bucket
+// behind Downloads and artifacts) starting at offset bytes. validator, from
+// an earlier DownloadStream, is sent as If-Range so the server only resumes
+// the same version of the file. Servers that support Range answer 206 and
+// the download resumes; others, or a changed file, answer 200 and the
+// caller must start over, which Offset reports. A 416 whose total does not
+// match offset also starts over.
+func (c *Client) OpenDownload(path string, offset int64, validator string) (*DownloadStream, error) {
+	header := http.Header{"Accept": {"*/*"}}
+	if offset > 0 {
+		header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if validator != "" {
+			header.Set("If-Range", validator)
+		}
+	}
+	resp, err := c.doStream(http.MethodGet, path, nil, 0, header)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+		return &DownloadStream{Body: resp.Body, Offset: offset, Total: contentRangeTotal(resp.Header.Get("Content-Range")), Validator: validator}, nil
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		resp.Body.Close()
+		// Only complete if the file is exactly as long as what we have and
+		// has not been replaced since.
+		v := responseValidator(resp)
+		if contentRangeTotal(resp.Header.Get("Content-Range")) != offset || (v != "" && validator != "" && v != validator) {
+			return c.OpenDownload(path, 0, "")
+		}
+		return &DownloadStream{Body: io.NopCloser(strings.NewReader("")), Offset: offset, Total: offset, Complete: true, Validator: validator}, nil
+	case resp.StatusCode >= 400:
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return nil, parseAuthError(resp, data)
+	}
+	return &DownloadStream{Body: resp.Body, Offset: 0, Total: resp.ContentLength, Validator: responseValidator(resp)}, nil
+}
+
+// responseValidator is the If-Range value for resp's file. Weak ETags
+// cannot be used with If-Range.
+func responseValidator(resp *http.Response) string {
+	if etag := resp.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return resp.Header.Get("Last-Modified")
+}
+
+// contentRangeTotal parses the size out of "bytes 100-199/2000".
+func contentRangeTotal(v string) int64 {
+	i := strings.LastIndexByte(v, '/')
+	if i < 0 {
+		return -1
+	}
+	n, err := strconv.ParseInt(v[i+1:], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// DownloadResult describes a file written by DownloadToFile.
+type DownloadResult struct {
+	Path    string `json:"path"`
+	Bytes   int64  `json:"bytes"`
+	Resumed bool   `json:"resumed"`
+	SHA256  string `json:"sha256"`
+}
+
+// DownloadToFile downloads path into dest. Data is written to dest+".part"
+// and renamed on success, so an interrupted download leaves a partial file
+// that the next call resumes from via Range. The file's validator is kept
+// in dest+".part.validator"; a partial file without one, or whose remote
+// file has since changed, is downloaded again from the start. progress, if
+// non-nil, is called with bytes written so far and the total (-1 if
+// unknown).
+func (c *Client) DownloadToFile(path, dest string, progress func(done, total int64)) (*DownloadResult, error) {
+	part := dest + ".part"
+	validatorFile := part + ".validator"
+	var offset int64
+	var validator string
+	if st, err := os.Stat(part); err == nil {
+		if v, err := os.ReadFile(validatorFile); err == nil && len(v) > 0 { //nolint:gosec // next to dest, chosen by the user
+			offset, validator = st.Size(), string(v)
+		}
+	}
+
+	stream, err := c.OpenDownload(path, offset, validator)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Body.Close()
+
+	if stream.Offset == 0 {
+		if stream.Validator != "" {
+			if err := os.WriteFile(validatorFile, []byte(stream.Validator), 0o644); err != nil { //nolint:gosec // dest is chosen by the user
+				return nil, err
+			}
+		} else {
+			_ = os.Remove(validatorFile)
+		}
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if stream.Offset > 0 {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(part, flags, 0o644) //nolint:gosec // dest is chosen by the user
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// The checksum covers the whole file, including any bytes kept from an
+	// earlier attempt.
+	h := sha256.New()
+	if stream.Offset > 0 {
+		if err := hashFilePrefix(h, part, stream.Offset); err != nil {
+			return nil, err
+		}
+	}
+
+	var dst io.Writer = io.MultiWriter(f, h)
+	if progress != nil {
+		dst = &progressWriter{w: dst, done: stream.Offset, total: stream.Total, fn: progress}
+	}
+	n, err := io.Copy(dst, stream.Body)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w (partial data kept in %s; rerun to resume)", path, err, part)
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	size := stream.Offset + n
+	if stream.Total >= 0 && size != stream.Total {
+		// The kept bytes and the server's file disagree; a resumed
+		// download starts over once, a fresh one fails.
+		_ = os.Remove(part)
+		_ = os.Remove(validatorFile)
+		if stream.Offset > 0 {
+			stream.Body.Close()
+			return c.DownloadToFile(path, dest, progress)
+		}
+		return nil, fmt.Errorf("downloading %s: got %d bytes, expected %d", path, size, stream.Total)
+	}
+	if err := os.Rename(part, dest); err != nil {
+		return nil, err
+	}
+	_ = os.Remove(validatorFile)
+
+	return &DownloadResult{
+		Path:    dest,
+		Bytes:   size,
+		Resumed: stream.Offset > 0,
+		SHA256:  hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+func hashFilePrefix(h hash.Hash, path string, n int64) error {
+	f, err := os.Open(path) //nolint:gosec // our own .part file
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.CopyN(h, f, n)
+	return err
+}
+
+type progressWriter struct {
+	w           io.Writer
+	done, total int64
+	fn          func(done, total int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.done += int64(n)
+	p.fn(p.done, p.total)
+	return n, err
+}
@@ -0,0 +1,204 @@
+package bitbucket
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUploadDownload_StreamsMultipartWithLength(t *testing.T) {
+	var gotName, gotBody string
+	var gotLength int64
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotLength = r.ContentLength
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("ParseMultipartForm: %v", err)
+			return
+		}
+		fh := r.MultipartForm.File["files"]
+		if len(fh) != 1 {
+			t.Errorf("files parts = %d, want 1", len(fh))
+			return
+		}
+		gotName = fh[0].Filename
+		f, _ := fh[0].Open()
+		b, _ := io.ReadAll(f)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	content := strings.Repeat("x", 4096)
+	err := c.UploadDownload(UploadDownloadArgs{
+		Workspace: "w", RepoSlug: "r", Filename: "app-1.0.tar.gz",
+		Reader: strings.NewReader(content), Size: int64(len(content)),
+	})
+	if err != nil {
+		t.Fatalf("UploadDownload: %v", err)
+	}
+	if gotName != "app-1.0.tar.gz" || gotBody != content {
+		t.Errorf("server got %q with %d bytes", gotName, len(gotBody))
+	}
+	if gotLength <= int64(len(content)) {
+		t.Errorf("Content-Length = %d, want exact length including framing", gotLength)
+	}
+}
+
+func TestPostMultipartStream_ReportsAuthErrors(t *testing.T) {
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Accepted-Oauth-Scopes", "repository:write")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"type": "error", "error": {"message": "Forbidden"}}`))
+	})
+
+	_, err := c.PostMultipartStream("/repositories/w/r/downloads", nil, []MultipartFile{
+		{Field: "files", Filename: "a.txt", Reader: strings.NewReader("a"), Size: 1},
+	})
+	var authErr *AuthError
+	if !errors.As(err, &authErr) || authErr.AcceptedOAuthScopes != "repository:write" {
+		t.Errorf("err = %v", err)
+	}
+}
+
+// rangeServer serves content with ETag "v1", honouring Range (subject to
+// If-Range) unless ignoreRange is set.
+func rangeServer(t *testing.T, content string, ignoreRange bool, gotRange *string) *Client {
+	return newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		*gotRange = r.Header.Get("Range")
+		w.Header().Set("ETag", `"v1"`)
+		var start int
+		if ifRange := r.Header.Get("If-Range"); *gotRange != "" && !ignoreRange && (ifRange == "" || ifRange == `"v1"`) {
+			fmt.Sscanf(*gotRange, "bytes=%d-", &start)
+			if start >= len(content) {
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", len(content)))
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+			w.WriteHeader(http.StatusPartialContent)
+		}
+		_, _ = w.Write([]byte(content[start:]))
+	})
+}
+
+func TestDownloadFile_ResumesPartial(t *testing.T) {
+	const content = "0123456789abcdef"
+	sum := sha256.Sum256([]byte(content))
+	want := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name        string
+		partial     string
+		validator   string
+		ignoreRange bool
+		wantRange   string
+		wantResumed bool
+	}{
+		{"fresh", "", "", false, "", false},
+		{"resume", "0123", `"v1"`, false, "bytes=4-", true},
+		{"server ignores range", "0123", `"v1"`, true, "bytes=4-", false},
+		{"already complete", content, `"v1"`, false, "bytes=16-", true},
+		{"partial without validator", "0123", "", false, "", false},
+		{"remote file changed", "wxyz", `"v0"`, false, "bytes=4-", false},
+		// The 416 total shows the partial file is too long, so the last
+		// request starts over.
+		{"partial longer than file", content + "xx", `"v1"`, false, "", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotRange string
+			c := rangeServer(t, content, tc.ignoreRange, &gotRange)
+			dest := filepath.Join(t.TempDir(), "out.bin")
+			if tc.partial != "" {
+				if err := os.WriteFile(dest+".part", []byte(tc.partial), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tc.validator != "" {
+				if err := os.WriteFile(dest+".part.validator", []byte(tc.validator), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var lastDone int64
+			res, err := c.DownloadFile(DownloadFileArgs{Workspace: "w", RepoSlug: "r", Name: "out.bin", Dest: dest},
+				func(done, total int64) { lastDone = done })
+			if err != nil {
+				t.Fatalf("DownloadFile: %v", err)
+			}
+			if gotRange != tc.wantRange {
+				t.Errorf("Range = %q, want %q", gotRange, tc.wantRange)
+			}
+			data, _ := os.ReadFile(dest)
+			if string(data) != content {
+				t.Errorf("file = %q, want %q", data, content)
+			}
+			if res.Resumed != tc.wantResumed || res.Bytes != int64(len(content)) || res.SHA256 != want {
+				t.Errorf("result = %+v", res)
+			}
+			if tc.partial != content && lastDone != int64(len(content)) {
+				t.Errorf("progress ended at %d", lastDone)
+			}
+			if _, err := os.Stat(dest + ".part"); !os.IsNotExist(err) {
+				t.Errorf(".part file should be renamed away")
+			}
+			if _, err := os.Stat(dest + ".part.validator"); !os.IsNotExist(err) {
+				t.Errorf(".part.validator file should be removed")
+			}
+		})
+	}
+}
+
+func TestDownloadFile_RestartsWhenResumedSizeMismatches(t *testing.T) {
+	const content = "0123456789abcdef"
+	var ranges []string
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("Range") != "" {
+			// Claims a longer file than the bytes it sends.
+			w.Header().Set("Content-Range", "bytes 4-19/20")
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write([]byte(content[4:]))
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	})
+	dest := filepath.Join(t.TempDir(), "out.bin")
+	_ = os.WriteFile(dest+".part", []byte("0123"), 0o600)
+	_ = os.WriteFile(dest+".part.validator", []byte(`"v1"`), 0o600)
+
+	res, err := c.DownloadFile(DownloadFileArgs{Workspace: "w", RepoSlug: "r", Name: "out.bin", Dest: dest}, nil)
+	if err != nil {
+		t.Fatalf("DownloadFile: %v", err)
+	}
+	if strings.Join(ranges, "|") != "bytes=4-|" || res.Resumed || res.Bytes != int64(len(content)) {
+		t.Errorf("ranges = %q, result = %+v", ranges, res)
+	}
+}
+
+func TestListPipelineArtifacts_SkipsStepsWithout(t *testing.T) {
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/steps"):
+			_, _ = w.Write([]byte(`{"values":[{"uuid":"{a}","name":"Build"},{"uuid":"{b}","name":"Test"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/steps/{a}/artifacts"):
+			_, _ = w.Write([]byte(`{"values":[{"uuid":"{x}","path":"dist/app.zip","file_size_bytes":42}]}`))
+		default:
+			http.Error(w, "{}", http.StatusNotFound)
+		}
+	})
+	arts, err := c.ListPipelineArtifacts(ListPipelineArtifactsArgs{Workspace: "w", RepoSlug: "r", PipelineUUID: "{p}"})
+	if err != nil {
+		t.Fatalf("ListPipelineArtifacts: %v", err)
+	}
+	if len(arts) != 1 || arts[0].DisplayName() != "dist/app.zip" || arts[0].StepName != "Build" || arts[0].StepUUID != "{a}" {
+		t.Errorf("artifacts = %+v", arts)
+	}
+}
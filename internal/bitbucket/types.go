@@ -39,11 +39,14 @@ type Repository struct {
 	Description string    `json:"description"`
 	IsPrivate   bool      `json:"is_private"`
 	Language    string    `json:"language"`
+	Website     string    `json:"website"`
+	ForkPolicy  string    `json:"fork_policy"`
 	SCM         string    `json:"scm"`
 	Size        int64     `json:"size"`
 	MainBranch  *Branch   `json:"mainbranch"`
 	Owner       *User     `json:"owner"`
 	Project     *Project  `json:"project"`
+	Parent      *MinRepo  `json:"parent,omitempty"`
 	CreatedOn   time.Time `json:"created_on"`
 	UpdatedOn   time.Time `json:"updated_on"`
 	Links       Links     `json:"links"`
@@ -101,12 +104,19 @@ type MinRepo struct {
 	Type     string `json:"type"`
 }
 
-// Project represents a Bitbucket project.
+// Project represents a Bitbucket project. Repositories embed a minimal
+// project; the remaining fields are set when projects are fetched directly.
 type Project struct {
-	UUID string `json:"uuid"`
-	Key  string `json:"key"`
-	Name string `json:"name"`
-	Type string `json:"type"`
+	UUID                    string    `json:"uuid"`
+	Key                     string    `json:"key"`
+	Name                    string    `json:"name"`
+	Type                    string    `json:"type"`
+	Description             string    `json:"description,omitempty"`
+	IsPrivate               bool      `json:"is_private,omitempty"`
+	HasPubliclyVisibleRepos bool      `json:"has_publicly_visible_repos,omitempty"`
+	CreatedOn               time.Time `json:"created_on,omitzero"`
+	UpdatedOn               time.Time `json:"updated_on,omitzero"`
+	Links                   Links     `json:"links,omitempty"`
 }
 
 // PullRequest represents a pull request.
@@ -189,6 +199,7 @@ type Pipeline struct {
 	CreatedOn    time.Time   `json:"created_on"`
 	CompletedOn  *time.Time  `json:"completed_on"`
 	DurationSecs int         `json:"duration_in_seconds"`
+	BuildSecs    int         `json:"build_seconds_used"`
 	TriggerName  string      `json:"trigger_name"`
 	Links        Links       `json:"links"`
 }
@@ -215,9 +226,11 @@ type PipeStage struct {
 
 // PipeTarget is the pipeline target.
 type PipeTarget struct {
-	Type    string `json:"type"`
-	RefType string `json:"ref_type"`
-	RefName string `json:"ref_name"`
+	Type     string            `json:"type"`
+	RefType  string            `json:"ref_type"`
+	RefName  string            `json:"ref_name"`
+	Commit   *Commit           `json:"commit,omitempty"`
+	Selector *PipelineSelector `json:"selector,omitempty"`
 }
 
 // PipelineStep represents a single step in a pipeline.
@@ -260,6 +273,26 @@ type TreeEntry struct {
 // Links is a map of link objects.
 type Links map[string]interface{}
 
+// Href returns the href of the named link, or "" if there is none.
+func (l Links) Href(name string) string {
+	link, _ := l[name].(map[string]interface{})
+	href, _ := link["href"].(string)
+	return href
+}
+
+// Clone returns the clone URL for protocol ("https" or "ssh"), or "".
+func (l Links) Clone(protocol string) string {
+	clones, _ := l["clone"].([]interface{})
+	for _, c := range clones {
+		link, _ := c.(map[string]interface{})
+		if name, _ := link["name"].(string); name == protocol {
+			href, _ := link["href"].(string)
+			return href
+		}
+	}
+	return ""
+}
+
 // APIError is the standard Bitbucket error response.
 type APIError struct {
 	Type  string `json:"type"`
@@ -308,6 +341,7 @@ type PipelineSelector struct {
 
 // PipelineVariable represents a pipeline variable.
 type PipelineVariable struct {
+	UUID    string `json:"uuid,omitempty"`
 	Key     string `json:"key"`
 	Value   string `json:"value"`
 	Secured bool   `json:"secured"`
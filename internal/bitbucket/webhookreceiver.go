@@ -0,0 +1,331 @@
+package bitbucket
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// WebhookDelivery is a parsed webhook delivery. Only the fields relevant to
+// the event are set; Payload always holds the raw body.
+type WebhookDelivery struct {
+	Event       string `json:"event"`
+	HookUUID    string `json:"hook_uuid,omitempty"`
+	RequestUUID string `json:"request_uuid,omitempty"`
+	Attempt     int    `json:"attempt,omitempty"`
+
+	Repository   *Repository   `json:"repository,omitempty"`
+	Actor        *User         `json:"actor,omitempty"`
+	PullRequest  *PullRequest  `json:"pullrequest,omitempty"`
+	Issue        *Issue        `json:"issue,omitempty"`
+	Comment      *PRComment    `json:"comment,omitempty"`
+	Push         *WebhookPush  `json:"push,omitempty"`
+	CommitStatus *CommitStatus `json:"commit_status,omitempty"`
+	// Commit is the commit the event is about: a pull request's source
+	// commit, the newest pushed commit, or the commit a status was set on.
+	Commit *Commit `json:"commit,omitempty"`
+	// Pipeline is set for build statuses reported by Bitbucket Pipelines,
+	// which is how pipeline results reach webhooks.
+	Pipeline *Pipeline `json:"pipeline,omitempty"`
+
+	Payload json.RawMessage `json:"payload"`
+}
+
+// RepoFullName is the delivery's "workspace/repo", or "" if it has none.
+func (d *WebhookDelivery) RepoFullName() string {
+	if d.Repository == nil {
+		return ""
+	}
+	return d.Repository.FullName
+}
+
+// WebhookPush is the body of a repo:push event.
+type WebhookPush struct {
+	Changes []WebhookPushChange `json:"changes"`
+}
+
+// WebhookPushChange is one ref update in a push. New is nil when the ref was
+// deleted, Old is nil when it was created.
+type WebhookPushChange struct {
+	New       *Branch  `json:"new"`
+	Old       *Branch  `json:"old"`
+	Created   bool     `json:"created"`
+	Closed    bool     `json:"closed"`
+	Forced    bool     `json:"forced"`
+	Truncated bool     `json:"truncated"`
+	Commits   []Commit `json:"commits"`
+}
+
+// webhookStatus is a commit_status as delivered, which also carries the
+// commit it belongs to.
+type webhookStatus struct {
+	CommitStatus
+	Commit *Commit `json:"commit"`
+	Links  struct {
+		Commit struct {
+			Href string `json:"href"`
+		} `json:"commit"`
+	} `json:"links"`
+}
+
+// ParseWebhookDelivery decodes a delivery body for the event named by the
+// X-Event-Key header.
+func ParseWebhookDelivery(event string, body []byte) (*WebhookDelivery, error) {
+	if event == "" {
+		return nil, fmt.Errorf("event key is required")
+	}
+	var raw struct {
+		Repository   *Repository    `json:"repository"`
+		Actor        *User          `json:"actor"`
+		PullRequest  *PullRequest   `json:"pullrequest"`
+		Issue        *Issue         `json:"issue"`
+		Comment      *PRComment     `json:"comment"`
+		Push         *WebhookPush   `json:"push"`
+		CommitStatus *webhookStatus `json:"commit_status"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("invalid %s payload: %v", event, err)
+	}
+
+	d := &WebhookDelivery{
+		Event:       event,
+		Repository:  raw.Repository,
+		Actor:       raw.Actor,
+		PullRequest: raw.PullRequest,
+		Issue:       raw.Issue,
+		Comment:     raw.Comment,
+		Push:        raw.Push,
+		Payload:     json.RawMessage(body),
+	}
+	switch {
+	case raw.CommitStatus != nil:
+		st := raw.CommitStatus
+		d.CommitStatus = &st.CommitStatus
+		d.Commit = st.Commit
+		if d.Commit == nil {
+			if href := st.Links.Commit.Href; href != "" {
+				d.Commit = &Commit{Hash: href[strings.LastIndex(href, "/")+1:]}
+			}
+		}
+		d.Pipeline = pipelineFromStatus(&st.CommitStatus, d.Commit)
+	case raw.PullRequest != nil:
+		d.Commit = raw.PullRequest.Source.Commit
+	case raw.Push != nil:
+		for i := len(raw.Push.Changes) - 1; i >= 0; i-- {
+			if n := raw.Push.Changes[i].New; n != nil && n.Target != nil {
+				d.Commit = n.Target
+				break
+			}
+		}
+	}
+	return d, nil
+}
+
+var pipelineResultRe = regexp.MustCompile(`/pipelines/(?:home/)?results/(\d+)`)
+
+// pipelineFromStatus reconstructs a Pipeline from a build status that
+// Bitbucket Pipelines reported. Statuses from other CI systems return nil.
+func pipelineFromStatus(st *CommitStatus, commit *Commit) *Pipeline {
+	m := pipelineResultRe.FindStringSubmatch(strings.ReplaceAll(st.URL, "#!", ""))
+	if m == nil {
+		return nil
+	}
+	build, _ := strconv.Atoi(m[1])
+
+	state := &PipeState{Name: "COMPLETED"}
+	switch st.State {
+	case StatusInProgress:
+		state.Name = "IN_PROGRESS"
+	case StatusSuccessful, StatusFailed, StatusStopped:
+		state.Result = &PipeResult{Name: st.State}
+	}
+	return &Pipeline{
+		BuildNumber: build,
+		State:       state,
+		Target:      &PipeTarget{Type: "pipeline_ref_target", RefName: st.RefName, Commit: commit},
+		CreatedOn:   st.CreatedOn,
+		Links:       Links{"html": map[string]any{"href": st.URL}},
+	}
+}
+
+// VerifyWebhookSignature checks an X-Hub-Signature header against the body
+// using the webhook's secret.
+func VerifyWebhookSignature(secret string, body []byte, header string) bool {
+	if header == "" {
+		return false
+	}
+	return hmac.Equal([]byte(SignWebhookPayload(secret, body)), []byte(header))
+}
+
+// WebhookFilter selects deliveries by event and repository. Patterns use
+// path.Match syntax, so "pullrequest:*" and "my-team/*" work; an empty list
+// matches everything.
+type WebhookFilter struct {
+	Events []string `json:"events,omitempty"`
+	Repos  []string `json:"repos,omitempty"`
+}
+
+// Match reports whether the delivery passes the filter.
+func (f WebhookFilter) Match(d *WebhookDelivery) bool {
+	return matchAny(f.Events, d.Event) && matchAny(f.Repos, d.RepoFullName())
+}
+
+func matchAny(patterns []string, s string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultWebhookBodyLimit caps delivery bodies; Bitbucket payloads are far
+// smaller, so anything bigger is not a delivery.
+const DefaultWebhookBodyLimit = 10 << 20
+
+// recentDeliveries is how many request UUIDs a receiver remembers to drop
+// retried deliveries.
+const recentDeliveries = 256
+
+// WebhookReceiver is an http.Handler that verifies and parses webhook
+// deliveries and passes them to Handle. A Handle error answers 500, which
+// makes Bitbucket retry; retries of an already handled delivery are
+// acknowledged without calling Handle again.
+type WebhookReceiver struct {
+	// Secret verifies X-Hub-Signature; empty accepts unsigned deliveries.
+	Secret string
+	Handle func(context.Context, *WebhookDelivery) error
+	// MaxBodyBytes defaults to DefaultWebhookBodyLimit.
+	MaxBodyBytes int64
+
+	mu     sync.Mutex
+	seen   map[string]bool
+	recent []string
+}
+
+func (rcv *WebhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	limit := rcv.MaxBodyBytes
+	if limit == 0 {
+		limit = DefaultWebhookBodyLimit
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	if err != nil {
+		var tooBig *http.MaxBytesError
+		if errors.As(err, &tooBig) {
+			http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if rcv.Secret != "" && !VerifyWebhookSignature(rcv.Secret, body, r.Header.Get("X-Hub-Signature")) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	d, err := ParseWebhookDelivery(r.Header.Get("X-Event-Key"), body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	d.HookUUID = r.Header.Get("X-Hook-UUID")
+	d.RequestUUID = r.Header.Get("X-Request-UUID")
+	d.Attempt, _ = strconv.Atoi(r.Header.Get("X-Attempt-Number"))
+
+	if rcv.duplicate(d.RequestUUID) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if rcv.Handle != nil {
+		if err := rcv.Handle(r.Context(), d); err != nil {
+			rcv.forget(d.RequestUUID)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// duplicate records id and reports whether it was already seen.
+func (rcv *WebhookReceiver) duplicate(id string) bool {
+	if id == "" {
+		return false
+	}
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	if rcv.seen == nil {
+		rcv.seen = map[string]bool{}
+	}
+	if rcv.seen[id] {
+		return true
+	}
+	rcv.seen[id] = true
+	rcv.recent = append(rcv.recent, id)
+	if len(rcv.recent) > recentDeliveries {
+		delete(rcv.seen, rcv.recent[0])
+		rcv.recent = rcv.recent[1:]
+	}
+	return false
+}
+
+// forget drops id so a retry after a failed Handle is processed again.
+func (rcv *WebhookReceiver) forget(id string) {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	delete(rcv.seen, id)
+}
+
+// WebhookRecording is a delivery saved to disk for replaying later.
+type WebhookRecording struct {
+	Event       string          `json:"event"`
+	RequestUUID string          `json:"request_uuid,omitempty"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// Recording returns the delivery in its on-disk form.
+func (d *WebhookDelivery) Recording() WebhookRecording {
+	return WebhookRecording{Event: d.Event, RequestUUID: d.RequestUUID, Payload: d.Payload}
+}
+
+// LoadWebhookRecording parses a saved delivery. data is either a
+// WebhookRecording or a bare payload, e.g. one copied from Bitbucket's
+// request log; event names the event for a bare payload and overrides a
+// recording's.
+func LoadWebhookRecording(data []byte, event string) (*WebhookDelivery, error) {
+	var rec WebhookRecording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("invalid recording: %v", err)
+	}
+	if rec.Event == "" || len(rec.Payload) == 0 {
+		rec = WebhookRecording{Payload: data}
+	}
+	if event != "" {
+		rec.Event = event
+	}
+	if rec.Event == "" {
+		return nil, fmt.Errorf("recording has no event; pass the event key")
+	}
+	d, err := ParseWebhookDelivery(rec.Event, rec.Payload)
+	if err != nil {
+		return nil, err
+	}
+	d.RequestUUID = rec.RequestUUID
+	return d, nil
+}
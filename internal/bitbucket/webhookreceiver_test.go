@@ -0,0 +1,139 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func loadWebhookFixture(t *testing.T, name string) *WebhookDelivery {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "webhooks", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := LoadWebhookRecording(data, "")
+	if err != nil {
+		t.Fatalf("LoadWebhookRecording(%s): %v", name, err)
+	}
+	return d
+}
+
+func TestParseWebhookDeliveries(t *testing.T) {
+	pr := loadWebhookFixture(t, "pullrequest_created.json")
+	if pr.Event != "pullrequest:created" || pr.RepoFullName() != "acme/api" || pr.PullRequest == nil || pr.PullRequest.ID != 42 {
+		t.Errorf("pull request delivery = %+v", pr)
+	}
+	if pr.Commit == nil || pr.Commit.Hash != "3f2aa9a1c0de" || pr.Actor.DisplayName != "Sam Rivera" {
+		t.Errorf("commit = %+v, actor = %+v", pr.Commit, pr.Actor)
+	}
+
+	push := loadWebhookFixture(t, "repo_push.json")
+	if push.Push == nil || len(push.Push.Changes) != 1 || push.Push.Changes[0].New.Name != "main" {
+		t.Fatalf("push = %+v", push.Push)
+	}
+	if push.Commit == nil || push.Commit.Hash != "a1b2c3d4e5f6" || push.Pipeline != nil {
+		t.Errorf("push commit = %+v, pipeline = %+v", push.Commit, push.Pipeline)
+	}
+
+	st := loadWebhookFixture(t, "commit_status_failed.json")
+	if st.CommitStatus == nil || st.CommitStatus.State != StatusFailed || st.Commit.Hash != "a1b2c3d4e5f6" {
+		t.Fatalf("status = %+v, commit = %+v", st.CommitStatus, st.Commit)
+	}
+	p := st.Pipeline
+	if p == nil || p.BuildNumber != 318 || p.State.Result == nil || p.State.Result.Name != "FAILED" || p.Target.RefName != "main" {
+		t.Errorf("pipeline = %+v", p)
+	}
+}
+
+func TestLoadWebhookRecordingBarePayload(t *testing.T) {
+	if _, err := LoadWebhookRecording([]byte(`{"repository":{"full_name":"a/b"}}`), ""); err == nil {
+		t.Error("expected error without an event key")
+	}
+	d, err := LoadWebhookRecording([]byte(`{"repository":{"full_name":"a/b"}}`), "repo:updated")
+	if err != nil || d.Event != "repo:updated" || d.RepoFullName() != "a/b" {
+		t.Errorf("delivery = %+v, err = %v", d, err)
+	}
+}
+
+func TestWebhookFilter(t *testing.T) {
+	d := &WebhookDelivery{Event: "pullrequest:created", Repository: &Repository{FullName: "acme/api"}}
+	cases := []struct {
+		f    WebhookFilter
+		want bool
+	}{
+		{WebhookFilter{}, true},
+		{WebhookFilter{Events: []string{"pullrequest:*"}}, true},
+		{WebhookFilter{Events: []string{"repo:push"}}, false},
+		{WebhookFilter{Repos: []string{"acme/*"}}, true},
+		{WebhookFilter{Events: []string{"pullrequest:created"}, Repos: []string{"other/*"}}, false},
+	}
+	for _, c := range cases {
+		if got := c.f.Match(d); got != c.want {
+			t.Errorf("%+v.Match = %v, want %v", c.f, got, c.want)
+		}
+	}
+}
+
+func TestWebhookReceiver(t *testing.T) {
+	var rec WebhookRecording
+	data, _ := os.ReadFile(filepath.Join("testdata", "webhooks", "pullrequest_created.json"))
+	_ = json.Unmarshal(data, &rec)
+	body := []byte(rec.Payload)
+
+	var handled []*WebhookDelivery
+	fail := false
+	rcv := &WebhookReceiver{Secret: "s3cret", Handle: func(_ context.Context, d *WebhookDelivery) error {
+		if fail {
+			return errors.New("handler failed")
+		}
+		handled = append(handled, d)
+		return nil
+	}}
+	send := func(sig, requestID string) int {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		req.Header.Set("X-Event-Key", "pullrequest:created")
+		req.Header.Set("X-Request-UUID", requestID)
+		if sig != "" {
+			req.Header.Set("X-Hub-Signature", sig)
+		}
+		w := httptest.NewRecorder()
+		rcv.ServeHTTP(w, req)
+		return w.Code
+	}
+	good := SignWebhookPayload("s3cret", body)
+
+	if code := send("", "r1"); code != http.StatusUnauthorized {
+		t.Errorf("unsigned: %d", code)
+	}
+	if code := send(SignWebhookPayload("wrong", body), "r1"); code != http.StatusUnauthorized {
+		t.Errorf("bad signature: %d", code)
+	}
+	fail = true
+	if code := send(good, "r1"); code != http.StatusInternalServerError {
+		t.Errorf("failing handler: %d", code)
+	}
+	fail = false
+	if code := send(good, "r1"); code != http.StatusNoContent {
+		t.Errorf("retry after failure: %d", code)
+	}
+	// A retry of a delivery that was handled is acknowledged but not redelivered.
+	if code := send(good, "r1"); code != http.StatusOK {
+		t.Errorf("duplicate: %d", code)
+	}
+	if len(handled) != 1 || handled[0].PullRequest.ID != 42 || handled[0].RequestUUID != "r1" {
+		t.Errorf("handled = %+v", handled)
+	}
+
+	w := httptest.NewRecorder()
+	rcv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: %d", w.Code)
+	}
+}
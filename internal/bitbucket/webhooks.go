@@ -0,0 +1,463 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Webhook is a repository or workspace webhook subscription.
+type Webhook struct {
+	UUID                 string     `json:"uuid"`
+	URL                  string     `json:"url"`
+	Description          string     `json:"description"`
+	SubjectType          string     `json:"subject_type"`
+	Active               bool       `json:"active"`
+	Events               []string   `json:"events"`
+	SecretSet            bool       `json:"secret_set"`
+	SkipCertVerification bool       `json:"skip_cert_verification"`
+	CreatedAt            *time.Time `json:"created_at"`
+}
+
+// WebhookEvent describes one event a webhook can subscribe to.
+type WebhookEvent struct {
+	Event       string `json:"event"`
+	Description string `json:"description"`
+}
+
+// WebhookEvents is the catalog of events Bitbucket delivers. Workspace hooks
+// accept the same events and receive them for every repository.
+var WebhookEvents = []WebhookEvent{
+	{"repo:push", "Commits or tags pushed"},
+	{"repo:fork", "Repository forked"},
+	{"repo:updated", "Repository settings changed"},
+	{"repo:imported", "Repository import finished"},
+	{"repo:transfer", "Repository ownership transferred"},
+	{"repo:commit_comment_created", "Comment added to a commit"},
+	{"repo:commit_status_created", "Build status created"},
+	{"repo:commit_status_updated", "Build status updated"},
+	{"issue:created", "Issue created"},
+	{"issue:updated", "Issue updated"},
+	{"issue:comment_created", "Comment added to an issue"},
+	{"pullrequest:created", "Pull request created"},
+	{"pullrequest:updated", "Pull request updated"},
+	{"pullrequest:push", "Commits pushed to a pull request's source branch"},
+	{"pullrequest:approved", "Pull request approved"},
+	{"pullrequest:unapproved", "Pull request approval removed"},
+	{"pullrequest:changes_request_created", "Changes requested"},
+	{"pullrequest:changes_request_removed", "Changes request removed"},
+	{"pullrequest:fulfilled", "Pull request merged"},
+	{"pullrequest:rejected", "Pull request declined"},
+	{"pullrequest:comment_created", "Comment added to a pull request"},
+	{"pullrequest:comment_updated", "Pull request comment edited"},
+	{"pullrequest:comment_deleted", "Pull request comment deleted"},
+	{"pullrequest:comment_resolved", "Pull request comment resolved"},
+	{"pullrequest:comment_reopened", "Pull request comment reopened"},
+}
+
+// ExpandWebhookEvents validates event names against WebhookEvents and
+// expands "category:*" wildcards such as "pullrequest:*". The result is
+// sorted and free of duplicates.
+func ExpandWebhookEvents(events []string) ([]string, error) {
+	var out []string
+	for _, e := range events {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		category, name, ok := strings.Cut(e, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid webhook event %q: expected category:name, e.g. repo:push", e)
+		}
+
+		var matched []string
+		for _, known := range WebhookEvents {
+			if known.Event == e || (name == "*" && strings.HasPrefix(known.Event, category+":")) {
+				matched = append(matched, known.Event)
+			}
+		}
+		if len(matched) == 0 {
+			var inCategory []string
+			for _, known := range WebhookEvents {
+				if strings.HasPrefix(known.Event, category+":") {
+					inCategory = append(inCategory, known.Event)
+				}
+			}
+			if len(inCategory) > 0 {
+				return nil, fmt.Errorf("unknown webhook event %q; %s events are: %s", e, category, strings.Join(inCategory, ", "))
+			}
+			return nil, fmt.Errorf("unknown webhook event %q; categories are repo, issue, and pullrequest", e)
+		}
+		out = append(out, matched...)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("at least one event is required")
+	}
+	out = dedupeStrings(out)
+	sort.Strings(out)
+	return out, nil
+}
+
+// WebhookScopeArgs addresses the webhooks of a workspace, or of a single
+// repository when RepoSlug is set.
+type WebhookScopeArgs struct {
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug  string `json:"repo_slug,omitempty" jsonschema:"Repository slug (omit for workspace webhooks)"`
+}
+
+func (a WebhookScopeArgs) basePath() (string, error) {
+	if a.Workspace == "" {
+		return "", fmt.Errorf("workspace is required")
+	}
+	if a.RepoSlug != "" {
+		return fmt.Sprintf("/repositories/%s/%s/hooks", QueryEscape(a.Workspace), QueryEscape(a.RepoSlug)), nil
+	}
+	return fmt.Sprintf("/workspaces/%s/hooks", QueryEscape(a.Workspace)), nil
+}
+
+type ListWebhooksArgs struct {
+	WebhookScopeArgs
+	Pagelen int `json:"pagelen,omitempty" jsonschema:"Results per page (default 25)"`
+	Page    int `json:"page,omitempty" jsonschema:"Page number"`
+}
+
+// ListWebhooks lists workspace or repository webhooks.
+func (c *Client) ListWebhooks(args ListWebhooksArgs) (*Paginated[Webhook], error) {
+	base, err := args.basePath()
+	if err != nil {
+		return nil, err
+	}
+
+	pagelen := args.Pagelen
+	if pagelen == 0 {
+		pagelen = 25
+	}
+	page := args.Page
+	if page == 0 {
+		page = 1
+	}
+
+	return GetPaginated[Webhook](c, fmt.Sprintf("%s?pagelen=%d&page=%d", base, pagelen, page))
+}
+
+// maxWebhookPages bounds ListAllWebhooks; Bitbucket caps hooks per subject
+// well below this.
+const maxWebhookPages = 10
+
+// ListAllWebhooks lists every webhook in the scope.
+func (c *Client) ListAllWebhooks(args WebhookScopeArgs) ([]Webhook, error) {
+	hooks := []Webhook{}
+	for page := 1; page <= maxWebhookPages; page++ {
+		result, err := c.ListWebhooks(ListWebhooksArgs{WebhookScopeArgs: args, Pagelen: 100, Page: page})
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, result.Values...)
+		if result.Next == "" {
+			break
+		}
+	}
+	return hooks, nil
+}
+
+type WebhookArgs struct {
+	WebhookScopeArgs
+	UID string `json:"uid" jsonschema:"Webhook UUID"`
+}
+
+func (a WebhookArgs) path() (string, error) {
+	base, err := a.basePath()
+	if err != nil {
+		return "", err
+	}
+	if a.UID == "" {
+		return "", fmt.Errorf("uid is required")
+	}
+	return base + "/" + QueryEscape(normalizeUUID(a.UID)), nil
+}
+
+// GetWebhook gets a single webhook.
+func (c *Client) GetWebhook(args WebhookArgs) (*Webhook, error) {
+	path, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+	return GetJSON[Webhook](c, path)
+}
+
+// DeleteWebhook deletes a webhook.
+func (c *Client) DeleteWebhook(args WebhookArgs) error {
+	path, err := args.path()
+	if err != nil {
+		return err
+	}
+	return c.Delete(path)
+}
+
+type CreateWebhookArgs struct {
+	WebhookScopeArgs
+	URL                  string   `json:"url" jsonschema:"Delivery URL"`
+	Description          string   `json:"description,omitempty" jsonschema:"Description"`
+	Events               []string `json:"events" jsonschema:"Events to subscribe to, e.g. repo:push or pullrequest:*"`
+	Secret               string   `json:"secret,omitempty" jsonschema:"Secret used to sign deliveries (X-Hub-Signature)"`
+	Inactive             bool     `json:"inactive,omitempty" jsonschema:"Create the webhook disabled"`
+	SkipCertVerification bool     `json:"skip_cert_verification,omitempty" jsonschema:"Skip TLS certificate verification"`
+}
+
+// CreateWebhook creates a webhook after validating its URL and events.
+func (c *Client) CreateWebhook(args CreateWebhookArgs) (*Webhook, error) {
+	base, err := args.basePath()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateWebhookURL(args.URL); err != nil {
+		return nil, err
+	}
+	events, err := ExpandWebhookEvents(args.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	description := args.Description
+	if description == "" {
+		description = args.URL
+	}
+	body := map[string]any{
+		"url":                    args.URL,
+		"description":            description,
+		"active":                 !args.Inactive,
+		"events":                 events,
+		"skip_cert_verification": args.SkipCertVerification,
+	}
+	if args.Secret != "" {
+		body["secret"] = args.Secret
+	}
+
+	respData, err := c.Post(base, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %v", err)
+	}
+
+	var hook Webhook
+	if err := json.Unmarshal(respData, &hook); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &hook, nil
+}
+
+type UpdateWebhookArgs struct {
+	WebhookArgs
+	URL                  *string  `json:"url,omitempty" jsonschema:"New delivery URL"`
+	Description          *string  `json:"description,omitempty" jsonschema:"New description"`
+	Events               []string `json:"events,omitempty" jsonschema:"Replacement event list"`
+	Active               *bool    `json:"active,omitempty" jsonschema:"Enable or disable the webhook"`
+	Secret               *string  `json:"secret,omitempty" jsonschema:"New secret (empty string removes it)"`
+	SkipCertVerification *bool    `json:"skip_cert_verification,omitempty" jsonschema:"Skip TLS certificate verification"`
+}
+
+// UpdateWebhook changes the given fields of a webhook and keeps the rest.
+// The secret is left untouched unless Secret is set.
+func (c *Client) UpdateWebhook(args UpdateWebhookArgs) (*Webhook, error) {
+	path, err := args.path()
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := GetJSON[Webhook](c, path)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]any{
+		"url":                    current.URL,
+		"description":            current.Description,
+		"active":                 current.Active,
+		"events":                 current.Events,
+		"skip_cert_verification": current.SkipCertVerification,
+	}
+	if args.URL != nil {
+		if err := validateWebhookURL(*args.URL); err != nil {
+			return nil, err
+		}
+		body["url"] = *args.URL
+	}
+	if args.Description != nil {
+		body["description"] = *args.Description
+	}
+	if len(args.Events) > 0 {
+		events, err := ExpandWebhookEvents(args.Events)
+		if err != nil {
+			return nil, err
+		}
+		body["events"] = events
+	}
+	if args.Active != nil {
+		body["active"] = *args.Active
+	}
+	if args.SkipCertVerification != nil {
+		body["skip_cert_verification"] = *args.SkipCertVerification
+	}
+	if args.Secret != nil {
+		if *args.Secret == "" {
+			body["secret"] = nil // Remove
+		} else {
+			body["secret"] = *args.Secret
+		}
+	}
+
+	respData, err := c.Put(path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %v", err)
+	}
+
+	var hook Webhook
+	if err := json.Unmarshal(respData, &hook); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return &hook, nil
+}
+
+func validateWebhookURL(raw string) error {
+	if raw == "" {
+		return fmt.Errorf("url is required")
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook url %q: must be an absolute http(s) URL", raw)
+	}
+	return nil
+}
+
+// SignWebhookPayload computes the X-Hub-Signature value Bitbucket sends with
+// deliveries from a webhook that has a secret.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookTestResult is the outcome of a test delivery.
+type WebhookTestResult struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	Signed     bool   `json:"signed"`
+	Error      string `json:"error,omitempty"`
+}
+
+// OK reports whether the endpoint accepted the delivery with a 2xx status.
+func (r WebhookTestResult) OK() bool {
+	return r.Error == "" && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// TestWebhook sends a diagnostics:ping delivery straight to the hook's URL,
+// shaped like a Bitbucket delivery. Bitbucket never returns a hook's secret,
+// so pass it to have the request signed the way real deliveries are.
+func TestWebhook(ctx context.Context, hc *http.Client, hook *Webhook, secret string) WebhookTestResult {
+	res := WebhookTestResult{URL: hook.URL, Signed: secret != ""}
+	body, _ := json.Marshal(map[string]any{
+		"event":      "diagnostics:ping",
+		"hook_uuid":  hook.UUID,
+		"events":     hook.Events,
+		"sent_by":    "bbkt",
+		"created_on": time.Now().UTC().Format(time.RFC3339),
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Bitbucket-Webhooks/2.0 (bbkt test)")
+	req.Header.Set("X-Event-Key", "diagnostics:ping")
+	req.Header.Set("X-Hook-UUID", trimBraces(hook.UUID))
+	if secret != "" {
+		req.Header.Set("X-Hub-Signature", SignWebhookPayload(secret, body))
+	}
+
+	start := time.Now()
+	resp, err := hc.Do(req)
+	res.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	resp.Body.Close()
+	res.StatusCode = resp.StatusCode
+	return res
+}
+
+// WebhookAudit lists the problems found with one webhook.
+type WebhookAudit struct {
+	Webhook Webhook  `json:"webhook"`
+	Issues  []string `json:"issues"`
+}
+
+// internalSuffixes are host suffixes that only resolve on private networks.
+var internalSuffixes = []string{".local", ".localhost", ".internal", ".lan", ".corp", ".home", ".test", ".example", ".invalid"}
+
+// AuditWebhooks flags webhooks that Bitbucket probably cannot deliver to, or
+// that deliver insecurely: non-HTTPS URLs, private or loopback addresses,
+// internal-looking host names, disabled certificate checks, missing secrets,
+// and inactive hooks. When resolve is non-nil it is also asked to look up
+// each host, and failures are reported.
+func AuditWebhooks(hooks []Webhook, resolve func(host string) error) []WebhookAudit {
+	out := []WebhookAudit{}
+	for _, h := range hooks {
+		a := WebhookAudit{Webhook: h, Issues: []string{}}
+		u, err := url.Parse(h.URL)
+		if err != nil || u.Host == "" {
+			a.Issues = append(a.Issues, "URL is not valid")
+		} else {
+			if u.Scheme != "https" {
+				a.Issues = append(a.Issues, "not HTTPS: payloads are sent in clear text")
+			}
+			host := strings.ToLower(u.Hostname())
+			if ip := net.ParseIP(host); ip != nil {
+				if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() {
+					a.Issues = append(a.Issues, "private or loopback address: unreachable from Bitbucket")
+				} else {
+					a.Issues = append(a.Issues, "raw IP address: certificate validation is unlikely to work")
+				}
+			} else if host == "localhost" || !strings.Contains(host, ".") {
+				a.Issues = append(a.Issues, "single-label host name: unreachable from Bitbucket")
+			} else if suffix := matchSuffix(host, internalSuffixes); suffix != "" {
+				a.Issues = append(a.Issues, fmt.Sprintf("%s host name: probably only resolvable internally", suffix))
+			} else if resolve != nil {
+				if err := resolve(host); err != nil {
+					a.Issues = append(a.Issues, "host does not resolve")
+				}
+			}
+		}
+		if h.SkipCertVerification {
+			a.Issues = append(a.Issues, "TLS certificate verification is disabled")
+		}
+		if !h.SecretSet {
+			a.Issues = append(a.Issues, "no secret: deliveries are unsigned")
+		}
+		if !h.Active {
+			a.Issues = append(a.Issues, "inactive")
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+func matchSuffix(host string, suffixes []string) string {
+	for _, s := range suffixes {
+		if strings.HasSuffix(host, s) {
+			return s
+		}
+	}
+	return ""
+}
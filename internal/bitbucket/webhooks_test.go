@@ -0,0 +1,154 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExpandWebhookEvents(t *testing.T) {
+	got, err := ExpandWebhookEvents([]string{"repo:push", "pullrequest:*", "repo:push"})
+	if err != nil {
+		t.Fatalf("ExpandWebhookEvents: %v", err)
+	}
+	if !containsString(got, "pullrequest:fulfilled") || !containsString(got, "repo:push") || containsString(got, "issue:created") {
+		t.Errorf("events = %v", got)
+	}
+	if n := strings.Count(strings.Join(got, ","), "repo:push"); n != 1 {
+		t.Errorf("repo:push appears %d times", n)
+	}
+
+	for in, want := range map[string]string{
+		"repo:pushed": "repo events are",
+		"build:done":  "categories are",
+		"push":        "expected category:name",
+	} {
+		if _, err := ExpandWebhookEvents([]string{in}); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want %q", in, err, want)
+		}
+	}
+	if _, err := ExpandWebhookEvents(nil); err == nil {
+		t.Error("expected error for no events")
+	}
+}
+
+func TestCreateWebhook(t *testing.T) {
+	var body map[string]any
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/workspaces/w/hooks" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		b, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(b, &body)
+		_, _ = w.Write([]byte(`{"uuid":"{h1}","url":"https://ci.example.com/hook","active":true,"secret_set":true}`))
+	})
+
+	hook, err := c.CreateWebhook(CreateWebhookArgs{
+		WebhookScopeArgs: WebhookScopeArgs{Workspace: "w"},
+		URL:              "https://ci.example.com/hook",
+		Events:           []string{"issue:*"},
+		Secret:           "s3cret",
+	})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	if hook.UUID != "{h1}" || body["secret"] != "s3cret" || body["active"] != true || len(body["events"].([]any)) != 3 {
+		t.Errorf("hook = %+v, body = %v", hook, body)
+	}
+
+	if _, err := c.CreateWebhook(CreateWebhookArgs{
+		WebhookScopeArgs: WebhookScopeArgs{Workspace: "w"},
+		URL:              "ftp://example.com",
+		Events:           []string{"repo:push"},
+	}); err == nil {
+		t.Error("expected error for a non-http URL")
+	}
+}
+
+func TestUpdateWebhookKeepsUnsetFields(t *testing.T) {
+	var body map[string]any
+	c := newBearerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/w/r/hooks/{h1}" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"uuid":"{h1}","url":"https://a.example.com","description":"ci","active":true,"events":["repo:push"]}`))
+		case http.MethodPut:
+			b, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(b, &body)
+			_, _ = w.Write(b)
+		}
+	})
+
+	inactive := false
+	noSecret := ""
+	_, err := c.UpdateWebhook(UpdateWebhookArgs{
+		WebhookArgs: WebhookArgs{WebhookScopeArgs: WebhookScopeArgs{Workspace: "w", RepoSlug: "r"}, UID: "h1"},
+		Active:      &inactive,
+		Secret:      &noSecret,
+	})
+	if err != nil {
+		t.Fatalf("UpdateWebhook: %v", err)
+	}
+	if body["active"] != false || body["url"] != "https://a.example.com" || body["description"] != "ci" {
+		t.Errorf("body = %v", body)
+	}
+	if v, ok := body["secret"]; !ok || v != nil {
+		t.Errorf("secret = %v (present %v), want explicit null", v, ok)
+	}
+}
+
+func TestTestWebhookSignsDelivery(t *testing.T) {
+	var sig, event string
+	var payload []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sig = r.Header.Get("X-Hub-Signature")
+		event = r.Header.Get("X-Event-Key")
+		payload, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	res := TestWebhook(context.Background(), srv.Client(), &Webhook{UUID: "{h1}", URL: srv.URL}, "s3cret")
+	if !res.OK() || res.StatusCode != http.StatusNoContent || !res.Signed {
+		t.Errorf("result = %+v", res)
+	}
+	if event != "diagnostics:ping" || sig != SignWebhookPayload("s3cret", payload) {
+		t.Errorf("event = %q, signature = %q", event, sig)
+	}
+}
+
+func TestAuditWebhooks(t *testing.T) {
+	hooks := []Webhook{
+		{URL: "https://ci.example.com/hook", Active: true, SecretSet: true},
+		{URL: "http://10.0.0.5:8080/hook", Active: true, SecretSet: true},
+		{URL: "https://jenkins.corp/hook", Active: false, SecretSet: false, SkipCertVerification: true},
+		{URL: "https://gone.example.org/hook", Active: true, SecretSet: true},
+	}
+	audits := AuditWebhooks(hooks, func(host string) error {
+		if host == "gone.example.org" {
+			return errors.New("no such host")
+		}
+		return nil
+	})
+
+	if len(audits[0].Issues) != 0 {
+		t.Errorf("clean hook issues = %v", audits[0].Issues)
+	}
+	if got := strings.Join(audits[1].Issues, "|"); !strings.Contains(got, "not HTTPS") || !strings.Contains(got, "private") {
+		t.Errorf("private hook issues = %v", audits[1].Issues)
+	}
+	if got := strings.Join(audits[2].Issues, "|"); !strings.Contains(got, ".corp") || !strings.Contains(got, "no secret") ||
+		!strings.Contains(got, "inactive") || !strings.Contains(got, "verification") {
+		t.Errorf("internal hook issues = %v", audits[2].Issues)
+	}
+	if len(audits[3].Issues) != 1 || audits[3].Issues[0] != "host does not resolve" {
+		t.Errorf("unresolvable hook issues = %v", audits[3].Issues)
+	}
+}
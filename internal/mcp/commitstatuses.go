@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+type ManageCommitStatusesArgs struct {
+	Action      string `json:"action" jsonschema:"Action to perform: 'list', 'list-pr', 'summary', 'set'" jsonschema_enum:"list,list-pr,summary,set"`
+	Workspace   string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug    string `json:"repo_slug" jsonschema:"Repository slug"`
+	Commit      string `json:"commit,omitempty" jsonschema:"Commit hash (for 'list' and 'set')"`
+	PRID        int    `json:"pr_id,omitempty" jsonschema:"Pull request ID (for 'list-pr' and 'summary')"`
+	Key         string `json:"key,omitempty" jsonschema:"Unique build key; reusing a key updates that status (for 'set')"`
+	State       string `json:"state,omitempty" jsonschema:"SUCCESSFUL, FAILED, INPROGRESS, or STOPPED (for 'set')"`
+	URL         string `json:"url,omitempty" jsonschema:"Link to the build results (for 'set')"`
+	Name        string `json:"name,omitempty" jsonschema:"Display name (for 'set')"`
+	Description string `json:"description,omitempty" jsonschema:"Short result description (for 'set')"`
+	RefName     string `json:"refname,omitempty" jsonschema:"Branch or tag the build ran for (for 'set')"`
+	Page        int    `json:"page,omitempty" jsonschema:"Page number"`
+	Pagelen     int    `json:"pagelen,omitempty" jsonschema:"Results per page"`
+}
+
+// ManageCommitStatusesHandler handles commit build status operations.
+// 'set' is refused unless canWrite, i.e. the token has the
+// repository:write scope.
+func ManageCommitStatusesHandler(c *bitbucket.Client, canWrite bool) func(context.Context, *mcp.CallToolRequest, ManageCommitStatusesArgs) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args ManageCommitStatusesArgs) (*mcp.CallToolResult, any, error) {
+		args.Workspace, args.RepoSlug = ResolveScope(args.Workspace, args.RepoSlug)
+		switch args.Action {
+		case "list":
+			if args.Commit == "" {
+				return ToolResultError("commit is required for 'list' action"), nil, nil
+			}
+			result, err := c.ListCommitStatuses(bitbucket.ListCommitStatusesArgs{
+				Workspace: args.Workspace,
+				RepoSlug:  args.RepoSlug,
+				Commit:    args.Commit,
+				Page:      args.Page,
+				Pagelen:   args.Pagelen,
+			})
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to list commit statuses: %v", err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(result, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
+		case "list-pr":
+			if args.PRID == 0 {
+				return ToolResultError("pr_id is required for 'list-pr' action"), nil, nil
+			}
+			result, err := c.ListPullRequestStatuses(bitbucket.ListPullRequestStatusesArgs{
+				Workspace: args.Workspace,
+				RepoSlug:  args.RepoSlug,
+				PRID:      args.PRID,
+				Page:      args.Page,
+				Pagelen:   args.Pagelen,
+			})
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to list pull request statuses: %v", err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(result, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
+		case "summary":
+			if args.PRID == 0 {
+				return ToolResultError("pr_id is required for 'summary' action"), nil, nil
+			}
+			sum, err := c.PullRequestBuildSummary(args.Workspace, args.RepoSlug, args.PRID)
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to summarise pull request statuses: %v", err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(sum, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
+		case "set":
+			if !canWrite {
+				return ToolResultError("the 'set' action requires the repository:write scope"), nil, nil
+			}
+			st, err := c.SetCommitStatus(bitbucket.SetCommitStatusArgs{
+				Workspace:   args.Workspace,
+				RepoSlug:    args.RepoSlug,
+				Commit:      args.Commit,
+				Key:         args.Key,
+				State:       args.State,
+				URL:         args.URL,
+				Name:        args.Name,
+				Description: args.Description,
+				RefName:     args.RefName,
+			})
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to set commit status: %v", err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(st, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
+		default:
+			return ToolResultError(fmt.Sprintf("unknown action: %s", args.Action)), nil, nil
+		}
+	}
+}
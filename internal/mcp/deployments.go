@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+type ManageDeploymentsArgs struct {
+	Action      string `json:"action" jsonschema:"Action to perform: 'list-environments', 'get-environment', 'list-deployments', 'status', 'diff'" jsonschema_enum:"list-environments,get-environment,list-deployments,status,diff"`
+	Workspace   string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug    string `json:"repo_slug" jsonschema:"Repository slug"`
+	Environment string `json:"environment,omitempty" jsonschema:"Environment name, slug, or UUID (for 'get-environment'; optional filter for 'list-deployments')"`
+	From        string `json:"from,omitempty" jsonschema:"Environment with the newer deployment, e.g. staging (for 'diff')"`
+	To          string `json:"to,omitempty" jsonschema:"Environment to compare against, e.g. production (for 'diff')"`
+	Page        int    `json:"page,omitempty" jsonschema:"Page number"`
+	Pagelen     int    `json:"pagelen,omitempty" jsonschema:"Results per page"`
+}
+
+// ManageDeploymentsHandler handles read-only deployment and environment operations.
+func ManageDeploymentsHandler(c *bitbucket.Client) func(context.Context, *mcp.CallToolRequest, ManageDeploymentsArgs) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args ManageDeploymentsArgs) (*mcp.CallToolResult, any, error) {
+		args.Workspace, args.RepoSlug = ResolveScope(args.Workspace, args.RepoSlug)
+		var (
+			result any
+			err    error
+		)
+		switch args.Action {
+		case "list-environments":
+			result, err = c.ListEnvironments(bitbucket.ListEnvironmentsArgs{
+				Workspace: args.Workspace,
+				RepoSlug:  args.RepoSlug,
+			})
+
+		case "get-environment":
+			result, err = c.GetEnvironment(bitbucket.GetEnvironmentArgs{
+				Workspace:   args.Workspace,
+				RepoSlug:    args.RepoSlug,
+				Environment: args.Environment,
+			})
+
+		case "list-deployments":
+			result, err = c.ListDeployments(bitbucket.ListDeploymentsArgs{
+				Workspace:   args.Workspace,
+				RepoSlug:    args.RepoSlug,
+				Environment: args.Environment,
+				Page:        args.Page,
+				Pagelen:     args.Pagelen,
+			})
+
+		case "status":
+			result, err = c.GetEnvironmentStatuses(bitbucket.ListEnvironmentsArgs{
+				Workspace: args.Workspace,
+				RepoSlug:  args.RepoSlug,
+			})
+
+		case "diff":
+			result, err = c.DiffEnvironments(bitbucket.DiffEnvironmentsArgs{
+				Workspace: args.Workspace,
+				RepoSlug:  args.RepoSlug,
+				From:      args.From,
+				To:        args.To,
+				Pagelen:   args.Pagelen,
+			})
+
+		default:
+			return ToolResultError(fmt.Sprintf("unknown action: %s", args.Action)), nil, nil
+		}
+		if err != nil {
+			return ToolResultError(fmt.Sprintf("failed to %s: %v", args.Action, err)), nil, nil
+		}
+		data, _ := json.MarshalIndent(result, "", "  ")
+		return ToolResultText(string(data)), nil, nil
+	}
+}
@@ -1,40 +1,66 @@
 package mcp
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/zach-snell/bbkt/internal/bitbucket"
 )
 
 type ManageIssuesArgs struct {
-	Action    string `json:"action" jsonschema:"Action to perform: 'list', 'get', 'create', 'update'" jsonschema_enum:"list,get,create,update"`
+	Action    string `json:"action" jsonschema:"Action to perform: 'list', 'get', 'create', 'update', 'list-comments', 'add-comment', 'history', 'list-attachments', 'attach', 'get-attachment', 'vote', 'unvote', 'watch', 'unwatch', 'list-milestones', 'list-components', 'list-versions'" jsonschema_enum:"list,get,create,update,list-milestones,list-components,list-versions,list-comments,add-comment,history,list-attachments,attach,get-attachment,vote,unvote,watch,unwatch"`
 	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
 	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
-	IssueID   int    `json:"issue_id,omitempty" jsonschema:"Issue ID (for 'get', 'update')"`
+	IssueID   int    `json:"issue_id,omitempty" jsonschema:"Issue ID (for all but 'list' and 'create')"`
 	Title     string `json:"title,omitempty" jsonschema:"Issue title (for 'create', 'update')"`
-	Content   string `json:"content,omitempty" jsonschema:"Issue description content (for 'create', 'update')"`
+	Content   string `json:"content,omitempty" jsonschema:"Issue description content (for 'create', 'update'), the comment text (for 'add-comment'), or the text of the file to attach (for 'attach')"`
 	State     string `json:"state,omitempty" jsonschema:"Issue state: new, open, resolved, on hold, invalid, duplicate, wontfix, closed (for 'list', 'create', 'update')"`
 	Kind      string `json:"kind,omitempty" jsonschema:"Issue kind: bug, enhancement, proposal, task (for 'create', 'update')"`
 	Priority  string `json:"priority,omitempty" jsonschema:"Issue priority: trivial, minor, major, critical, blocker (for 'create', 'update')"`
 	Assignee  string `json:"assignee,omitempty" jsonschema:"Account ID of the user assigned to the issue (for 'create', 'update')"`
+	Milestone string `json:"milestone,omitempty" jsonschema:"Milestone name (filter for 'list'; set for 'create', 'update'). Must be one of 'list-milestones'"`
+	Component string `json:"component,omitempty" jsonschema:"Component name (filter for 'list'; set for 'create', 'update'). Must be one of 'list-components'"`
+	Version   string `json:"version,omitempty" jsonschema:"Version name (filter for 'list'; set for 'create', 'update'). Must be one of 'list-versions'"`
 	Query     string `json:"query,omitempty" jsonschema:"Filter query (for 'list')"`
+	Name      string `json:"name,omitempty" jsonschema:"Attachment name (for 'attach', 'get-attachment')"`
+	Base64    string `json:"content_base64,omitempty" jsonschema:"Base64-encoded content of a binary file to attach, instead of content (for 'attach')"`
 	Page      int    `json:"page,omitempty" jsonschema:"Page number"`
 	Pagelen   int    `json:"pagelen,omitempty" jsonschema:"Results per page"`
 }
 
-// ManageIssuesHandler handles the consolidated issue operations.
-func ManageIssuesHandler(c *bitbucket.Client) func(context.Context, *mcp.CallToolRequest, ManageIssuesArgs) (*mcp.CallToolResult, any, error) {
+// issueWriteActions change issues or the caller's votes and watches.
+var issueWriteActions = []string{"create", "update", "add-comment", "attach", "vote", "unvote", "watch", "unwatch"}
+
+// maxInlineAttachment bounds the attachments 'get-attachment' returns;
+// larger ones are left to 'bbkt issues attachments download'.
+const maxInlineAttachment = 1 << 20
+
+// ManageIssuesHandler handles the consolidated issue operations. Actions
+// that change issues are refused unless canWrite, i.e. the token has the
+// issue:write scope.
+func ManageIssuesHandler(c *bitbucket.Client, canWrite bool) func(context.Context, *mcp.CallToolRequest, ManageIssuesArgs) (*mcp.CallToolResult, any, error) {
 	return func(ctx context.Context, req *mcp.CallToolRequest, args ManageIssuesArgs) (*mcp.CallToolResult, any, error) {
 		args.Workspace, args.RepoSlug = ResolveScope(args.Workspace, args.RepoSlug)
+		for _, a := range issueWriteActions {
+			if a == args.Action && !canWrite {
+				return ToolResultError(fmt.Sprintf("'%s' requires the issue:write scope", args.Action)), nil, nil
+			}
+		}
 		switch args.Action {
 		case "list":
 			result, err := c.ListIssues(bitbucket.ListIssuesArgs{
 				Workspace: args.Workspace,
 				RepoSlug:  args.RepoSlug,
 				State:     args.State,
+				Milestone: args.Milestone,
+				Component: args.Component,
+				Version:   args.Version,
 				Page:      args.Page,
 				Pagelen:   args.Pagelen,
 				Search:    args.Query, // map query to search
@@ -72,6 +98,9 @@ func ManageIssuesHandler(c *bitbucket.Client) func(context.Context, *mcp.CallToo
 				Kind:      args.Kind,
 				Priority:  args.Priority,
 				Assignee:  args.Assignee,
+				Milestone: args.Milestone,
+				Component: args.Component,
+				Version:   args.Version,
 			})
 			if err != nil {
 				return ToolResultError(fmt.Sprintf("failed to create issue: %v", err)), nil, nil
@@ -84,7 +113,7 @@ func ManageIssuesHandler(c *bitbucket.Client) func(context.Context, *mcp.CallToo
 				return ToolResultError("issue_id is required for 'update' action"), nil, nil
 			}
 
-			var title, content, state, kind, priority, assignee *string
+			var title, content, state, kind, priority, assignee, milestone, component, version *string
 			if args.Title != "" {
 				title = &args.Title
 			}
@@ -100,6 +129,15 @@ func ManageIssuesHandler(c *bitbucket.Client) func(context.Context, *mcp.CallToo
 			if args.Priority != "" {
 				priority = &args.Priority
 			}
+			if args.Milestone != "" {
+				milestone = &args.Milestone
+			}
+			if args.Component != "" {
+				component = &args.Component
+			}
+			if args.Version != "" {
+				version = &args.Version
+			}
 			if args.Assignee != "" {
 				if args.Assignee == "unassigned" {
 					empty := ""
@@ -119,6 +157,9 @@ func ManageIssuesHandler(c *bitbucket.Client) func(context.Context, *mcp.CallToo
 				Kind:      kind,
 				Priority:  priority,
 				Assignee:  assignee,
+				Milestone: milestone,
+				Component: component,
+				Version:   version,
 			})
 			if err != nil {
 				return ToolResultError(fmt.Sprintf("failed to update issue: %v", err)), nil, nil
@@ -126,8 +167,108 @@ func ManageIssuesHandler(c *bitbucket.Client) func(context.Context, *mcp.CallToo
 			data, _ := json.MarshalIndent(result, "", "  ")
 			return ToolResultText(string(data)), nil, nil
 
+		case "list-milestones", "list-components", "list-versions":
+			list := bitbucket.ListIssueOptionsArgs{
+				Workspace: args.Workspace,
+				RepoSlug:  args.RepoSlug,
+				Page:      args.Page,
+				Pagelen:   args.Pagelen,
+			}
+			var result *bitbucket.Paginated[bitbucket.IssueOption]
+			var err error
+			switch args.Action {
+			case "list-milestones":
+				result, err = c.ListMilestones(list)
+			case "list-components":
+				result, err = c.ListComponents(list)
+			default:
+				result, err = c.ListVersions(list)
+			}
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to %s: %v", strings.ReplaceAll(args.Action, "-", " "), err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(result, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
+		case "list-comments", "add-comment", "history", "list-attachments", "attach", "get-attachment", "vote", "unvote", "watch", "unwatch":
+			if args.IssueID == 0 {
+				return ToolResultError(fmt.Sprintf("issue_id is required for '%s' action", args.Action)), nil, nil
+			}
+			result, err := issueActivity(c, args)
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to %s: %v", strings.ReplaceAll(args.Action, "-", " "), err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(result, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
 		default:
 			return ToolResultError(fmt.Sprintf("unknown action: %s", args.Action)), nil, nil
 		}
 	}
 }
+
+// issueActivity runs the actions on an existing issue's comments,
+// history, attachments, votes, and watches.
+func issueActivity(c *bitbucket.Client, args ManageIssuesArgs) (any, error) {
+	issue := bitbucket.IssueArgs{Workspace: args.Workspace, RepoSlug: args.RepoSlug, IssueID: args.IssueID}
+	switch args.Action {
+	case "list-comments":
+		return c.ListIssueComments(issue, args.Page, args.Pagelen)
+
+	case "add-comment":
+		return c.AddIssueComment(issue, args.Content)
+
+	case "history":
+		return c.ListIssueChanges(issue, args.Page, args.Pagelen)
+
+	case "list-attachments":
+		return c.ListIssueAttachments(issue, args.Page, args.Pagelen)
+
+	case "attach":
+		if args.Name == "" {
+			return nil, fmt.Errorf("name is required")
+		}
+		data := []byte(args.Content)
+		if args.Base64 != "" {
+			var err error
+			if data, err = base64.StdEncoding.DecodeString(args.Base64); err != nil {
+				return nil, fmt.Errorf("invalid content_base64: %v", err)
+			}
+		}
+		if len(data) == 0 {
+			return nil, fmt.Errorf("content or content_base64 is required")
+		}
+		if err := c.UploadIssueAttachment(issue, args.Name, bytes.NewReader(data), int64(len(data))); err != nil {
+			return nil, err
+		}
+		return map[string]any{"issue_id": args.IssueID, "name": args.Name, "bytes": len(data)}, nil
+
+	case "get-attachment":
+		data, err := c.GetIssueAttachment(issue, args.Name)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > maxInlineAttachment {
+			return nil, fmt.Errorf("%s is %d bytes, more than can be returned inline; use 'bbkt issues attachments download'", args.Name, len(data))
+		}
+		result := map[string]any{"issue_id": args.IssueID, "name": args.Name, "bytes": len(data)}
+		if utf8.Valid(data) {
+			result["content"] = string(data)
+		} else {
+			result["content_base64"] = base64.StdEncoding.EncodeToString(data)
+		}
+		return result, nil
+
+	case "vote", "unvote":
+		if err := c.VoteIssue(issue, args.Action == "vote"); err != nil {
+			return nil, err
+		}
+		return map[string]any{"issue_id": args.IssueID, "voted": args.Action == "vote"}, nil
+
+	default: // "watch", "unwatch"
+		if err := c.WatchIssue(issue, args.Action == "watch"); err != nil {
+			return nil, err
+		}
+		return map[string]any{"issue_id": args.IssueID, "watching": args.Action == "watch"}, nil
+	}
+}
@@ -4,24 +4,33 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/zach-snell/bbkt/internal/bitbucket"
 )
 
 type ManagePipelinesArgs struct {
-	Action       string `json:"action" jsonschema:"Action to perform: 'list', 'get', 'trigger', 'stop', 'list-steps', 'get-step-log'" jsonschema_enum:"list,get,trigger,stop,list-steps,get-step-log"`
+	Action       string `json:"action" jsonschema:"Action to perform: 'list', 'get', 'trigger', 'stop', 'list-steps', 'get-step-log', 'lint', 'plan', 'get_failures', 'list-caches', 'clear-cache', 'get-ssh-key', 'list-known-hosts', 'get-oidc', 'stats'" jsonschema_enum:"list,get,trigger,stop,list-steps,get-step-log,lint,plan,get_failures,list-caches,clear-cache,get-ssh-key,list-known-hosts,get-oidc,stats"`
 	Workspace    string `json:"workspace" jsonschema:"Workspace slug"`
 	RepoSlug     string `json:"repo_slug" jsonschema:"Repository slug"`
 	PipelineUUID string `json:"pipeline_uuid,omitempty" jsonschema:"Pipeline UUID"`
-	StepUUID     string `json:"step_uuid,omitempty" jsonschema:"Step UUID (for 'get-step-log')"`
+	StepUUID     string `json:"step_uuid,omitempty" jsonschema:"Step UUID (for 'get-step-log'; optional step UUID or name for 'get_failures')"`
 	RefType      string `json:"ref_type,omitempty" jsonschema:"Reference type: branch or tag (default branch) (for 'trigger')"`
 	RefName      string `json:"ref_name,omitempty" jsonschema:"Branch or tag name to run pipeline on (for 'trigger')"`
-	Pattern      string `json:"pattern,omitempty" jsonschema:"Custom pipeline pattern name to trigger (for 'trigger')"`
+	Pattern      string `json:"pattern,omitempty" jsonschema:"Custom pipeline pattern name (for 'trigger' and 'plan')"`
 	Page         int    `json:"page,omitempty" jsonschema:"Page number"`
 	Pagelen      int    `json:"pagelen,omitempty" jsonschema:"Results per page"`
 	Sort         string `json:"sort,omitempty" jsonschema:"Sort field"`
 	Status       string `json:"status,omitempty" jsonschema:"Filter by status"`
+	Ref          string `json:"ref,omitempty" jsonschema:"Branch, tag, or commit to read bitbucket-pipelines.yml from (for 'lint' without content); also the branch or tag to simulate (for 'plan')"`
+	Content      string `json:"content,omitempty" jsonschema:"Raw bitbucket-pipelines.yml to validate before committing it (for 'lint' and 'plan')"`
+	PullRequest  bool   `json:"pull_request,omitempty" jsonschema:"Simulate a pull request whose source branch is ref (for 'plan')"`
+	Tag          bool   `json:"tag,omitempty" jsonschema:"Treat ref as a tag (for 'plan')"`
+	CacheName    string `json:"cache_name,omitempty" jsonschema:"Cache name to delete, e.g. node (for 'clear-cache')"`
+	Branch       string `json:"branch,omitempty" jsonschema:"Only runs on this branch (for 'stats')"`
+	SinceDays    int    `json:"since_days,omitempty" jsonschema:"Only runs from the last N days (for 'stats', default 30)"`
 }
 
 // ManagePipelinesHandler handles the consolidated pipeline operations.
@@ -119,8 +128,149 @@ func ManagePipelinesHandler(c *bitbucket.Client) func(context.Context, *mcp.Call
 			}
 			return ToolResultText(string(raw)), nil, nil
 
+		case "lint":
+			result, err := c.LintPipelinesConfig(bitbucket.LintPipelinesConfigArgs{
+				Workspace: args.Workspace,
+				RepoSlug:  args.RepoSlug,
+				Ref:       args.Ref,
+				Content:   args.Content,
+			})
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to lint pipelines config: %v", err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(result, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
+		case "plan":
+			result, err := c.PlanPipelinesConfig(bitbucket.PlanPipelinesConfigArgs{
+				Workspace:   args.Workspace,
+				RepoSlug:    args.RepoSlug,
+				Ref:         args.Ref,
+				PullRequest: args.PullRequest,
+				Tag:         args.Tag,
+				Custom:      args.Pattern,
+				Content:     args.Content,
+			})
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to plan pipeline: %v", err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(result, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
+		case "get_failures":
+			results, err := c.GetPipelineTestResults(bitbucket.GetPipelineTestResultsArgs{
+				Workspace:    args.Workspace,
+				RepoSlug:     args.RepoSlug,
+				PipelineUUID: args.PipelineUUID,
+				Step:         args.StepUUID,
+				MaxFailures:  20,
+			})
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to get pipeline failures: %v", err)), nil, nil
+			}
+			// Only failing steps, with stack traces trimmed: the point of this
+			// action is to avoid handing the model megabytes of log.
+			failing := []bitbucket.StepTestResult{}
+			for _, r := range results {
+				if !r.Failing() {
+					continue
+				}
+				for i := range r.FailedTests {
+					for j := range r.FailedTests[i].Reasons {
+						r.FailedTests[i].Reasons[j].StackTrace = headLines(r.FailedTests[i].Reasons[j].StackTrace, 15)
+					}
+				}
+				failing = append(failing, r)
+			}
+			data, _ := json.MarshalIndent(failing, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
+		case "list-caches":
+			result, err := c.ListPipelineCaches(bitbucket.ListPipelineCachesArgs{
+				Workspace: args.Workspace,
+				RepoSlug:  args.RepoSlug,
+			})
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to list caches: %v", err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(result, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
+		case "clear-cache":
+			if args.CacheName == "" {
+				return ToolResultError("cache_name is required for 'clear-cache' (see 'list-caches')"), nil, nil
+			}
+			err := c.DeletePipelineCache(bitbucket.DeletePipelineCacheArgs{
+				Workspace: args.Workspace,
+				RepoSlug:  args.RepoSlug,
+				Name:      args.CacheName,
+			})
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to clear cache: %v", err)), nil, nil
+			}
+			return ToolResultText(fmt.Sprintf("Cache %q cleared; the next run will rebuild it.", args.CacheName)), nil, nil
+
+		case "stats":
+			days := args.SinceDays
+			if days <= 0 {
+				days = 30
+			}
+			st, err := c.GetPipelineStats(bitbucket.PipelineStatsArgs{
+				Workspace: args.Workspace,
+				RepoSlug:  args.RepoSlug,
+				Branch:    args.Branch,
+				Since:     time.Now().AddDate(0, 0, -days),
+			})
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to compute pipeline stats: %v", err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(st, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
+		case "get-ssh-key":
+			kp, err := c.GetPipelineSSHKeyPair(bitbucket.PipelineSSHArgs{
+				Workspace: args.Workspace,
+				RepoSlug:  args.RepoSlug,
+			})
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to get SSH key: %v", err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(map[string]string{
+				"public_key":  kp.PublicKey,
+				"fingerprint": kp.Fingerprint(),
+			}, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
+		case "list-known-hosts":
+			result, err := c.ListKnownHosts(bitbucket.PipelineSSHArgs{
+				Workspace: args.Workspace,
+				RepoSlug:  args.RepoSlug,
+			})
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to list known hosts: %v", err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(result, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
+		case "get-oidc":
+			oidc, err := c.GetPipelinesOIDC(bitbucket.GetPipelinesOIDCArgs{Workspace: args.Workspace})
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to get OIDC configuration: %v", err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(oidc, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
 		default:
 			return ToolResultError(fmt.Sprintf("unknown action: %s", args.Action)), nil, nil
 		}
 	}
 }
+
+// headLines keeps the first n lines of s.
+func headLines(s string, n int) string {
+	lines := strings.SplitN(s, "\n", n+1)
+	if len(lines) <= n {
+		return s
+	}
+	return strings.Join(lines[:n], "\n") + "\n…"
+}
@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+type ManageProjectsArgs struct {
+	Action      string `json:"action" jsonschema:"Action to perform: 'list', 'get', 'create', 'update', 'delete', 'list-repos', 'list-reviewers', 'add-reviewer', 'remove-reviewer', 'list-permissions', 'grant', 'revoke'" jsonschema_enum:"list,get,create,update,delete,list-repos,list-reviewers,add-reviewer,remove-reviewer,list-permissions,grant,revoke"`
+	Workspace   string `json:"workspace" jsonschema:"Workspace slug"`
+	ProjectKey  string `json:"project_key,omitempty" jsonschema:"Project key (required for all but 'list')"`
+	Name        string `json:"name,omitempty" jsonschema:"Project name (for 'create', 'update')"`
+	NewKey      string `json:"new_key,omitempty" jsonschema:"New project key (for 'update')"`
+	Description string `json:"description,omitempty" jsonschema:"Project description (for 'create', 'update')"`
+	IsPrivate   *bool  `json:"is_private,omitempty" jsonschema:"Whether the project is private (default true for 'create'; for 'update')"`
+	User        string `json:"user,omitempty" jsonschema:"User UUID or account ID (for reviewer actions, 'grant', 'revoke')"`
+	Group       string `json:"group,omitempty" jsonschema:"Group slug (for 'grant', 'revoke')"`
+	Permission  string `json:"permission,omitempty" jsonschema:"read, write, create-repo, or admin (for 'grant')"`
+	Query       string `json:"query,omitempty" jsonschema:"Bitbucket query filter (for 'list', e.g. name~\"platform\")"`
+	Pagelen     int    `json:"pagelen,omitempty" jsonschema:"Results per page (default 25)"`
+	Page        int    `json:"page,omitempty" jsonschema:"Page number"`
+}
+
+// projectWriteActions change a project or who can use it.
+var projectWriteActions = []string{"create", "update", "delete", "add-reviewer", "remove-reviewer", "grant", "revoke"}
+
+// ManageProjectsHandler handles project operations. Actions that change
+// projects are refused unless canAdmin, i.e. the token has the
+// project:admin scope.
+func ManageProjectsHandler(c *bitbucket.Client, canAdmin bool) func(context.Context, *mcp.CallToolRequest, ManageProjectsArgs) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args ManageProjectsArgs) (*mcp.CallToolResult, any, error) {
+		args.Workspace, _ = ResolveScope(args.Workspace, "")
+		if args.Workspace == "" {
+			return ToolResultError("workspace is required"), nil, nil
+		}
+		if args.Action != "list" && args.ProjectKey == "" {
+			return ToolResultError(fmt.Sprintf("project_key is required for '%s' action", args.Action)), nil, nil
+		}
+		for _, a := range projectWriteActions {
+			if a == args.Action && !canAdmin {
+				return ToolResultError(fmt.Sprintf("'%s' requires the project:admin scope", args.Action)), nil, nil
+			}
+		}
+		project := bitbucket.ProjectArgs{Workspace: args.Workspace, ProjectKey: args.ProjectKey}
+
+		var result any
+		var err error
+		switch args.Action {
+		case "list":
+			result, err = c.ListProjects(bitbucket.ListProjectsArgs{
+				Workspace: args.Workspace,
+				Query:     args.Query,
+				Page:      args.Page,
+				Pagelen:   args.Pagelen,
+			})
+
+		case "get":
+			result, err = c.GetProject(project)
+
+		case "create":
+			if args.Name == "" {
+				return ToolResultError("name is required for 'create' action"), nil, nil
+			}
+			result, err = c.CreateProject(bitbucket.CreateProjectArgs{
+				Workspace:   args.Workspace,
+				Key:         args.ProjectKey,
+				Name:        args.Name,
+				Description: args.Description,
+				IsPrivate:   args.IsPrivate,
+			})
+
+		case "update":
+			update := bitbucket.UpdateProjectArgs{Workspace: args.Workspace, ProjectKey: args.ProjectKey, IsPrivate: args.IsPrivate}
+			if args.NewKey != "" {
+				update.Key = &args.NewKey
+			}
+			if args.Name != "" {
+				update.Name = &args.Name
+			}
+			if args.Description != "" {
+				update.Description = &args.Description
+			}
+			result, err = c.UpdateProject(update)
+
+		case "delete":
+			if err = c.DeleteProject(project); err == nil {
+				result = map[string]any{"project_key": args.ProjectKey, "deleted": true}
+			}
+
+		case "list-repos":
+			result, err = c.ListProjectRepositories(project, args.Page, args.Pagelen)
+
+		case "list-reviewers":
+			result, err = c.ListProjectDefaultReviewers(project)
+
+		case "add-reviewer":
+			if err = c.AddProjectDefaultReviewer(project, args.User); err == nil {
+				result = map[string]any{"project_key": args.ProjectKey, "user": args.User, "added": true}
+			}
+
+		case "remove-reviewer":
+			if err = c.RemoveProjectDefaultReviewer(project, args.User); err == nil {
+				result = map[string]any{"project_key": args.ProjectKey, "user": args.User, "removed": true}
+			}
+
+		case "list-permissions":
+			result, err = c.ListProjectPermissions(project)
+
+		case "grant", "revoke":
+			kind, subject := bitbucket.PermissionUser, args.User
+			if args.Group != "" {
+				kind, subject = bitbucket.PermissionGroup, args.Group
+			}
+			if (args.User == "") == (args.Group == "") {
+				return ToolResultError(fmt.Sprintf("exactly one of user or group is required for '%s' action", args.Action)), nil, nil
+			}
+			if args.Action == "grant" {
+				result, err = c.SetProjectPermission(project, kind, subject, args.Permission)
+			} else if err = c.RemoveProjectPermission(project, kind, subject); err == nil {
+				result = map[string]any{"project_key": args.ProjectKey, kind: subject, "revoked": true}
+			}
+
+		default:
+			return ToolResultError(fmt.Sprintf("unknown action: %s", args.Action)), nil, nil
+		}
+
+		if err != nil {
+			return ToolResultError(fmt.Sprintf("failed to %s: %v", strings.ReplaceAll(args.Action, "-", " "), err)), nil, nil
+		}
+		data, _ := json.MarshalIndent(result, "", "  ")
+		return ToolResultText(string(data)), nil, nil
+	}
+}
@@ -10,7 +10,7 @@ import (
 )
 
 type ManagePullRequestsArgs struct {
-	Action            string `json:"action" jsonschema:"Action to perform: 'list', 'get', 'create', 'update', 'merge', 'approve', 'unapprove', 'decline', 'get-diff', 'get-diffstat', 'get-commits'" jsonschema_enum:"list,get,create,update,merge,approve,unapprove,decline,get-diff,get-diffstat,get-commits"`
+	Action            string `json:"action" jsonschema:"Action to perform: 'list', 'get', 'create', 'update', 'merge', 'approve', 'unapprove', 'decline', 'get-diff', 'get-diffstat', 'get-commits', 'get-insights'" jsonschema_enum:"list,get,create,update,merge,approve,unapprove,decline,get-diff,get-diffstat,get-commits,get-insights"`
 	Workspace         string `json:"workspace" jsonschema:"Workspace slug"`
 	RepoSlug          string `json:"repo_slug" jsonschema:"Repository slug"`
 	PRID              int    `json:"pr_id,omitempty" jsonschema:"Pull request ID"`
@@ -210,6 +210,17 @@ func ManagePullRequestsHandler(c *bitbucket.Client) func(context.Context, *mcp.C
 			data, _ := json.MarshalIndent(result, "", "  ")
 			return ToolResultText(string(data)), nil, nil
 
+		case "get-insights":
+			if args.PRID == 0 {
+				return ToolResultError("pr_id is required for 'get-insights' action"), nil, nil
+			}
+			result, err := c.GetPullRequestInsights(args.Workspace, args.RepoSlug, args.PRID)
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to get PR insights: %v", err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(result, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
 		default:
 			return ToolResultError(fmt.Sprintf("unknown action: %s", args.Action)), nil, nil
 		}
@@ -10,22 +10,31 @@ import (
 )
 
 type ManageRepositoriesArgs struct {
-	Action      string `json:"action" jsonschema:"Action to perform: 'list', 'get', 'create', 'delete'" jsonschema_enum:"list,get,create,delete"`
-	Workspace   string `json:"workspace" jsonschema:"Workspace slug"`
-	RepoSlug    string `json:"repo_slug,omitempty" jsonschema:"Repository slug (required for 'get', 'create', 'delete')"`
-	Description string `json:"description,omitempty" jsonschema:"Repository description (for 'create')"`
-	Language    string `json:"language,omitempty" jsonschema:"Primary programming language (for 'create')"`
-	IsPrivate   *bool  `json:"is_private,omitempty" jsonschema:"Whether the repo is private (default true, for 'create')"`
-	ProjectKey  string `json:"project_key,omitempty" jsonschema:"Project key to assign the repo to (for 'create')"`
-	Pagelen     int    `json:"pagelen,omitempty" jsonschema:"Results per page (default 25)"`
-	Page        int    `json:"page,omitempty" jsonschema:"Page number"`
-	Query       string `json:"query,omitempty" jsonschema:"Bitbucket query filter (e.g. name~'myrepo')"`
-	Role        string `json:"role,omitempty" jsonschema:"Filter by role: owner, admin, contributor, member"`
-	Sort        string `json:"sort,omitempty" jsonschema:"Sort field (e.g. -updated_on)"`
+	Action            string `json:"action" jsonschema:"Action to perform: 'list', 'get', 'create', 'delete', 'edit', 'fork', 'list-forks'" jsonschema_enum:"list,get,create,delete,edit,fork,list-forks"`
+	Workspace         string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug          string `json:"repo_slug,omitempty" jsonschema:"Repository slug (required for all but 'list')"`
+	Description       string `json:"description,omitempty" jsonschema:"Repository description (for 'create', 'edit', 'fork')"`
+	Language          string `json:"language,omitempty" jsonschema:"Primary programming language (for 'create', 'edit')"`
+	IsPrivate         *bool  `json:"is_private,omitempty" jsonschema:"Whether the repo is private (default true for 'create'; for 'edit', 'fork')"`
+	ProjectKey        string `json:"project_key,omitempty" jsonschema:"Project key to assign the repo to (for 'create', 'edit', 'fork')"`
+	MainBranch        string `json:"main_branch,omitempty" jsonschema:"New main branch, which must exist (for 'edit')"`
+	ForkPolicy        string `json:"fork_policy,omitempty" jsonschema:"allow_forks, no_public_forks, or no_forks (for 'edit')"`
+	Website           string `json:"website,omitempty" jsonschema:"Website URL (for 'edit')"`
+	TargetWorkspace   string `json:"target_workspace,omitempty" jsonschema:"Workspace to fork into (for 'fork', default your personal workspace)"`
+	Name              string `json:"name,omitempty" jsonschema:"Name of the fork (for 'fork')"`
+	EnablePipelines   bool   `json:"enable_pipelines,omitempty" jsonschema:"Enable Pipelines on the new repository (for 'create')"`
+	PipelinesTemplate string `json:"pipelines_template,omitempty" jsonschema:"Commit a starter bitbucket-pipelines.yml: default, node, go, python, docker (for 'create')"`
+	Pagelen           int    `json:"pagelen,omitempty" jsonschema:"Results per page (default 25)"`
+	Page              int    `json:"page,omitempty" jsonschema:"Page number"`
+	Query             string `json:"query,omitempty" jsonschema:"Bitbucket query filter (e.g. name~'myrepo')"`
+	Role              string `json:"role,omitempty" jsonschema:"Filter by role: owner, admin, contributor, member"`
+	Sort              string `json:"sort,omitempty" jsonschema:"Sort field (e.g. -updated_on)"`
 }
 
 // ManageRepositoriesHandler handles the consolidated repository operations.
-func ManageRepositoriesHandler(c *bitbucket.Client) func(context.Context, *mcp.CallToolRequest, ManageRepositoriesArgs) (*mcp.CallToolResult, any, error) {
+// 'edit' and 'fork' are refused unless canWrite, i.e. the token has the
+// repository:write scope.
+func ManageRepositoriesHandler(c *bitbucket.Client, canWrite bool) func(context.Context, *mcp.CallToolRequest, ManageRepositoriesArgs) (*mcp.CallToolResult, any, error) {
 	return func(ctx context.Context, req *mcp.CallToolRequest, args ManageRepositoriesArgs) (*mcp.CallToolResult, any, error) {
 		args.Workspace, args.RepoSlug = ResolveScope(args.Workspace, args.RepoSlug)
 		switch args.Action {
@@ -69,10 +78,18 @@ func ManageRepositoriesHandler(c *bitbucket.Client) func(context.Context, *mcp.C
 				Language:    args.Language,
 				IsPrivate:   args.IsPrivate,
 				ProjectKey:  args.ProjectKey,
+
+				EnablePipelines:   args.EnablePipelines,
+				PipelinesTemplate: args.PipelinesTemplate,
 			})
-			if err != nil {
+			if err != nil && repo == nil {
 				return ToolResultError(fmt.Sprintf("failed to create repository: %v", err)), nil, nil
 			}
+			if err != nil {
+				// Created, but a follow-up step failed.
+				data, _ := json.MarshalIndent(map[string]any{"repository": repo, "partial_failure": err.Error()}, "", "  ")
+				return ToolResultText(string(data)), nil, nil
+			}
 			data, _ := json.MarshalIndent(repo, "", "  ")
 			return ToolResultText(string(data)), nil, nil
 
@@ -89,6 +106,79 @@ func ManageRepositoriesHandler(c *bitbucket.Client) func(context.Context, *mcp.C
 			}
 			return ToolResultText("Repository deleted successfully"), nil, nil
 
+		case "edit":
+			if !canWrite {
+				return ToolResultError("the 'edit' action requires the repository:write scope"), nil, nil
+			}
+			if args.Workspace == "" || args.RepoSlug == "" {
+				return ToolResultError("workspace and repo_slug are required for 'edit' action"), nil, nil
+			}
+			update := bitbucket.UpdateRepositoryArgs{
+				Workspace: args.Workspace,
+				RepoSlug:  args.RepoSlug,
+				IsPrivate: args.IsPrivate,
+			}
+			for _, f := range []struct {
+				value string
+				dst   **string
+			}{
+				{args.Description, &update.Description},
+				{args.Language, &update.Language},
+				{args.ProjectKey, &update.ProjectKey},
+				{args.MainBranch, &update.MainBranch},
+				{args.ForkPolicy, &update.ForkPolicy},
+				{args.Website, &update.Website},
+			} {
+				if f.value != "" {
+					v := f.value
+					*f.dst = &v
+				}
+			}
+			repo, err := c.UpdateRepository(update)
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to edit repository: %v", err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(repo, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
+		case "fork":
+			if !canWrite {
+				return ToolResultError("the 'fork' action requires the repository:write scope"), nil, nil
+			}
+			if args.Workspace == "" || args.RepoSlug == "" {
+				return ToolResultError("workspace and repo_slug are required for 'fork' action"), nil, nil
+			}
+			repo, err := c.ForkRepository(bitbucket.ForkRepositoryArgs{
+				Workspace:       args.Workspace,
+				RepoSlug:        args.RepoSlug,
+				TargetWorkspace: args.TargetWorkspace,
+				Name:            args.Name,
+				Description:     args.Description,
+				IsPrivate:       args.IsPrivate,
+				ProjectKey:      args.ProjectKey,
+			})
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to fork repository: %v", err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(repo, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
+		case "list-forks":
+			if args.Workspace == "" || args.RepoSlug == "" {
+				return ToolResultError("workspace and repo_slug are required for 'list-forks' action"), nil, nil
+			}
+			result, err := c.ListForks(bitbucket.ListForksArgs{
+				Workspace: args.Workspace,
+				RepoSlug:  args.RepoSlug,
+				Pagelen:   args.Pagelen,
+				Page:      args.Page,
+			})
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to list forks: %v", err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(result, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
 		default:
 			return ToolResultError(fmt.Sprintf("unknown action: %s", args.Action)), nil, nil
 		}
@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+type ManageRunnersArgs struct {
+	Action     string `json:"action" jsonschema:"Action to perform: 'list', 'get'" jsonschema_enum:"list,get"`
+	Workspace  string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug   string `json:"repo_slug,omitempty" jsonschema:"Repository slug (omit for workspace runners)"`
+	RunnerUUID string `json:"runner_uuid,omitempty" jsonschema:"Runner UUID (for 'get')"`
+	Page       int    `json:"page,omitempty" jsonschema:"Page number"`
+	Pagelen    int    `json:"pagelen,omitempty" jsonschema:"Results per page"`
+}
+
+// ManageRunnersHandler handles read-only runner operations. Registering runners
+// hands out OAuth secrets, so it is deliberately left to the CLI.
+func ManageRunnersHandler(c *bitbucket.Client) func(context.Context, *mcp.CallToolRequest, ManageRunnersArgs) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args ManageRunnersArgs) (*mcp.CallToolResult, any, error) {
+		// Only the workspace falls back to the server scope: a default repo
+		// would silently turn a workspace-runner query into a repository one.
+		args.Workspace, _ = ResolveScope(args.Workspace, "")
+		scope := bitbucket.RunnerScopeArgs{Workspace: args.Workspace, RepoSlug: args.RepoSlug}
+
+		switch args.Action {
+		case "list":
+			result, err := c.ListRunners(bitbucket.ListRunnersArgs{
+				RunnerScopeArgs: scope,
+				Page:            args.Page,
+				Pagelen:         args.Pagelen,
+			})
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to list runners: %v", err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(result, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
+		case "get":
+			result, err := c.GetRunner(bitbucket.RunnerArgs{RunnerScopeArgs: scope, RunnerUUID: args.RunnerUUID})
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to get runner: %v", err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(result, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
+		default:
+			return ToolResultError(fmt.Sprintf("unknown action: %s", args.Action)), nil, nil
+		}
+	}
+}
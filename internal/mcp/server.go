@@ -38,16 +38,22 @@ func newServer(client *bitbucket.Client) *mcp.Server {
 
 func getToolRequiredScope(toolName string) []string {
 	switch toolName {
-	case "manage_workspaces":
+	case "manage_workspaces", "manage_users":
 		return nil
-	case "manage_repositories", "manage_refs", "manage_commits", "manage_source":
+	case "manage_repositories", "manage_refs", "manage_commits", "manage_source", "manage_commit_statuses":
 		return []string{"repository"}
 	case "manage_pull_requests", "manage_pr_comments":
 		return []string{"pullrequest"}
-	case "manage_pipelines":
+	case "manage_pipelines", "manage_deployments":
 		return []string{"pipeline"}
+	case "manage_runners":
+		return []string{"runner"}
 	case "manage_issues":
 		return []string{"issue"}
+	case "manage_projects":
+		return []string{"project"}
+	case "manage_snippets":
+		return []string{"snippet"}
 	}
 	return nil
 }
@@ -103,6 +109,11 @@ func hasRequiredScope(tokenScopes, required []string) bool {
 				if ts == "write:pipeline:bitbucket" {
 					return true
 				}
+			case "runner":
+				if ts == "runner:write" ||
+					ts == "read:runner:bitbucket" || ts == "write:runner:bitbucket" {
+					return true
+				}
 			case "issue":
 				if ts == "issue:write" ||
 					ts == "read:issue:bitbucket" || ts == "write:issue:bitbucket" {
@@ -112,6 +123,24 @@ func hasRequiredScope(tokenScopes, required []string) bool {
 				if ts == "write:issue:bitbucket" {
 					return true
 				}
+			case "project":
+				if ts == "project:write" || ts == "project:admin" ||
+					ts == "read:project:bitbucket" || ts == "admin:project:bitbucket" {
+					return true
+				}
+			case "project:admin":
+				if ts == "admin:project:bitbucket" {
+					return true
+				}
+			case "snippet":
+				if ts == "snippet:write" ||
+					ts == "read:snippet:bitbucket" || ts == "write:snippet:bitbucket" {
+					return true
+				}
+			case "snippet:write":
+				if ts == "write:snippet:bitbucket" {
+					return true
+				}
 			}
 		}
 	}
@@ -163,10 +192,28 @@ func registerToolsUnauthenticated(s *mcp.Server) {
 		Description: "Unified tool for getting and listing Bitbucket workspaces",
 	})
 
+	// ─── Users ───────────────────────────────────────────────────────
+	addUnauthenticatedTool[ManageUsersArgs](s, mcp.Tool{
+		Name:        "manage_users",
+		Description: "Resolve people to Bitbucket account IDs and UUIDs: find searches workspace members by name, @nickname, account ID, UUID, or email (exact matches first); list-members lists the workspace's members. Use find before passing reviewers, assignees, or permission users",
+	})
+
 	// ─── Repositories ────────────────────────────────────────────────
 	addUnauthenticatedTool[ManageRepositoriesArgs](s, mcp.Tool{
 		Name:        "manage_repositories",
-		Description: "Unified tool for listing, getting, creating, and deleting repositories",
+		Description: "Unified tool for listing, getting, creating, editing, forking, and deleting repositories, and listing forks",
+	})
+
+	// ─── Projects ────────────────────────────────────────────────────
+	addUnauthenticatedTool[ManageProjectsArgs](s, mcp.Tool{
+		Name:        "manage_projects",
+		Description: "Unified tool for listing, getting, creating, updating, and deleting projects, listing a project's repositories, and managing project default reviewers and user/group permissions. Use list to find the project_key for a new repository",
+	})
+
+	// ─── Snippets ────────────────────────────────────────────────────
+	addUnauthenticatedTool[ManageSnippetsArgs](s, mcp.Tool{
+		Name:        "manage_snippets",
+		Description: "Unified tool for listing, getting, creating, updating, and deleting snippets, their comments, and revision history. Use create with content (or files) to share long output such as logs or query results as a link; snippets are private by default",
 	})
 
 	// ─── Branches & Tags ─────────────────────────────────────────────
@@ -184,7 +231,7 @@ func registerToolsUnauthenticated(s *mcp.Server) {
 	// ─── Pull Requests ───────────────────────────────────────────────
 	addUnauthenticatedTool[ManagePullRequestsArgs](s, mcp.Tool{
 		Name:        "manage_pull_requests",
-		Description: "Unified tool covering all pull request operations (list, get, create, update, merge, approve, unapprove, decline, diff, diffstat, commits)",
+		Description: "Unified tool covering all pull request operations (list, get, create, update, merge, approve, unapprove, decline, diff, diffstat, commits, Code Insights reports and annotations on the head commit)",
 	})
 
 	// ─── PR Comments ─────────────────────────────────────────────────
@@ -196,24 +243,42 @@ func registerToolsUnauthenticated(s *mcp.Server) {
 	// ─── Source / File Browsing ──────────────────────────────────────
 	addUnauthenticatedTool[ManageSourceArgs](s, mcp.Tool{
 		Name:        "manage_source",
-		Description: "Unified tool for source code operations (read, list_directory, get_history, search, write, delete)",
+		Description: "Unified tool for source code operations (read, list_directory, get_history, search, write, delete). search covers one repository or, with workspace_wide, the whole workspace, and returns matching lines with repository, path, and line number. Writes to the root bitbucket-pipelines.yml report any lint problems; call manage_pipelines 'lint' first to check content before committing it",
 	})
 
 	// ─── Pipelines ───────────────────────────────────────────────────
 	addUnauthenticatedTool[ManagePipelinesArgs](s, mcp.Tool{
 		Name:        "manage_pipelines",
-		Description: "Unified tool for managing Bitbucket Pipelines (list, get, trigger, stop, list-steps, get-step-log)",
+		Description: "Unified tool for managing Bitbucket Pipelines (list, get, trigger, stop, list-steps, get-step-log, lint, plan, get_failures, list-caches, clear-cache, get-ssh-key, list-known-hosts, get-oidc, stats). Use 'lint' to validate bitbucket-pipelines.yml content before writing it, 'plan' to see which steps would run for a ref, 'get_failures' instead of 'get-step-log' to see why a run failed, 'stats' for success rates, durations and flaky steps, 'clear-cache' when a stale dependency cache is the suspect, and 'get-oidc' for the issuer and audience a cloud role must trust",
+	})
+
+	// ─── Deployments ─────────────────────────────────────────────────
+	addUnauthenticatedTool[ManageDeploymentsArgs](s, mcp.Tool{
+		Name:        "manage_deployments",
+		Description: "Read-only view of deployment environments and deployments (list-environments, get-environment, list-deployments, status, diff). Use 'status' to see which commit is live in each environment and 'diff' to list commits between two environments",
+	})
+
+	// ─── Commit statuses ─────────────────────────────────────────────
+	addUnauthenticatedTool[ManageCommitStatusesArgs](s, mcp.Tool{
+		Name:        "manage_commit_statuses",
+		Description: "Read and report commit build statuses from external CI (list, list-pr, summary, set). Use 'summary' with pr_id for one overall state before merging; 'set' with an existing key updates that build",
+	})
+
+	// ─── Runners ─────────────────────────────────────────────────────
+	addUnauthenticatedTool[ManageRunnersArgs](s, mcp.Tool{
+		Name:        "manage_runners",
+		Description: "Read-only view of self-hosted Pipelines runners (list, get) with labels, status, and last-seen time. Omit repo_slug for workspace runners",
 	})
 
 	// ─── Issues ──────────────────────────────────────────────────────
 	addUnauthenticatedTool[APIRequestArgs](s, mcp.Tool{
 		Name:        "bitbucket_api",
-		Description: "Authenticated passthrough to ANY Bitbucket Cloud REST API v2 endpoint not covered by the manage_* tools above (e.g. pipelines-config/OIDC, webhooks, branch restrictions). Prefer a typed manage_* tool when one fits; use this for the long tail instead of calling the API yourself. Reads (GET/HEAD) work by default; write methods are rejected unless BBKT_API_ALLOW_WRITE is set on the server. Supports pagination and returns the raw HTTP status + JSON body.",
+		Description: "Authenticated passthrough to ANY Bitbucket Cloud REST API v2 endpoint not covered by the manage_* tools above (e.g. webhooks, branch restrictions). Prefer a typed manage_* tool when one fits; use this for the long tail instead of calling the API yourself. Reads (GET/HEAD) work by default; write methods are rejected unless BBKT_API_ALLOW_WRITE is set on the server. Supports pagination and returns the raw HTTP status + JSON body.",
 	})
 
 	addUnauthenticatedTool[ManageIssuesArgs](s, mcp.Tool{
 		Name:        "manage_issues",
-		Description: "Unified tool for managing repository issues (list, get, create, update)",
+		Description: "Unified tool for managing repository issues (list, get, create, update) and an issue's comments, change history, attachments, votes, and watches. Use list-milestones, list-components, and list-versions for the values milestone, component, and version accept",
 	})
 }
 
@@ -237,11 +302,29 @@ func registerTools(s *mcp.Server, c *bitbucket.Client) {
 		Description: "Unified tool for getting and listing Bitbucket workspaces",
 	}, ManageWorkspacesHandler(c))
 
+	// ─── Users ───────────────────────────────────────────────────────
+	addTool(s, disabled, tokenScopes, mcp.Tool{
+		Name:        "manage_users",
+		Description: "Resolve people to Bitbucket account IDs and UUIDs: find searches workspace members by name, @nickname, account ID, UUID, or email (exact matches first); list-members lists the workspace's members. Use find before passing reviewers, assignees, or permission users",
+	}, ManageUsersHandler(c))
+
 	// ─── Repositories ────────────────────────────────────────────────
 	addTool(s, disabled, tokenScopes, mcp.Tool{
 		Name:        "manage_repositories",
-		Description: "Unified tool for listing, getting, creating, and deleting repositories",
-	}, ManageRepositoriesHandler(c))
+		Description: "Unified tool for listing, getting, creating, editing, forking, and deleting repositories, and listing forks",
+	}, ManageRepositoriesHandler(c, hasRequiredScope(tokenScopes, []string{"repository:write"})))
+
+	// ─── Projects ────────────────────────────────────────────────────
+	addTool(s, disabled, tokenScopes, mcp.Tool{
+		Name:        "manage_projects",
+		Description: "Unified tool for listing, getting, creating, updating, and deleting projects, listing a project's repositories, and managing project default reviewers and user/group permissions. Use list to find the project_key for a new repository",
+	}, ManageProjectsHandler(c, hasRequiredScope(tokenScopes, []string{"project:admin"})))
+
+	// ─── Snippets ────────────────────────────────────────────────────
+	addTool(s, disabled, tokenScopes, mcp.Tool{
+		Name:        "manage_snippets",
+		Description: "Unified tool for listing, getting, creating, updating, and deleting snippets, their comments, and revision history. Use create with content (or files) to share long output such as logs or query results as a link; snippets are private by default",
+	}, ManageSnippetsHandler(c, hasRequiredScope(tokenScopes, []string{"snippet:write"})))
 
 	// ─── Branches & Tags ─────────────────────────────────────────────
 	addTool(s, disabled, tokenScopes, mcp.Tool{
@@ -258,7 +341,7 @@ func registerTools(s *mcp.Server, c *bitbucket.Client) {
 	// ─── Pull Requests ───────────────────────────────────────────────
 	addTool(s, disabled, tokenScopes, mcp.Tool{
 		Name:        "manage_pull_requests",
-		Description: "Unified tool covering all pull request operations (list, get, create, update, merge, approve, unapprove, decline, diff, diffstat, commits)",
+		Description: "Unified tool covering all pull request operations (list, get, create, update, merge, approve, unapprove, decline, diff, diffstat, commits, Code Insights reports and annotations on the head commit)",
 	}, ManagePullRequestsHandler(c))
 
 	// ─── PR Comments ─────────────────────────────────────────────────
@@ -270,24 +353,42 @@ func registerTools(s *mcp.Server, c *bitbucket.Client) {
 	// ─── Source / File Browsing ──────────────────────────────────────
 	addTool(s, disabled, tokenScopes, mcp.Tool{
 		Name:        "manage_source",
-		Description: "Unified tool for source code operations (read, list_directory, get_history, search, write, delete)",
+		Description: "Unified tool for source code operations (read, list_directory, get_history, search, write, delete). search covers one repository or, with workspace_wide, the whole workspace, and returns matching lines with repository, path, and line number. Writes to the root bitbucket-pipelines.yml report any lint problems; call manage_pipelines 'lint' first to check content before committing it",
 	}, ManageSourceHandler(c))
 
 	// ─── Pipelines ───────────────────────────────────────────────────
 	addTool(s, disabled, tokenScopes, mcp.Tool{
 		Name:        "manage_pipelines",
-		Description: "Unified tool for managing Bitbucket Pipelines (list, get, trigger, stop, list-steps, get-step-log)",
+		Description: "Unified tool for managing Bitbucket Pipelines (list, get, trigger, stop, list-steps, get-step-log, lint, plan, get_failures, list-caches, clear-cache, get-ssh-key, list-known-hosts, get-oidc, stats). Use 'lint' to validate bitbucket-pipelines.yml content before writing it, 'plan' to see which steps would run for a ref, 'get_failures' instead of 'get-step-log' to see why a run failed, 'stats' for success rates, durations and flaky steps, 'clear-cache' when a stale dependency cache is the suspect, and 'get-oidc' for the issuer and audience a cloud role must trust",
 	}, ManagePipelinesHandler(c))
 
+	// ─── Deployments ─────────────────────────────────────────────────
+	addTool(s, disabled, tokenScopes, mcp.Tool{
+		Name:        "manage_deployments",
+		Description: "Read-only view of deployment environments and deployments (list-environments, get-environment, list-deployments, status, diff). Use 'status' to see which commit is live in each environment and 'diff' to list commits between two environments",
+	}, ManageDeploymentsHandler(c))
+
+	// ─── Commit statuses ─────────────────────────────────────────────
+	addTool(s, disabled, tokenScopes, mcp.Tool{
+		Name:        "manage_commit_statuses",
+		Description: "Read and report commit build statuses from external CI (list, list-pr, summary, set). Use 'summary' with pr_id for one overall state before merging; 'set' with an existing key updates that build",
+	}, ManageCommitStatusesHandler(c, hasRequiredScope(tokenScopes, []string{"repository:write"})))
+
+	// ─── Runners ─────────────────────────────────────────────────────
+	addTool(s, disabled, tokenScopes, mcp.Tool{
+		Name:        "manage_runners",
+		Description: "Read-only view of self-hosted Pipelines runners (list, get) with labels, status, and last-seen time. Omit repo_slug for workspace runners",
+	}, ManageRunnersHandler(c))
+
 	// ─── Issues ──────────────────────────────────────────────────────
 	addTool(s, disabled, tokenScopes, mcp.Tool{
 		Name:        "manage_issues",
-		Description: "Unified tool for managing repository issues (list, get, create, update)",
-	}, ManageIssuesHandler(c))
+		Description: "Unified tool for managing repository issues (list, get, create, update) and an issue's comments, change history, attachments, votes, and watches. Use list-milestones, list-components, and list-versions for the values milestone, component, and version accept",
+	}, ManageIssuesHandler(c, hasRequiredScope(tokenScopes, []string{"issue:write"})))
 
 	// ─── Raw API passthrough (escape hatch) ──────────────────────────
 	addTool(s, disabled, tokenScopes, mcp.Tool{
 		Name:        "bitbucket_api",
-		Description: "Authenticated passthrough to ANY Bitbucket Cloud REST API v2 endpoint not covered by the manage_* tools above (e.g. pipelines-config/OIDC, webhooks, branch restrictions). Prefer a typed manage_* tool when one fits; use this for the long tail instead of calling the API yourself. Reads (GET/HEAD) work by default; write methods are rejected unless BBKT_API_ALLOW_WRITE is set on the server. Supports pagination and returns the raw HTTP status + JSON body.",
+		Description: "Authenticated passthrough to ANY Bitbucket Cloud REST API v2 endpoint not covered by the manage_* tools above (e.g. webhooks, branch restrictions). Prefer a typed manage_* tool when one fits; use this for the long tail instead of calling the API yourself. Reads (GET/HEAD) work by default; write methods are rejected unless BBKT_API_ALLOW_WRITE is set on the server. Supports pagination and returns the raw HTTP status + JSON body.",
 	}, APIRequestHandler(c))
 }
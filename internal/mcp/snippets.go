@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+type ManageSnippetsArgs struct {
+	Action    string            `json:"action" jsonschema:"Action to perform: 'list', 'get', 'create', 'update', 'delete', 'list-comments', 'add-comment', 'history'" jsonschema_enum:"list,get,create,update,delete,list-comments,add-comment,history"`
+	Workspace string            `json:"workspace" jsonschema:"Workspace slug"`
+	SnippetID string            `json:"snippet_id,omitempty" jsonschema:"Snippet ID as shown in its URL, e.g. kypj9 (required for all but 'list' and 'create')"`
+	Title     string            `json:"title,omitempty" jsonschema:"Snippet title (for 'create', 'update')"`
+	IsPrivate *bool             `json:"is_private,omitempty" jsonschema:"Only workspace members can see the snippet (for 'create', 'update'; default private for 'create')"`
+	Filename  string            `json:"filename,omitempty" jsonschema:"Name of the single file given by content (for 'create', 'update'; default snippet.txt), or the one file to return (for 'get')"`
+	Content   string            `json:"content,omitempty" jsonschema:"Content of a single file (for 'create', 'update'), or the comment text (for 'add-comment')"`
+	Files     map[string]string `json:"files,omitempty" jsonschema:"File contents keyed by file name, for multi-file snippets (for 'create', 'update')"`
+	Role      string            `json:"role,omitempty" jsonschema:"owner, contributor, or member (for 'list')"`
+	Pagelen   int               `json:"pagelen,omitempty" jsonschema:"Results per page (default 25)"`
+	Page      int               `json:"page,omitempty" jsonschema:"Page number"`
+}
+
+// snippetWriteActions change snippets or their comments.
+var snippetWriteActions = []string{"create", "update", "delete", "add-comment"}
+
+// snippetSummary is the compact form of a snippet returned by 'get',
+// 'create', and 'update'.
+type snippetSummary struct {
+	SnippetID string   `json:"snippet_id"`
+	URL       string   `json:"url"`
+	Title     string   `json:"title,omitempty"`
+	IsPrivate bool     `json:"is_private"`
+	Files     []string `json:"files"`
+}
+
+// ManageSnippetsHandler handles snippet operations. Actions that change
+// snippets are refused unless canWrite, i.e. the token has the
+// snippet:write scope.
+func ManageSnippetsHandler(c *bitbucket.Client, canWrite bool) func(context.Context, *mcp.CallToolRequest, ManageSnippetsArgs) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args ManageSnippetsArgs) (*mcp.CallToolResult, any, error) {
+		args.Workspace, _ = ResolveScope(args.Workspace, "")
+		if args.Workspace == "" {
+			return ToolResultError("workspace is required"), nil, nil
+		}
+		if args.Action != "list" && args.Action != "create" && args.SnippetID == "" {
+			return ToolResultError(fmt.Sprintf("snippet_id is required for '%s' action", args.Action)), nil, nil
+		}
+		for _, a := range snippetWriteActions {
+			if a == args.Action && !canWrite {
+				return ToolResultError(fmt.Sprintf("'%s' requires the snippet:write scope", args.Action)), nil, nil
+			}
+		}
+		snippet := bitbucket.SnippetArgs{Workspace: args.Workspace, SnippetID: args.SnippetID}
+
+		// A single file may be given as content (and filename) instead of files.
+		files := args.Files
+		if args.Content != "" && args.Action != "add-comment" {
+			name := args.Filename
+			if name == "" {
+				name = "snippet.txt"
+			}
+			if files == nil {
+				files = map[string]string{}
+			}
+			files[name] = args.Content
+		}
+
+		var result any
+		var err error
+		switch args.Action {
+		case "list":
+			result, err = c.ListSnippets(bitbucket.ListSnippetsArgs{
+				Workspace: args.Workspace,
+				Role:      args.Role,
+				Page:      args.Page,
+				Pagelen:   args.Pagelen,
+			})
+
+		case "get":
+			var s *bitbucket.Snippet
+			if s, err = c.GetSnippet(snippet); err != nil {
+				break
+			}
+			names := s.FileNames()
+			if args.Filename != "" {
+				names = []string{args.Filename}
+			}
+			contents := map[string]string{}
+			for _, name := range names {
+				var data []byte
+				if data, err = c.GetSnippetFile(snippet, name); err != nil {
+					break
+				}
+				contents[name] = string(data)
+			}
+			result = map[string]any{"snippet": compactSnippet(s), "contents": contents}
+
+		case "create":
+			private := args.IsPrivate == nil || *args.IsPrivate
+			var s *bitbucket.Snippet
+			if s, err = c.CreateSnippet(bitbucket.CreateSnippetArgs{
+				Workspace: args.Workspace,
+				Title:     args.Title,
+				IsPrivate: private,
+				Files:     files,
+			}); err == nil {
+				result = compactSnippet(s)
+			}
+
+		case "update":
+			update := bitbucket.UpdateSnippetArgs{Workspace: args.Workspace, SnippetID: args.SnippetID, IsPrivate: args.IsPrivate, Files: files}
+			if args.Title != "" {
+				update.Title = &args.Title
+			}
+			var s *bitbucket.Snippet
+			if s, err = c.UpdateSnippet(update); err == nil {
+				result = compactSnippet(s)
+			}
+
+		case "delete":
+			if err = c.DeleteSnippet(snippet); err == nil {
+				result = map[string]any{"snippet_id": args.SnippetID, "deleted": true}
+			}
+
+		case "list-comments":
+			result, err = c.ListSnippetComments(snippet, args.Page, args.Pagelen)
+
+		case "add-comment":
+			result, err = c.AddSnippetComment(snippet, args.Content)
+
+		case "history":
+			result, err = c.ListSnippetCommits(snippet, args.Page, args.Pagelen)
+
+		default:
+			return ToolResultError(fmt.Sprintf("unknown action: %s", args.Action)), nil, nil
+		}
+
+		if err != nil {
+			return ToolResultError(fmt.Sprintf("failed to %s: %v", strings.ReplaceAll(args.Action, "-", " "), err)), nil, nil
+		}
+		data, _ := json.MarshalIndent(result, "", "  ")
+		return ToolResultText(string(data)), nil, nil
+	}
+}
+
+func compactSnippet(s *bitbucket.Snippet) snippetSummary {
+	return snippetSummary{
+		SnippetID: s.EncodedID(),
+		URL:       s.URL(),
+		Title:     s.Title,
+		IsPrivate: s.IsPrivate,
+		Files:     s.FileNames(),
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"path"
 	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -11,19 +12,22 @@ import (
 )
 
 type ManageSourceArgs struct {
-	Action    string `json:"action" jsonschema:"Action to perform: 'read_file', 'list_directory', 'get_history', 'search', 'write_file', 'delete_file'" jsonschema_enum:"read_file,list_directory,get_history,search,write_file,delete_file"`
-	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
-	RepoSlug  string `json:"repo_slug" jsonschema:"Repository slug"`
-	Path      string `json:"path,omitempty" jsonschema:"Path to the file or directory"`
-	Ref       string `json:"ref,omitempty" jsonschema:"Commit hash, branch, or tag (default: HEAD)"`
-	Query     string `json:"query,omitempty" jsonschema:"Search query"`
-	Content   string `json:"content,omitempty" jsonschema:"Content to write to the file"`
-	Message   string `json:"message,omitempty" jsonschema:"Commit message"`
-	Branch    string `json:"branch,omitempty" jsonschema:"Branch to commit to"`
-	Author    string `json:"author,omitempty" jsonschema:"Commit author in 'Name <email>' format"`
-	MaxDepth  int    `json:"max_depth,omitempty" jsonschema:"Maximum depth of recursion (for list_directory)"`
-	Page      int    `json:"page,omitempty" jsonschema:"Page number"`
-	Pagelen   int    `json:"pagelen,omitempty" jsonschema:"Results per page"`
+	Action        string `json:"action" jsonschema:"Action to perform: 'read_file', 'list_directory', 'get_history', 'search', 'write_file', 'delete_file'" jsonschema_enum:"read_file,list_directory,get_history,search,write_file,delete_file"`
+	Workspace     string `json:"workspace" jsonschema:"Workspace slug"`
+	RepoSlug      string `json:"repo_slug" jsonschema:"Repository slug"`
+	Path          string `json:"path,omitempty" jsonschema:"Path to the file or directory"`
+	Ref           string `json:"ref,omitempty" jsonschema:"Commit hash, branch, or tag (default: HEAD)"`
+	Query         string `json:"query,omitempty" jsonschema:"Search query; may include repo:, lang:, ext:, and path: qualifiers"`
+	Language      string `json:"language,omitempty" jsonschema:"Only files in this language (for search)"`
+	Extension     string `json:"extension,omitempty" jsonschema:"Only files with this extension (for search)"`
+	WorkspaceWide bool   `json:"workspace_wide,omitempty" jsonschema:"Search every repository in the workspace instead of repo_slug (for search)"`
+	Content       string `json:"content,omitempty" jsonschema:"Content to write to the file"`
+	Message       string `json:"message,omitempty" jsonschema:"Commit message"`
+	Branch        string `json:"branch,omitempty" jsonschema:"Branch to commit to"`
+	Author        string `json:"author,omitempty" jsonschema:"Commit author in 'Name <email>' format"`
+	MaxDepth      int    `json:"max_depth,omitempty" jsonschema:"Maximum depth of recursion (for list_directory)"`
+	Page          int    `json:"page,omitempty" jsonschema:"Page number"`
+	Pagelen       int    `json:"pagelen,omitempty" jsonschema:"Results per page"`
 }
 
 // ManageSourceHandler handles the consolidated source file and directory operations.
@@ -90,22 +94,29 @@ func ManageSourceHandler(c *bitbucket.Client) func(context.Context, *mcp.CallToo
 			if args.Query == "" {
 				return ToolResultError("query is required for 'search' action"), nil, nil
 			}
-			raw, err := c.SearchCode(bitbucket.SearchCodeArgs{
+			repoSlug := args.RepoSlug
+			if args.WorkspaceWide {
+				repoSlug = ""
+			}
+			result, err := c.SearchCode(bitbucket.SearchCodeArgs{
 				Workspace:   args.Workspace,
-				RepoSlug:    args.RepoSlug,
+				RepoSlug:    repoSlug,
 				SearchQuery: args.Query,
+				Language:    args.Language,
+				Extension:   args.Extension,
 				Page:        args.Page,
 				Pagelen:     args.Pagelen,
 			})
 			if err != nil {
 				return ToolResultError(fmt.Sprintf("failed to search code: %v", err)), nil, nil
 			}
-			var prettyJSON interface{}
-			if err := json.Unmarshal(raw, &prettyJSON); err == nil {
-				data, _ := json.MarshalIndent(prettyJSON, "", "  ")
-				return ToolResultText(string(data)), nil, nil
-			}
-			return ToolResultText(string(raw)), nil, nil
+			data, _ := json.MarshalIndent(map[string]any{
+				"files":    result.Size,
+				"page":     result.Page,
+				"has_more": result.Next != "",
+				"matches":  result.Matches(),
+			}, "", "  ")
+			return ToolResultText(string(data)), nil, nil
 
 		case "write_file":
 			if args.Path == "" || args.Content == "" || args.Message == "" {
@@ -123,6 +134,14 @@ func ManageSourceHandler(c *bitbucket.Client) func(context.Context, *mcp.CallToo
 			if err != nil {
 				return ToolResultError(fmt.Sprintf("failed to write file: %v", err)), nil, nil
 			}
+			// A broken pipelines config only surfaces when the next pipeline
+			// fails to start, so report lint problems with the write.
+			if path.Clean(strings.TrimPrefix(args.Path, "/")) == bitbucket.PipelinesConfigPath {
+				if lint := bitbucket.LintPipelinesYAML([]byte(args.Content)); !lint.Valid {
+					data, _ := json.MarshalIndent(lint, "", "  ")
+					return ToolResultText(fmt.Sprintf("Successfully wrote %s, but it does not lint: %s\n%s", args.Path, lint.Summary(), data)), nil, nil
+				}
+			}
 			return ToolResultText(fmt.Sprintf("Successfully wrote %s", args.Path)), nil, nil
 
 		case "delete_file":
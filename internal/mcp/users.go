@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/zach-snell/bbkt/internal/bitbucket"
+)
+
+type ManageUsersArgs struct {
+	Action    string `json:"action" jsonschema:"Action to perform: 'find', 'list-members'" jsonschema_enum:"find,list-members"`
+	Workspace string `json:"workspace" jsonschema:"Workspace slug"`
+	Query     string `json:"query,omitempty" jsonschema:"Name, @nickname, account ID, UUID, or email to resolve (for 'find')"`
+	Pagelen   int    `json:"pagelen,omitempty" jsonschema:"Results per page (for 'list-members', default 25)"`
+	Page      int    `json:"page,omitempty" jsonschema:"Page number (for 'list-members')"`
+}
+
+// userMatch is the compact form of a user returned by 'find'.
+type userMatch struct {
+	DisplayName string `json:"display_name"`
+	Nickname    string `json:"nickname,omitempty"`
+	AccountID   string `json:"account_id,omitempty"`
+	UUID        string `json:"uuid"`
+}
+
+// ManageUsersHandler resolves people to the account IDs and UUIDs that
+// reviewer, assignee, and permission parameters take.
+func ManageUsersHandler(c *bitbucket.Client) func(context.Context, *mcp.CallToolRequest, ManageUsersArgs) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args ManageUsersArgs) (*mcp.CallToolResult, any, error) {
+		args.Workspace, _ = ResolveScope(args.Workspace, "")
+		switch args.Action {
+		case "find":
+			if args.Query == "" {
+				return ToolResultError("query is required for 'find' action"), nil, nil
+			}
+			users, err := c.FindMembers(args.Workspace, args.Query)
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to find users: %v", err)), nil, nil
+			}
+			matches := make([]userMatch, 0, len(users))
+			for _, u := range users {
+				matches = append(matches, userMatch{DisplayName: u.DisplayName, Nickname: u.Nickname, AccountID: u.AccountID, UUID: u.UUID})
+			}
+			data, _ := json.MarshalIndent(matches, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
+		case "list-members":
+			result, err := c.ListMembers(bitbucket.ListMembersArgs{
+				Workspace: args.Workspace,
+				Page:      args.Page,
+				Pagelen:   args.Pagelen,
+			})
+			if err != nil {
+				return ToolResultError(fmt.Sprintf("failed to list members: %v", err)), nil, nil
+			}
+			data, _ := json.MarshalIndent(result, "", "  ")
+			return ToolResultText(string(data)), nil, nil
+
+		default:
+			return ToolResultError(fmt.Sprintf("unknown action: %s", args.Action)), nil, nil
+		}
+	}
+}